@@ -1,40 +1,142 @@
 package cmd
 
 import (
+	"errors"
 	"os"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/shipyard"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
 func newDestroyCmd(cc clients.Connector) *cobra.Command {
-	return &cobra.Command{
-		Use:     "down",
-		Short:   "Remove all resources in the current state",
-		Long:    "Remove all resources in the current state",
-		Example: `jumppad down`,
+	var targets []string
+	var output string
+	var force bool
+	var continueOnError bool
+
+	destroyCmd := &cobra.Command{
+		Use:   "down",
+		Short: "Remove all resources in the current state",
+		Long:  "Remove all resources in the current state",
+		Example: `
+  # Remove all resources
+  jumppad down
+
+  # Remove a single resource and any resources that depend on it
+  jumppad down --target resource.container.api
+
+  # Remove all resources including resources with prevent_destroy set
+  jumppad down --force
+
+  # Attempt to remove every resource even when a resource fails to be removed
+  jumppad down --continue-on-error
+
+  # Write the progress of each resource as newline delimited JSON
+  jumppad down --output json
+	`,
 		Run: func(cmd *cobra.Command, args []string) {
-			err := engine.Destroy()
+			if err := validateOutputFormat(output); err != nil {
+				hclog.Default().Error(err.Error())
+				return
+			}
+
+			o := shipyard.DefaultOptions()
+			o.Targets = targets
+			o.Force = force
+			o.ContinueOnError = continueOnError
+			engine.SetOptions(o)
+
+			ctx, cancel := newInterruptContext(hclog.Default())
+			defer cancel()
+
+			progress := watchProgress(engine, cmd.OutOrStdout(), output)
+			err := engine.Destroy(ctx)
+			progress.Stop()
+
 			if err != nil {
 				hclog.Default().Error("Unable to destroy stack", "error", err)
+
+				de := shipyard.DestroyError{}
+				if errors.As(err, &de) {
+					printOrphans(cmd, de.Orphans)
+				}
+
 				return
 			}
 
-			// clean up the data folder
-			os.RemoveAll(utils.GetDataFolder("", os.ModePerm))
+			// only the targeted resources have been removed, the remaining
+			// resources still need the data folder, certs and ingress
+			if len(targets) > 0 {
+				return
+			}
 
-			// remove the certs
-			os.RemoveAll(utils.CertsDir(""))
+			// clean up the data folder and the certs for the workspace
+			err = utils.RemoveWorkspaceFiles()
+			if err != nil {
+				hclog.Default().Error("Unable to remove workspace files", "error", err)
+			}
+
+			// the ingress is shared by all workspaces, the state of other
+			// workspaces can not be listed with a remote or directory backend
+			if !resources.IsLocalStateBackend() {
+				hclog.Default().Debug("Not stopping ingress, state backend is not local")
+				return
+			}
+
+			// only shut the ingress down when no workspace has any resources
+			ws, err := utils.WorkspacesWithState()
+			if err != nil {
+				hclog.Default().Error("Unable to list workspaces", "error", err)
+				return
+			}
+
+			if len(ws) > 0 {
+				hclog.Default().Debug("Not stopping ingress, workspaces have resources", "workspaces", ws)
+				return
+			}
 
-			// shutdown ingress when we destroy all resources
 			if cc.IsRunning() {
 				err = cc.Stop()
 				if err != nil {
 					hclog.Default().Error("Unable to stop ingress", "error", err)
 				}
 			}
+
+			// the connector certificates are re-generated on the next up
+			os.RemoveAll(utils.ConnectorCertsDir())
 		},
 	}
+
+	destroyCmd.Flags().StringSliceVarP(&targets, "target", "", nil, "Only remove the resource with the given id and any resources that depend on it, e.g --target resource.container.api. Can be specified multiple times")
+	destroyCmd.Flags().BoolVarP(&force, "force", "", false, "Remove resources which have lifecycle prevent_destroy set")
+	destroyCmd.Flags().BoolVarP(&continueOnError, "continue-on-error", "", false, "Attempt to remove every resource, by default no further resources are removed once a resource fails to be removed")
+	destroyCmd.Flags().StringVarP(&output, "output", "o", outputText, "Format for the progress of each resource, text or json. json writes an event per line to stdout")
+
+	return destroyCmd
+}
+
+// printOrphans prints the resources which could not be removed and the
+// objects, such as Docker containers or networks, which still exist for them
+func printOrphans(cmd *cobra.Command, orphans []shipyard.Orphan) {
+	if len(orphans) == 0 {
+		return
+	}
+
+	cmd.Println()
+	cmd.Printf("%-50s %s\n", "RESOURCE", "REASON")
+
+	for _, o := range orphans {
+		cmd.Printf("%-50s %s\n", o.Resource, o.Reason)
+
+		for _, id := range o.IDs {
+			cmd.Printf("  %s %s\n", o.ResourceType, id)
+		}
+	}
+
+	cmd.Println()
+	cmd.Printf("%d resource(s) could not be removed and remain in the state, run 'jumppad down' again to retry\n", len(orphans))
 }
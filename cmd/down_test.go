@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/shipyard"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintOrphansListsResourcesAndObjects(t *testing.T) {
+	out := bytes.NewBufferString("")
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+
+	printOrphans(cmd, []shipyard.Orphan{
+		{Resource: "resource.network.onprem", ResourceType: "network", IDs: []string{"abc123"}, Reason: "network has active endpoints"},
+	})
+
+	require.Contains(t, out.String(), "resource.network.onprem")
+	require.Contains(t, out.String(), "network abc123")
+	require.Contains(t, out.String(), "network has active endpoints")
+	require.Contains(t, out.String(), "1 resource(s) could not be removed")
+}
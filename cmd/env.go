@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/jumppad-labs/jumppad/pkg/config/resources"
@@ -59,6 +60,31 @@ func newEnvCmd(e shipyard.Engine) *cobra.Command {
 				}
 			}
 
+			// add the environment variables defined by the blueprint
+			bps, _ := c.FindResourcesByType(resources.TypeBlueprint)
+			for _, r := range bps {
+				if r.Metadata().Disabled || r.Metadata().Module != "" {
+					continue
+				}
+
+				env := r.(*resources.Blueprint).Environment
+
+				keys := []string{}
+				for k := range env {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+
+				for _, k := range keys {
+					val := strings.ReplaceAll(env[k], `\`, `\\`)
+					if unset {
+						fmt.Printf("%s%s\n", prefix, k)
+					} else {
+						fmt.Printf("%s%s=\"%s\"\n", prefix, k, val)
+					}
+				}
+			}
+
 			// add output variables
 			for _, r := range c.Resources {
 				if r.Metadata().Type == types.TypeOutput {
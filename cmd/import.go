@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jumppad-labs/jumppad/pkg/shipyard"
+	"github.com/spf13/cobra"
+)
+
+func newImportCmd(e shipyard.Engine) *cobra.Command {
+	return &cobra.Command{
+		Use:   "import [resource] [docker id or name]",
+		Short: "Import an existing Docker container, network or volume into the state",
+		Long: `Import an existing Docker container, network or volume into the state
+
+The Docker object is inspected and a matching resource is added to the state,
+the resource is then managed by jumppad as if it had been created by jumppad.
+Add the resource to your configuration before running 'jumppad up', changes
+to the configuration are applied to the imported resource.
+
+Networks must be imported using a resource with the same name as the Docker network.`,
+		Example: `  # Import the container named db
+  jumppad import resource.container.db db
+
+  # Import a network
+  jumppad import resource.network.local local
+
+  # Import a volume
+  jumppad import resource.volume.data my-data`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := e.Import(args[0], args[1])
+			if err != nil {
+				return fmt.Errorf("Unable to import %s: %s", args[0], err)
+			}
+
+			cmd.Printf("Imported %s as %s\n", args[1], r.Metadata().ID)
+
+			return nil
+		},
+		SilenceUsage: true,
+	}
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hokaccha/go-prettyjson"
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/shipyard"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newPlanCmd(e shipyard.Engine, bp clients.Getter) *cobra.Command {
+	var variables []string
+	var variablesFile string
+	var jsonOutput bool
+	var output string
+
+	planCmd := &cobra.Command{
+		Use:   "plan [file] | [directory]",
+		Short: "Show the changes that will be made when the resources at the given path are created",
+		Long:  `Show the changes that will be made when the resources at the given path are created`,
+		Example: `
+  # Show the changes for the .hcl files in the current folder
+  jumppad plan ./
+
+  # Show the changes as JSON
+  jumppad plan --output json ./
+	`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if jsonOutput {
+				output = outputJSON
+			}
+
+			if err := validateOutputFormat(output); err != nil {
+				return err
+			}
+
+			// parse the vars into a map
+			vars := map[string]string{}
+			for _, v := range variables {
+				parts := strings.Split(v, "=")
+				if len(parts) == 2 {
+					vars[parts[0]] = parts[1]
+				}
+			}
+
+			// check the variables file exists
+			if variablesFile != "" {
+				if _, err := os.Stat(variablesFile); err != nil {
+					return fmt.Errorf("Variables file %s, does not exist", variablesFile)
+				}
+			}
+
+			dst := "./"
+			if len(args) == 1 {
+				dst = args[0]
+			}
+
+			if !utils.IsLocalFolder(dst) && !utils.IsHCLFile(dst) {
+				// fetch the remote server from github
+				err := bp.Get(dst, utils.GetBlueprintLocalFolder(dst))
+				if err != nil {
+					return fmt.Errorf("Unable to retrieve blueprint: %s", err)
+				}
+
+				dst = utils.GetBlueprintLocalFolder(dst)
+			}
+
+			// an error loading the state means that there is no existing state
+			// and all resources will be created
+			state, _ := resources.LoadState()
+
+			res, err := e.ParseConfigWithVariables(dst, vars, variablesFile)
+			if err != nil {
+				return err
+			}
+
+			plan := shipyard.NewPlan(state, res)
+
+			out := cmd.OutOrStdout()
+
+			if output == outputJSON {
+				s, err := prettyjson.Marshal(plan)
+				if err != nil {
+					return fmt.Errorf("Unable to output plan as JSON: %s", err)
+				}
+
+				fmt.Fprintln(out, string(s))
+				return nil
+			}
+
+			fmt.Fprintln(out)
+			fmt.Fprintf(out, "%-13s %-60s %s\n", "ACTION", "RESOURCE", "REASON")
+
+			for _, i := range plan.Resources {
+				action := ""
+				switch i.Action {
+				case shipyard.PlanActionCreate:
+					action = fmt.Sprintf(Green, "[ CREATE ]   ")
+				case shipyard.PlanActionRefresh:
+					action = fmt.Sprintf(White, "[ REFRESH ]  ")
+				case shipyard.PlanActionUpdate:
+					action = fmt.Sprintf(Yellow, "[ UPDATE ]   ")
+				case shipyard.PlanActionRecreate:
+					action = fmt.Sprintf(Yellow, "[ RECREATE ] ")
+				case shipyard.PlanActionDestroy:
+					action = fmt.Sprintf(Red, "[ DESTROY ]  ")
+				}
+
+				fmt.Fprintf(out, "%-13s %-60s %s\n", action, i.ID, i.Reason)
+			}
+
+			fmt.Fprintln(out)
+			fmt.Fprintf(
+				out,
+				"Create: %d Refresh: %d Update: %d Recreate: %d Destroy: %d\n",
+				plan.Count(shipyard.PlanActionCreate),
+				plan.Count(shipyard.PlanActionRefresh),
+				plan.Count(shipyard.PlanActionUpdate),
+				plan.Count(shipyard.PlanActionRecreate),
+				plan.Count(shipyard.PlanActionDestroy),
+			)
+
+			return nil
+		},
+		SilenceUsage: true,
+	}
+
+	planCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
+	planCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+	planCmd.Flags().StringVarP(&output, "output", "o", outputText, "Format for the plan, text or json. json writes the plan to stdout")
+	planCmd.Flags().BoolVarP(&jsonOutput, "json", "", false, "Output the plan as JSON")
+	planCmd.Flags().MarkDeprecated("json", "use --output json")
+
+	return planCmd
+}
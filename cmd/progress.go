@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/shipyard"
+	"github.com/shipyard-run/hclconfig/types"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// validateOutputFormat returns an error when the format is not supported
+// by the --output flag
+func validateOutputFormat(format string) error {
+	if format != outputText && format != outputJSON {
+		return fmt.Errorf("Output must be one of %s or %s", outputText, outputJSON)
+	}
+
+	return nil
+}
+
+// progress writes the events published by the engine to the output, as
+// human readable lines or newline delimited JSON
+type progress struct {
+	out    io.Writer
+	format string
+
+	mu      sync.Mutex
+	running map[string]time.Time
+
+	unsubscribe func()
+	done        chan struct{}
+}
+
+// watchProgress subscribes to the engine events and writes them to out
+// until Stop is called
+func watchProgress(e shipyard.Engine, out io.Writer, format string) *progress {
+	events, unsubscribe := e.Subscribe()
+
+	p := &progress{
+		out:         out,
+		format:      format,
+		running:     map[string]time.Time{},
+		unsubscribe: unsubscribe,
+		done:        make(chan struct{}),
+	}
+
+	go func() {
+		for ev := range events {
+			p.write(ev)
+		}
+
+		close(p.done)
+	}()
+
+	return p
+}
+
+// Stop ends the subscription, all events published before Stop is called
+// have been written when it returns
+func (p *progress) Stop() {
+	p.unsubscribe()
+	<-p.done
+}
+
+// Running returns the ids of the resources which are being created or
+// destroyed
+func (p *progress) Running() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ids := []string{}
+	for id := range p.running {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+func (p *progress) write(ev shipyard.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if ev.Type == shipyard.EventStarted {
+		p.running[ev.Resource] = ev.Time
+	} else {
+		delete(p.running, ev.Resource)
+	}
+
+	if p.format == outputJSON {
+		d, err := json.Marshal(ev)
+		if err != nil {
+			return
+		}
+
+		fmt.Fprintln(p.out, string(d))
+		return
+	}
+
+	// outputs, variables and modules are not shown in the progress view
+	switch ev.ResourceType {
+	case types.TypeOutput, types.TypeVariable, types.TypeModule:
+		return
+	}
+
+	fmt.Fprintln(p.out, formatEvent(ev))
+}
+
+// formatEvent returns a human readable line for the event
+// [ CREATED    ]  resource.network.cloud (1.2s)
+func formatEvent(ev shipyard.Event) string {
+	status := ""
+	color := Yellow
+
+	switch ev.Type {
+	case shipyard.EventStarted:
+		status = "CREATING"
+		if ev.Operation == shipyard.OperationDestroy {
+			status = "DESTROYING"
+		}
+	case shipyard.EventSucceeded:
+		color = Green
+		status = "CREATED"
+		if ev.Operation == shipyard.OperationDestroy {
+			status = "DESTROYED"
+		}
+	case shipyard.EventFailed:
+		color = Red
+		status = "FAILED"
+	case shipyard.EventSkipped:
+		color = White
+		status = "SKIPPED"
+	}
+
+	line := fmt.Sprintf(color, fmt.Sprintf("[ %-10s ]  ", status)) + ev.Resource
+
+	switch ev.Type {
+	case shipyard.EventSucceeded:
+		line += fmt.Sprintf(" (%s)", ev.Duration.Round(100*time.Millisecond))
+	case shipyard.EventFailed:
+		line += fmt.Sprintf(" (%s): %s", ev.Duration.Round(100*time.Millisecond), ev.Error)
+	case shipyard.EventSkipped:
+		line += fmt.Sprintf(" (%s)", ev.Reason)
+	}
+
+	return line
+}
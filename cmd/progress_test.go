@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/shipyard"
+	"github.com/jumppad-labs/jumppad/pkg/shipyard/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func setupProgress(t *testing.T, format string) (*progress, chan shipyard.Event, *bytes.Buffer) {
+	events := make(chan shipyard.Event)
+
+	e := &mocks.Engine{}
+	e.On("Subscribe").Return((<-chan shipyard.Event)(events), func() { close(events) })
+
+	out := bytes.NewBufferString("")
+
+	return watchProgress(e, out, format), events, out
+}
+
+func TestProgressWritesEventsAsJSONLines(t *testing.T) {
+	p, events, out := setupProgress(t, outputJSON)
+
+	events <- shipyard.Event{Type: shipyard.EventStarted, Operation: shipyard.OperationCreate, Resource: "resource.network.cloud"}
+	events <- shipyard.Event{Type: shipyard.EventSucceeded, Operation: shipyard.OperationCreate, Resource: "resource.network.cloud", Duration: 2 * time.Second}
+	p.Stop()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+
+	ev := map[string]interface{}{}
+	err := json.Unmarshal([]byte(lines[1]), &ev)
+	require.NoError(t, err)
+
+	require.Equal(t, "succeeded", ev["type"])
+	require.Equal(t, "resource.network.cloud", ev["resource"])
+	require.Equal(t, float64(2000), ev["duration_ms"])
+}
+
+func TestProgressWritesTextLines(t *testing.T) {
+	p, events, out := setupProgress(t, outputText)
+
+	events <- shipyard.Event{Type: shipyard.EventFailed, Operation: shipyard.OperationCreate, Resource: "resource.container.consul", ResourceType: "container", Error: "boom"}
+	events <- shipyard.Event{Type: shipyard.EventSucceeded, Operation: shipyard.OperationCreate, Resource: "output.address", ResourceType: "output"}
+	p.Stop()
+
+	require.Contains(t, out.String(), "FAILED")
+	require.Contains(t, out.String(), "resource.container.consul")
+	require.Contains(t, out.String(), "boom")
+
+	// outputs are not shown
+	require.NotContains(t, out.String(), "output.address")
+}
+
+func TestProgressTracksRunningResources(t *testing.T) {
+	p, events, _ := setupProgress(t, outputText)
+
+	events <- shipyard.Event{Type: shipyard.EventStarted, Resource: "resource.network.cloud"}
+	events <- shipyard.Event{Type: shipyard.EventStarted, Resource: "resource.container.consul"}
+	events <- shipyard.Event{Type: shipyard.EventSucceeded, Resource: "resource.network.cloud"}
+	p.Stop()
+
+	require.Equal(t, []string{"resource.container.consul"}, p.Running())
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	require.NoError(t, validateOutputFormat("text"))
+	require.NoError(t, validateOutputFormat("json"))
+	require.Error(t, validateOutputFormat("yaml"))
+}
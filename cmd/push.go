@@ -1,8 +1,8 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"io/ioutil"
 	"strings"
 
 	"github.com/hashicorp/go-hclog"
@@ -10,7 +10,6 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/config/resources"
 	"github.com/jumppad-labs/jumppad/pkg/providers"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
-	"github.com/shipyard-run/hclconfig"
 	"github.com/spf13/cobra"
 	"golang.org/x/xerrors"
 )
@@ -46,17 +45,11 @@ func newPushCmd(ct clients.ContainerTasks, kc clients.Kubernetes, ht clients.HTT
 			}
 
 			// find the cluster in the state
-			p := hclconfig.NewParser(hclconfig.DefaultOptions())
-			d, err := ioutil.ReadFile(utils.StatePath())
+			cfg, err := resources.LoadState()
 			if err != nil {
 				return fmt.Errorf("Unable to read state file")
 			}
 
-			cfg, err := p.UnmarshalJSON(d)
-			if err != nil {
-				return fmt.Errorf("Unable to unmarshal state file")
-			}
-
 			r, err := cfg.FindResource(cluster)
 			if err != nil {
 				return xerrors.Errorf("Cluster %s is not running", cluster)
@@ -89,7 +82,7 @@ func pushK8sCluster(image string, c *resources.K8sCluster, ct clients.ContainerT
 
 	for _, id := range ids {
 		log.Info("Pushing to container", "id", id, "image", image)
-		err = cl.ImportLocalDockerImages(utils.ImageVolumeName, id, []resources.Image{resources.Image{Name: strings.Trim(image, " ")}}, force)
+		err = cl.ImportLocalDockerImages(context.Background(), utils.ImageVolumeName, id, []resources.Image{resources.Image{Name: strings.Trim(image, " ")}}, force)
 		if err != nil {
 			return xerrors.Errorf("Error pushing image: %w ", err)
 		}
@@ -109,7 +102,7 @@ func pushNomadCluster(image string, c *resources.NomadCluster, ct clients.Contai
 
 	for _, id := range ids {
 		log.Info("Pushing to container", "id", id, "image", image)
-		err = cl.ImportLocalDockerImages(utils.ImageVolumeName, id, []resources.Image{resources.Image{Name: strings.Trim(image, " ")}}, force)
+		err = cl.ImportLocalDockerImages(context.Background(), utils.ImageVolumeName, id, []resources.Image{resources.Image{Name: strings.Trim(image, " ")}}, force)
 		if err != nil {
 			return xerrors.Errorf("Error pushing image: %w ", err)
 		}
@@ -22,7 +22,7 @@ func setupPush(state string) (*cobra.Command, *clients.MockContainerTasks, func(
 
 	mk := &clients.MockKubernetes{}
 	mh := &mocks.MockHTTP{}
-	mn := &mocks.MockNomad{}
+	mn := &clients.MockNomad{}
 
 	return newPushCmd(mt, mk, mh, mn, hclog.NewNullLogger()), mt, setupState(state)
 }
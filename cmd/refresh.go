@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jumppad-labs/jumppad/pkg/shipyard"
+	"github.com/spf13/cobra"
+)
+
+func newRefreshCmd(e shipyard.Engine) *cobra.Command {
+	return &cobra.Command{
+		Use:   "refresh",
+		Short: "Check the resources in the state for drift",
+		Long: `Check the resources in the state for drift
+
+Containers, networks, volumes, certificates, copied files and daemon processes
+are checked to ensure that they still exist and match the state. Resources which
+have drifted are marked as tainted and are re-created on the next 'jumppad up',
+no other changes are made.`,
+		Example: `jumppad refresh`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			drift, err := e.Refresh()
+
+			if len(drift) > 0 {
+				cmd.Println()
+				cmd.Printf("%-13s %-50s %s\n", "STATUS", "RESOURCE", "REASON")
+
+				for _, d := range drift {
+					cmd.Printf("%-13s %-50s %s\n", fmt.Sprintf(Yellow, "[ DRIFTED ]  "), d.Resource.Metadata().ID, d.Reason)
+				}
+
+				cmd.Println()
+				cmd.Printf("%d resource(s) have drifted and will be re-created on the next 'jumppad up'\n", len(drift))
+			}
+
+			if err != nil {
+				return fmt.Errorf("Unable to refresh resources: %s", err)
+			}
+
+			if len(drift) == 0 {
+				cmd.Println("No drift detected")
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+	}
+}
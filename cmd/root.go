@@ -8,6 +8,7 @@ import (
 	gvm "github.com/shipyard-run/version-manager"
 
 	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
 	"github.com/jumppad-labs/jumppad/pkg/shipyard"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 
@@ -15,6 +16,8 @@ import (
 )
 
 var configFile = ""
+var stateAddress = ""
+var workspaceName = ""
 
 var rootCmd = &cobra.Command{
 	Use:   "jumppad",
@@ -43,15 +46,42 @@ func init() {
 
 	//rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "config file (default is $HOME/.shipyard/config)")
 
+	rootCmd.PersistentFlags().StringVar(&stateAddress, "state", "", fmt.Sprintf("Location of the state, local, an http(s) url or dir:<path>?env=<name>, can also be set with the %s environment variable", resources.StateEnvName))
+	rootCmd.PersistentFlags().StringVar(&workspaceName, "workspace", "", fmt.Sprintf("Workspace to use instead of the selected workspace, can also be set with the %s environment variable", utils.WorkspaceEnvName))
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		err := configureWorkspace(workspaceName)
+		if err != nil {
+			return err
+		}
+
+		return configureStateBackend(stateAddress)
+	}
+
 	rootCmd.AddCommand(checkCmd)
 	rootCmd.AddCommand(outputCmd)
 	rootCmd.AddCommand(newEnvCmd(engine))
+	rootCmd.AddCommand(newPlanCmd(engine, engineClients.Getter))
 	rootCmd.AddCommand(newRunCmd(engine, engineClients.Getter, engineClients.HTTP, engineClients.Browser, vm, engineClients.Connector, logger))
 	rootCmd.AddCommand(newTestCmd(engine, engineClients.Getter, engineClients.HTTP, engineClients.Browser, logger))
 	rootCmd.AddCommand(newDestroyCmd(engineClients.Connector))
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(newPurgeCmd(engineClients.Docker, engineClients.ImageLog, logger))
+	rootCmd.AddCommand(newImportCmd(engine))
+	rootCmd.AddCommand(newRefreshCmd(engine))
 	rootCmd.AddCommand(taintCmd)
+	rootCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateListCmd)
+	stateCmd.AddCommand(stateShowCmd)
+	stateCmd.AddCommand(stateRmCmd)
+	stateCmd.AddCommand(stateMvCmd)
+	stateCmd.AddCommand(stateUnlockCmd)
+	stateCmd.AddCommand(stateHistoryCmd)
+	stateCmd.AddCommand(newStateRollbackCmd(engine))
+	rootCmd.AddCommand(workspaceCmd)
+	workspaceCmd.AddCommand(workspaceNewCmd)
+	workspaceCmd.AddCommand(workspaceSelectCmd)
+	workspaceCmd.AddCommand(workspaceListCmd)
+	workspaceCmd.AddCommand(newWorkspaceDeleteCmd())
 	rootCmd.AddCommand(newVersionCmd(vm))
 	rootCmd.AddCommand(uninstallCmd)
 	rootCmd.AddCommand(newPushCmd(engineClients.ContainerTasks, engineClients.Kubernetes, engineClients.HTTP, engineClients.Nomad, logger))
@@ -64,6 +94,23 @@ func init() {
 	connectorCmd.AddCommand(newConnectorCertCmd())
 }
 
+// configureStateBackend sets the backend used to store the state, when the
+// address is empty the environment variable is used
+func configureStateBackend(address string) error {
+	if address == "" {
+		address = os.Getenv(resources.StateEnvName)
+	}
+
+	b, err := resources.NewStateBackend(address)
+	if err != nil {
+		return err
+	}
+
+	resources.SetStateBackend(b)
+
+	return nil
+}
+
 func createEngine(l hclog.Logger) (shipyard.Engine, gvm.Versions) {
 	engine, err := shipyard.New(l)
 	if err != nil {
@@ -129,10 +176,17 @@ func Execute(v, c, d string) error {
 
 	err := rootCmd.Execute()
 
+	// stop any plugins started by the command
+	if engine != nil {
+		engine.Close()
+	}
+
+	// errors are written to stderr so that they do not interfere with the
+	// json output of commands
 	if err != nil {
-		fmt.Println("")
-		fmt.Println(err)
-		fmt.Println(discordHelp)
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, discordHelp)
 	}
 
 	return err
@@ -59,14 +59,14 @@ func setupServerRunTests(t *testing.T) string {
 	oh := os.Getenv(utils.HomeEnvName())
 	os.Setenv(utils.HomeEnvName(), tmpDir)
 
-	setupGenerateCerts(t, utils.CertsDir(""))
+	setupGenerateCerts(t, utils.ConnectorCertsDir())
 
 	t.Cleanup(func() {
 		os.Setenv(utils.HomeEnvName(), oh)
 		os.RemoveAll(tmpDir)
 	})
 
-	return utils.CertsDir("")
+	return utils.ConnectorCertsDir()
 }
 
 func TestServerStarts(t *testing.T) {
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// newInterruptContext returns a context which is cancelled when the user
+// presses Ctrl-C. The first interrupt cancels the context allowing the
+// in-flight resources to complete or roll back, a second interrupt exits
+// immediately.
+func newInterruptContext(l hclog.Logger) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, os.Interrupt)
+
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-sigs:
+		case <-done:
+			return
+		}
+
+		l.Warn("Interrupt received, waiting for running operations to complete, press Ctrl-C again to force quit")
+		cancel()
+
+		select {
+		case <-sigs:
+		case <-done:
+			return
+		}
+
+		l.Error("Interrupt received, forcing exit, resources may be left in an inconsistent state")
+		os.Exit(1)
+	}()
+
+	return ctx, func() {
+		signal.Stop(sigs)
+		close(done)
+		cancel()
+	}
+}
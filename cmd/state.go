@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hokaccha/go-prettyjson"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/shipyard"
+	"github.com/jumppad-labs/jumppad/pkg/shipyard/constants"
+	"github.com/shipyard-run/hclconfig"
+	"github.com/shipyard-run/hclconfig/types"
+	"github.com/spf13/cobra"
+)
+
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Manage the state for the current resources",
+	Long:  `Manage the state for the current resources`,
+}
+
+var stateUnlockCmd = &cobra.Command{
+	Use:   "unlock",
+	Short: "Remove the lock on the state",
+	Long: `Remove the lock on the state
+
+The state is locked while commands which modify the state are running to
+prevent concurrent modification. If a process is terminated before it can release
+the lock, this command can be used to remove it.
+
+Only remove the lock if the process which holds it is no longer running.`,
+	Example: `jumppad state unlock`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l, err := resources.ReadStateLock()
+		if errors.Is(err, os.ErrNotExist) {
+			cmd.Println("State is not locked")
+			return nil
+		}
+
+		// remove the lock even if it can not be read as it may be corrupt
+		if err == nil {
+			cmd.Printf(
+				"Removing lock held by process %d on host %s since %s\n",
+				l.PID,
+				l.Hostname,
+				l.Created.Format(time.RFC3339),
+			)
+		}
+
+		err = resources.UnlockState()
+		if err != nil {
+			return fmt.Errorf("Unable to remove state lock: %s", err)
+		}
+
+		return nil
+	},
+	SilenceUsage: true,
+}
+
+var stateHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List the previous versions of the state",
+	Long: `List the previous versions of the state
+
+A copy of the state is kept every time resources are created or destroyed. Previous versions can be restored with 'jumppad state rollback'.`,
+	Example: `jumppad state history`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		versions, err := resources.StateHistory()
+		if err != nil {
+			return fmt.Errorf("Unable to read state history: %s", err)
+		}
+
+		if len(versions) == 0 {
+			cmd.Println("No previous versions of the state")
+			return nil
+		}
+
+		cmd.Println()
+		cmd.Printf("%-10s %-27s %s\n", "VERSION", "CREATED", "RESOURCES")
+
+		for _, v := range versions {
+			count := "unknown"
+			c, err := resources.LoadStateVersion(v.Version)
+			if err == nil {
+				count = strconv.Itoa(c.ResourceCount())
+			}
+
+			cmd.Printf("%-10d %-27s %s\n", v.Version, v.Created.Format(time.RFC3339), count)
+		}
+
+		cmd.Println()
+
+		return nil
+	},
+	SilenceUsage: true,
+}
+
+func newStateRollbackCmd(e shipyard.Engine) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rollback [version]",
+		Short: "Restore the resources recorded in a previous version of the state",
+		Long: `Restore the resources recorded in a previous version of the state
+
+Resources which do not exist in the previous version are destroyed, all other
+resources are created or updated to match the configuration in the previous
+version. Use 'jumppad state history' to list the available versions.`,
+		Example: `jumppad state rollback 3`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("Version must be a number: %s", args[0])
+			}
+
+			ctx, cancel := newInterruptContext(hclog.Default())
+			defer cancel()
+
+			_, err = e.Rollback(ctx, v)
+			if err != nil {
+				return fmt.Errorf("Unable to rollback to version %d: %s", v, err)
+			}
+
+			cmd.Printf("Rolled back to version %d\n", v)
+
+			return nil
+		},
+		SilenceUsage: true,
+	}
+}
+
+var stateListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the resources in the state",
+	Long:    `List the resources in the state`,
+	Example: `jumppad state list`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := resources.LoadState()
+		if err != nil {
+			return fmt.Errorf("Unable to load statefile, do you have a running blueprint?")
+		}
+
+		cmd.Println()
+		cmd.Printf("%-13s %s\n", "STATUS", "RESOURCE")
+
+		for _, r := range cfg.Resources {
+			if r.Metadata().Type == types.TypeModule {
+				continue
+			}
+
+			status, _ := r.Metadata().Properties[constants.PropertyStatus].(string)
+			if r.Metadata().Disabled {
+				status = constants.StatusDisabled
+			}
+
+			cmd.Printf("%-13s %s\n", status, r.Metadata().ID)
+		}
+
+		cmd.Println()
+
+		return nil
+	},
+	SilenceUsage: true,
+}
+
+var stateShowCmd = &cobra.Command{
+	Use:   "show [resource]",
+	Short: "Show all the attributes for a resource in the state",
+	Long: `Show all the attributes for a resource in the state
+
+The output includes the attributes that are computed when the resource is
+created.`,
+	Example: `jumppad state show resource.container.consul`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := resources.LoadState()
+		if err != nil {
+			return fmt.Errorf("Unable to load statefile, do you have a running blueprint?")
+		}
+
+		r, err := cfg.FindResource(args[0])
+		if err != nil {
+			return fmt.Errorf("Unable to locate resource in the state %s", args[0])
+		}
+
+		d, err := prettyjson.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("Unable to output resource as JSON: %s", err)
+		}
+
+		cmd.Println(string(d))
+
+		return nil
+	},
+	SilenceUsage: true,
+}
+
+var stateRmCmd = &cobra.Command{
+	Use:   "rm [resource]",
+	Short: "Remove a resource from the state without destroying it",
+	Long: `Remove a resource from the state without destroying it
+
+jumppad will no longer manage the resource, it will not be destroyed by
+jumppad down and will be created again by the next jumppad up.`,
+	Example: `jumppad state rm resource.container.consul`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateState(func(cfg *hclconfig.Config) error {
+			r, err := cfg.FindResource(args[0])
+			if err != nil {
+				return fmt.Errorf("Unable to locate resource in the state %s", args[0])
+			}
+
+			err = cfg.RemoveResource(r)
+			if err != nil {
+				return fmt.Errorf("Unable to remove resource from the state: %s", err)
+			}
+
+			cmd.Printf("Removed %s\n", args[0])
+
+			return nil
+		})
+	},
+	SilenceUsage: true,
+}
+
+var stateMvCmd = &cobra.Command{
+	Use:   "mv [resource] [new resource]",
+	Short: "Rename a resource in the state without re-creating it",
+	Long: `Rename a resource in the state without re-creating it
+
+Use this command after renaming a resource in the configuration to prevent
+the next jumppad up from creating a new resource.`,
+	Example: `jumppad state mv resource.container.consul resource.container.consul_server`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateState(func(cfg *hclconfig.Config) error {
+			err := resources.MoveResource(cfg, args[0], args[1])
+			if err != nil {
+				return fmt.Errorf("Unable to move resource: %s", err)
+			}
+
+			cmd.Printf("Moved %s to %s\n", args[0], args[1])
+
+			return nil
+		})
+	},
+	SilenceUsage: true,
+}
+
+// updateState locks and loads the state, calls the given function to modify
+// the state, and saves the result
+func updateState(f func(cfg *hclconfig.Config) error) error {
+	unlock, err := resources.LockState()
+	if err != nil {
+		return fmt.Errorf("Unable to lock state: %s", err)
+	}
+	defer unlock()
+
+	cfg, err := resources.LoadState()
+	if err != nil {
+		return fmt.Errorf("Unable to load statefile, do you have a running blueprint?")
+	}
+
+	err = f(cfg)
+	if err != nil {
+		return err
+	}
+
+	err = resources.SaveState(cfg)
+	if err != nil {
+		return fmt.Errorf("Unable to save state: %s", err)
+	}
+
+	return nil
+}
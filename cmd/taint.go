@@ -2,12 +2,10 @@ package cmd
 
 import (
 	"fmt"
-	"io/ioutil"
 	"os"
 
 	"github.com/jumppad-labs/jumppad/pkg/config/resources"
 	"github.com/jumppad-labs/jumppad/pkg/shipyard/constants"
-	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -25,26 +23,34 @@ var taintCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		unlock, err := resources.LockState()
+		if err != nil {
+			fmt.Println("Unable to lock state", err)
+			os.Exit(1)
+		}
+		defer unlock()
+
 		cfg, err := resources.LoadState()
 		if err != nil {
 			fmt.Println("Unable to load statefile, do you have a running blueprint?")
+			unlock()
 			os.Exit(1)
 		}
 
 		r, err := cfg.FindResource(args[0])
 		if err != nil || r == nil {
 			fmt.Println("Unable to locate resource in the state", args[0])
+			unlock()
 			os.Exit(1)
 		}
 
 		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusTainted
 
-		d, err := cfg.ToJSON()
+		err = resources.SaveState(cfg)
 		if err != nil {
 			fmt.Println("Unable to save state", err)
+			unlock()
 			os.Exit(1)
 		}
-
-		ioutil.WriteFile(utils.StatePath(), d, os.ModePerm)
 	},
 }
@@ -261,6 +261,7 @@ func (cr *CucumberRunner) iRunApplyAtPathWithVersion(fp, version string) error {
 
 	noOpen := true
 	approve := true
+	format := outputText
 
 	// re-use the run command
 	rc := newRunCmdFunc(
@@ -276,6 +277,7 @@ func (cr *CucumberRunner) iRunApplyAtPathWithVersion(fp, version string) error {
 		&approve,
 		&cr.variables,
 		&cr.variablesFile,
+		&format,
 		cr.l,
 	)
 
@@ -312,7 +314,7 @@ func getLookupAddress(resourceName string) (string, string, int, error) {
 
 	switch res.Metadata().Type {
 	case resources.TypeNetwork:
-		return res.Metadata().Name, res.Metadata().Type, 1, nil
+		return utils.FQDNNetworkName(res.Metadata().Name), res.Metadata().Type, 1, nil
 	case resources.TypeK8sCluster:
 		return res.(*resources.K8sCluster).FQRN, res.Metadata().Type, 1, nil
 	case resources.TypeNomadCluster:
@@ -16,6 +16,7 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources"
 	"github.com/jumppad-labs/jumppad/pkg/shipyard"
+	"github.com/jumppad-labs/jumppad/pkg/shipyard/constants"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/spf13/cobra"
 
@@ -29,6 +30,12 @@ func newRunCmd(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc clients
 	var runVersion string
 	var variables []string
 	var variablesFile string
+	var parallelism int
+	var targets []string
+	var output string
+	var forceDestroy bool
+
+	runFunc := newRunCmdFunc(e, bp, hc, bc, vm, cc, &noOpen, &force, &runVersion, &y, &variables, &variablesFile, &output, l)
 
 	runCmd := &cobra.Command{
 		Use:   "up [file] | [directory]",
@@ -43,9 +50,31 @@ func newRunCmd(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc clients
 
   # Create resources from a blueprint in GitHub
   jumppad up github.com/jumppad-labs/blueprints/kubernetes-vault
+
+  # Create a single resource and the resources it depends on
+  jumppad up --target resource.container.api ./
+
+  # Write the progress of each resource as newline delimited JSON
+  jumppad up --output json ./
 	`,
-		Args:         cobra.ArbitraryArgs,
-		RunE:         newRunCmdFunc(e, bp, hc, bc, vm, cc, &noOpen, &force, &runVersion, &y, &variables, &variablesFile, l),
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if parallelism < 0 {
+				return fmt.Errorf("Parallelism must be greater than or equal to 0")
+			}
+
+			if err := validateOutputFormat(output); err != nil {
+				return err
+			}
+
+			o := shipyard.DefaultOptions()
+			o.Parallelism = parallelism
+			o.Targets = targets
+			o.Force = forceDestroy
+			e.SetOptions(o)
+
+			return runFunc(cmd, args)
+		},
 		SilenceUsage: true,
 	}
 
@@ -53,11 +82,15 @@ func newRunCmd(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc clients
 	runCmd.Flags().BoolVarP(&force, "force-update", "", false, "When set to true Jumppad ignores cached images or files and will download all resources")
 	runCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
 	runCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+	runCmd.Flags().IntVarP(&parallelism, "parallelism", "", shipyard.DefaultOptions().Parallelism, "Limit the number of resources which are created concurrently, 0 does not limit concurrency")
+	runCmd.Flags().StringSliceVarP(&targets, "target", "", nil, "Only create the resource with the given id and the resources it depends on, e.g --target resource.container.api. Can be specified multiple times")
+	runCmd.Flags().BoolVarP(&forceDestroy, "force", "", false, "Re-create changed resources which have lifecycle prevent_destroy set")
+	runCmd.Flags().StringVarP(&output, "output", "o", outputText, "Format for the progress of each resource, text or json. json writes an event per line to stdout")
 
 	return runCmd
 }
 
-func newRunCmdFunc(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc clients.System, vm gvm.Versions, cc clients.Connector, noOpen *bool, force *bool, runVersion *string, autoApprove *bool, variables *[]string, variablesFile *string, l hclog.Logger) func(cmd *cobra.Command, args []string) error {
+func newRunCmdFunc(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc clients.System, vm gvm.Versions, cc clients.Connector, noOpen *bool, force *bool, runVersion *string, autoApprove *bool, variables *[]string, variablesFile *string, output *string, l hclog.Logger) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		// create the shipyard and sub folders in the users home directory
 		utils.CreateFolders()
@@ -96,18 +129,28 @@ func newRunCmdFunc(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc cli
 		}
 
 		// create the certificates for the connector
-		if cb, err := cc.GetLocalCertBundle(utils.CertsDir("")); err != nil || cb == nil {
+		if cb, err := cc.GetLocalCertBundle(utils.ConnectorCertsDir()); err != nil || cb == nil {
 			// generate certs
-			l.Debug("Generating TLS Certificates for Ingress", "path", utils.CertsDir(""))
-			_, err := cc.GenerateLocalCertBundle(utils.CertsDir(""))
+			l.Debug("Generating TLS Certificates for Ingress", "path", utils.ConnectorCertsDir())
+			_, err := cc.GenerateLocalCertBundle(utils.ConnectorCertsDir())
 			if err != nil {
 				return fmt.Errorf("Unable to generate connector certificates: %s", err)
 			}
+
+			// a running connector is still using the previous certificates
+			if cc.IsRunning() {
+				l.Debug("Restarting API server with the new certificates")
+
+				err = cc.Stop()
+				if err != nil {
+					return fmt.Errorf("Unable to stop API server: %s", err)
+				}
+			}
 		}
 
 		// start the connector
 		if !cc.IsRunning() {
-			cb, err := cc.GetLocalCertBundle(utils.CertsDir(""))
+			cb, err := cc.GetLocalCertBundle(utils.ConnectorCertsDir())
 			if err != nil {
 				return fmt.Errorf("Unable to get certificates to secure ingress: %s", err)
 			}
@@ -152,12 +195,27 @@ func newRunCmdFunc(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc cli
 			return err
 		}
 
+		// check the blueprint can be run with this version of jumppad
+		if bp := e.Blueprint(); bp != nil {
+			err := bp.CheckVersion(version)
+			if err != nil {
+				return err
+			}
+		}
+
 		// have we already got a blueprint in the state
 		blueprintExists := false
 		if bluePrintInState() {
 			blueprintExists = true
 		}
 
+		// browser windows are only opened for resources which are created
+		// by this run
+		existing := createdResourcesInState()
+
+		// show the progress of each resource as it is created
+		progress := watchProgress(e, cmd.OutOrStdout(), *output)
+
 		// update status every 30s to let people know we are still running
 		statusUpdate := time.NewTicker(15 * time.Second)
 		startTime := time.Now()
@@ -165,12 +223,20 @@ func newRunCmdFunc(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc cli
 		go func() {
 			for range statusUpdate.C {
 				elapsedTime := time.Since(startTime).Seconds()
-				logger.Info(fmt.Sprintf("Please wait, still creating resources [Elapsed Time: %f]", elapsedTime))
+				logger.Info(fmt.Sprintf("Please wait, still creating resources [Elapsed Time: %f]", elapsedTime), "running", strings.Join(progress.Running(), ", "))
 			}
 		}()
 
-		res, err := e.ApplyWithVariables(dst, vars, *variablesFile)
+		// cancel the apply when the user presses Ctrl-C, resources which are
+		// being created are completed or rolled back and the state is saved
+		ctx, cancel := newInterruptContext(l)
+		defer cancel()
+
+		res, err := e.ApplyWithVariables(ctx, dst, vars, *variablesFile)
+		progress.Stop()
+
 		if err != nil {
+			statusUpdate.Stop()
 			return err
 		}
 
@@ -193,6 +259,10 @@ func newRunCmdFunc(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc cli
 			}
 
 			for _, r := range res {
+				if existing[r.Metadata().ID] {
+					continue
+				}
+
 				switch r.Metadata().Type {
 				case resources.TypeContainer:
 					c := r.(*resources.Container)
@@ -233,7 +303,7 @@ func newRunCmdFunc(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc cli
 			for _, b := range browserList {
 				go func(uri string) {
 					// health check the URL
-					err := hc.HealthCheckHTTP(uri, []int{200}, checkDuration)
+					err := hc.HealthCheckHTTP(ctx, uri, []int{200}, checkDuration)
 					if err == nil {
 						be := bc.OpenBrowser(uri)
 						if be != nil {
@@ -304,11 +374,43 @@ func buildBrowserPath(n, p string, resourceType string, path string) string {
 	return fmt.Sprintf("http://%s:%s.%s", utils.FQDN(n, "", ty), p, path)
 }
 
+// bluePrintInState returns true when the state contains a blueprint which
+// has been created by a previous run
 func bluePrintInState() bool {
-	//load the state
-	//sc := config.New()
-	//sc.FromJSON(utils.StatePath())
+	c, err := resources.LoadState()
+	if err != nil {
+		return false
+	}
+
+	bps, err := c.FindResourcesByType(resources.TypeBlueprint)
+	if err != nil {
+		return false
+	}
+
+	for _, bp := range bps {
+		if bp.Metadata().Module == "" && bp.Metadata().Properties[constants.PropertyStatus] == constants.StatusCreated {
+			return true
+		}
+	}
 
-	//return sc.Blueprint != nil
 	return false
 }
+
+// createdResourcesInState returns the ids of the resources which have been
+// created by a previous run
+func createdResourcesInState() map[string]bool {
+	created := map[string]bool{}
+
+	c, err := resources.LoadState()
+	if err != nil {
+		return created
+	}
+
+	for _, r := range c.Resources {
+		if r.Metadata().Properties[constants.PropertyStatus] == constants.StatusCreated {
+			created[r.Metadata().ID] = true
+		}
+	}
+
+	return created
+}
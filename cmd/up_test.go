@@ -77,9 +77,13 @@ func setupRun(t *testing.T, timeout string) (*cobra.Command, *runMocks) {
 
 	mockEngine := &mocks.Engine{}
 	mockEngine.On("ParseConfigWithVariables", mock.Anything, mock.Anything, mock.Anything).Return(nil)
-	mockEngine.On("ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	mockEngine.On("ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 	mockEngine.On("GetClients", mock.Anything).Return(clients)
 	mockEngine.On("ResourceCountForType", mock.Anything).Return(0)
+	mockEngine.On("SetOptions", mock.Anything)
+
+	events := make(chan shipyard.Event)
+	mockEngine.On("Subscribe").Return((<-chan shipyard.Event)(events), func() { close(events) })
 
 	bp := config.Blueprint{BrowserWindows: []string{"http://localhost", "http://localhost2"}}
 
@@ -232,7 +236,7 @@ func TestRunSetsDestinationFromArgsWhenPresent(t *testing.T) {
 	err := rf.Execute()
 	assert.NoError(t, err)
 
-	rm.engine.AssertCalled(t, "ApplyWithVariables", "/tmp", mock.Anything, mock.Anything)
+	rm.engine.AssertCalled(t, "ApplyWithVariables", mock.Anything, "/tmp", mock.Anything, mock.Anything)
 }
 
 func TestRunSetsVariablesFileReturnsErrorWhenMissing(t *testing.T) {
@@ -257,7 +261,7 @@ func TestRunSetsVariablesFileWhenPresent(t *testing.T) {
 	err = rf.Execute()
 	assert.NoError(t, err)
 
-	rm.engine.AssertCalled(t, "ApplyWithVariables", "/tmp", mock.Anything, tmpFile.Name())
+	rm.engine.AssertCalled(t, "ApplyWithVariables", mock.Anything, "/tmp", mock.Anything, tmpFile.Name())
 }
 
 func TestRunSetsDestinationToDownloadedBlueprintFromArgsWhenRemote(t *testing.T) {
@@ -267,7 +271,7 @@ func TestRunSetsDestinationToDownloadedBlueprintFromArgsWhenRemote(t *testing.T)
 	err := rf.Execute()
 	assert.NoError(t, err)
 
-	rm.engine.AssertCalled(t, "ApplyWithVariables", filepath.Join(utils.JumppadHome(), "blueprints/github.com/shipyard-run/blueprints/vault-k8s"), mock.Anything, mock.Anything)
+	rm.engine.AssertCalled(t, "ApplyWithVariables", mock.Anything, filepath.Join(utils.JumppadHome(), "blueprints/github.com/shipyard-run/blueprints/vault-k8s"), mock.Anything, mock.Anything)
 }
 
 func TestRunFetchesBlueprint(t *testing.T) {
@@ -372,7 +376,7 @@ func TestRunOpensBrowserWindowForResources(t *testing.T) {
 	n1.OpenInBrowser = true
 	nomadConfig, _ := utils.GetClusterConfig("nomad_cluster.test")
 
-	rm.engine.On("ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything).Return(
+	rm.engine.On("ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
 		[]config.Resource{d, i, c, d2, i2, c2, n1},
 		nil,
 	)
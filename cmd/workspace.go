@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Manage workspaces",
+	Long: `Manage workspaces
+
+Each workspace has its own state and its own names for Docker containers,
+volumes and networks, allowing several blueprints to run side by side.
+Resources in a workspace other than the default include the workspace in
+their name, e.g. consul.container.demo.jumppad.dev or cloud.demo.jumppad.dev.
+
+Docker does not allow networks with overlapping subnets, blueprints running
+in different workspaces must still use different subnets.
+
+The workspace used by a command can be set with the --workspace flag or the
+JUMPPAD_WORKSPACE environment variable, otherwise the selected workspace is used.`,
+}
+
+var workspaceNewCmd = &cobra.Command{
+	Use:     "new [name]",
+	Short:   "Create a new workspace and select it",
+	Long:    `Create a new workspace and select it`,
+	Example: `jumppad workspace new demo`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := utils.CreateWorkspace(args[0])
+		if err != nil {
+			return fmt.Errorf("Unable to create workspace %s: %s", args[0], err)
+		}
+
+		err = utils.SelectWorkspace(args[0])
+		if err != nil {
+			return fmt.Errorf("Unable to select workspace %s: %s", args[0], err)
+		}
+
+		cmd.Printf("Created and selected workspace %s\n", args[0])
+
+		return nil
+	},
+	SilenceUsage: true,
+}
+
+var workspaceSelectCmd = &cobra.Command{
+	Use:     "select [name]",
+	Short:   "Select the workspace used by commands",
+	Long:    `Select the workspace used by commands when the --workspace flag is not set`,
+	Example: `jumppad workspace select demo`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := utils.SelectWorkspace(args[0])
+		if err != nil {
+			return fmt.Errorf("Unable to select workspace %s: %s", args[0], err)
+		}
+
+		cmd.Printf("Selected workspace %s\n", args[0])
+
+		return nil
+	},
+	SilenceUsage: true,
+}
+
+var workspaceListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the workspaces",
+	Long:    `List the workspaces, the current workspace is marked with *`,
+	Example: `jumppad workspace list`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ws, err := utils.ListWorkspaces()
+		if err != nil {
+			return fmt.Errorf("Unable to list workspaces: %s", err)
+		}
+
+		for _, w := range ws {
+			marker := " "
+			if w == utils.Workspace() {
+				marker = "*"
+			}
+
+			cmd.Printf("%s %s\n", marker, w)
+		}
+
+		return nil
+	},
+	SilenceUsage: true,
+}
+
+func newWorkspaceDeleteCmd() *cobra.Command {
+	var force bool
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete [name]",
+		Short: "Delete a workspace",
+		Long: `Delete a workspace and its state
+
+A workspace which contains resources can not be deleted, run 'jumppad down'
+in the workspace first. Deleting the selected workspace selects the default
+workspace.`,
+		Example: `jumppad workspace delete demo`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			_, err := os.Stat(utils.WorkspaceStatePath(name))
+			if err == nil && name != utils.DefaultWorkspace {
+				if !force {
+					return fmt.Errorf("Workspace %s contains resources, run 'jumppad down --workspace %s' before deleting it or use --force", name, name)
+				}
+
+				cmd.Printf("Deleting workspace %s with resources, Docker objects created in the workspace will not be removed\n", name)
+			}
+
+			err = utils.DeleteWorkspace(name)
+			if err != nil {
+				return fmt.Errorf("Unable to delete workspace %s: %s", name, err)
+			}
+
+			cmd.Printf("Deleted workspace %s\n", name)
+
+			return nil
+		},
+		SilenceUsage: true,
+	}
+
+	deleteCmd.Flags().BoolVarP(&force, "force", "", false, "Delete the workspace even when it contains resources")
+
+	return deleteCmd
+}
+
+// configureWorkspace sets the workspace used by the command, when name is
+// empty the environment variable or the selected workspace is used
+func configureWorkspace(name string) error {
+	if name == "" {
+		name = os.Getenv(utils.WorkspaceEnvName)
+	}
+
+	if name == "" {
+		name = utils.SelectedWorkspace()
+	}
+
+	err := utils.ValidateWorkspaceName(name)
+	if err != nil {
+		return fmt.Errorf("Invalid workspace %s: %s", name, err)
+	}
+
+	if !utils.WorkspaceExists(name) {
+		return fmt.Errorf("Workspace %s does not exist, create it with 'jumppad workspace new %s'", name, name)
+	}
+
+	utils.SetWorkspace(name)
+
+	return nil
+}
@@ -0,0 +1,35 @@
+resource "blueprint" "consul" {
+  title  = "Consul"
+  author = "Jumppad"
+  slug   = "consul"
+
+  intro = <<-EOF
+  # Consul
+
+  A single Consul server running in a Docker container.
+  EOF
+
+  browser_windows = ["http://consul.container.jumppad.dev:8500"]
+
+  env = {
+    CONSUL_HTTP_ADDR = "http://consul.container.jumppad.dev:8500"
+  }
+
+  shipyard_version = ">= 0.5.0"
+}
+
+resource "network" "onprem" {
+  subnet = "10.6.0.0/16"
+}
+
+resource "container" "consul" {
+  image {
+    name = "consul:1.10.1"
+  }
+
+  command = ["consul", "agent", "-dev", "-client", "0.0.0.0"]
+
+  network {
+    id = resource.network.onprem.id
+  }
+}
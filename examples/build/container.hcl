@@ -2,6 +2,10 @@ resource "container" "build" {
   build {
     dockerfile = "Dockerfile"
     context    = "./src"
+
+    args = {
+      GO_VERSION = "1.19"
+    }
   }
 
   command = ["/bin/app"]
@@ -0,0 +1,40 @@
+resource "network" "onprem" {
+  subnet = "10.6.0.0/16"
+
+  lifecycle {
+    before_create {
+      command = ["echo", "creating network"]
+    }
+
+    after_destroy {
+      command    = ["echo", "network destroyed"]
+      on_failure = "continue"
+    }
+  }
+}
+
+resource "container" "consul" {
+  image {
+    name = "consul:1.10.1"
+  }
+
+  command = ["consul", "agent", "-dev", "-client", "0.0.0.0"]
+
+  network {
+    id = resource.network.onprem.id
+  }
+
+  lifecycle {
+    after_create {
+      target  = "resource.container.consul"
+      command = ["consul", "kv", "put", "ready", "true"]
+      timeout = "30s"
+    }
+
+    before_destroy {
+      target     = "resource.container.consul"
+      command    = ["consul", "leave"]
+      on_failure = "continue"
+    }
+  }
+}
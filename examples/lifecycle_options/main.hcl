@@ -0,0 +1,58 @@
+variable "postgres_version" {
+  default = "15.3"
+}
+
+variable "consul_version" {
+  default = "1.10.1"
+}
+
+variable "consul_token" {
+  default = "root"
+}
+
+resource "network" "onprem" {
+  subnet = "10.6.0.0/16"
+}
+
+// the seeded database should never be removed by accident, down and any
+// change which re-creates the container fail unless --force is given
+resource "container" "database" {
+  image {
+    name = "postgres:${variable.postgres_version}"
+  }
+
+  network {
+    id = resource.network.onprem.id
+  }
+
+  environment = {
+    POSTGRES_PASSWORD = "password"
+  }
+
+  lifecycle {
+    prevent_destroy = true
+  }
+}
+
+// the replacement consul server is started before the existing server is
+// removed, changes to the token do not re-create the container
+resource "container" "consul" {
+  image {
+    name = "consul:${variable.consul_version}"
+  }
+
+  command = ["consul", "agent", "-dev", "-client", "0.0.0.0"]
+
+  network {
+    id = resource.network.onprem.id
+  }
+
+  environment = {
+    CONSUL_HTTP_TOKEN = variable.consul_token
+  }
+
+  lifecycle {
+    create_before_destroy = true
+    ignore_changes        = ["environment"]
+  }
+}
@@ -0,0 +1,24 @@
+resource "network" "onprem" {
+  subnet = "10.6.0.0/16"
+}
+
+resource "container" "consul" {
+  image {
+    name = "consul:1.10.1"
+  }
+
+  command = ["consul", "agent", "-dev", "-client", "0.0.0.0"]
+
+  network {
+    id = resource.network.onprem.id
+  }
+
+  // pulling the image can fail when the registry is unavailable
+  retry {
+    attempts    = 3
+    backoff     = "1s"
+    max_backoff = "5s"
+  }
+
+  timeout = "120s"
+}
@@ -0,0 +1,71 @@
+variable "app_version" {
+  default = "1.0.0"
+}
+
+resource "network" "onprem" {
+  subnet = "10.7.0.0/16"
+}
+
+// the health check of the database is re-run before any dependent is
+// created, even when the database container is unchanged
+resource "container" "database" {
+  image {
+    name = "postgres:15.3"
+  }
+
+  network {
+    id = resource.network.onprem.id
+  }
+
+  environment = {
+    POSTGRES_PASSWORD = "password"
+  }
+
+  health_check {
+    timeout = "60s"
+    exec    = ["pg_isready", "-U", "postgres"]
+  }
+
+  lifecycle {
+    wait_for_healthy = true
+  }
+}
+
+resource "container" "app" {
+  depends_on = ["resource.container.database"]
+
+  image {
+    name = "nicholasjackson/fake-service:v0.25.2"
+  }
+
+  network {
+    id = resource.network.onprem.id
+  }
+
+  environment = {
+    VERSION = variable.app_version
+  }
+}
+
+// blocks until the app is serving requests, the worker is not created
+// until the check passes
+resource "wait" "app" {
+  depends_on = ["resource.container.app"]
+
+  health_check {
+    timeout = "30s"
+    http    = "http://app.container.jumppad.dev:9090/health"
+  }
+}
+
+resource "container" "worker" {
+  depends_on = ["resource.wait.app"]
+
+  image {
+    name = "nicholasjackson/fake-service:v0.25.2"
+  }
+
+  network {
+    id = resource.network.onprem.id
+  }
+}
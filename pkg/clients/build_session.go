@@ -0,0 +1,45 @@
+package clients
+
+import (
+	"context"
+	"net"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/secrets/secretsprovider"
+	"golang.org/x/xerrors"
+)
+
+// startBuildSession starts a BuildKit session which provides the given
+// secrets to the builder, the session runs until the context is cancelled.
+// The Docker client can not pass secrets to a build, like the Docker CLI the
+// secrets are served from a session hijacked from the Docker API connection.
+func (d *DockerTasks) startBuildSession(ctx context.Context, secrets map[string]string) (*session.Session, error) {
+	s, err := session.NewSession(ctx, "jumppad", "")
+	if err != nil {
+		return nil, xerrors.Errorf("unable to create build session: %w", err)
+	}
+
+	sources := []secretsprovider.Source{}
+	for id, path := range secrets {
+		sources = append(sources, secretsprovider.Source{ID: id, FilePath: path})
+	}
+
+	store, err := secretsprovider.NewStore(sources)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to read build secrets: %w", err)
+	}
+
+	s.Allow(secretsprovider.NewSecretProvider(store))
+
+	go func() {
+		err := s.Run(ctx, func(ctx context.Context, proto string, meta map[string][]string) (net.Conn, error) {
+			return d.c.DialHijack(ctx, "/session", proto, meta)
+		})
+
+		if err != nil {
+			d.l.Debug("Build session closed with error", "error", err)
+		}
+	}()
+
+	return s, nil
+}
@@ -1,6 +1,9 @@
 package clients
 
-import "github.com/hashicorp/go-hclog"
+import (
+	"github.com/hashicorp/go-hclog"
+	"github.com/jumppad-labs/jumppad/pkg/plugins"
+)
 
 type Clients struct {
 	Docker         Docker
@@ -16,4 +19,5 @@ type Clients struct {
 	ImageLog       ImageLog
 	Connector      Connector
 	TarGz          *TarGz
+	Plugins        *plugins.Manager
 }
@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -24,8 +25,9 @@ type CommandConfig struct {
 }
 
 type Command interface {
-	Execute(config CommandConfig) (int, error)
+	Execute(ctx context.Context, config CommandConfig) (int, error)
 	Kill(pid int) error
+	IsRunning(pid int) bool
 }
 
 // Command executes local commands
@@ -44,8 +46,9 @@ type done struct {
 	err error
 }
 
-// Execute the given command
-func (c *CommandImpl) Execute(config CommandConfig) (int, error) {
+// Execute the given command, when the command is not run in the background
+// the process is stopped once the timeout elapses or the context is cancelled
+func (c *CommandImpl) Execute(ctx context.Context, config CommandConfig) (int, error) {
 	mutex := sync.Mutex{}
 
 	lp := &gohup.LocalProcess{}
@@ -121,6 +124,12 @@ func (c *CommandImpl) Execute(config CommandConfig) (int, error) {
 		lp.Stop(pidfile)
 		mutex.Unlock()
 		return pid, ErrorCommandTimeout
+	case <-ctx.Done():
+		// kill the running process
+		mutex.Lock()
+		lp.Stop(pidfile)
+		mutex.Unlock()
+		return pid, ctx.Err()
 	case d := <-doneCh:
 		return d.pid, d.err
 	}
@@ -137,3 +146,14 @@ func (c *CommandImpl) Kill(pid int) error {
 
 	return nil
 }
+
+// IsRunning returns true when the background process with the given pid is
+// still running
+func (c *CommandImpl) IsRunning(pid int) bool {
+	lp := gohup.LocalProcess{}
+	pidPath := filepath.Join(os.TempDir(), fmt.Sprintf("%d.pid", pid))
+
+	s, _ := lp.QueryStatus(pidPath)
+
+	return s == gohup.StatusRunning
+}
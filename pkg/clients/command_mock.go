@@ -1,6 +1,8 @@
 package clients
 
 import (
+	"context"
+
 	"github.com/stretchr/testify/mock"
 )
 
@@ -10,7 +12,7 @@ type CommandMock struct {
 	mock.Mock
 }
 
-func (m *CommandMock) Execute(config CommandConfig) (int, error) {
+func (m *CommandMock) Execute(ctx context.Context, config CommandConfig) (int, error) {
 	args := m.Called(config)
 
 	return args.Int(0), args.Error(1)
@@ -21,3 +23,9 @@ func (m *CommandMock) Kill(pid int) error {
 
 	return args.Error(0)
 }
+
+func (m *CommandMock) IsRunning(pid int) bool {
+	args := m.Called(pid)
+
+	return args.Bool(0)
+}
@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"context"
 	"runtime"
 	"testing"
 	"time"
@@ -24,7 +25,7 @@ func TestExecuteForgroundWithBasicParams(t *testing.T) {
 
 	e := setupExecute(t)
 
-	p, err := e.Execute(CommandConfig{
+	p, err := e.Execute(context.Background(), CommandConfig{
 		Command: command,
 		Args:    args,
 	})
@@ -44,7 +45,7 @@ func TestExecuteForgroundLongRunningTimesOut(t *testing.T) {
 
 	e := setupExecute(t)
 
-	p, err := e.Execute(CommandConfig{
+	p, err := e.Execute(context.Background(), CommandConfig{
 		Command: command,
 		Args:    args,
 	})
@@ -54,10 +55,33 @@ func TestExecuteForgroundLongRunningTimesOut(t *testing.T) {
 	assert.Greater(t, p, 1)
 }
 
+func TestExecuteForgroundStopsWhenContextCancelled(t *testing.T) {
+	command := "sh"
+	args := []string{"-c", "sleep 10s"}
+
+	if runtime.GOOS == "windows" {
+		command = "cmd.exe"
+		args = []string{"/c", "ping", "192.0.2.1", "-n", "1", "-w", "100000", ">NUL"}
+	}
+
+	e := setupExecute(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	p, err := e.Execute(ctx, CommandConfig{
+		Command: command,
+		Args:    args,
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, e.IsRunning(p))
+}
+
 func TestExecuteInvalidCommandReturnsError(t *testing.T) {
 	e := setupExecute(t)
 
-	_, err := e.Execute(CommandConfig{Command: "nocommand"})
+	_, err := e.Execute(context.Background(), CommandConfig{Command: "nocommand"})
 	assert.Error(t, err)
 }
 
@@ -76,7 +100,7 @@ func TestExecuteBackgroundWithBasicParams(t *testing.T) {
 	doneCh := make(chan done)
 
 	go func() {
-		p, err := e.Execute(CommandConfig{
+		p, err := e.Execute(context.Background(), CommandConfig{
 			Command:         command,
 			Args:            args,
 			RunInBackground: true,
@@ -109,7 +133,7 @@ func TestKillRemovesProcessWhenRunning(t *testing.T) {
 	doneCh := make(chan done)
 
 	go func() {
-		p, err := e.Execute(CommandConfig{
+		p, err := e.Execute(context.Background(), CommandConfig{
 			Command:         command,
 			Args:            args,
 			RunInBackground: true,
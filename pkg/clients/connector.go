@@ -313,7 +313,7 @@ func (c *ConnectorImpl) ExposeService(
 	direction string,
 ) (string, error) {
 
-	dir := utils.CertsDir("")
+	dir := utils.ConnectorCertsDir()
 	cb, err := c.GetLocalCertBundle(dir)
 	if err != nil {
 		return "", fmt.Errorf("Unable to find certificate at location: %s, error: %s", dir, err)
@@ -348,7 +348,7 @@ func (c *ConnectorImpl) ExposeService(
 
 // RemoveService removes a previously exposed service
 func (c *ConnectorImpl) RemoveService(id string) error {
-	cb, err := c.GetLocalCertBundle(utils.CertsDir(""))
+	cb, err := c.GetLocalCertBundle(utils.ConnectorCertsDir())
 	if err != nil {
 		return err
 	}
@@ -371,7 +371,7 @@ func (c *ConnectorImpl) RemoveService(id string) error {
 
 // ListServices lists all active services
 func (c *ConnectorImpl) ListServices() ([]*shipyard.Service, error) {
-	cb, err := c.GetLocalCertBundle(utils.CertsDir(""))
+	cb, err := c.GetLocalCertBundle(utils.ConnectorCertsDir())
 	if err != nil {
 		return nil, err
 	}
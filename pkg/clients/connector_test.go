@@ -49,7 +49,7 @@ func testGenerateCreatesBundle(t *testing.T) {
 	c := NewConnector(suiteOptions)
 
 	var err error
-	suiteCertBundle, err = c.GenerateLocalCertBundle(utils.CertsDir(""))
+	suiteCertBundle, err = c.GenerateLocalCertBundle(utils.ConnectorCertsDir())
 	assert.NoError(t, err)
 
 	assert.FileExists(t, suiteCertBundle.RootCertPath)
@@ -61,7 +61,7 @@ func testGenerateCreatesBundle(t *testing.T) {
 func testFetchesLocalCertBundle(t *testing.T) {
 	c := NewConnector(suiteOptions)
 
-	cb, err := c.GetLocalCertBundle(utils.CertsDir(""))
+	cb, err := c.GetLocalCertBundle(utils.ConnectorCertsDir())
 	assert.NoError(t, err)
 	assert.NotNil(t, cb)
 }
@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"context"
 	"io"
 
 	"github.com/jumppad-labs/jumppad/pkg/config/resources"
@@ -25,11 +26,13 @@ type ContainerTasks interface {
 	ContainerInfo(id string) (interface{}, error)
 	// RemoveContainer stops and removes a running container
 	RemoveContainer(id string, force bool) error
+	// RenameContainer changes the name of the container with the given id
+	RenameContainer(id, name string) error
 	// BuildContainer builds a container based on the given configuration
-	// If a cahced image already exists Build will noop
+	// If a cached image built from the same build context and options already exists Build will noop
 	// When force is specificed BuildContainer will rebuild the container regardless of cached images
 	// Returns the canonical name of the built image and an error
-	BuildContainer(config *resources.Container, force bool) (string, error)
+	BuildContainer(ctx context.Context, config *resources.Container, force bool) (string, error)
 	// CreateVolume creates a new volume with the given name.
 	// If successful the id of the newly created volume is returned
 	CreateVolume(name string) (id string, err error)
@@ -41,7 +44,7 @@ type ContainerTasks interface {
 	// authenticate with the registry before pulling the image.
 	// If the force parameter is set then PullImage will pull regardless of the image already
 	// being cached locally.
-	PullImage(image resources.Image, force bool) error
+	PullImage(ctx context.Context, image resources.Image, force bool) error
 	// FindContainerIDs returns the Container IDs for the given identifier
 	FindContainerIDs(fqdn string) ([]string, error)
 	// ContainerLogs attaches to the container and streams the logs to the returned
@@ -64,7 +67,7 @@ type ContainerTasks interface {
 	// id is the id of the container to execute the command in
 	// command is a slice of strings to execute
 	// writer [optional] will be used to write any output from the command execution.
-	ExecuteCommand(id string, command []string, env []string, workingDirectory string, user, group string, writer io.Writer) error
+	ExecuteCommand(ctx context.Context, id string, command []string, env []string, workingDirectory string, user, group string, writer io.Writer) error
 	// AttachNetwork attaches a container to a network
 	// if aliases is set an alias for the container name will be added
 	// if ipAddress is not null then a user defined ipaddress will be used
@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"context"
 	"io"
 
 	"github.com/jumppad-labs/jumppad/pkg/config/resources"
@@ -33,7 +34,13 @@ func (m *MockContainerTasks) RemoveContainer(id string, force bool) error {
 	return args.Error(0)
 }
 
-func (m *MockContainerTasks) BuildContainer(config *resources.Container, force bool) (string, error) {
+func (m *MockContainerTasks) RenameContainer(id, name string) error {
+	args := m.Called(id, name)
+
+	return args.Error(0)
+}
+
+func (m *MockContainerTasks) BuildContainer(ctx context.Context, config *resources.Container, force bool) (string, error) {
 	args := m.Called(config, force)
 	return args.String(0), args.Error(1)
 }
@@ -50,7 +57,7 @@ func (m *MockContainerTasks) RemoveVolume(name string) error {
 	return args.Error(0)
 }
 
-func (m *MockContainerTasks) PullImage(i resources.Image, f bool) error {
+func (m *MockContainerTasks) PullImage(ctx context.Context, i resources.Image, f bool) error {
 	args := m.Called(i, f)
 
 	return args.Error(0)
@@ -108,7 +115,7 @@ func (d *MockContainerTasks) CopyFilesToVolume(volume string, files []string, pa
 	return nil, args.Error(1)
 }
 
-func (d *MockContainerTasks) ExecuteCommand(id string, command []string, env []string, workingDirectory string, user, group string, writer io.Writer) error {
+func (d *MockContainerTasks) ExecuteCommand(ctx context.Context, id string, command []string, env []string, workingDirectory string, user, group string, writer io.Writer) error {
 	args := d.Called(id, command, env, workingDirectory, user, group, writer)
 
 	return args.Error(0)
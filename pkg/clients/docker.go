@@ -3,6 +3,7 @@ package clients
 import (
 	"context"
 	"io"
+	"net"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -29,6 +30,7 @@ type Docker interface {
 	ContainerStart(context.Context, string, types.ContainerStartOptions) error
 	ContainerStop(ctx context.Context, containerID string, timeout *time.Duration) error
 	ContainerRemove(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error
+	ContainerRename(ctx context.Context, containerID, newContainerName string) error
 	ContainerLogs(ctx context.Context, container string, options types.ContainerLogsOptions) (io.ReadCloser, error)
 	ContainerExecCreate(ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error)
 	ContainerExecStart(ctx context.Context, execID string, config types.ExecStartCheck) error
@@ -58,6 +60,8 @@ type Docker interface {
 	ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error)
 	ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error)
 
+	DialHijack(ctx context.Context, url, proto string, meta map[string][]string) (net.Conn, error)
+
 	ServerVersion(ctx context.Context) (types.Version, error)
 	Info(ctx context.Context) (types.Info, error)
 }
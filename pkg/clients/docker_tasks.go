@@ -27,6 +27,7 @@ import (
 	"github.com/docker/docker/pkg/signal"
 	"github.com/docker/docker/pkg/term"
 	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
 	"github.com/hashicorp/go-hclog"
 	"github.com/jumppad-labs/jumppad/pkg/clients/streams"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources"
@@ -51,6 +52,14 @@ const (
 	StorageDriverOverlay      = "overlay"
 )
 
+// buildChecksumLabel is the image label which holds the checksum of the
+// build context an image was built from
+const buildChecksumLabel = "dev.jumppad.build.checksum"
+
+// buildResourceLabel is the image label which holds the workspace and ID of
+// the resource an image was built for
+const buildResourceLabel = "dev.jumppad.build.resource"
+
 // DockerTasks is a concrete implementation of ContainerTasks which uses the Docker SDK
 type DockerTasks struct {
 	engineType    string
@@ -141,6 +150,11 @@ func (d *DockerTasks) CreateContainer(c *resources.Container) (string, error) {
 		AttachStdout: true,
 		AttachStderr: true,
 		User:         user,
+		Labels:       c.Labels,
+	}
+
+	if c.Hostname != "" {
+		dc.Hostname = c.Hostname
 	}
 
 	// create the host and network configs
@@ -179,6 +193,43 @@ func (d *DockerTasks) CreateContainer(c *resources.Container) (string, error) {
 		hc.Resources = rc
 	}
 
+	// add the kernel and security options
+	hc.CapAdd = c.CapAdd
+	hc.CapDrop = c.CapDrop
+	hc.Sysctls = c.Sysctls
+	hc.SecurityOpt = c.SecurityOpt
+	hc.ReadonlyRootfs = c.ReadOnlyRootfs
+
+	if c.ShmSize > 0 {
+		hc.ShmSize = int64(c.ShmSize) * 1000000 // docker specifies shm size in bytes, shipyard megabytes
+	}
+
+	if c.Init {
+		hc.Init = &c.Init
+	}
+
+	for _, u := range c.Ulimits {
+		hc.Ulimits = append(hc.Ulimits, &units.Ulimit{Name: u.Name, Soft: int64(u.Soft), Hard: int64(u.Hard)})
+	}
+
+	for _, dev := range c.Devices {
+		dm := container.DeviceMapping{
+			PathOnHost:        dev.Source,
+			PathInContainer:   dev.Destination,
+			CgroupPermissions: dev.Permissions,
+		}
+
+		if dm.PathInContainer == "" {
+			dm.PathInContainer = dev.Source
+		}
+
+		if dm.CgroupPermissions == "" {
+			dm.CgroupPermissions = "rwm"
+		}
+
+		hc.Devices = append(hc.Devices, dm)
+	}
+
 	// by default the container should NOT be attached to a network
 	nc.EndpointsConfig = make(map[string]*network.EndpointSettings)
 
@@ -347,7 +398,7 @@ func (d *DockerTasks) CreateContainer(c *resources.Container) (string, error) {
 				return "", xerrors.Errorf("Network not found: %w", err)
 			}
 
-			err = d.AttachNetwork(net.Metadata().Name, cont.ID, n.Aliases, n.IPAddress)
+			err = d.AttachNetwork(utils.FQDNNetworkName(net.Metadata().Name), cont.ID, n.Aliases, n.IPAddress)
 
 			if err != nil {
 				// if we fail to connect to the network roll back the container
@@ -391,7 +442,7 @@ func (d *DockerTasks) ContainerInfo(id string) (interface{}, error) {
 }
 
 // PullImage pulls a Docker image from a remote repo
-func (d *DockerTasks) PullImage(image resources.Image, force bool) error {
+func (d *DockerTasks) PullImage(ctx context.Context, image resources.Image, force bool) error {
 	// if image is local not try to pull jumppad.dev/localcache
 	if strings.HasPrefix(image.Name, "jumppad.dev/localcache") {
 		return nil
@@ -405,7 +456,7 @@ func (d *DockerTasks) PullImage(image resources.Image, force bool) error {
 		args := filters.NewArgs()
 		args.Add("reference", image.Name)
 
-		sum, err := d.c.ImageList(context.Background(), types.ImageListOptions{Filters: args})
+		sum, err := d.c.ImageList(ctx, types.ImageListOptions{Filters: args})
 		if err != nil {
 			return xerrors.Errorf("unable to list images in local Docker cache: %w", err)
 		}
@@ -421,7 +472,7 @@ func (d *DockerTasks) PullImage(image resources.Image, force bool) error {
 		args = filters.NewArgs()
 		args.Add("reference", in)
 
-		sum, err = d.c.ImageList(context.Background(), types.ImageListOptions{Filters: args})
+		sum, err = d.c.ImageList(ctx, types.ImageListOptions{Filters: args})
 		if err != nil {
 			return xerrors.Errorf("unable to list images in local Docker cache: %w", err)
 		}
@@ -444,7 +495,7 @@ func (d *DockerTasks) PullImage(image resources.Image, force bool) error {
 
 	d.l.Debug("Pulling image", "image", in)
 
-	out, err := d.c.ImagePull(context.Background(), in, ipo)
+	out, err := d.c.ImagePull(ctx, in, ipo)
 	if err != nil {
 		return xerrors.Errorf("Error pulling image: %w", err)
 	}
@@ -455,8 +506,18 @@ func (d *DockerTasks) PullImage(image resources.Image, force bool) error {
 		d.l.Error("Unable to add image name to cache", "error", err)
 	}
 
-	// write the output to the debug log
-	io.Copy(d.l.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}), out)
+	// write the output to the debug log, the pull is not complete until
+	// the output has been read
+	_, err = io.Copy(d.l.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}), out)
+	out.Close()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if err != nil {
+		return xerrors.Errorf("Error pulling image: %w", err)
+	}
 
 	return nil
 }
@@ -509,16 +570,37 @@ func (d *DockerTasks) RemoveContainer(id string, force bool) error {
 	return d.c.ContainerRemove(context.Background(), id, types.ContainerRemoveOptions{Force: true, RemoveVolumes: true})
 }
 
-func (d *DockerTasks) BuildContainer(config *resources.Container, force bool) (string, error) {
-	imageName := fmt.Sprintf("jumppad.dev/localcache/%s:%s", config.Name, config.Build.Tag)
+// RenameContainer changes the name of the container with the given id
+func (d *DockerTasks) RenameContainer(id, name string) error {
+	d.l.Debug("Renaming container", "container", id, "name", name)
+
+	err := d.c.ContainerRename(context.Background(), id, name)
+	if err != nil {
+		return xerrors.Errorf("unable to rename container %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// BuildContainer builds the image for the container from the build config,
+// the build is skipped when an image with the same name has already been built
+// from the same build context and options
+func (d *DockerTasks) BuildContainer(ctx context.Context, config *resources.Container, force bool) (string, error) {
+	imageName := fmt.Sprintf("jumppad.dev/localcache/%s:%s", config.Name, config.Build.ImageTag())
 	imageName = makeImageCanonical(imageName)
 
 	args := filters.NewArgs()
 	args.Add("reference", imageName)
 
+	// when the checksum is set only use the image when it was built from the
+	// same sources, this ensures images with a fixed tag are rebuilt
+	if config.Build.Checksum != "" {
+		args.Add("label", fmt.Sprintf("%s=%s", buildChecksumLabel, config.Build.Checksum))
+	}
+
 	// check if the image already exists, if so do not rebuild unless force
 	if !force && !d.force {
-		sum, err := d.c.ImageList(context.Background(), types.ImageListOptions{Filters: args})
+		sum, err := d.c.ImageList(ctx, types.ImageListOptions{Filters: args})
 		if err != nil {
 			return "", xerrors.Errorf("unable to list images in local Docker cache: %w", err)
 		}
@@ -536,16 +618,63 @@ func (d *DockerTasks) BuildContainer(config *resources.Container, force bool) (s
 		config.Build.DockerFile = "./Dockerfile"
 	}
 
-	// tar the build context folder and send to the server
+	buildArgs := map[string]*string{}
+	for k, v := range config.Build.Args {
+		v := v
+		buildArgs[k] = &v
+	}
+
+	labels := map[string]string{}
+	for k, v := range config.Build.Labels {
+		labels[k] = v
+	}
+
+	if config.Build.Checksum != "" {
+		labels[buildChecksumLabel] = config.Build.Checksum
+	}
+
+	labels[buildResourceLabel] = buildResource(config)
+
 	buildOpts := types.ImageBuildOptions{
 		Dockerfile: config.Build.DockerFile,
 		Tags:       []string{imageName},
+		BuildArgs:  buildArgs,
+		Target:     config.Build.Target,
+		Labels:     labels,
+		CacheFrom:  config.Build.CacheFrom,
+	}
+
+	// the build session must stop when the build completes
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// secrets can only be used with BuildKit, the builder fetches the secrets
+	// from a session which is served over the Docker API connection
+	if len(config.Build.Secrets) > 0 {
+		s, err := d.startBuildSession(ctx, config.Build.Secrets)
+		if err != nil {
+			return "", err
+		}
+		defer s.Close()
+
+		buildOpts.Version = types.BuilderBuildKit
+		buildOpts.SessionID = s.ID()
+	}
+
+	// tar the build context folder, excluding the files in .dockerignore,
+	// and send to the server
+	excludes, err := utils.DockerIgnorePatterns(config.Build.Context, config.Build.DockerFile)
+	if err != nil {
+		return "", err
 	}
 
 	var buf bytes.Buffer
-	d.tg.Compress(&buf, &TarGzOptions{OmitRoot: true}, config.Build.Context)
+	err = d.tg.Compress(&buf, &TarGzOptions{OmitRoot: true, Excludes: excludes}, config.Build.Context)
+	if err != nil {
+		return "", xerrors.Errorf("unable to create build context: %w", err)
+	}
 
-	resp, err := d.c.ImageBuild(context.Background(), &buf, buildOpts)
+	resp, err := d.c.ImageBuild(ctx, &buf, buildOpts)
 	if err != nil {
 		return "", err
 	}
@@ -559,9 +688,50 @@ func (d *DockerTasks) BuildContainer(config *resources.Container, force bool) (s
 		return "", err
 	}
 
+	// the tag changes every time the sources change, remove the images built
+	// previously for the container so they do not accumulate
+	d.removePreviousBuilds(ctx, config, imageName)
+
 	return imageName, nil
 }
 
+// removePreviousBuilds removes the tags of the images built for the container
+// other than the current image, only images labelled with the workspace and ID
+// of the container are removed as containers with the same name in other
+// modules or workspaces share the image repository. Images which are still
+// used by a container are not removed.
+func (d *DockerTasks) removePreviousBuilds(ctx context.Context, config *resources.Container, current string) {
+	args := filters.NewArgs()
+	args.Add("reference", fmt.Sprintf("jumppad.dev/localcache/%s", config.Name))
+	args.Add("label", fmt.Sprintf("%s=%s", buildResourceLabel, buildResource(config)))
+
+	sum, err := d.c.ImageList(ctx, types.ImageListOptions{Filters: args})
+	if err != nil {
+		d.l.Debug("Unable to list previous builds", "image", current, "error", err)
+		return
+	}
+
+	for _, i := range sum {
+		for _, t := range i.RepoTags {
+			if makeImageCanonical(t) == current {
+				continue
+			}
+
+			d.l.Debug("Removing previous build", "image", t)
+
+			_, err := d.c.ImageRemove(ctx, t, types.ImageRemoveOptions{PruneChildren: true})
+			if err != nil {
+				d.l.Debug("Unable to remove previous build", "image", t, "error", err)
+			}
+		}
+	}
+}
+
+// buildResource returns the value of the build resource label for the container
+func buildResource(config *resources.Container) string {
+	return fmt.Sprintf("%s/%s", utils.Workspace(), config.ID)
+}
+
 // CreateVolume creates a Docker volume for a cluster
 // if the volume exists performs no action
 // returns the volume name and an error if unsuccessful
@@ -714,7 +884,7 @@ func (d *DockerTasks) CopyLocalDockerImagesToVolume(images []string, volume stri
 // returns the names of the stored files
 func (d *DockerTasks) CopyFilesToVolume(volumeID string, filenames []string, path string, force bool) ([]string, error) {
 	// make sure we have the alpine image needed to copy
-	err := d.PullImage(resources.Image{Name: "alpine:latest"}, false)
+	err := d.PullImage(context.Background(), resources.Image{Name: "alpine:latest"}, false)
 	if err != nil {
 		return nil, xerrors.Errorf("Unable pull alpine:latest for importing images: %w", err)
 	}
@@ -783,7 +953,7 @@ func (d *DockerTasks) CopyFilesToVolume(volumeID string, filenames []string, pat
 
 	// create the directory paths ensure unix paths for containers
 	destPath := filepath.ToSlash(filepath.Join("/cache", path))
-	err = d.ExecuteCommand(tmpID, []string{"mkdir", "-p", destPath}, nil, "/", "", "", nil)
+	err = d.ExecuteCommand(context.Background(), tmpID, []string{"mkdir", "-p", destPath}, nil, "/", "", "", nil)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to create destination path %s in volume: %s", destPath, err)
 	}
@@ -797,7 +967,7 @@ func (d *DockerTasks) CopyFilesToVolume(volumeID string, filenames []string, pat
 
 		// check if the image exists if we are not doing a forced update
 		if !d.force && !force {
-			err := d.ExecuteCommand(tmpID, []string{"find", destFile}, nil, "/", "", "", nil)
+			err := d.ExecuteCommand(context.Background(), tmpID, []string{"find", destFile}, nil, "/", "", "", nil)
 			if err == nil {
 				// we have the image already
 				d.l.Debug("File already cached", "name", name, "path", path)
@@ -878,13 +1048,16 @@ func (d *DockerTasks) CopyFileToContainer(containerID, filename, path string) er
 // id is the id of the container to execute the command in
 // command is a slice of strings to execute
 // writer [optional] will be used to write any output from the command execution.
-func (d *DockerTasks) ExecuteCommand(id string, command []string, env []string, workingDir string, user, group string, writer io.Writer) error {
+// When the context is cancelled ExecuteCommand stops streaming the output and
+// returns the context error, Docker can not signal an exec so the command is
+// left to finish in the container.
+func (d *DockerTasks) ExecuteCommand(ctx context.Context, id string, command []string, env []string, workingDir string, user, group string, writer io.Writer) error {
 	// set the user details
 	if user != "" && group != "" {
 		user = fmt.Sprintf("%s:%s", user, group)
 	}
 
-	execid, err := d.c.ContainerExecCreate(context.Background(), id, types.ExecConfig{
+	execid, err := d.c.ContainerExecCreate(ctx, id, types.ExecConfig{
 		Cmd:          command,
 		AttachStdout: true,
 		AttachStderr: true,
@@ -898,14 +1071,14 @@ func (d *DockerTasks) ExecuteCommand(id string, command []string, env []string,
 	}
 
 	// get logs from an attach
-	stream, err := d.c.ContainerExecAttach(context.Background(), execid.ID, types.ExecStartCheck{})
+	stream, err := d.c.ContainerExecAttach(ctx, execid.ID, types.ExecStartCheck{})
 	if err != nil {
 		return xerrors.Errorf("unable to attach logging to exec process: %w", err)
 	}
 
 	defer stream.Close()
 
-	streamContext, cancelStream := context.WithCancel(context.Background())
+	streamContext, cancelStream := context.WithCancel(ctx)
 	defer cancelStream()
 
 	// if we have a writer stream the logs from the container to the writer
@@ -925,16 +1098,25 @@ func (d *DockerTasks) ExecuteCommand(id string, command []string, env []string,
 			}()
 		}()
 
-		if err := <-errCh; err != nil {
-			d.l.Error("unable to hijack exec stream: %s", err)
-			return err
+		select {
+		case err := <-errCh:
+			if err != nil {
+				d.l.Error("unable to hijack exec stream: %s", err)
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
 		}
 	}
 
 	// loop until the container finishes execution
 	for {
-		i, err := d.c.ContainerExecInspect(context.Background(), execid.ID)
+		i, err := d.c.ContainerExecInspect(ctx, execid.ID)
 		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
 			return xerrors.Errorf("unable to determine status of exec process: %w", err)
 		}
 
@@ -946,7 +1128,11 @@ func (d *DockerTasks) ExecuteCommand(id string, command []string, env []string,
 			return xerrors.Errorf("container exec failed with exit code %d", i.ExitCode)
 		}
 
-		time.Sleep(1 * time.Second)
+		select {
+		case <-time.After(1 * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 }
 
@@ -0,0 +1,196 @@
+package clients
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dtypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/hashicorp/go-hclog"
+	"github.com/jumppad-labs/jumppad/pkg/clients/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/shipyard-run/hclconfig/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupBuildOptionsTests(t *testing.T, images []dtypes.ImageSummary) (*DockerTasks, *mocks.MockDocker, *resources.Container) {
+	dir := t.TempDir()
+	ioutil.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM alpine"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, ".dockerignore"), []byte("*.log\nDockerfile\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "debug.log"), []byte("debug"), 0644)
+
+	md := &mocks.MockDocker{}
+	md.On("ServerVersion", mock.Anything).Return(dtypes.Version{}, nil)
+	md.On("Info", mock.Anything).Return(dtypes.Info{Driver: StorageDriverOverlay2}, nil)
+	md.On("ImageList", mock.Anything, mock.Anything).Return(images, nil)
+	md.On("ImageBuild", mock.Anything, mock.Anything, mock.Anything).Return(
+		dtypes.ImageBuildResponse{
+			Body: ioutil.NopCloser(strings.NewReader("")),
+		}, nil)
+
+	c := &resources.Container{
+		ResourceMetadata: types.ResourceMetadata{Name: "test"},
+		Build: &resources.Build{
+			Context:   dir,
+			Args:      map[string]string{"VERSION": "1.0"},
+			Target:    "build",
+			Labels:    map[string]string{"team": "platform"},
+			CacheFrom: []string{"nicholasjackson/fake:cache"},
+			Checksum:  "0123456789abcdef0123",
+		},
+	}
+
+	dt := NewDockerTasks(md, nil, &TarGz{}, hclog.NewNullLogger())
+
+	return dt, md, c
+}
+
+func TestBuildUsesChecksumForTagAndImageFilter(t *testing.T) {
+	dt, md, c := setupBuildOptionsTests(t, []dtypes.ImageSummary{{ID: "abc"}})
+
+	in, err := dt.BuildContainer(context.Background(), c, false)
+	require.NoError(t, err)
+	require.Equal(t, "jumppad.dev/localcache/test:0123456789ab", in)
+
+	md.AssertCalled(t, "ImageList", mock.Anything, dtypes.ImageListOptions{Filters: filters.NewArgs(
+		filters.Arg("reference", "jumppad.dev/localcache/test:0123456789ab"),
+		filters.Arg("label", "dev.jumppad.build.checksum=0123456789abcdef0123"),
+	)})
+
+	md.AssertNotCalled(t, "ImageBuild", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBuildWithFixedTagRebuildsWhenChecksumDiffers(t *testing.T) {
+	dt, md, c := setupBuildOptionsTests(t, []dtypes.ImageSummary{})
+	c.Build.Tag = "latest"
+
+	in, err := dt.BuildContainer(context.Background(), c, false)
+	require.NoError(t, err)
+	require.Equal(t, "jumppad.dev/localcache/test:latest", in)
+
+	md.AssertCalled(t, "ImageList", mock.Anything, dtypes.ImageListOptions{Filters: filters.NewArgs(
+		filters.Arg("reference", "jumppad.dev/localcache/test:latest"),
+		filters.Arg("label", "dev.jumppad.build.checksum=0123456789abcdef0123"),
+	)})
+
+	md.AssertCalled(t, "ImageBuild", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBuildSetsBuildOptions(t *testing.T) {
+	dt, md, c := setupBuildOptionsTests(t, []dtypes.ImageSummary{})
+
+	_, err := dt.BuildContainer(context.Background(), c, false)
+	require.NoError(t, err)
+
+	var opts dtypes.ImageBuildOptions
+	for _, call := range md.Calls {
+		if call.Method == "ImageBuild" {
+			opts = call.Arguments[2].(dtypes.ImageBuildOptions)
+		}
+	}
+
+	require.Equal(t, "1.0", *opts.BuildArgs["VERSION"])
+	require.Equal(t, "build", opts.Target)
+	require.Equal(t, "platform", opts.Labels["team"])
+	require.Equal(t, "0123456789abcdef0123", opts.Labels["dev.jumppad.build.checksum"])
+	require.Equal(t, []string{"nicholasjackson/fake:cache"}, opts.CacheFrom)
+	require.Empty(t, opts.SessionID)
+	require.NotEqual(t, dtypes.BuilderBuildKit, opts.Version)
+}
+
+func TestBuildExcludesDockerIgnoreFromContext(t *testing.T) {
+	dt, md, c := setupBuildOptionsTests(t, []dtypes.ImageSummary{})
+
+	_, err := dt.BuildContainer(context.Background(), c, false)
+	require.NoError(t, err)
+
+	var buildContext io.Reader
+	for _, call := range md.Calls {
+		if call.Method == "ImageBuild" {
+			buildContext = call.Arguments[1].(io.Reader)
+		}
+	}
+
+	zr, err := gzip.NewReader(buildContext)
+	require.NoError(t, err)
+
+	files := []string{}
+	tr := tar.NewReader(zr)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+		files = append(files, h.Name)
+	}
+
+	require.ElementsMatch(t, []string{".dockerignore", "Dockerfile", "main.go"}, files)
+}
+
+func TestBuildWithSecretsUsesBuildKitSession(t *testing.T) {
+	dt, md, c := setupBuildOptionsTests(t, []dtypes.ImageSummary{})
+	md.On("DialHijack", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, os.ErrClosed)
+
+	secret := filepath.Join(t.TempDir(), "token")
+	ioutil.WriteFile(secret, []byte("secret"), 0600)
+	c.Build.Secrets = map[string]string{"token": secret}
+
+	_, err := dt.BuildContainer(context.Background(), c, false)
+	require.NoError(t, err)
+
+	var opts dtypes.ImageBuildOptions
+	for _, call := range md.Calls {
+		if call.Method == "ImageBuild" {
+			opts = call.Arguments[2].(dtypes.ImageBuildOptions)
+		}
+	}
+
+	require.Equal(t, dtypes.BuilderBuildKit, opts.Version)
+	require.NotEmpty(t, opts.SessionID)
+}
+
+func TestBuildWithMissingSecretReturnsError(t *testing.T) {
+	dt, _, c := setupBuildOptionsTests(t, []dtypes.ImageSummary{})
+	c.Build.Secrets = map[string]string{"token": filepath.Join(t.TempDir(), "missing")}
+
+	_, err := dt.BuildContainer(context.Background(), c, false)
+	require.Error(t, err)
+}
+
+func TestBuildRemovesPreviousBuildsOfTheSameResource(t *testing.T) {
+	dt, md, c := setupBuildOptionsTests(t, []dtypes.ImageSummary{})
+	c.ID = "module.app.resource.container.test"
+
+	previous := []dtypes.ImageSummary{
+		{ID: "old", RepoTags: []string{"jumppad.dev/localcache/test:aaaaaaaaaaaa"}},
+		{ID: "new", RepoTags: []string{"jumppad.dev/localcache/test:0123456789ab"}},
+	}
+
+	removeOn(&md.Mock, "ImageList")
+	md.On("ImageList", mock.Anything, mock.Anything).Return([]dtypes.ImageSummary{}, nil).Once()
+	md.On("ImageList", mock.Anything, mock.Anything).Return(previous, nil).Once()
+	md.On("ImageRemove", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+	_, err := dt.BuildContainer(context.Background(), c, false)
+	require.NoError(t, err)
+
+	// only images labelled with the workspace and id of the resource are removed
+	md.AssertCalled(t, "ImageList", mock.Anything, dtypes.ImageListOptions{Filters: filters.NewArgs(
+		filters.Arg("reference", "jumppad.dev/localcache/test"),
+		filters.Arg("label", "dev.jumppad.build.resource=default/module.app.resource.container.test"),
+	)})
+
+	md.AssertNumberOfCalls(t, "ImageRemove", 1)
+	md.AssertCalled(t, "ImageRemove", mock.Anything, "jumppad.dev/localcache/test:aaaaaaaaaaaa", mock.Anything)
+}
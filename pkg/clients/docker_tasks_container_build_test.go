@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"strings"
@@ -9,7 +10,8 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/jumppad-labs/jumppad/pkg/clients/mocks"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	htypes "github.com/shipyard-run/hclconfig/types"
 	"github.com/stretchr/testify/mock"
 	assert "github.com/stretchr/testify/require"
 )
@@ -21,7 +23,7 @@ func testBuildMockSetup() *mocks.MockDocker {
 
 	mk := &mocks.MockDocker{}
 	mk.On("ServerVersion", mock.Anything).Return(types.Version{}, nil)
-	mk.On("ImageList", mock.Anything, mock.Anything, mock.Anything).Return([]types.ImageSummary{{ID: "abc"}}, nil)
+	mk.On("ImageList", mock.Anything, mock.Anything).Return([]types.ImageSummary{{ID: "abc"}}, nil)
 	mk.On("ImageBuild", mock.Anything, mock.Anything, mock.Anything).Return(
 		types.ImageBuildResponse{
 			Body: ioutil.NopCloser(strings.NewReader("")),
@@ -34,44 +36,44 @@ func testBuildMockSetup() *mocks.MockDocker {
 func TestBuildListsImagesAndErrorWhenError(t *testing.T) {
 	md := testBuildMockSetup()
 	removeOn(&md.Mock, "ImageList")
-	md.On("ImageList", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("Boom"))
+	md.On("ImageList", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("Boom"))
 
-	cc := config.NewContainer("test")
-	cc.Build = &config.Build{Context: "./context", Tag: "latest"}
+	cc := &resources.Container{ResourceMetadata: htypes.ResourceMetadata{Name: "test"}}
+	cc.Build = &resources.Build{Context: t.TempDir(), Tag: "latest"}
 
 	dt := NewDockerTasks(md, nil, &TarGz{}, hclog.NewNullLogger())
 
-	_, err := dt.BuildContainer(cc, false)
+	_, err := dt.BuildContainer(context.Background(), cc, false)
 
 	assert.Error(t, err)
 }
 func TestBuildListsImagesAndDoesNotBuildWhenExists(t *testing.T) {
 	md := testBuildMockSetup()
-	cc := config.NewContainer("test")
-	cc.Build = &config.Build{Context: "./context", Tag: "latest"}
+	cc := &resources.Container{ResourceMetadata: htypes.ResourceMetadata{Name: "test"}}
+	cc.Build = &resources.Build{Context: t.TempDir(), Tag: "latest"}
 
 	dt := NewDockerTasks(md, nil, &TarGz{}, hclog.NewNullLogger())
 
-	in, err := dt.BuildContainer(cc, false)
+	in, err := dt.BuildContainer(context.Background(), cc, false)
 
 	assert.NoError(t, err)
-	assert.Equal(t, "shipyard.run/localcache/test:latest", in)
+	assert.Equal(t, "jumppad.dev/localcache/test:latest", in)
 }
 
 func TestBuildListsImagesAndBuildsWhenNotExists(t *testing.T) {
 	md := testBuildMockSetup()
 	removeOn(&md.Mock, "ImageList")
-	md.On("ImageList", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	md.On("ImageList", mock.Anything, mock.Anything).Return(nil, nil)
 
-	cc := config.NewContainer("test")
-	cc.Build = &config.Build{Context: "./context", Tag: "latest"}
+	cc := &resources.Container{ResourceMetadata: htypes.ResourceMetadata{Name: "test"}}
+	cc.Build = &resources.Build{Context: t.TempDir(), Tag: "latest"}
 
 	dt := NewDockerTasks(md, nil, &TarGz{}, hclog.NewNullLogger())
 
-	in, err := dt.BuildContainer(cc, false)
+	in, err := dt.BuildContainer(context.Background(), cc, false)
 
 	assert.NoError(t, err)
-	assert.Equal(t, "shipyard.run/localcache/test:latest", in)
+	assert.Equal(t, "jumppad.dev/localcache/test:latest", in)
 
 	params := getCalls(&md.Mock, "ImageBuild")[0].Arguments[2].(types.ImageBuildOptions)
 	assert.Equal(t, "./Dockerfile", params.Dockerfile)
@@ -80,17 +82,17 @@ func TestBuildListsImagesAndBuildsWhenNotExists(t *testing.T) {
 func TestBuildListsImagesAndBuildsWhenNotExistsCustomDockerfile(t *testing.T) {
 	md := testBuildMockSetup()
 	removeOn(&md.Mock, "ImageList")
-	md.On("ImageList", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	md.On("ImageList", mock.Anything, mock.Anything).Return(nil, nil)
 
-	cc := config.NewContainer("test")
-	cc.Build = &config.Build{Context: "./context", File: "./Dockerfile-test", Tag: "latest"}
+	cc := &resources.Container{ResourceMetadata: htypes.ResourceMetadata{Name: "test"}}
+	cc.Build = &resources.Build{Context: t.TempDir(), DockerFile: "./Dockerfile-test", Tag: "latest"}
 
 	dt := NewDockerTasks(md, nil, &TarGz{}, hclog.NewNullLogger())
 
-	in, err := dt.BuildContainer(cc, false)
+	in, err := dt.BuildContainer(context.Background(), cc, false)
 
 	assert.NoError(t, err)
-	assert.Equal(t, "shipyard.run/localcache/test:latest", in)
+	assert.Equal(t, "jumppad.dev/localcache/test:latest", in)
 
 	params := getCalls(&md.Mock, "ImageBuild")[0].Arguments[2].(types.ImageBuildOptions)
 	assert.Equal(t, "./Dockerfile-test", params.Dockerfile)
@@ -15,21 +15,23 @@ import (
 	"github.com/docker/go-connections/nat"
 	"github.com/hashicorp/go-hclog"
 	clients "github.com/jumppad-labs/jumppad/pkg/clients/mocks"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/mohae/deepcopy"
+	"github.com/shipyard-run/hclconfig"
+	htypes "github.com/shipyard-run/hclconfig/types"
 	"github.com/stretchr/testify/mock"
 	assert "github.com/stretchr/testify/require"
 )
 
-var wanNetwork = &config.Network{ResourceInfo: config.ResourceInfo{Name: "wan", Type: config.TypeNetwork}, Subnet: "192.168.6.0/24"}
-var containerNetwork = &config.Network{ResourceInfo: config.ResourceInfo{Name: "testnet", Type: config.TypeNetwork}, Subnet: "192.168.4.0/24"}
-var containerConfig = &config.Container{
-	ResourceInfo: config.ResourceInfo{Name: "testcontainer", Type: config.TypeContainer},
-	Image:        &config.Image{Name: "consul:v1.6.1"},
-	Command:      []string{"tail", "-f", "/dev/null"},
-	Volumes: []config.Volume{
-		config.Volume{
+var wanNetwork = &resources.Network{ResourceMetadata: htypes.ResourceMetadata{Name: "wan", Type: resources.TypeNetwork}, Subnet: "192.168.6.0/24"}
+var containerNetwork = &resources.Network{ResourceMetadata: htypes.ResourceMetadata{Name: "testnet", Type: resources.TypeNetwork}, Subnet: "192.168.4.0/24"}
+var containerConfig = &resources.Container{
+	ResourceMetadata: htypes.ResourceMetadata{Name: "testcontainer", Type: resources.TypeContainer},
+	Image:            &resources.Image{Name: "consul:v1.6.1"},
+	Command:          []string{"tail", "-f", "/dev/null"},
+	Volumes: []resources.Volume{
+		resources.Volume{
 			Source:                      "/tmp",
 			Destination:                 "/data",
 			ReadOnly:                    false,
@@ -37,60 +39,57 @@ var containerConfig = &config.Container{
 			BindPropagationNonRecursive: true,
 		},
 	},
-	Environment: []config.KV{
-		config.KV{Key: "TEST", Value: "true"},
+	Environment: map[string]string{
+		"TEST": "true",
 	},
-	EnvVar: map[string]string{
-		"key": "value",
-	},
-	Ports: []config.Port{
-		config.Port{
+	Ports: []resources.Port{
+		resources.Port{
 			Local:    "8080",
 			Host:     "9080",
 			Protocol: "tcp",
 		},
-		config.Port{
+		resources.Port{
 			Local:    "8081",
 			Host:     "9081",
 			Protocol: "udp",
 		},
 	},
-	PortRanges: []config.PortRange{
-		config.PortRange{
+	PortRanges: []resources.PortRange{
+		resources.PortRange{
 			Range:      "9000-9002",
 			Protocol:   "tcp",
 			EnableHost: true,
 		},
-		config.PortRange{
+		resources.PortRange{
 			Range:      "9100-9102",
 			Protocol:   "udp",
 			EnableHost: false,
 		},
 	},
-	Resources: &config.Resources{
+	Resources: &resources.Resources{
 		CPU:    1000,
 		Memory: 1000,
 		CPUPin: []int{1, 4},
 	},
-	Networks: []config.NetworkAttachment{
-		config.NetworkAttachment{Name: "network.testnet"},
-		config.NetworkAttachment{Name: "network.wan"},
+	Networks: []resources.NetworkAttachment{
+		resources.NetworkAttachment{ID: "resource.network.testnet"},
+		resources.NetworkAttachment{ID: "resource.network.wan"},
 	},
 }
 
-func createContainerConfig() (*config.Container, *config.Network, *config.Network, *clients.MockDocker, *clients.ImageLog) {
-	cc := deepcopy.Copy(containerConfig).(*config.Container)
-	cc2 := deepcopy.Copy(containerConfig).(*config.Container)
-	cn := deepcopy.Copy(containerNetwork).(*config.Network)
-	wn := deepcopy.Copy(wanNetwork).(*config.Network)
+func createContainerConfig() (*resources.Container, *resources.Network, *resources.Network, *clients.MockDocker, *clients.ImageLog) {
+	cc := deepcopy.Copy(containerConfig).(*resources.Container)
+	cc2 := deepcopy.Copy(containerConfig).(*resources.Container)
+	cn := deepcopy.Copy(containerNetwork).(*resources.Network)
+	wn := deepcopy.Copy(wanNetwork).(*resources.Network)
 
 	cc2.Name = "testcontainer2"
 
-	c := config.New()
-	c.AddResource(cc)
-	c.AddResource(cc2)
-	c.AddResource(cn)
-	c.AddResource(wn)
+	c := hclconfig.NewConfig()
+	c.AppendResource(cc)
+	c.AppendResource(cc2)
+	c.AppendResource(cn)
+	c.AppendResource(wn)
 
 	mc, mic := setupContainerMocks()
 
@@ -126,7 +125,7 @@ func setupContainerMocks() (*clients.MockDocker, *clients.ImageLog) {
 	return md, mic
 }
 
-func setupContainer(t *testing.T, cc *config.Container, md *clients.MockDocker, mic *clients.ImageLog) error {
+func setupContainer(t *testing.T, cc *resources.Container, md *clients.MockDocker, mic *clients.ImageLog) error {
 	p := NewDockerTasks(md, mic, &TarGz{}, hclog.NewNullLogger())
 
 	// create the container
@@ -149,9 +148,9 @@ func TestContainerCreatesCorrectly(t *testing.T) {
 
 	cfg := params[1].(*container.Config)
 
-	assert.Equal(t, cc.Info().Name, cfg.Hostname)
+	assert.Equal(t, cc.Name, cfg.Hostname)
 	assert.Equal(t, cc.Image.Name, cfg.Image)
-	assert.Equal(t, fmt.Sprintf("%s=%s", cc.Environment[0].Key, cc.Environment[0].Value), cfg.Env[0])
+	assert.Equal(t, "TEST=true", cfg.Env[0])
 	assert.Equal(t, cc.Command[0], cfg.Cmd[0])
 	assert.Equal(t, cc.Command[1], cfg.Cmd[1])
 	assert.Equal(t, cc.Command[2], cfg.Cmd[2])
@@ -189,14 +188,14 @@ func TestContainerAttachesToUserNetwork(t *testing.T) {
 	params := getCalls(&md.Mock, "NetworkConnect")[0].Arguments
 	nc := params[3].(*network.EndpointSettings)
 
-	assert.Equal(t, cn.Info().Name, params[1])
+	assert.Equal(t, utils.FQDNNetworkName(cn.Name), params[1])
 	assert.Equal(t, "test", params[2])
 	assert.Nil(t, nc.IPAMConfig) // unless an IP address is set this will be nil
 }
 
 func TestContainerAttachesToContainerNetwork(t *testing.T) {
 	cc, _, _, md, mic := createContainerConfig()
-	cc.Networks = []config.NetworkAttachment{config.NetworkAttachment{Name: "container.testcontainer2"}}
+	cc.Networks = []resources.NetworkAttachment{resources.NetworkAttachment{ID: "resource.container.testcontainer2"}}
 	md.On("ContainerList", mock.Anything, mock.Anything).Return([]types.Container{types.Container{ID: "abc"}})
 
 	err := setupContainer(t, cc, md, mic)
@@ -212,7 +211,7 @@ func TestContainerAttachesToContainerNetwork(t *testing.T) {
 
 func TestContainerAttachesToContainerNetworkReturnsErrorWhenListError(t *testing.T) {
 	cc, _, _, md, mic := createContainerConfig()
-	cc.Networks = []config.NetworkAttachment{config.NetworkAttachment{Name: "container.testcontainer2"}}
+	cc.Networks = []resources.NetworkAttachment{resources.NetworkAttachment{ID: "resource.container.testcontainer2"}}
 	md.On("ContainerList", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("boom"))
 
 	err := setupContainer(t, cc, md, mic)
@@ -221,7 +220,7 @@ func TestContainerAttachesToContainerNetworkReturnsErrorWhenListError(t *testing
 
 func TestContainerAttachesToContainerNetworkReturnsErrorWhenContainerNotFound(t *testing.T) {
 	cc, _, _, md, mic := createContainerConfig()
-	cc.Networks = []config.NetworkAttachment{config.NetworkAttachment{Name: "container.testcontainer2"}}
+	cc.Networks = []resources.NetworkAttachment{resources.NetworkAttachment{ID: "resource.container.testcontainer2"}}
 	md.On("ContainerList", mock.Anything, mock.Anything).Return(nil, nil)
 
 	err := setupContainer(t, cc, md, mic)
@@ -241,7 +240,7 @@ func TestContainerRollsbackWhenUnableToConnectToNetwork(t *testing.T) {
 
 func TestContainerDoesNOTAttachesToUserNetworkWhenNil(t *testing.T) {
 	cc, nc, _, md, mic := createContainerConfig()
-	cc.Networks = []config.NetworkAttachment{}
+	cc.Networks = []resources.NetworkAttachment{}
 
 	err := setupContainer(t, cc, md, mic)
 	assert.NoError(t, err)
@@ -498,7 +497,7 @@ func TestContainerNotConfiguresRetryWhen0(t *testing.T) {
 
 func TestContainerAddUserWhenSpecified(t *testing.T) {
 	cc, _, _, md, mic := createContainerConfig()
-	cc.RunAs = &config.User{
+	cc.RunAs = &resources.User{
 		User:  "1010",
 		Group: "1011",
 	}
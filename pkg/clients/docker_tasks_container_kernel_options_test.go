@@ -0,0 +1,116 @@
+package clients
+
+import (
+	"testing"
+
+	dtypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-units"
+	"github.com/hashicorp/go-hclog"
+	"github.com/jumppad-labs/jumppad/pkg/clients/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/shipyard-run/hclconfig/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupKernelOptionsTests(t *testing.T) (*DockerTasks, *mocks.MockDocker, *resources.Container) {
+	md := &mocks.MockDocker{}
+	md.On("ServerVersion", mock.Anything).Return(dtypes.Version{}, nil)
+	md.On("Info", mock.Anything).Return(dtypes.Info{Driver: StorageDriverOverlay2}, nil)
+	md.On("ContainerCreate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(container.ContainerCreateCreatedBody{ID: "abc"}, nil)
+	md.On("ContainerStart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	c := &resources.Container{
+		ResourceMetadata: types.ResourceMetadata{Name: "tools", Type: resources.TypeContainer},
+		Image:            &resources.Image{Name: "nicolaka/netshoot"},
+	}
+
+	dt := NewDockerTasks(md, nil, &TarGz{}, hclog.NewNullLogger())
+
+	return dt, md, c
+}
+
+func createdContainerConfig(md *mocks.MockDocker) (*container.Config, *container.HostConfig) {
+	for _, c := range md.Calls {
+		if c.Method == "ContainerCreate" {
+			return c.Arguments[1].(*container.Config), c.Arguments[2].(*container.HostConfig)
+		}
+	}
+
+	return nil, nil
+}
+
+func TestCreateContainerSetsKernelOptions(t *testing.T) {
+	dt, md, c := setupKernelOptionsTests(t)
+	c.CapAdd = []string{"NET_ADMIN", "SYS_PTRACE"}
+	c.CapDrop = []string{"MKNOD"}
+	c.Sysctls = map[string]string{"net.ipv4.ip_forward": "1"}
+	c.Ulimits = []resources.Ulimit{{Name: "nofile", Soft: 1024, Hard: 2048}}
+	c.ShmSize = 256
+	c.SecurityOpt = []string{"seccomp=unconfined"}
+	c.Init = true
+	c.ReadOnlyRootfs = true
+
+	_, err := dt.CreateContainer(c)
+	require.NoError(t, err)
+
+	_, hc := createdContainerConfig(md)
+
+	require.Equal(t, []string{"NET_ADMIN", "SYS_PTRACE"}, []string(hc.CapAdd))
+	require.Equal(t, []string{"MKNOD"}, []string(hc.CapDrop))
+	require.Equal(t, "1", hc.Sysctls["net.ipv4.ip_forward"])
+	require.Equal(t, []*units.Ulimit{{Name: "nofile", Soft: 1024, Hard: 2048}}, hc.Ulimits)
+	require.Equal(t, int64(256000000), hc.ShmSize)
+	require.Equal(t, []string{"seccomp=unconfined"}, hc.SecurityOpt)
+	require.True(t, *hc.Init)
+	require.True(t, hc.ReadonlyRootfs)
+	require.False(t, hc.Privileged)
+}
+
+func TestCreateContainerWithoutKernelOptionsUsesDockerDefaults(t *testing.T) {
+	dt, md, c := setupKernelOptionsTests(t)
+
+	_, err := dt.CreateContainer(c)
+	require.NoError(t, err)
+
+	dc, hc := createdContainerConfig(md)
+
+	require.Equal(t, "tools", dc.Hostname)
+	require.Nil(t, hc.Init)
+	require.Zero(t, hc.ShmSize)
+	require.Empty(t, hc.Devices)
+	require.False(t, hc.ReadonlyRootfs)
+}
+
+func TestCreateContainerSetsDevicesWithDefaults(t *testing.T) {
+	dt, md, c := setupKernelOptionsTests(t)
+	c.Devices = []resources.Device{
+		{Source: "/dev/net/tun"},
+		{Source: "/dev/sda", Destination: "/dev/xvda", Permissions: "r"},
+	}
+
+	_, err := dt.CreateContainer(c)
+	require.NoError(t, err)
+
+	_, hc := createdContainerConfig(md)
+
+	require.Equal(t, []container.DeviceMapping{
+		{PathOnHost: "/dev/net/tun", PathInContainer: "/dev/net/tun", CgroupPermissions: "rwm"},
+		{PathOnHost: "/dev/sda", PathInContainer: "/dev/xvda", CgroupPermissions: "r"},
+	}, hc.Devices)
+}
+
+func TestCreateContainerSetsHostnameAndLabels(t *testing.T) {
+	dt, md, c := setupKernelOptionsTests(t)
+	c.Hostname = "router"
+	c.Labels = map[string]string{"team": "network"}
+
+	_, err := dt.CreateContainer(c)
+	require.NoError(t, err)
+
+	dc, _ := createdContainerConfig(md)
+
+	require.Equal(t, "router", dc.Hostname)
+	require.Equal(t, "network", dc.Labels["team"])
+}
@@ -30,8 +30,7 @@ func testCreateCopyLocalMocks() *mocks.MockDocker {
 	mk.On("Info", mock.Anything).Return(types.Info{Driver: StorageDriverOverlay2}, nil)
 	mk.On("ContainerInspect", mock.Anything, mock.Anything).Return(
 		types.ContainerJSON{
-			&types.ContainerJSONBase{State: &types.ContainerState{Running: true}},
-			nil, nil, nil,
+			ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Running: true}},
 		},
 		nil)
 
@@ -211,8 +210,7 @@ func TestCopyToVolumeReturnsErrorOnFailedContainerStart(t *testing.T) {
 	removeOn(&mk.Mock, "ContainerInspect")
 	mk.On("ContainerInspect", mock.Anything, mock.Anything).Return(
 		types.ContainerJSON{
-			&types.ContainerJSONBase{State: &types.ContainerState{Running: false}},
-			nil, nil, nil,
+			ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Running: false}},
 		},
 		nil)
 
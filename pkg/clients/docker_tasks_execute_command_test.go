@@ -3,6 +3,7 @@ package clients
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"net"
 	"testing"
@@ -49,7 +50,7 @@ func TestExecuteCommandCreatesExec(t *testing.T) {
 	writer := bytes.NewBufferString("")
 
 	command := []string{"ls", "-las"}
-	err := md.ExecuteCommand("testcontainer", command, []string{"abc=123"}, "/files", "1000", "2000", writer)
+	err := md.ExecuteCommand(context.Background(), "testcontainer", command, []string{"abc=123"}, "/files", "1000", "2000", writer)
 	assert.NoError(t, err)
 
 	mk.AssertCalled(t, "ContainerExecCreate", mock.Anything, "testcontainer", mock.Anything)
@@ -81,7 +82,7 @@ func TestExecuteCommandExecFailReturnError(t *testing.T) {
 	writer := bytes.NewBufferString("")
 
 	command := []string{"ls", "-las"}
-	err := md.ExecuteCommand("testcontainer", command, nil, "/", "", "", writer)
+	err := md.ExecuteCommand(context.Background(), "testcontainer", command, nil, "/", "", "", writer)
 	assert.Error(t, err)
 }
 
@@ -95,7 +96,7 @@ func TestExecuteCommandAttachesToExec(t *testing.T) {
 	writer := bytes.NewBufferString("")
 
 	command := []string{"ls", "-las"}
-	err := md.ExecuteCommand("testcontainer", command, nil, "/", "", "", writer)
+	err := md.ExecuteCommand(context.Background(), "testcontainer", command, nil, "/", "", "", writer)
 	assert.NoError(t, err)
 
 	mk.AssertCalled(t, "ContainerExecAttach", mock.Anything, "abc", mock.Anything)
@@ -113,7 +114,7 @@ func TestExecuteCommandAttachFailReturnError(t *testing.T) {
 	writer := bytes.NewBufferString("")
 
 	command := []string{"ls", "-las"}
-	err := md.ExecuteCommand("testcontainer", command, nil, "/", "", "", writer)
+	err := md.ExecuteCommand(context.Background(), "testcontainer", command, nil, "/", "", "", writer)
 	assert.Error(t, err)
 }
 
@@ -127,7 +128,7 @@ func TestExecuteCommandAttachFailReturnError(t *testing.T) {
 //	writer := bytes.NewBufferString("")
 //
 //	command := []string{"ls", "-las"}
-//	err := md.ExecuteCommand("testcontainer", command, nil, "/", "", "", writer)
+//	err := md.ExecuteCommand(context.Background(), "testcontainer", command, nil, "/", "", "", writer)
 //	assert.NoError(t, err)
 //
 //	mk.AssertCalled(t, "ContainerExecStart", mock.Anything, "abc", mock.Anything)
@@ -145,7 +146,7 @@ func TestExecuteCommandAttachFailReturnError(t *testing.T) {
 //	writer := bytes.NewBufferString("")
 //
 //	command := []string{"ls", "-las"}
-//	err := md.ExecuteCommand("testcontainer", command, nil, "/", "", "", writer)
+//	err := md.ExecuteCommand(context.Background(), "testcontainer", command, nil, "/", "", "", writer)
 //	assert.Error(t, err)
 //}
 
@@ -161,7 +162,7 @@ func TestExecuteCommandInspectsExecAndReturnsErrorOnFail(t *testing.T) {
 	writer := bytes.NewBufferString("")
 
 	command := []string{"ls", "-las"}
-	err := md.ExecuteCommand("testcontainer", command, nil, "/", "", "", writer)
+	err := md.ExecuteCommand(context.Background(), "testcontainer", command, nil, "/", "", "", writer)
 	assert.Error(t, err)
 
 	mk.AssertCalled(t, "ContainerExecInspect", mock.Anything, "abc", mock.Anything)
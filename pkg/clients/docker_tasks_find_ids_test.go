@@ -25,7 +25,7 @@ func TestFindContainerIDsReturnsID(t *testing.T) {
 
 	dt := NewDockerTasks(md, nil, &TarGz{}, hclog.NewNullLogger())
 
-	ids, err := dt.FindContainerIDs("test", "cloud")
+	ids, err := dt.FindContainerIDs("test.cloud.jumppad.dev")
 	assert.NoError(t, err)
 
 	// assert that the docker api call was made
@@ -33,7 +33,7 @@ func TestFindContainerIDsReturnsID(t *testing.T) {
 
 	// ensure that the FQDN was passed as an argument
 	args := getCalls(&md.Mock, "ContainerList")[0].Arguments[1].(types.ContainerListOptions)
-	assert.Equal(t, "^test.cloud.shipyard.run$", args.Filters.Get("name")[0])
+	assert.Equal(t, "^test.cloud.jumppad.dev$", args.Filters.Get("name")[0])
 
 	// ensure that the id has been returned
 	assert.Len(t, ids, 2)
@@ -49,7 +49,7 @@ func TestFindContainerIDsReturnsErrorWhenDockerFail(t *testing.T) {
 
 	dt := NewDockerTasks(md, nil, &TarGz{}, hclog.NewNullLogger())
 
-	_, err := dt.FindContainerIDs("test", "cloud")
+	_, err := dt.FindContainerIDs("test.cloud.jumppad.dev")
 	assert.Error(t, err)
 }
 
@@ -61,7 +61,7 @@ func TestFindContainerIDsReturnsNilWhenNoIDs(t *testing.T) {
 
 	dt := NewDockerTasks(md, nil, &TarGz{}, hclog.NewNullLogger())
 
-	ids, err := dt.FindContainerIDs("test", "cloud")
+	ids, err := dt.FindContainerIDs("test.cloud.jumppad.dev")
 	assert.NoError(t, err)
 	assert.Nil(t, ids)
 }
@@ -74,7 +74,7 @@ func TestFindContainerIDsReturnsNilWhenEmpty(t *testing.T) {
 
 	dt := NewDockerTasks(md, nil, &TarGz{}, hclog.NewNullLogger())
 
-	ids, err := dt.FindContainerIDs("test", "cloud")
+	ids, err := dt.FindContainerIDs("test.cloud.jumppad.dev")
 	assert.NoError(t, err)
 	assert.Nil(t, ids)
 }
@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"context"
 	"encoding/base64"
 	"io/ioutil"
 	"strings"
@@ -10,7 +11,7 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/hashicorp/go-hclog"
 	"github.com/jumppad-labs/jumppad/pkg/clients/mocks"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -32,8 +33,8 @@ func setupImagePullMocks() (*mocks.MockDocker, *mocks.ImageLog) {
 	return md, mic
 }
 
-func createImagePullConfig() (config.Image, *mocks.MockDocker, *mocks.ImageLog) {
-	ic := config.Image{
+func createImagePullConfig() (resources.Image, *mocks.MockDocker, *mocks.ImageLog) {
+	ic := resources.Image{
 		Name: "consul:1.6.1",
 	}
 
@@ -41,11 +42,11 @@ func createImagePullConfig() (config.Image, *mocks.MockDocker, *mocks.ImageLog)
 	return ic, mk, mic
 }
 
-func setupImagePull(t *testing.T, cc config.Image, md *mocks.MockDocker, mic *mocks.ImageLog, force bool) {
+func setupImagePull(t *testing.T, cc resources.Image, md *mocks.MockDocker, mic *mocks.ImageLog, force bool) {
 	p := NewDockerTasks(md, mic, &TarGz{}, hclog.NewNullLogger())
 
 	// create the container
-	err := p.PullImage(cc, force)
+	err := p.PullImage(context.Background(), cc, force)
 	assert.NoError(t, err)
 
 	return
@@ -100,7 +101,7 @@ func TestPullImageWithValidCredentials(t *testing.T) {
 
 func TestPullImageNothingWhenLocalImage(t *testing.T) {
 	cc, md, mic := createImagePullConfig()
-	cc.Name = "shipyard.run/localcache/mine:latest"
+	cc.Name = "jumppad.dev/localcache/mine:latest"
 	setupImagePull(t, cc, md, mic, false)
 
 	md.AssertNotCalled(t, "ImagePull", mock.Anything, mock.Anything, mock.Anything)
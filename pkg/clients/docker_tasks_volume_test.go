@@ -18,7 +18,7 @@ func TestCreateVolumeDoesNothingWhenVolumeExists(t *testing.T) {
 
 	removeOn(&md.Mock, "VolumeList")
 	f := filters.NewArgs()
-	f.Add("name", "test.volume.shipyard.run")
+	f.Add("name", "test.volume.jumppad.dev")
 	md.On("VolumeList", mock.Anything, f).Return(volume.VolumeListOKBody{Volumes: []*types.Volume{&types.Volume{}}}, nil)
 
 	_, err := p.CreateVolume("test")
@@ -33,7 +33,7 @@ func TestCreateVolumeReturnsErrorWhenVolumeListError(t *testing.T) {
 
 	removeOn(&md.Mock, "VolumeList")
 	f := filters.NewArgs()
-	f.Add("name", "test.volume.shipyard.run")
+	f.Add("name", "test.volume.jumppad.dev")
 	md.On("VolumeList", mock.Anything, f).Return(nil, fmt.Errorf("Boom"))
 
 	_, err := p.CreateVolume("test")
@@ -60,5 +60,5 @@ func TestRemoveVolumeRemotesSuccesfully(t *testing.T) {
 	err := p.RemoveVolume("test")
 	assert.NoError(t, err)
 
-	md.AssertCalled(t, "VolumeRemove", mock.Anything, "test.volume.shipyard.run", true)
+	md.AssertCalled(t, "VolumeRemove", mock.Anything, "test.volume.jumppad.dev", true)
 }
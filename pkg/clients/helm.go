@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path"
@@ -32,7 +33,7 @@ func init() {
 // Helm defines an interface for a client which can manage Helm charts
 type Helm interface {
 	// CreateFromRepository creates a Helm install from a repository
-	Create(kubeConfig, name, namespace string, createNamespace bool, skipCRDs bool, chart, version, valuesPath string, valuesString map[string]string) error
+	Create(ctx context.Context, kubeConfig, name, namespace string, createNamespace bool, skipCRDs bool, chart, version, valuesPath string, valuesString map[string]string) error
 
 	// Destroy the given chart
 	Destroy(kubeConfig, name, namespace string) error
@@ -77,7 +78,7 @@ func NewHelm(l hclog.Logger) Helm {
 	return &HelmImpl{l, helmRepoConfig, helmCachePath, helmDataPath, helmConfigPath}
 }
 
-func (h *HelmImpl) Create(kubeConfig, name, namespace string, createNamespace bool, skipCRDs bool, chart, version, valuesPath string, valuesString map[string]string) error {
+func (h *HelmImpl) Create(ctx context.Context, kubeConfig, name, namespace string, createNamespace bool, skipCRDs bool, chart, version, valuesPath string, valuesString map[string]string) error {
 	// set the kube client for Helm
 	s := kube.GetConfig(kubeConfig, "default", namespace)
 	cfg := &action.Configuration{}
@@ -173,7 +174,7 @@ func (h *HelmImpl) Create(kubeConfig, name, namespace string, createNamespace bo
 	}
 
 	h.log.Debug("Run chart", "ref", name)
-	_, err = client.Run(chartRequested, vals)
+	_, err = client.RunWithContext(ctx, chartRequested, vals)
 	if err != nil {
 		return xerrors.Errorf("Error running chart: %w", err)
 	}
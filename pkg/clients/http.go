@@ -1,8 +1,10 @@
 package clients
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
@@ -14,8 +16,12 @@ type HTTP interface {
 	// HealthCheckHTTP makes a HTTP GET request to the given URI and
 	// if a successful status []codes is returned the method returns a nil error.
 	// If it is not possible to contact the URI or if any status other than the passed codes is returned
-	// by the upstream, then the URI is retried until the timeout elapses.
-	HealthCheckHTTP(uri string, codes []int, timeout time.Duration) error
+	// by the upstream, then the URI is retried until the timeout elapses or the context is cancelled.
+	HealthCheckHTTP(ctx context.Context, uri string, codes []int, timeout time.Duration) error
+	// HealthCheckTCP attempts to open a TCP connection to the given address,
+	// if the connection can not be made it is retried until the timeout elapses
+	// or the context is cancelled.
+	HealthCheckTCP(ctx context.Context, address string, timeout time.Duration) error
 	// Do executes a HTTP request and returns the response
 	Do(r *http.Request) (*http.Response, error)
 }
@@ -35,7 +41,7 @@ func NewHTTP(backoff time.Duration, l hclog.Logger) HTTP {
 }
 
 // HealthCheckHTTP checks a http or HTTPS endpoint for a status 200
-func (h *HTTPImpl) HealthCheckHTTP(address string, codes []int, timeout time.Duration) error {
+func (h *HTTPImpl) HealthCheckHTTP(ctx context.Context, address string, codes []int, timeout time.Duration) error {
 	h.l.Debug("Performing health check for address", "address", address)
 	st := time.Now()
 	for {
@@ -45,14 +51,52 @@ func (h *HTTPImpl) HealthCheckHTTP(address string, codes []int, timeout time.Dur
 			return fmt.Errorf("Timeout waiting for HTTP healthcheck %s", address)
 		}
 
-		resp, err := h.httpc.Get(address)
+		rq, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := h.httpc.Do(rq)
 		if err == nil && assertResponseCode(codes, resp.StatusCode) {
 			h.l.Debug("Health check complete", "address", address)
 			return nil
 		}
 
 		// backoff
-		time.Sleep(h.backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(h.backoff):
+		}
+	}
+}
+
+// HealthCheckTCP checks that a TCP connection can be made to the address
+func (h *HTTPImpl) HealthCheckTCP(ctx context.Context, address string, timeout time.Duration) error {
+	h.l.Debug("Performing TCP health check for address", "address", address)
+	st := time.Now()
+	for {
+		if time.Now().Sub(st) > timeout {
+			h.l.Error("Timeout wating for TCP healthcheck", "address", address)
+
+			return fmt.Errorf("Timeout waiting for TCP healthcheck %s", address)
+		}
+
+		d := net.Dialer{Timeout: timeout}
+		conn, err := d.DialContext(ctx, "tcp", address)
+		if err == nil {
+			conn.Close()
+
+			h.l.Debug("Health check complete", "address", address)
+			return nil
+		}
+
+		// backoff
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(h.backoff):
+		}
 	}
 }
 
@@ -1,6 +1,8 @@
 package clients
 
 import (
+	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -29,7 +31,7 @@ func TestHTTPHealthCallsGet(t *testing.T) {
 
 	c := NewHTTP(1*time.Millisecond, hclog.NewNullLogger())
 
-	err := c.HealthCheckHTTP(url, []int{200}, 10*time.Millisecond)
+	err := c.HealthCheckHTTP(context.Background(), url, []int{200}, 10*time.Millisecond)
 	assert.NoError(t, err)
 	assert.Len(t, *reqs, 1)
 }
@@ -40,7 +42,7 @@ func TestHTTPHealthCallsGetMultipleStatusCodes(t *testing.T) {
 
 	c := NewHTTP(1*time.Millisecond, hclog.NewNullLogger())
 
-	err := c.HealthCheckHTTP(url, []int{200, 204}, 10*time.Millisecond)
+	err := c.HealthCheckHTTP(context.Background(), url, []int{200, 204}, 10*time.Millisecond)
 	assert.NoError(t, err)
 	assert.Len(t, *reqs, 1)
 }
@@ -51,7 +53,7 @@ func TestHTTPHealthRetryiesOnServerErrorCode(t *testing.T) {
 
 	c := NewHTTP(1*time.Millisecond, hclog.NewNullLogger())
 
-	err := c.HealthCheckHTTP(url, []int{200}, 10*time.Millisecond)
+	err := c.HealthCheckHTTP(context.Background(), url, []int{200}, 10*time.Millisecond)
 	assert.Error(t, err)
 	assert.Greater(t, len(*reqs), 1)
 }
@@ -62,7 +64,31 @@ func TestHTTPHealthErrorsOnClientError(t *testing.T) {
 
 	c := NewHTTP(1*time.Millisecond, hclog.NewNullLogger())
 
-	err := c.HealthCheckHTTP("http://127.0.0.2:19091", []int{200}, 10*time.Millisecond)
+	err := c.HealthCheckHTTP(context.Background(), "http://127.0.0.2:19091", []int{200}, 10*time.Millisecond)
 	assert.Error(t, err)
 	assert.Len(t, *reqs, 0)
 }
+
+func TestHTTPHealthTCPConnects(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+
+	c := NewHTTP(1*time.Millisecond, hclog.NewNullLogger())
+
+	err = c.HealthCheckTCP(context.Background(), l.Addr().String(), 10*time.Millisecond)
+	assert.NoError(t, err)
+}
+
+func TestHTTPHealthTCPErrorsWhenNotListening(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	addr := l.Addr().String()
+	l.Close()
+
+	c := NewHTTP(1*time.Millisecond, hclog.NewNullLogger())
+
+	err = c.HealthCheckTCP(context.Background(), addr, 10*time.Millisecond)
+	assert.Error(t, err)
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
 // ImageTypeDocker defines a type for a Docker image
@@ -22,11 +24,22 @@ type ImageFileLog struct {
 }
 
 // NewImageFileLog creates an ImageLog which uses a file as the underlying
-// Datastore
+// Datastore, when file is empty the image log for the current workspace is
+// used
 func NewImageFileLog(file string) *ImageFileLog {
 	return &ImageFileLog{file}
 }
 
+// path returns the location of the log, the workspace is resolved on every
+// call as it is selected after the clients have been created
+func (i *ImageFileLog) path() string {
+	if i.f == "" {
+		return utils.ImageCacheLog()
+	}
+
+	return i.f
+}
+
 // Log an image has been downloaded by Shypyard
 func (i *ImageFileLog) Log(name, t string) error {
 	// check the existing entries do not add if allready in there
@@ -40,7 +53,7 @@ func (i *ImageFileLog) Log(name, t string) error {
 		}
 	}
 
-	f, err := os.OpenFile(i.f, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	f, err := os.OpenFile(i.path(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
 	if err != nil {
 		return err
 	}
@@ -52,7 +65,7 @@ func (i *ImageFileLog) Log(name, t string) error {
 
 // Read a list of images which have been downloaded by Shipyard
 func (i *ImageFileLog) Read(t string) ([]string, error) {
-	f, err := os.Open(i.f)
+	f, err := os.Open(i.path())
 	if err != nil {
 		return nil, err
 	}
@@ -74,5 +87,5 @@ func (i *ImageFileLog) Read(t string) ([]string, error) {
 
 // Clear the list of images
 func (i *ImageFileLog) Clear() error {
-	return os.Remove(i.f)
+	return os.Remove(i.path())
 }
@@ -7,11 +7,14 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"golang.org/x/xerrors"
 	"helm.sh/helm/v3/pkg/kube"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -23,15 +26,27 @@ import (
 type Kubernetes interface {
 	SetConfig(string) (Kubernetes, error)
 	GetPods(string) (*v1.PodList, error)
-	HealthCheckPods(selectors []string, timeout time.Duration) error
-	Apply(files []string, waitUntilReady bool) error
+	HealthCheckPods(ctx context.Context, selectors []string, timeout time.Duration) error
+	// HealthCheckServices checks that the services exist and have at least one
+	// ready endpoint, services are referenced as "namespace/name" or "name"
+	// for services in the default namespace
+	HealthCheckServices(ctx context.Context, services []string, timeout time.Duration) error
+	// HealthCheckDeployments checks that the rollout of the deployments is
+	// complete and all replicas are available
+	HealthCheckDeployments(ctx context.Context, deployments []string, timeout time.Duration) error
+	// HealthCheckStatefulSets checks that the rollout of the stateful sets is
+	// complete and all replicas are ready
+	HealthCheckStatefulSets(ctx context.Context, statefulSets []string, timeout time.Duration) error
+	// HealthCheckJobs checks that the jobs have completed successfully
+	HealthCheckJobs(ctx context.Context, jobs []string, timeout time.Duration) error
+	Apply(ctx context.Context, files []string, waitUntilReady bool) error
 	Delete(files []string) error
 	GetPodLogs(ctx context.Context, podName, nameSpace string) (io.ReadCloser, error)
 }
 
 // KubernetesImpl is a concrete implementation of a Kubernetes client
 type KubernetesImpl struct {
-	clientset  *kubernetes.Clientset
+	clientset  kubernetes.Interface
 	client     corev1.CoreV1Interface
 	configPath string
 	timeout    time.Duration
@@ -113,8 +128,9 @@ func (k *KubernetesImpl) GetPods(selector string) (*v1.PodList, error) {
 }
 
 // Apply Kubernetes YAML files at path
-// if waitUntilReady is true then the client will block until all resources have been created
-func (k *KubernetesImpl) Apply(files []string, waitUntilReady bool) error {
+// if waitUntilReady is true then the client will block until all resources have been created,
+// no further files are applied once the context is cancelled
+func (k *KubernetesImpl) Apply(ctx context.Context, files []string, waitUntilReady bool) error {
 	allFiles, err := buildFileList(files)
 	if err != nil {
 		return err
@@ -125,6 +141,10 @@ func (k *KubernetesImpl) Apply(files []string, waitUntilReady bool) error {
 
 	// process the files
 	for _, f := range allFiles {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
 		k.l.Debug("Applying Kubernetes config", "file", f)
 		err := applyFile(f, waitUntilReady, kc)
 		if err != nil {
@@ -162,12 +182,12 @@ func (k *KubernetesImpl) Delete(files []string) error {
 // and running.
 // selectors are checked sequentially
 // pods = ["component=server,app=consul", "component=client,app=consul"]
-func (k *KubernetesImpl) HealthCheckPods(selectors []string, timeout time.Duration) error {
+func (k *KubernetesImpl) HealthCheckPods(ctx context.Context, selectors []string, timeout time.Duration) error {
 	// check all pods are running
 	for _, s := range selectors {
 		k.l.Debug("Health checking pods", "selector", s)
 
-		err := k.healthCheckSingle(s, timeout)
+		err := k.healthCheckSingle(ctx, s, timeout)
 		if err != nil {
 			return err
 		}
@@ -177,11 +197,15 @@ func (k *KubernetesImpl) HealthCheckPods(selectors []string, timeout time.Durati
 }
 
 // healthCheckSingle checks for running containers with the given selector
-func (k *KubernetesImpl) healthCheckSingle(selector string, timeout time.Duration) error {
+func (k *KubernetesImpl) healthCheckSingle(ctx context.Context, selector string, timeout time.Duration) error {
 	st := time.Now()
 	for {
 		// backoff
-		time.Sleep(2 * time.Second)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(kubernetesHealthCheckBackoff):
+		}
 
 		if time.Now().Sub(st) > timeout {
 			return fmt.Errorf("Timeout waiting for pods %s to start", selector)
@@ -226,6 +250,231 @@ func (k *KubernetesImpl) healthCheckSingle(selector string, timeout time.Duratio
 	return nil
 }
 
+// kubernetesHealthCheckBackoff is the time to wait between health check attempts
+var kubernetesHealthCheckBackoff = 2 * time.Second
+
+// HealthCheckServices checks that each service exists and has ready endpoints
+// services = ["consul-consul", "vault/vault-ui"]
+func (k *KubernetesImpl) HealthCheckServices(ctx context.Context, services []string, timeout time.Duration) error {
+	for _, s := range services {
+		k.l.Debug("Health checking service", "service", s)
+
+		err := k.waitFor(ctx, "service", s, timeout, k.serviceReady)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HealthCheckDeployments checks that each deployment has completed its rollout
+// deployments = ["consul/consul-connect-injector"]
+func (k *KubernetesImpl) HealthCheckDeployments(ctx context.Context, deployments []string, timeout time.Duration) error {
+	for _, d := range deployments {
+		k.l.Debug("Health checking deployment", "deployment", d)
+
+		err := k.waitFor(ctx, "deployment", d, timeout, k.deploymentReady)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HealthCheckStatefulSets checks that each stateful set has completed its rollout
+// stateful_sets = ["consul/consul-server"]
+func (k *KubernetesImpl) HealthCheckStatefulSets(ctx context.Context, statefulSets []string, timeout time.Duration) error {
+	for _, s := range statefulSets {
+		k.l.Debug("Health checking stateful set", "stateful_set", s)
+
+		err := k.waitFor(ctx, "stateful set", s, timeout, k.statefulSetReady)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HealthCheckJobs checks that each job has succeeded, a job which has failed
+// returns an error immediately
+// jobs = ["vault/vault-init"]
+func (k *KubernetesImpl) HealthCheckJobs(ctx context.Context, jobs []string, timeout time.Duration) error {
+	for _, j := range jobs {
+		k.l.Debug("Health checking job", "job", j)
+
+		err := k.waitFor(ctx, "job", j, timeout, k.jobSucceeded)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readyFunc returns true when the object is ready, the string returned
+// describes why the object is not ready. An error stops the health check.
+type readyFunc func(ctx context.Context, namespace, name string) (bool, string, error)
+
+// waitFor calls ready until the object is ready, the timeout elapses or the
+// context is cancelled
+func (k *KubernetesImpl) waitFor(ctx context.Context, kind, ref string, timeout time.Duration, ready readyFunc) error {
+	namespace, name := splitKubernetesRef(ref)
+
+	reason := ""
+	st := time.Now()
+	for {
+		ok, r, err := ready(ctx, namespace, name)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			k.l.Debug("Health check complete", "kind", kind, "namespace", namespace, "name", name)
+			return nil
+		}
+
+		reason = r
+		k.l.Debug("Not ready, will retry", "kind", kind, "namespace", namespace, "name", name, "reason", reason)
+
+		if time.Now().Sub(st) > timeout {
+			return fmt.Errorf("Timeout waiting for %s %s/%s to be ready: %s", kind, namespace, name, reason)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(kubernetesHealthCheckBackoff):
+		}
+	}
+}
+
+func (k *KubernetesImpl) serviceReady(ctx context.Context, namespace, name string) (bool, string, error) {
+	svc, err := k.client.Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, err.Error(), nil
+	}
+
+	// external name services do not have endpoints
+	if svc.Spec.Type == v1.ServiceTypeExternalName {
+		return true, "", nil
+	}
+
+	ep, err := k.client.Endpoints(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, err.Error(), nil
+	}
+
+	for _, s := range ep.Subsets {
+		if len(s.Addresses) > 0 {
+			return true, "", nil
+		}
+	}
+
+	return false, "service has no ready endpoints", nil
+}
+
+// deploymentReady uses the same criteria as `kubectl rollout status`
+func (k *KubernetesImpl) deploymentReady(ctx context.Context, namespace, name string) (bool, string, error) {
+	d, err := k.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, err.Error(), nil
+	}
+
+	if d.Generation > d.Status.ObservedGeneration {
+		return false, "waiting for the deployment spec update to be observed", nil
+	}
+
+	for _, c := range d.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Reason == "ProgressDeadlineExceeded" {
+			return false, "", fmt.Errorf("deployment %s/%s exceeded its progress deadline", namespace, name)
+		}
+	}
+
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+
+	if d.Status.UpdatedReplicas < replicas {
+		return false, fmt.Sprintf("%d of %d replicas have been updated", d.Status.UpdatedReplicas, replicas), nil
+	}
+
+	if d.Status.Replicas > d.Status.UpdatedReplicas {
+		return false, fmt.Sprintf("%d old replicas are pending termination", d.Status.Replicas-d.Status.UpdatedReplicas), nil
+	}
+
+	if d.Status.AvailableReplicas < d.Status.UpdatedReplicas {
+		return false, fmt.Sprintf("%d of %d updated replicas are available", d.Status.AvailableReplicas, d.Status.UpdatedReplicas), nil
+	}
+
+	return true, "", nil
+}
+
+// statefulSetReady uses the same criteria as `kubectl rollout status`
+func (k *KubernetesImpl) statefulSetReady(ctx context.Context, namespace, name string) (bool, string, error) {
+	s, err := k.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, err.Error(), nil
+	}
+
+	if s.Status.ObservedGeneration == 0 || s.Generation > s.Status.ObservedGeneration {
+		return false, "waiting for the stateful set spec update to be observed", nil
+	}
+
+	replicas := int32(1)
+	if s.Spec.Replicas != nil {
+		replicas = *s.Spec.Replicas
+	}
+
+	if s.Status.ReadyReplicas < replicas {
+		return false, fmt.Sprintf("%d of %d replicas are ready", s.Status.ReadyReplicas, replicas), nil
+	}
+
+	if s.Spec.UpdateStrategy.Type == appsv1.RollingUpdateStatefulSetStrategyType && s.Status.UpdateRevision != s.Status.CurrentRevision {
+		return false, fmt.Sprintf("%d of %d replicas have been updated", s.Status.UpdatedReplicas, replicas), nil
+	}
+
+	return true, "", nil
+}
+
+func (k *KubernetesImpl) jobSucceeded(ctx context.Context, namespace, name string) (bool, string, error) {
+	j, err := k.clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, err.Error(), nil
+	}
+
+	for _, c := range j.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == v1.ConditionTrue {
+			return false, "", fmt.Errorf("job %s/%s failed: %s", namespace, name, c.Message)
+		}
+	}
+
+	completions := int32(1)
+	if j.Spec.Completions != nil {
+		completions = *j.Spec.Completions
+	}
+
+	if j.Status.Succeeded < completions {
+		return false, fmt.Sprintf("%d of %d completions have succeeded", j.Status.Succeeded, completions), nil
+	}
+
+	return true, "", nil
+}
+
+// splitKubernetesRef splits a reference in the form namespace/name, when
+// the namespace is not specified the default namespace is returned
+func splitKubernetesRef(ref string) (string, string) {
+	parts := strings.SplitN(ref, "/", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+
+	return "default", ref
+}
+
 func buildFileList(files []string) ([]string, error) {
 	allFiles := make([]string, 0)
 
@@ -37,7 +37,7 @@ func (m *MockKubernetes) GetPodLogs(ctx context.Context, podName, nameSpace stri
 	return ior, args.Error(1)
 }
 
-func (m *MockKubernetes) Apply(files []string, waitUntilReady bool) error {
+func (m *MockKubernetes) Apply(ctx context.Context, files []string, waitUntilReady bool) error {
 	args := m.Called(files, waitUntilReady)
 
 	return args.Error(0)
@@ -49,8 +49,32 @@ func (m *MockKubernetes) Delete(files []string) error {
 	return args.Error(0)
 }
 
-func (m *MockKubernetes) HealthCheckPods(selectors []string, timeout time.Duration) error {
+func (m *MockKubernetes) HealthCheckPods(ctx context.Context, selectors []string, timeout time.Duration) error {
 	args := m.Called(selectors, timeout)
 
 	return args.Error(0)
 }
+
+func (m *MockKubernetes) HealthCheckServices(ctx context.Context, services []string, timeout time.Duration) error {
+	args := m.Called(services, timeout)
+
+	return args.Error(0)
+}
+
+func (m *MockKubernetes) HealthCheckDeployments(ctx context.Context, deployments []string, timeout time.Duration) error {
+	args := m.Called(deployments, timeout)
+
+	return args.Error(0)
+}
+
+func (m *MockKubernetes) HealthCheckStatefulSets(ctx context.Context, statefulSets []string, timeout time.Duration) error {
+	args := m.Called(statefulSets, timeout)
+
+	return args.Error(0)
+}
+
+func (m *MockKubernetes) HealthCheckJobs(ctx context.Context, jobs []string, timeout time.Duration) error {
+	args := m.Called(jobs, timeout)
+
+	return args.Error(0)
+}
@@ -1,9 +1,34 @@
 package clients
 
 import (
+	"context"
 	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
+func setupKubernetesHealthChecks(t *testing.T, objects ...runtime.Object) *KubernetesImpl {
+	old := kubernetesHealthCheckBackoff
+	kubernetesHealthCheckBackoff = time.Millisecond
+	t.Cleanup(func() { kubernetesHealthCheckBackoff = old })
+
+	cs := fake.NewSimpleClientset(objects...)
+
+	return &KubernetesImpl{clientset: cs, client: cs.CoreV1(), l: hclog.NewNullLogger()}
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
 // TODO: implement these tests
 
 func TestKubernetesSetConfigCompletesWithoutError(t *testing.T) {
@@ -14,6 +39,136 @@ func TestApply(t *testing.T) {
 	t.Skip()
 }
 
+func TestHealthCheckServicesWithEndpoints(t *testing.T) {
+	k := setupKubernetesHealthChecks(t,
+		&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "consul", Namespace: "consul"}},
+		&v1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: "consul", Namespace: "consul"},
+			Subsets:    []v1.EndpointSubset{{Addresses: []v1.EndpointAddress{{IP: "10.0.0.1"}}}},
+		},
+	)
+
+	err := k.HealthCheckServices(context.Background(), []string{"consul/consul"}, 10*time.Millisecond)
+	require.NoError(t, err)
+}
+
+func TestHealthCheckServicesWithoutEndpointsTimesOut(t *testing.T) {
+	k := setupKubernetesHealthChecks(t,
+		&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "consul", Namespace: "default"}},
+		&v1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "consul", Namespace: "default"}},
+	)
+
+	err := k.HealthCheckServices(context.Background(), []string{"consul"}, 10*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no ready endpoints")
+}
+
+func TestHealthCheckServicesMissingTimesOut(t *testing.T) {
+	k := setupKubernetesHealthChecks(t)
+
+	err := k.HealthCheckServices(context.Background(), []string{"consul"}, 10*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "default/consul")
+}
+
+func TestHealthCheckDeploymentsWhenRolledOut(t *testing.T) {
+	k := setupKubernetesHealthChecks(t, &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Generation: 1},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+	})
+
+	err := k.HealthCheckDeployments(context.Background(), []string{"web"}, 10*time.Millisecond)
+	require.NoError(t, err)
+}
+
+func TestHealthCheckDeploymentsWhenNotAvailableTimesOut(t *testing.T) {
+	k := setupKubernetesHealthChecks(t, &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Generation: 1},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 1},
+	})
+
+	err := k.HealthCheckDeployments(context.Background(), []string{"web"}, 10*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "1 of 2 updated replicas are available")
+}
+
+func TestHealthCheckDeploymentsReturnsWhenContextCancelled(t *testing.T) {
+	k := setupKubernetesHealthChecks(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := k.HealthCheckDeployments(ctx, []string{"web"}, time.Minute)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestHealthCheckDeploymentsReturnsErrorWhenDeadlineExceeded(t *testing.T) {
+	k := setupKubernetesHealthChecks(t, &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Generation: 1},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 1,
+			Conditions:         []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing, Reason: "ProgressDeadlineExceeded"}},
+		},
+	})
+
+	err := k.HealthCheckDeployments(context.Background(), []string{"web"}, time.Minute)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "exceeded its progress deadline")
+}
+
+func TestHealthCheckStatefulSetsWhenReady(t *testing.T) {
+	k := setupKubernetesHealthChecks(t, &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "consul-server", Namespace: "consul", Generation: 1},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:       int32Ptr(3),
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType},
+		},
+		Status: appsv1.StatefulSetStatus{ObservedGeneration: 1, ReadyReplicas: 3, UpdatedReplicas: 3, CurrentRevision: "a", UpdateRevision: "a"},
+	})
+
+	err := k.HealthCheckStatefulSets(context.Background(), []string{"consul/consul-server"}, 10*time.Millisecond)
+	require.NoError(t, err)
+}
+
+func TestHealthCheckStatefulSetsWhenUpdatingTimesOut(t *testing.T) {
+	k := setupKubernetesHealthChecks(t, &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "consul-server", Namespace: "consul", Generation: 1},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:       int32Ptr(3),
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType},
+		},
+		Status: appsv1.StatefulSetStatus{ObservedGeneration: 1, ReadyReplicas: 3, UpdatedReplicas: 1, CurrentRevision: "a", UpdateRevision: "b"},
+	})
+
+	err := k.HealthCheckStatefulSets(context.Background(), []string{"consul/consul-server"}, 10*time.Millisecond)
+	require.Error(t, err)
+}
+
+func TestHealthCheckJobsWhenSucceeded(t *testing.T) {
+	k := setupKubernetesHealthChecks(t, &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "init", Namespace: "vault"},
+		Status:     batchv1.JobStatus{Succeeded: 1},
+	})
+
+	err := k.HealthCheckJobs(context.Background(), []string{"vault/init"}, 10*time.Millisecond)
+	require.NoError(t, err)
+}
+
+func TestHealthCheckJobsReturnsErrorWhenFailed(t *testing.T) {
+	k := setupKubernetesHealthChecks(t, &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "init", Namespace: "vault"},
+		Status: batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: v1.ConditionTrue, Message: "BackoffLimitExceeded"}},
+		},
+	})
+
+	err := k.HealthCheckJobs(context.Background(), []string{"vault/init"}, time.Minute)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "BackoffLimitExceeded")
+}
+
 const guestbookManifest = `
 apiVersion: v1
 kind: Service
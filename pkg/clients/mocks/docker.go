@@ -3,6 +3,7 @@ package mocks
 import (
 	"context"
 	"io"
+	"net"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -75,6 +76,12 @@ func (m *MockDocker) ContainerRemove(ctx context.Context, containerID string, op
 	return args.Error(0)
 }
 
+func (m *MockDocker) ContainerRename(ctx context.Context, containerID, newContainerName string) error {
+	args := m.Called(ctx, containerID, newContainerName)
+
+	return args.Error(0)
+}
+
 func (m *MockDocker) ContainerLogs(ctx context.Context, containerID string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
 	args := m.Called(ctx, containerID, options)
 
@@ -159,6 +166,16 @@ func (m *MockDocker) NetworkList(ctx context.Context, options types.NetworkListO
 	return nil, args.Error(1)
 }
 
+func (m *MockDocker) NetworkInspect(ctx context.Context, networkID string, options types.NetworkInspectOptions) (types.NetworkResource, error) {
+	args := m.Called(ctx, networkID, options)
+
+	if n, ok := args.Get(0).(types.NetworkResource); ok {
+		return n, args.Error(1)
+	}
+
+	return types.NetworkResource{}, args.Error(1)
+}
+
 func (m *MockDocker) NetworkCreate(ctx context.Context, name string, options types.NetworkCreate) (types.NetworkCreateResponse, error) {
 	args := m.Called(ctx, name, options)
 
@@ -253,6 +270,16 @@ func (m *MockDocker) ImageBuild(ctx context.Context, buildContext io.Reader, opt
 	return types.ImageBuildResponse{}, args.Error(1)
 }
 
+func (m *MockDocker) DialHijack(ctx context.Context, url, proto string, meta map[string][]string) (net.Conn, error) {
+	args := m.Called(ctx, url, proto, meta)
+
+	if c, ok := args.Get(0).(net.Conn); ok {
+		return c, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
 func (m *MockDocker) ServerVersion(ctx context.Context) (types.Version, error) {
 	args := m.Called(ctx)
 
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/stretchr/testify/mock"
 )
 
@@ -8,7 +10,7 @@ type MockHelm struct {
 	mock.Mock
 }
 
-func (h *MockHelm) Create(kubeConfig, name, namespace string, createNamespace bool, skipCRDs bool, chart, version, valuesPath string, valueString map[string]string) error {
+func (h *MockHelm) Create(ctx context.Context, kubeConfig, name, namespace string, createNamespace bool, skipCRDs bool, chart, version, valuesPath string, valueString map[string]string) error {
 	args := h.Called(kubeConfig, name, namespace, createNamespace, skipCRDs, chart, version, valuesPath, valueString)
 
 	return args.Error(0)
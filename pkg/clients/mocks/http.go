@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"bytes"
+	"context"
 	"io/ioutil"
 	"net/http"
 	"time"
@@ -15,12 +16,18 @@ type MockHTTP struct {
 	mock.Mock
 }
 
-func (m *MockHTTP) HealthCheckHTTP(uri string, codes []int, timeout time.Duration) error {
+func (m *MockHTTP) HealthCheckHTTP(ctx context.Context, uri string, codes []int, timeout time.Duration) error {
 	args := m.Called(uri, codes, timeout)
 
 	return args.Error(0)
 }
 
+func (m *MockHTTP) HealthCheckTCP(ctx context.Context, address string, timeout time.Duration) error {
+	args := m.Called(address, timeout)
+
+	return args.Error(0)
+}
+
 func (m *MockHTTP) Do(r *http.Request) (*http.Response, error) {
 	args := m.Called(r)
 
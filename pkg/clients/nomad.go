@@ -2,6 +2,7 @@ package clients
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -14,8 +15,10 @@ import (
 
 // Nomad defines an interface for a Nomad client
 type Nomad interface {
-	// SetConfig for the client, path is a valid Nomad JSON config file
-	SetConfig(address string, port, nodes int) error
+	// SetConfig returns a copy of the client configured for the Nomad cluster
+	// at the given address, the original client is not modified so it can be
+	// shared by providers for different clusters
+	SetConfig(address string, port, nodes int) (Nomad, error)
 	// Create jobs in the provided files
 	Create(files []string) error
 	// Stop jobs in the provided files
@@ -27,7 +30,7 @@ type Nomad interface {
 	// HealthCheckAPI uses the Nomad API to check that all servers and nodes
 	// are ready. The function will block until either all nodes are healthy or the
 	// timeout period elapses.
-	HealthCheckAPI(time.Duration) error
+	HealthCheckAPI(context.Context, time.Duration) error
 	// Endpoints returns a list of endpoints for a cluster
 	Endpoints(job, group, task string) ([]map[string]string, error)
 }
@@ -56,17 +59,19 @@ type createRequest struct {
 	Job string
 }
 
-// SetConfig loads the Nomad config from a file
-func (n *NomadImpl) SetConfig(address string, port, nodes int) error {
-	n.address = address
-	n.port = port
-	n.clientNodes = nodes
+// SetConfig for the Nomad cluster and clones the client
+func (n *NomadImpl) SetConfig(address string, port, nodes int) (Nomad, error) {
+	nc := NewNomad(n.httpClient, n.backoff, n.l).(*NomadImpl)
 
-	return nil
+	nc.address = address
+	nc.port = port
+	nc.clientNodes = nodes
+
+	return nc, nil
 }
 
 // HealthCheckAPI executes a HTTP heath check for a Nomad cluster
-func (n *NomadImpl) HealthCheckAPI(timeout time.Duration) error {
+func (n *NomadImpl) HealthCheckAPI(ctx context.Context, timeout time.Duration) error {
 	n.l.Debug("Performing Nomad health check", "address", n.address)
 	st := time.Now()
 	for {
@@ -76,7 +81,7 @@ func (n *NomadImpl) HealthCheckAPI(timeout time.Duration) error {
 			return fmt.Errorf("Timeout waiting for Nomad healthcheck %s", n.address)
 		}
 
-		rq, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s:%d/v1/nodes", n.address, n.port), nil)
+		rq, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s:%d/v1/nodes", n.address, n.port), nil)
 		if err != nil {
 			return err
 		}
@@ -139,7 +144,11 @@ func (n *NomadImpl) HealthCheckAPI(timeout time.Duration) error {
 		}
 
 		// backoff
-		time.Sleep(n.backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(n.backoff):
+		}
 	}
 }
 
@@ -1,9 +1,9 @@
-package mocks
+package clients
 
 import (
+	"context"
 	"time"
 
-	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -11,10 +11,10 @@ type MockNomad struct {
 	mock.Mock
 }
 
-func (m *MockNomad) SetConfig(c utils.ClusterConfig, ctx string) error {
-	args := m.Called(c, ctx)
+func (m *MockNomad) SetConfig(address string, port, nodes int) (Nomad, error) {
+	args := m.Called(address, port, nodes)
 
-	return args.Error(0)
+	return m, args.Error(0)
 }
 
 func (m *MockNomad) Create(files []string) error {
@@ -51,7 +51,7 @@ func (m *MockNomad) Endpoints(job, group, task string) ([]map[string]string, err
 	return nil, args.Error(1)
 }
 
-func (m *MockNomad) HealthCheckAPI(timeout time.Duration) error {
+func (m *MockNomad) HealthCheckAPI(ctx context.Context, timeout time.Duration) error {
 	args := m.Called(timeout)
 
 	return args.Error(0)
@@ -2,6 +2,7 @@ package clients
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -16,11 +17,7 @@ import (
 	assert "github.com/stretchr/testify/require"
 )
 
-func setupNomadClient() {
-
-}
-
-func setupNomadTests(t *testing.T) (utils.ClusterConfig, string, *mocks.MockHTTP) {
+func setupNomadTests(t *testing.T) *mocks.MockHTTP {
 	tmpDir := t.TempDir()
 
 	home := os.Getenv(utils.HomeEnvName())
@@ -38,14 +35,11 @@ func setupNomadTests(t *testing.T) (utils.ClusterConfig, string, *mocks.MockHTTP
 		nil,
 	)
 
-	clusterConfig, _ := utils.GetClusterConfig("nomad_cluster." + "testing")
-	clusterConfig.NodeCount = 2
-
-	return clusterConfig, tmpDir, mh
+	return mh
 }
 
 func TestNomadCreateReturnsErrorWhenFileNotExist(t *testing.T) {
-	_, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
 	err := c.Create([]string{"../../examples/nomad/example.nomad"})
@@ -53,10 +47,10 @@ func TestNomadCreateReturnsErrorWhenFileNotExist(t *testing.T) {
 }
 
 func TestNomadCreateValidatesConfig(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	err := c.Create([]string{"../../examples/nomad/app_config/example.nomad"})
 	assert.NoError(t, err)
@@ -65,33 +59,33 @@ func TestNomadCreateValidatesConfig(t *testing.T) {
 }
 
 func TestNomadCreateValidateErrorReturnsError(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("Boom"))
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	err := c.Create([]string{"../../examples/nomad/app_config/example.nomad"})
 	assert.Error(t, err)
 }
 
 func TestNomadCreateValidateNot200ReturnsError(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(&http.Response{StatusCode: http.StatusInternalServerError}, nil)
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	err := c.Create([]string{"../../examples/nomad/app_config/example.nomad"})
 	assert.Error(t, err)
 }
 
 func TestNomadCreateValidateInvalidReturnsError(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(
@@ -101,7 +95,7 @@ func TestNomadCreateValidateInvalidReturnsError(t *testing.T) {
 		}, nil)
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	err := c.Create([]string{"../../examples/nomad/app_config/example.nomad"})
 	assert.Error(t, err)
@@ -109,10 +103,10 @@ func TestNomadCreateValidateInvalidReturnsError(t *testing.T) {
 }
 
 func TestNomadCreateSubmitsJob(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	err := c.Create([]string{"../../examples/nomad/app_config/example.nomad"})
 	assert.NoError(t, err)
@@ -121,7 +115,7 @@ func TestNomadCreateSubmitsJob(t *testing.T) {
 }
 
 func TestNomadCreateSubmitErrorReturnsError(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(
@@ -135,14 +129,14 @@ func TestNomadCreateSubmitErrorReturnsError(t *testing.T) {
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("Boom")).Once()
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	err := c.Create([]string{"../../examples/nomad/app_config/example.nomad"})
 	assert.Error(t, err)
 }
 
 func TestNomadCreateSubmitNot200ReturnsError(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(
@@ -162,17 +156,17 @@ func TestNomadCreateSubmitNot200ReturnsError(t *testing.T) {
 	)
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	err := c.Create([]string{"../../examples/nomad/app_config/example.nomad"})
 	assert.Error(t, err)
 }
 
 func TestNomadStopValidatesConfig(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	err := c.Stop([]string{"../../examples/nomad/app_config/example.nomad"})
 	assert.NoError(t, err)
@@ -181,23 +175,23 @@ func TestNomadStopValidatesConfig(t *testing.T) {
 }
 
 func TestNomadStopValidateErrorReturnsError(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("Boom"))
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	err := c.Stop([]string{"../../examples/nomad/app_config/example.nomad"})
 	assert.Error(t, err)
 }
 
 func TestNomadStopStopsJob(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	err := c.Stop([]string{"../../examples/nomad/app_config/example.nomad"})
 	assert.NoError(t, err)
@@ -206,7 +200,7 @@ func TestNomadStopStopsJob(t *testing.T) {
 }
 
 func TestNomadStopErrorReturnsError(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(
@@ -219,14 +213,14 @@ func TestNomadStopErrorReturnsError(t *testing.T) {
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("boom"))
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	err := c.Stop([]string{"../../examples/nomad/app_config/example.nomad"})
 	assert.Error(t, err)
 }
 
 func TestNomadStopNoStatus200ReturnsError(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(
@@ -240,14 +234,14 @@ func TestNomadStopNoStatus200ReturnsError(t *testing.T) {
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(&http.Response{StatusCode: http.StatusInternalServerError}, nil)
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	err := c.Stop([]string{"../../examples/nomad/app_config/example.nomad"})
 	assert.Error(t, err)
 }
 
 func TestNomadJobStatusReturnsNoErrorOnRunning(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(
@@ -259,7 +253,7 @@ func TestNomadJobStatusReturnsNoErrorOnRunning(t *testing.T) {
 	)
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	s, err := c.JobRunning("test")
 	assert.NoError(t, err)
@@ -268,7 +262,7 @@ func TestNomadJobStatusReturnsNoErrorOnRunning(t *testing.T) {
 }
 
 func TestNomadJobStatusReturnsErrorWhenPending(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(
@@ -280,7 +274,7 @@ func TestNomadJobStatusReturnsErrorWhenPending(t *testing.T) {
 	)
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	s, err := c.JobRunning("test")
 	assert.NoError(t, err)
@@ -289,7 +283,7 @@ func TestNomadJobStatusReturnsErrorWhenPending(t *testing.T) {
 }
 
 func TestNomadHealthCallsAPI(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(
@@ -301,14 +295,14 @@ func TestNomadHealthCallsAPI(t *testing.T) {
 	).Once()
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
-	err := c.HealthCheckAPI(10 * time.Millisecond)
+	err := c.HealthCheckAPI(context.Background(), 10*time.Millisecond)
 	assert.NoError(t, err)
 }
 
 func TestNomadHealthWithNotReadyNodeRetries(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 
@@ -329,15 +323,15 @@ func TestNomadHealthWithNotReadyNodeRetries(t *testing.T) {
 	).Once()
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
-	err := c.HealthCheckAPI(10 * time.Millisecond)
+	err := c.HealthCheckAPI(context.Background(), 10*time.Millisecond)
 	assert.NoError(t, err)
 	mh.AssertNumberOfCalls(t, "Do", 2)
 }
 
 func TestNomadHealthWithNotReadyDockerRetries(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 
@@ -358,16 +352,16 @@ func TestNomadHealthWithNotReadyDockerRetries(t *testing.T) {
 	).Once()
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
-	err := c.HealthCheckAPI(10 * time.Millisecond)
+	err := c.HealthCheckAPI(context.Background(), 10*time.Millisecond)
 	assert.NoError(t, err)
 	mh.AssertNumberOfCalls(t, "Do", 2)
 
 }
 
 func TestNomadHealthErrorsOnClientError(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(
@@ -376,14 +370,14 @@ func TestNomadHealthErrorsOnClientError(t *testing.T) {
 	)
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
-	err := c.HealthCheckAPI(10 * time.Millisecond)
+	err := c.HealthCheckAPI(context.Background(), 10*time.Millisecond)
 	assert.Error(t, err)
 }
 
 func TestNomadEndpointsErrorWhenUnableToGetJobs(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(
@@ -394,14 +388,14 @@ func TestNomadEndpointsErrorWhenUnableToGetJobs(t *testing.T) {
 	)
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	_, err := c.Endpoints("test", "test", "test")
 	assert.Error(t, err)
 }
 
 func TestNomadEndpointsReturnsTwoEndpoints(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(
@@ -429,7 +423,7 @@ func TestNomadEndpointsReturnsTwoEndpoints(t *testing.T) {
 	).Once()
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	e, err := c.Endpoints("example_1", "fake_service", "fake_service")
 	assert.NoError(t, err)
@@ -440,7 +434,7 @@ func TestNomadEndpointsReturnsTwoEndpoints(t *testing.T) {
 }
 
 func TestNomadEndpointsReturnsRunningEndpoints(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(
@@ -460,7 +454,7 @@ func TestNomadEndpointsReturnsRunningEndpoints(t *testing.T) {
 	).Once()
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	e, err := c.Endpoints("example_1", "fake_service", "fake_service")
 	assert.NoError(t, err)
@@ -469,7 +463,7 @@ func TestNomadEndpointsReturnsRunningEndpoints(t *testing.T) {
 	assert.Equal(t, "10.5.0.2:28862", e[0]["http"])
 }
 func TestNomadEndpointsReturnsConnectEndpoints(t *testing.T) {
-	fp, _, mh := setupNomadTests(t)
+	mh := setupNomadTests(t)
 
 	removeOn(&mh.Mock, "Do")
 	mh.On("Do", mock.Anything, mock.Anything, mock.Anything).Return(
@@ -489,7 +483,7 @@ func TestNomadEndpointsReturnsConnectEndpoints(t *testing.T) {
 	).Once()
 
 	c := NewNomad(mh, 1*time.Millisecond, hclog.NewNullLogger())
-	c.SetConfig(fp, "local")
+	c, _ = c.SetConfig("http://localhost", 4646, 2)
 
 	e, err := c.Endpoints("web", "web", "web")
 	assert.NoError(t, err)
@@ -8,12 +8,18 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/docker/docker/pkg/fileutils"
 )
 
 type TarGzOptions struct {
 	// OmitRoot when set to true ignores the top level directory in the tar archive
 	// only adding sub directories and files.
 	OmitRoot bool
+
+	// Excludes is a list of .dockerignore style patterns, files and folders
+	// matching the patterns relative to the src path are not added to the archive
+	Excludes []string
 }
 
 type TarGz struct {
@@ -27,6 +33,11 @@ func (tg *TarGz) Compress(buf io.Writer, options *TarGzOptions, src ...string) e
 		options = &TarGzOptions{}
 	}
 
+	pm, err := fileutils.NewPatternMatcher(options.Excludes)
+	if err != nil {
+		return err
+	}
+
 	// tar > gzip > buf
 	zr := gzip.NewWriter(buf)
 	tw := tar.NewWriter(zr)
@@ -46,6 +57,22 @@ func (tg *TarGz) Compress(buf io.Writer, options *TarGzOptions, src ...string) e
 
 		// walk through every file in the folder
 		filepath.Walk(path, func(file string, fi os.FileInfo, err error) error {
+			// skip anything matching the exclude patterns
+			if rel, _ := filepath.Rel(path, file); len(options.Excludes) > 0 && rel != "." {
+				skip, err := pm.Matches(filepath.ToSlash(rel))
+				if err != nil {
+					return err
+				}
+
+				if skip {
+					if fi.IsDir() && !pm.Exclusions() {
+						return filepath.SkipDir
+					}
+
+					return nil
+				}
+			}
+
 			// generate tar header
 			header, err := tar.FileInfoHeader(fi, strings.Replace(file, topLevel, "", -1))
 			if err != nil {
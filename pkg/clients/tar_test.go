@@ -142,3 +142,28 @@ func TestTarDirAndIndividualFile(t *testing.T) {
 	require.FileExists(t, filepath.Join(out, "/solo.txt"))
 	require.FileExists(t, filepath.Join(out, "/sub/test3.txt"))
 }
+
+func TestTarWithExcludes(t *testing.T) {
+	dir := setupTarTests(t)
+
+	in := filepath.Join(dir, "in")
+	out := filepath.Join(dir, "out")
+
+	buf := bytes.NewBuffer(nil)
+
+	tg := &TarGz{}
+	opts := TarGzOptions{OmitRoot: true, Excludes: []string{"test*.txt", "!test2.txt", "sub"}}
+
+	// compress the directory
+	err := tg.Compress(buf, &opts, in)
+	require.NoError(t, err)
+
+	// test the output
+	err = tg.Uncompress(buf, out)
+	require.NoError(t, err)
+
+	require.NoFileExists(t, filepath.Join(out, "/test1.txt"))
+	require.FileExists(t, filepath.Join(out, "/test2.txt"))
+	require.DirExists(t, filepath.Join(out, "/empty"))
+	require.NoDirExists(t, filepath.Join(out, "/sub"))
+}
@@ -3,10 +3,18 @@ package resources
 import (
 	"fmt"
 	"net/url"
+
+	"github.com/Masterminds/semver"
+	"github.com/shipyard-run/hclconfig/types"
 )
 
+// TypeBlueprint is the resource which describes a blueprint
+const TypeBlueprint string = "blueprint"
+
 // Blueprint defines a stack blueprint for defining yard configs
 type Blueprint struct {
+	types.ResourceMetadata `hcl:",remain"`
+
 	Title              string            `hcl:"title,optional" json:"title,omitempty"`
 	Author             string            `hcl:"author,optional" json:"author,omitempty"`
 	Slug               string            `hcl:"slug,optional" json:"slug,omitempty"`
@@ -17,6 +25,21 @@ type Blueprint struct {
 	ShipyardVersion    string            `hcl:"shipyard_version,optional" json:"shipyard_version,omitempty"`
 }
 
+// Process validates the blueprint
+func (b *Blueprint) Process() error {
+	if errs := b.Validate(); len(errs) > 0 {
+		return errs[0]
+	}
+
+	if b.ShipyardVersion != "" {
+		if _, err := semver.NewConstraint(b.ShipyardVersion); err != nil {
+			return fmt.Errorf("invalid shipyard_version %s, the version must be a semantic version constraint: %s", b.ShipyardVersion, err)
+		}
+	}
+
+	return nil
+}
+
 // Validate the Blueprint and return errors
 func (b *Blueprint) Validate() []error {
 	errors := make([]error, 0)
@@ -28,6 +51,8 @@ func (b *Blueprint) Validate() []error {
 				errors,
 				fmt.Errorf("invalid BrowserWindow URI: %s, %s", i, err),
 			)
+
+			continue
 		}
 
 		if uri.String() == "" {
@@ -40,3 +65,28 @@ func (b *Blueprint) Validate() []error {
 
 	return errors
 }
+
+// CheckVersion returns an error when the given version of jumppad does not
+// satisfy the shipyard_version constraint. Development builds which do not
+// have a semantic version satisfy all constraints.
+func (b *Blueprint) CheckVersion(version string) error {
+	if b.ShipyardVersion == "" {
+		return nil
+	}
+
+	c, err := semver.NewConstraint(b.ShipyardVersion)
+	if err != nil {
+		return fmt.Errorf("invalid shipyard_version %s, the version must be a semantic version constraint: %s", b.ShipyardVersion, err)
+	}
+
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return nil
+	}
+
+	if !c.Check(v) {
+		return fmt.Errorf("this blueprint requires jumppad version %s, the installed version is %s", b.ShipyardVersion, version)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlueprintProcessWithInvalidVersionReturnsError(t *testing.T) {
+	b := &Blueprint{ShipyardVersion: "not a version"}
+
+	err := b.Process()
+	require.Error(t, err)
+}
+
+func TestBlueprintCheckVersionWithNoConstraintReturnsNil(t *testing.T) {
+	b := &Blueprint{}
+
+	require.NoError(t, b.CheckVersion("0.1.0"))
+}
+
+func TestBlueprintCheckVersionSatisfiesConstraint(t *testing.T) {
+	b := &Blueprint{ShipyardVersion: ">= 0.5.0"}
+
+	require.NoError(t, b.CheckVersion("0.5.1"))
+	require.NoError(t, b.CheckVersion("v0.6.0"))
+}
+
+func TestBlueprintCheckVersionReturnsErrorWhenNotSatisfied(t *testing.T) {
+	b := &Blueprint{ShipyardVersion: ">= 0.5.0"}
+
+	err := b.CheckVersion("0.4.9")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), ">= 0.5.0")
+}
+
+func TestBlueprintCheckVersionIgnoresDevelopmentBuilds(t *testing.T) {
+	b := &Blueprint{ShipyardVersion: ">= 0.5.0"}
+
+	require.NoError(t, b.CheckVersion("dev"))
+	require.NoError(t, b.CheckVersion(""))
+}
@@ -9,20 +9,24 @@ const TypeCertificateCA string = "certificate_ca"
 type CertificateCA struct {
 	types.ResourceMetadata `hcl:",remain"`
 
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
 	// Output directory to write the certificate and key too
 	Output string `hcl:"output" json:"output"`
 
 	// output parameters
 
 	// Key is the value related to the certificate key
-	PrivateKey *File `hcl:"private_key,block" json:"private_key"`
+	PrivateKey *File `hcl:"private_key,block" json:"private_key" state:"true"`
 
 	// Key is the value related to the certificate key
-	PublicKeyPEM *File `hcl:"public_key_pem,block" json:"public_key_pem"`
-	PublicKeySSH *File `hcl:"public_key_ssh,block" json:"public_key_ssh"`
+	PublicKeyPEM *File `hcl:"public_key_pem,block" json:"public_key_pem" state:"true"`
+	PublicKeySSH *File `hcl:"public_key_ssh,block" json:"public_key_ssh" state:"true"`
 
 	// Cert is the value related to the certificate
-	Cert *File `hcl:"certificate,block" json:"cert"`
+	Cert *File `hcl:"certificate,block" json:"cert" state:"true"`
 }
 
 func (c *CertificateCA) Process() error {
@@ -57,6 +61,10 @@ const TypeCertificateLeaf string = "certificate_leaf"
 type CertificateLeaf struct {
 	types.ResourceMetadata `hcl:",remain"`
 
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
 	CAKey  string `hcl:"ca_key" json:"ca_key"`   // Path to the primary key for the root CA
 	CACert string `hcl:"ca_cert" json:"ca_cert"` // Path to the root CA
 
@@ -68,14 +76,14 @@ type CertificateLeaf struct {
 	// output parameters
 
 	// Key is the value related to the certificate key
-	PrivateKey *File `hcl:"private_key,block" json:"private_key"`
+	PrivateKey *File `hcl:"private_key,block" json:"private_key" state:"true"`
 
 	// Key is the value related to the certificate key
-	PublicKeyPEM *File `hcl:"public_key_pem,block" json:"public_key_pem"`
-	PublicKeySSH *File `hcl:"public_key_ssh,block" json:"public_key_ssh"`
+	PublicKeyPEM *File `hcl:"public_key_pem,block" json:"public_key_pem" state:"true"`
+	PublicKeySSH *File `hcl:"public_key_ssh,block" json:"public_key_ssh" state:"true"`
 
 	// Cert is the value related to the certificate
-	Cert *File `hcl:"certificate,block" json:"cert"`
+	Cert *File `hcl:"certificate,block" json:"cert" state:"true"`
 }
 
 func (c *CertificateLeaf) Process() error {
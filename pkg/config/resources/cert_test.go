@@ -34,8 +34,8 @@ func TestCertCALoadsValuesFromState(t *testing.T) {
       "name": "test",
       "status": "created",
       "type": "certificate_ca",
-			"key_path": "mine.key",
-			"cert_path": "mine.cert"
+			"private_key": {"path": "mine.key"},
+			"cert": {"path": "mine.cert"}
 	}
 	]
 }`)
@@ -51,8 +51,8 @@ func TestCertCALoadsValuesFromState(t *testing.T) {
 	err := ca.Process()
 	require.NoError(t, err)
 
-	require.Equal(t, "mine.key", ca.KeyPath)
-	require.Equal(t, "mine.cert", ca.CertPath)
+	require.Equal(t, "mine.key", ca.PrivateKey.Path)
+	require.Equal(t, "mine.cert", ca.Cert.Path)
 }
 
 func TestCertLeafProcessSetsAbsoluteValues(t *testing.T) {
@@ -84,8 +84,8 @@ func TestCertLeafLoadsValuesFromState(t *testing.T) {
       "name": "test",
       "status": "created",
       "type": "certificate_leaf",
-			"key_path": "mine.key",
-			"cert_path": "mine.cert"
+			"private_key": {"path": "mine.key"},
+			"cert": {"path": "mine.cert"}
 	}
 	]
 }`)
@@ -101,6 +101,6 @@ func TestCertLeafLoadsValuesFromState(t *testing.T) {
 	err := ca.Process()
 	require.NoError(t, err)
 
-	require.Equal(t, "mine.key", ca.KeyPath)
-	require.Equal(t, "mine.cert", ca.CertPath)
+	require.Equal(t, "mine.key", ca.PrivateKey.Path)
+	require.Equal(t, "mine.cert", ca.Cert.Path)
 }
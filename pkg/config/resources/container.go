@@ -1,6 +1,13 @@
 package resources
 
 import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/shipyard-run/hclconfig/types"
 )
 
@@ -12,6 +19,10 @@ type Container struct {
 	// embedded type holding name, etc
 	types.ResourceMetadata `hcl:",remain"`
 
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
 	Networks        []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"`           // Attach to the correct network // only when Image is specified
 	Image           *Image              `hcl:"image,block" json:"image"`                          // Image to use for the container
 	Entrypoint      []string            `hcl:"entrypoint,optional" json:"entrypoint,omitempty"`   // entrypoint to use when starting the container
@@ -33,6 +44,19 @@ type Container struct {
 	// User block for mapping the user id and group id inside the container
 	RunAs *User `hcl:"run_as,block" json:"run_as,omitempty"`
 
+	// kernel and security options, these allow finer control than privileged
+	Hostname       string            `hcl:"hostname,optional" json:"hostname,omitempty"`                 // hostname for the container, defaults to the name of the resource
+	Labels         map[string]string `hcl:"labels,optional" json:"labels,omitempty"`                     // labels to add to the container
+	CapAdd         []string          `hcl:"cap_add,optional" json:"cap_add,omitempty"`                   // kernel capabilities to add to the container e.g. NET_ADMIN
+	CapDrop        []string          `hcl:"cap_drop,optional" json:"cap_drop,omitempty"`                 // kernel capabilities to remove from the container
+	Sysctls        map[string]string `hcl:"sysctls,optional" json:"sysctls,omitempty"`                   // namespaced kernel parameters to set in the container
+	Ulimits        []Ulimit          `hcl:"ulimit,block" json:"ulimits,omitempty"`                       // resource limits for the processes in the container
+	ShmSize        int               `hcl:"shm_size,optional" json:"shm_size,omitempty"`                 // size of /dev/shm in MB
+	Devices        []Device          `hcl:"device,block" json:"devices,omitempty"`                       // host devices to add to the container
+	SecurityOpt    []string          `hcl:"security_opt,optional" json:"security_opt,omitempty"`         // security options e.g. seccomp=unconfined, apparmor=profile
+	Init           bool              `hcl:"init,optional" json:"init,omitempty"`                         // run an init process inside the container to reap zombie processes
+	ReadOnlyRootfs bool              `hcl:"read_only_rootfs,optional" json:"read_only_rootfs,omitempty"` // mount the root filesystem of the container as read only
+
 	// Enables containers to be built on the fly
 	Build *Build `hcl:"build,block" json:"build"`
 
@@ -40,7 +64,7 @@ type Container struct {
 
 	// FQRN is the fully qualified domain name for the container, this can be used
 	// to access the container from other sources
-	FQRN string `hcl:"fqrn,optional" json:"fqrn,omitempty"`
+	FQRN string `hcl:"fqrn,optional" json:"fqrn,omitempty" state:"true"`
 }
 
 type User struct {
@@ -58,11 +82,11 @@ type NetworkAttachment struct {
 	// output
 
 	// Name will equal the name of the network as created by jumppad
-	Name string `hcl:"name,optional" json:"name,omitempty"`
+	Name string `hcl:"name,optional" json:"name,omitempty" state:"true"`
 
 	// AssignedAddress will equal if IPAddress is set, else it will be the value automatically
 	// assigned from the network
-	AssignedAddress string `hcl:"assigned_address,optional" json:"assigned_address,omitempty"`
+	AssignedAddress string `hcl:"assigned_address,optional" json:"assigned_address,omitempty" state:"true"`
 }
 
 // Resources allows the setting of resource constraints for the Container
@@ -72,6 +96,20 @@ type Resources struct {
 	Memory int   `hcl:"memory,optional" json:"memory,omitempty"`   // max memory the container can consume in MB
 }
 
+// Ulimit defines a resource limit for the processes in the Container
+type Ulimit struct {
+	Name string `hcl:"name" json:"name"` // name of the limit e.g. nofile, nproc, memlock
+	Soft int    `hcl:"soft" json:"soft"` // soft limit
+	Hard int    `hcl:"hard" json:"hard"` // hard limit, must be greater than or equal to the soft limit
+}
+
+// Device defines a device on the host to add to the Container
+type Device struct {
+	Source      string `hcl:"source" json:"source"`                              // path of the device on the host e.g. /dev/net/tun
+	Destination string `hcl:"destination,optional" json:"destination,omitempty"` // path of the device in the container, defaults to source
+	Permissions string `hcl:"permissions,optional" json:"permissions,omitempty"` // cgroup permissions for the device [r, w, m], defaults to rwm
+}
+
 // Volume defines a folder, Docker volume, or temp folder to mount to the Container
 type Volume struct {
 	Source                      string `hcl:"source" json:"source"`                                                                    // source path on the local machine for the volume
@@ -87,7 +125,86 @@ type Volume struct {
 type Build struct {
 	DockerFile string `hcl:"dockerfile,optional" json:"dockerfile,omitempty"` // Location of build file inside build context defaults to ./Dockerfile
 	Context    string `hcl:"context" json:"context"`                          // Path to build context
-	Tag        string `hcl:"tag,optional" json:"tag,omitempty"`               // Image tag, defaults to latest
+	Tag        string `hcl:"tag,optional" json:"tag,omitempty"`               // Image tag, defaults to a hash of the build context
+
+	Args      map[string]string `hcl:"args,optional" json:"args,omitempty"`             // Build arguments passed to the Dockerfile ARG instructions
+	Target    string            `hcl:"target,optional" json:"target,omitempty"`         // Stage in a multi-stage Dockerfile to build
+	Labels    map[string]string `hcl:"labels,optional" json:"labels,omitempty"`         // Labels to add to the image
+	CacheFrom []string          `hcl:"cache_from,optional" json:"cache_from,omitempty"` // Images to use as a cache source for the build
+
+	// Secrets to expose to RUN --mount=type=secret instructions, the key is
+	// the id of the secret and the value the path to a file containing the
+	// secret. Secrets are not stored in the image and require BuildKit.
+	Secrets map[string]string `hcl:"secrets,optional" json:"secrets,omitempty"`
+
+	// Checksum is a computed attribute and can not be set in the config. It
+	// is a hash of the files in the build context which are not excluded by
+	// .dockerignore and the build options, calculated by ComputeChecksum
+	// when the resource is planned or created. It is added to the resource
+	// checksum so that changing the sources rebuilds the image and recreates
+	// the container on the next up.
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// ImageTag returns the tag for the built image, when tag is not set the
+// tag is derived from the checksum of the build context
+func (b *Build) ImageTag() string {
+	if b.Tag != "" {
+		return b.Tag
+	}
+
+	if len(b.Checksum) > 12 {
+		return b.Checksum[:12]
+	}
+
+	return "latest"
+}
+
+// process makes the paths in the build absolute
+func (b *Build) process(file string) {
+	b.Context = ensureAbsolute(b.Context, file)
+
+	for k, v := range b.Secrets {
+		b.Secrets[k] = ensureAbsolute(v, file)
+	}
+}
+
+// ComputeChecksum returns a hash of the build context and the build options,
+// reading the build context is expensive so the result should be stored in
+// Checksum rather than calling this every time the checksum is needed
+func (b *Build) ComputeChecksum() (string, error) {
+	excludes, err := utils.DockerIgnorePatterns(b.Context, b.DockerFile)
+	if err != nil {
+		return "", err
+	}
+
+	cs, err := utils.BuildContextChecksum(b.Context, excludes)
+	if err != nil {
+		return "", err
+	}
+
+	// the cache sources do not change the image so are not part of the hash,
+	// only the ids of the secrets are used as the contents are not in the image
+	secrets := []string{}
+	for k := range b.Secrets {
+		secrets = append(secrets, k)
+	}
+	sort.Strings(secrets)
+
+	d, err := json.Marshal(map[string]interface{}{
+		"context":    cs,
+		"dockerfile": b.DockerFile,
+		"args":       b.Args,
+		"target":     b.Target,
+		"labels":     b.Labels,
+		"secrets":    secrets,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(d)), nil
 }
 
 func (c *Container) Process() error {
@@ -99,9 +216,14 @@ func (c *Container) Process() error {
 		}
 	}
 
+	err := validateKernelOptions(c.Ulimits, c.Devices)
+	if err != nil {
+		return fmt.Errorf("invalid config for container %s: %s", c.ID, err)
+	}
+
 	// make sure build paths are absolute
 	if c.Build != nil {
-		c.Build.Context = ensureAbsolute(c.Build.Context, c.File)
+		c.Build.process(c.File)
 	}
 
 	// do we have an existing resource in the state?
@@ -129,3 +251,20 @@ func (c *Container) Process() error {
 
 	return nil
 }
+
+// validateKernelOptions checks the ulimits and devices for a container or sidecar
+func validateKernelOptions(ulimits []Ulimit, devices []Device) error {
+	for _, u := range ulimits {
+		if u.Soft > u.Hard {
+			return fmt.Errorf("soft limit %d for ulimit %s is greater than the hard limit %d", u.Soft, u.Name, u.Hard)
+		}
+	}
+
+	for _, d := range devices {
+		if strings.Trim(d.Permissions, "rwm") != "" {
+			return fmt.Errorf("permissions %q for device %s must only contain r, w and m", d.Permissions, d.Source)
+		}
+	}
+
+	return nil
+}
@@ -1,7 +1,9 @@
 package resources
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/shipyard-run/hclconfig/types"
@@ -31,3 +33,94 @@ func TestContainerProcessSetsAbsolute(t *testing.T) {
 	require.Equal(t, wd, c.Volumes[0].Source)
 	require.Equal(t, wd, c.Build.Context)
 }
+
+func setupContainerBuild(t *testing.T) *Container {
+	dir := t.TempDir()
+	ioutil.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM alpine"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "main.hcl"), []byte(""), 0644)
+
+	return &Container{
+		ResourceMetadata: types.ResourceMetadata{File: filepath.Join(dir, "main.hcl")},
+		Build: &Build{
+			Context: "./",
+			Args:    map[string]string{"VERSION": "1.0"},
+			Secrets: map[string]string{"token": "./token"},
+		},
+	}
+}
+
+func TestContainerProcessSetsBuildPathsAbsolute(t *testing.T) {
+	c := setupContainerBuild(t)
+
+	err := c.Process()
+	require.NoError(t, err)
+
+	require.Empty(t, c.Build.Checksum)
+	require.Equal(t, filepath.Join(c.Build.Context, "token"), c.Build.Secrets["token"])
+}
+
+func TestBuildComputeChecksum(t *testing.T) {
+	c := setupContainerBuild(t)
+	c.Process()
+
+	cs, err := c.Build.ComputeChecksum()
+	require.NoError(t, err)
+	require.Len(t, cs, 64)
+
+	c.Build.Checksum = cs
+	require.Equal(t, cs[:12], c.Build.ImageTag())
+}
+
+func TestBuildComputeChecksumChangesWithSourcesAndArgs(t *testing.T) {
+	c := setupContainerBuild(t)
+	c.Process()
+
+	cs, err := c.Build.ComputeChecksum()
+	require.NoError(t, err)
+
+	c.Build.Args["VERSION"] = "2.0"
+	cs2, err := c.Build.ComputeChecksum()
+	require.NoError(t, err)
+	require.NotEqual(t, cs, cs2)
+
+	ioutil.WriteFile(filepath.Join(c.Build.Context, "main.go"), []byte("package main\n\nfunc main() {}"), 0644)
+	cs3, err := c.Build.ComputeChecksum()
+	require.NoError(t, err)
+	require.NotEqual(t, cs2, cs3)
+}
+
+func TestBuildComputeChecksumWithMissingContextReturnsError(t *testing.T) {
+	c := setupContainerBuild(t)
+	c.Build.Context = "./missing"
+	c.Process()
+
+	_, err := c.Build.ComputeChecksum()
+	require.Error(t, err)
+}
+
+func TestBuildImageTagUsesTagWhenSet(t *testing.T) {
+	b := &Build{Tag: "v1", Checksum: "0123456789abcdef"}
+
+	require.Equal(t, "v1", b.ImageTag())
+}
+
+func TestContainerProcessWithUlimitSoftGreaterThanHardReturnsError(t *testing.T) {
+	c := &Container{
+		Ulimits: []Ulimit{{Name: "nofile", Soft: 2048, Hard: 1024}},
+	}
+
+	err := c.Process()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "soft limit 2048 for ulimit nofile")
+}
+
+func TestContainerProcessWithInvalidDevicePermissionsReturnsError(t *testing.T) {
+	c := &Container{
+		Devices: []Device{{Source: "/dev/net/tun", Permissions: "rwx"}},
+	}
+
+	err := c.Process()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "must only contain r, w and m")
+}
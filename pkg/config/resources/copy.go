@@ -11,6 +11,10 @@ type Copy struct {
 	// embedded type holding name, etc
 	types.ResourceMetadata `hcl:",remain"`
 
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
 	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
 
 	Source      string `hcl:"source" json:"source"`                              // Source file, folder or glob
@@ -18,7 +22,7 @@ type Copy struct {
 	Permissions string `hcl:"permissions,optional" json:"permissions,omitempty"` // Permissions 0777 to set for written file
 
 	// outputs
-	CopiedFiles []string `hcl:"permissions,optional" json:"copied_files"`
+	CopiedFiles []string `hcl:"permissions,optional" json:"copied_files" state:"true"`
 }
 
 func (t *Copy) Process() error {
@@ -10,6 +10,10 @@ const TypeDocs string = "docs"
 type Docs struct {
 	types.ResourceMetadata `hcl:",remain"`
 
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
 	Networks []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // Attach to the correct network // only when Image is specified
 
 	Image *Image `hcl:"image,block" json:"image,omitempty"` // image to use for the container
@@ -23,7 +27,7 @@ type Docs struct {
 
 	// FQDN is the fully qualified domain name for the container, this can be used
 	// to access the container from other sources
-	FQDN string `hcl:"fqdn,optional" json:"fqdn,omitempty"`
+	FQDN string `hcl:"fqdn,optional" json:"fqdn,omitempty" state:"true"`
 }
 
 func (d *Docs) Process() error {
@@ -10,6 +10,9 @@ type LocalExec struct {
 	// embedded type holding name, etc
 	types.ResourceMetadata `hcl:",remain"`
 
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+
 	Command          []string          `hcl:"command,optional" json:"command,omitempty"`                     // Command to execute
 	WorkingDirectory string            `hcl:"working_directory,optional" json:"working_directory,omitempty"` // Working directory to execute commands
 	Daemon           bool              `hcl:"daemon,optional" json:"daemon,omitempty"`                       // Should the process run as a daemon
@@ -19,7 +22,7 @@ type LocalExec struct {
 	// output
 
 	// Pid stores the ID of the created connector service
-	Pid int `hcl:"pid,optional" json:"pid,omitempty"`
+	Pid int `hcl:"pid,optional" json:"pid,omitempty" state:"true"`
 }
 
 func (e *LocalExec) Process() error {
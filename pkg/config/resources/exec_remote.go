@@ -9,6 +9,10 @@ const TypeRemoteExec string = "remote_exec"
 type RemoteExec struct {
 	types.ResourceMetadata `hcl:",remain"`
 
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
 	Networks []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // Attach to the correct network // only when Image is specified
 
 	// Either Image or Target must be specified
@@ -8,8 +8,20 @@ package resources
 //	http_success_codes  = [200,429]                                                      // https status codes that signal the health of the endpoint
 //	tcp      		        = "consul-consul:8500"                                           // can a TCP connection be made
 //	services 		        = ["consul-consul"]                                              // does service exist and there are endpoints
+//	deployments         = ["consul/consul-connect-injector"]                             // has the deployment rollout completed
+//	stateful_sets       = ["consul/consul-server"]                                       // has the stateful set rollout completed
+//	jobs                = ["vault/vault-init"]                                           // has the job succeeded
 //	pods     		        = ["component=server,app=consul", "component=client,app=consul"] // is the pod running and healthy
 //	nomad_jobs          = ["redis"] 																										   // are the Nomad jobs running and healthy
+//	exec                = ["pg_isready", "-U", "postgres"]                               // does the command exit with code 0 when run in the container
+//	docker_healthcheck  = true                                                           // is the Docker HEALTHCHECK defined by the image healthy
+//
+// When more than one check is defined all checks must pass, containers and
+// sidecars share the timeout between all checks, other resources apply the
+// timeout to each check. exec and docker_healthcheck are only supported by
+// containers and sidecars.
+// Kubernetes objects are referenced as "namespace/name", when the namespace is
+// omitted the namespace of the helm chart or "default" is used.
 type HealthCheck struct {
 	Timeout          string   `hcl:"timeout" json:"timeout"`
 	HTTP             string   `hcl:"http,optional" json:"http,omitempty"`
@@ -17,5 +29,10 @@ type HealthCheck struct {
 	TCP              string   `hcl:"tcp,optional" json:"tcp,omitempty"`
 	Services         []string `hcl:"services,optional" json:"services,omitempty"`
 	Pods             []string `hcl:"pods,optional" json:"pods,omitempty"`
+	Deployments      []string `hcl:"deployments,optional" json:"deployments,omitempty"`
+	StatefulSets     []string `hcl:"stateful_sets,optional" json:"stateful_sets,omitempty"`
+	Jobs             []string `hcl:"jobs,optional" json:"jobs,omitempty"`
 	NomadJobs        []string `hcl:"nomad_jobs,optional" json:"nomad_jobs,omitempty" mapstructure:"nomad_jobs"`
+	Exec             []string `hcl:"exec,optional" json:"exec,omitempty"`
+	DockerHealth     bool     `hcl:"docker_healthcheck,optional" json:"docker_healthcheck,omitempty"`
 }
@@ -12,6 +12,8 @@ const TypeHelm string = "helm"
 type Helm struct {
 	types.ResourceMetadata `hcl:",remain"`
 
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+
 	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
 
 	Cluster string `hcl:"cluster" json:"cluster"`
@@ -37,10 +39,11 @@ type Helm struct {
 	// Skip the install of any CRDs
 	SkipCRDs bool `hcl:"skip_crds,optional" json:"skip_crds,omitempty"`
 
-	// Retry the install n number of times
+	// Retry the install n number of times, retries are handled by the engine
 	Retry int `hcl:"retry,optional" json:"retry,omitempty"`
 
-	// Timeout specifies the maximum time a chart can run, default 300s
+	// Timeout specifies the maximum time to create the chart including the
+	// health check, when not set the chart install is limited to 300s
 	Timeout string `hcl:"timeout,optional" json:"timeout"`
 
 	HealthCheck *HealthCheck `hcl:"health_check,block" json:"health_check,omitempty"`
@@ -19,6 +19,10 @@ const (
 type Ingress struct {
 	types.ResourceMetadata `hcl:",remain"`
 
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
 	// local port to expose the service on
 	Port int `hcl:"port" json:"port"`
 
@@ -28,10 +32,10 @@ type Ingress struct {
 	// --- Output Params ----
 
 	// IngressId stores the ID of the created connector service
-	IngressID string `hcl:"ingress_id,optional" json:"ingress_id,omitempty"`
+	IngressID string `hcl:"ingress_id,optional" json:"ingress_id,omitempty" state:"true"`
 
 	// Address is the fully qualified uri for accessing the resource
-	Address string `hcl:"address,optional" json:"address,omitempty"`
+	Address string `hcl:"address,optional" json:"address,omitempty" state:"true"`
 }
 
 // Traffic defines either a source or a destination block for ingress traffic
@@ -34,3 +34,17 @@ func TestIngressSetsOutputsFromState(t *testing.T) {
 	require.Equal(t, "42", c.IngressID)
 	require.Equal(t, "127.0.0.1", c.Address)
 }
+
+func TestIngressProcessErrorsWhenNameReserved(t *testing.T) {
+	c := &Ingress{ResourceMetadata: types.ResourceMetadata{Name: "connector"}}
+
+	require.Error(t, c.Process())
+}
+
+func TestIngressProcessErrorsWhenPortReserved(t *testing.T) {
+	for _, p := range []int{60000, 60001} {
+		c := &Ingress{ResourceMetadata: types.ResourceMetadata{Name: "test"}, Port: p}
+
+		require.Error(t, c.Process())
+	}
+}
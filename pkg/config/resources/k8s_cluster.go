@@ -14,6 +14,10 @@ type K8sCluster struct {
 	// embedded type holding name, etc.
 	types.ResourceMetadata `hcl:",remain"`
 
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
 	Networks []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // Attach to the correct network // only when Image is specified
 
 	Image   *Image   `hcl:"image,block" json:"images,omitempty"` // optional image to use when creating the cluster
@@ -31,21 +35,21 @@ type K8sCluster struct {
 	// output parameters
 
 	// Path to the Kubernetes config
-	KubeConfig string `hcl:"kubeconfig,optional" json:"kubeconfig,omitempty"`
+	KubeConfig string `hcl:"kubeconfig,optional" json:"kubeconfig,omitempty" state:"true"`
 
 	// Port the API server is running on
-	APIPort int `hcl:"api_port,optional" json:"api_port,omitempty"`
+	APIPort int `hcl:"api_port,optional" json:"api_port,omitempty" state:"true"`
 
 	// Port the connector is running on
-	ConnectorPort int `hcl:"connector_port,optional" json:"connector_port,omitempty"`
+	ConnectorPort int `hcl:"connector_port,optional" json:"connector_port,omitempty" state:"true"`
 
 	// Fully qualified domain name for the container, this address can be
 	// used to reference the container within docker and from other containers
-	FQRN string `hcl:"fqrn,optional" json:"fqrn,omitempty"`
+	FQRN string `hcl:"fqrn,optional" json:"fqrn,omitempty" state:"true"`
 
 	// ExternalIP is the ip address of the cluster, this generally resolves
 	// to the docker ip
-	ExternalIP string `hcl:"external_ip,optional" json:"external_ip,omitempty"`
+	ExternalIP string `hcl:"external_ip,optional" json:"external_ip,omitempty" state:"true"`
 }
 
 const k3sBaseImage = "shipyardrun/k3s"
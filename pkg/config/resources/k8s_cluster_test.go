@@ -41,7 +41,7 @@ func TestK8sClusterSetsOutputsFromState(t *testing.T) {
 			"api_port": 123,
 			"connector_port": 124,
 			"kubeconfig": "./mine.yaml",
-			"fqdn": "fqdn.mine.com",
+			"fqrn": "fqdn.mine.com",
 			"networks": [{
 				"assigned_address": "10.5.0.2",
 				"name": "cloud"
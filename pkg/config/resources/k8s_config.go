@@ -9,6 +9,10 @@ const TypeK8sConfig string = "k8s_config"
 type K8sConfig struct {
 	types.ResourceMetadata `hcl:",remain"`
 
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
 	// Cluster is the name of the cluster to apply configuration to
 	Cluster string `hcl:"cluster" json:"cluster"`
 	// Path of a file or directory of Kubernetes config files to apply
@@ -0,0 +1,116 @@
+package resources
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shipyard-run/hclconfig/types"
+)
+
+const (
+	// HookOnFailureFail stops the creation or destruction of the resource
+	// when the hook fails
+	HookOnFailureFail = "fail"
+
+	// HookOnFailureContinue logs the failure of the hook and continues to
+	// create or destroy the resource
+	HookOnFailureContinue = "continue"
+)
+
+// Lifecycle defines the hooks which are run when a resource is created or
+// destroyed and how changes to the resource are handled, the lifecycle block
+// can be added to any resource
+//
+//	lifecycle {
+//	  prevent_destroy = true
+//	  ignore_changes  = ["environment", "image.name"]
+//	  wait_for_healthy = true
+//
+//	  after_create {
+//	    command = ["consul", "kv", "put", "config", "true"]
+//	    target  = "resource.container.consul"
+//	  }
+//	}
+type Lifecycle struct {
+	// PreventDestroy causes destroy and re-creation of the resource to fail
+	// unless forced
+	PreventDestroy bool `hcl:"prevent_destroy,optional" json:"prevent_destroy,omitempty"`
+
+	// IgnoreChanges is a list of attributes which are not used to detect
+	// changes to the resource, nested attributes are separated by a dot
+	IgnoreChanges []string `hcl:"ignore_changes,optional" json:"ignore_changes,omitempty"`
+
+	// CreateBeforeDestroy creates the replacement before the existing
+	// resource is removed, only supported by container and sidecar resources
+	CreateBeforeDestroy bool `hcl:"create_before_destroy,optional" json:"create_before_destroy,omitempty"`
+
+	// WaitForHealthy re-runs the health_check of the resource before any
+	// resource which depends on it is created, even when the resource itself
+	// is unchanged. Supported by resources which define a health_check.
+	WaitForHealthy bool `hcl:"wait_for_healthy,optional" json:"wait_for_healthy,omitempty"`
+
+	BeforeCreate  []LifecycleHook `hcl:"before_create,block" json:"before_create,omitempty"`
+	AfterCreate   []LifecycleHook `hcl:"after_create,block" json:"after_create,omitempty"`
+	BeforeDestroy []LifecycleHook `hcl:"before_destroy,block" json:"before_destroy,omitempty"`
+	AfterDestroy  []LifecycleHook `hcl:"after_destroy,block" json:"after_destroy,omitempty"`
+}
+
+// LifecycleHook defines a command which is executed on the local machine or,
+// when Target is set, inside a running container
+type LifecycleHook struct {
+	// Command to execute, the first element is the executable
+	Command []string `hcl:"command" json:"command"`
+
+	// Target is the id of a container, sidecar, k8s_cluster or nomad_cluster
+	// resource, when set the command is executed inside the container
+	Target string `hcl:"target,optional" json:"target,omitempty"`
+
+	WorkingDirectory string            `hcl:"working_directory,optional" json:"working_directory,omitempty"`
+	Environment      map[string]string `hcl:"environment,optional" json:"environment,omitempty"`
+
+	// Timeout is the maximum time the command can run, e.g. 30s, default 300s
+	Timeout string `hcl:"timeout,optional" json:"timeout,omitempty"`
+
+	// OnFailure defines the behaviour when the command fails, either "fail",
+	// the default, or "continue"
+	OnFailure string `hcl:"on_failure,optional" json:"on_failure,omitempty"`
+}
+
+// Validate the hook and return an error when it is not valid
+func (h *LifecycleHook) Validate() error {
+	if len(h.Command) == 0 {
+		return fmt.Errorf("command must be specified")
+	}
+
+	if h.Timeout != "" {
+		if _, err := time.ParseDuration(h.Timeout); err != nil {
+			return fmt.Errorf("unable to parse timeout %s: %s", h.Timeout, err)
+		}
+	}
+
+	if h.OnFailure != "" && h.OnFailure != HookOnFailureFail && h.OnFailure != HookOnFailureContinue {
+		return fmt.Errorf("on_failure must be either %s or %s", HookOnFailureFail, HookOnFailureContinue)
+	}
+
+	if h.Target != "" {
+		fqrn, err := types.ParseFQRN(h.Target)
+		if err != nil || fqrn.Resource == "" {
+			return fmt.Errorf("target %s is not a valid resource id", h.Target)
+		}
+	}
+
+	return nil
+}
+
+// LifecycleForResource returns the lifecycle block for the given resource,
+// nil is returned when the resource does not define a lifecycle
+func LifecycleForResource(r types.Resource) *Lifecycle {
+	f := resourceField(r, "Lifecycle")
+	if !f.IsValid() {
+		return nil
+	}
+
+	l, _ := f.Interface().(*Lifecycle)
+
+	return l
+}
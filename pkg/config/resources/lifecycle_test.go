@@ -0,0 +1,28 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycleHookValidateWithCommandReturnsNil(t *testing.T) {
+	h := LifecycleHook{Command: []string{"ls"}, Target: "resource.container.consul", Timeout: "30s", OnFailure: HookOnFailureContinue}
+
+	require.NoError(t, h.Validate())
+}
+
+func TestLifecycleHookValidateWithInvalidHookReturnsError(t *testing.T) {
+	require.Error(t, (&LifecycleHook{}).Validate())
+	require.Error(t, (&LifecycleHook{Command: []string{"ls"}, Timeout: "abc"}).Validate())
+	require.Error(t, (&LifecycleHook{Command: []string{"ls"}, OnFailure: "retry"}).Validate())
+	require.Error(t, (&LifecycleHook{Command: []string{"ls"}, Target: "container"}).Validate())
+}
+
+func TestLifecycleForResourceReturnsLifecycle(t *testing.T) {
+	l := &Lifecycle{}
+
+	require.Equal(t, l, LifecycleForResource(&Container{Lifecycle: l}))
+	require.Nil(t, LifecycleForResource(&Container{}))
+	require.Nil(t, LifecycleForResource(&Blueprint{}))
+}
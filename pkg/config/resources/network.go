@@ -10,6 +10,10 @@ type Network struct {
 	// embedded type holding name, etc
 	types.ResourceMetadata `hcl:",remain"`
 
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
 	Subnet string `hcl:"subnet" json:"subnet"`
 }
 
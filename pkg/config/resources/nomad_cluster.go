@@ -14,6 +14,10 @@ type NomadCluster struct {
 	// embedded type holding name, etc
 	types.ResourceMetadata `hcl:",remain"`
 
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
 	Networks      []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // Attach to the correct network // only when Image is specified
 	Image         *Image              `hcl:"image,block" json:"images,omitempty"`     // optional image to use for the cluster
 	ClientNodes   int                 `hcl:"client_nodes,optional" json:"client_nodes,omitempty"`
@@ -34,23 +38,23 @@ type NomadCluster struct {
 	// Output Parameters
 
 	// The APIPort the server is running on
-	APIPort int `hcl:"api_port,optional" json:"api_port,omitempty"`
+	APIPort int `hcl:"api_port,optional" json:"api_port,omitempty" state:"true"`
 
 	// The Port where the connector is running
-	ConnectorPort int `hcl:"connector_port,optional" json:"connector_port,omitempty"`
+	ConnectorPort int `hcl:"connector_port,optional" json:"connector_port,omitempty" state:"true"`
 
 	// The directory where the server and client config is written to
-	ConfigDir string `hcl:"config_dir,optional" json:"config_dir,omitempty"`
+	ConfigDir string `hcl:"config_dir,optional" json:"config_dir,omitempty" state:"true"`
 
 	// The fully qualified docker address for the server
-	ServerFQRN string `hcl:"server_fqrn,optional" json:"server_fqrn,omitempty"`
+	ServerFQRN string `hcl:"server_fqrn,optional" json:"server_fqrn,omitempty" state:"true"`
 
 	// The fully qualified docker address for the client nodes
-	ClientFQRN []string `hcl:"client_fqrn,optional" json:"client_fqrn,omitempty"`
+	ClientFQRN []string `hcl:"client_fqrn,optional" json:"client_fqrn,omitempty" state:"true"`
 
 	// ExternalIP is the ip address of the cluster, this generally resolves
 	// to the docker ip
-	ExternalIP string `hcl:"external_ip,optional" json:"external_ip,omitempty"`
+	ExternalIP string `hcl:"external_ip,optional" json:"external_ip,omitempty" state:"true"`
 }
 
 const nomadBaseImage = "shipyardrun/nomad"
@@ -49,8 +49,8 @@ func TestNomadClusterSetsOutputsFromState(t *testing.T) {
 			"api_port": 123,
 			"connector_port": 124,
 			"external_ip": "127.0.0.1",
-			"server_fqdn": "server.something.something",
-			"client_fqdn": ["1.client.something.something","2.client.something.something"],
+			"server_fqrn": "server.something.something",
+			"client_fqrn": ["1.client.something.something","2.client.something.something"],
 			"config_dir": "abc/123"
 	}
 	]
@@ -65,8 +65,8 @@ func TestNomadClusterSetsOutputsFromState(t *testing.T) {
 	c.Process()
 
 	require.Equal(t, "127.0.0.1", c.ExternalIP)
-	require.Equal(t, "server.something.something", c.ServerFQDN)
-	require.Equal(t, []string{"1.client.something.something", "2.client.something.something"}, c.ClientFQDN)
+	require.Equal(t, "server.something.something", c.ServerFQRN)
+	require.Equal(t, []string{"1.client.something.something", "2.client.something.something"}, c.ClientFQRN)
 	require.Equal(t, 123, c.APIPort)
 	require.Equal(t, 124, c.ConnectorPort)
 	require.Equal(t, "abc/123", c.ConfigDir)
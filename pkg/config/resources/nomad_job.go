@@ -10,6 +10,10 @@ type NomadJob struct {
 	// embedded type holding name, etc
 	types.ResourceMetadata `hcl:",remain"`
 
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
 	// Cluster is the name of the cluster to apply configuration to
 	Cluster string `hcl:"cluster" json:"cluster"`
 
@@ -0,0 +1,176 @@
+package resources
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/jumppad-labs/jumppad/pkg/plugins"
+	"github.com/shipyard-run/hclconfig/types"
+)
+
+// Plugin is a resource provided by an external plugin, the type of the
+// resource is one of the types advertised by the plugin e.g.
+//
+//	resource "service_mock" "payments" {
+//	  config = {
+//	    port = 9090
+//	  }
+//	}
+//
+// Plugin resources have the following limitations:
+//
+//   - attributes can only be set in the config map, number and bool values
+//     are validated against the schema but are sent to the plugin as
+//     strings which the plugin must parse
+//   - outputs are stored in the state but can not be interpolated by other
+//     resources, e.g. resource.service_mock.payments.outputs.address, the
+//     parser only resolves references to struct fields with a fixed type
+//     and the outputs of a plugin are a map
+type Plugin struct {
+	types.ResourceMetadata `hcl:",remain"`
+
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
+	// Config for the resource, the attributes are defined by the plugin
+	Config map[string]string `hcl:"config,optional" json:"config,omitempty"`
+
+	// Output parameters
+
+	// Outputs returned by the plugin when the resource is created, the
+	// outputs are stored in the state and shown by 'jumppad state show'
+	// but can not be interpolated by other resources, see Plugin
+	Outputs map[string]string `hcl:"outputs,optional" json:"outputs,omitempty" state:"true"`
+}
+
+var pluginTypes = map[string]plugins.Schema{}
+var pluginTypesLock sync.Mutex
+
+var pluginLoader func()
+var pluginLoaderOnce = &sync.Once{}
+var pluginLoaderLock sync.Mutex
+
+// SetPluginLoader sets the function which starts the plugins and registers
+// the resource types they provide. Starting the plugins is deferred until a
+// configuration or the state is first parsed so that commands which do not
+// parse any configuration do not start the plugins.
+func SetPluginLoader(fn func()) {
+	pluginLoaderLock.Lock()
+	defer pluginLoaderLock.Unlock()
+
+	pluginLoader = fn
+	pluginLoaderOnce = &sync.Once{}
+}
+
+// loadPlugins calls the plugin loader the first time it is called
+func loadPlugins() {
+	pluginLoaderLock.Lock()
+	fn := pluginLoader
+	once := pluginLoaderOnce
+	pluginLoaderLock.Unlock()
+
+	if fn != nil {
+		once.Do(fn)
+	}
+}
+
+// RegisterPluginType registers a resource type provided by a plugin so that
+// it can be used in the configuration
+func RegisterPluginType(s plugins.Schema) error {
+	if _, ok := builtinTypes()[s.Type]; ok {
+		return fmt.Errorf("resource type %s is provided by jumppad and can not be provided by a plugin", s.Type)
+	}
+
+	pluginTypesLock.Lock()
+	defer pluginTypesLock.Unlock()
+
+	pluginTypes[s.Type] = s
+
+	return nil
+}
+
+// PluginTypes returns the resource types provided by plugins sorted by type
+func PluginTypes() []plugins.Schema {
+	pluginTypesLock.Lock()
+	defer pluginTypesLock.Unlock()
+
+	s := []plugins.Schema{}
+	for _, v := range pluginTypes {
+		s = append(s, v)
+	}
+
+	sort.Slice(s, func(i, j int) bool {
+		return s[i].Type < s[j].Type
+	})
+
+	return s
+}
+
+func pluginSchema(t string) (plugins.Schema, bool) {
+	pluginTypesLock.Lock()
+	defer pluginTypesLock.Unlock()
+
+	s, ok := pluginTypes[t]
+	return s, ok
+}
+
+func (p *Plugin) Process() error {
+	s, ok := pluginSchema(p.Type)
+	if !ok {
+		return fmt.Errorf("resource type %s is not provided by any plugin", p.Type)
+	}
+
+	err := p.validateConfig(s)
+	if err != nil {
+		return err
+	}
+
+	// do we have an existing resource in the state?
+	// if so we need to set any computed resources for dependents
+	cfg, err := LoadState()
+	if err == nil {
+		// try and find the resource in the state
+		r, _ := cfg.FindResource(p.ID)
+		if r != nil {
+			state := r.(*Plugin)
+			p.Outputs = state.Outputs
+		}
+	}
+
+	return nil
+}
+
+// validateConfig checks the config against the schema from the plugin
+func (p *Plugin) validateConfig(s plugins.Schema) error {
+	attrs := map[string]plugins.Attribute{}
+	for _, a := range s.Attributes {
+		attrs[a.Name] = a
+
+		if _, ok := p.Config[a.Name]; a.Required && !ok {
+			return fmt.Errorf("config attribute %s is required for resource %s", a.Name, p.ID)
+		}
+	}
+
+	for k, v := range p.Config {
+		a, ok := attrs[k]
+		if !ok {
+			return fmt.Errorf("config attribute %s is not supported by resource type %s", k, p.Type)
+		}
+
+		switch a.Type {
+		case plugins.AttributeTypeNumber:
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				return fmt.Errorf("config attribute %s for resource %s must be a number, got %q", k, p.ID, v)
+			}
+		case plugins.AttributeTypeBool:
+			if _, err := strconv.ParseBool(v); err != nil {
+				return fmt.Errorf("config attribute %s for resource %s must be a bool, got %q", k, p.ID, v)
+			}
+		}
+	}
+
+	return nil
+}
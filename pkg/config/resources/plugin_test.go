@@ -0,0 +1,183 @@
+package resources
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/plugins"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPluginType(t *testing.T) {
+	err := RegisterPluginType(plugins.Schema{
+		Type: "service_mock",
+		Attributes: []plugins.Attribute{
+			{Name: "port", Type: plugins.AttributeTypeNumber, Required: true},
+			{Name: "verbose", Type: plugins.AttributeTypeBool},
+			{Name: "name"},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		pluginTypesLock.Lock()
+		defer pluginTypesLock.Unlock()
+
+		delete(pluginTypes, "service_mock")
+	})
+}
+
+func parsePluginConfig(t *testing.T, config string) error {
+	setupState(t, "")
+
+	f := filepath.Join(t.TempDir(), "main.hcl")
+	err := ioutil.WriteFile(f, []byte(config), 0644)
+	require.NoError(t, err)
+
+	p := SetupHCLConfig(nil, nil, nil)
+	_, err = p.ParseFile(f)
+
+	return err
+}
+
+func TestPluginLoaderIsCalledOnceWhenConfigIsFirstParsed(t *testing.T) {
+	calls := 0
+	SetPluginLoader(func() {
+		calls++
+		setupPluginType(t)
+	})
+	t.Cleanup(func() { SetPluginLoader(nil) })
+
+	require.Equal(t, 0, calls)
+
+	err := parsePluginConfig(t, `
+resource "service_mock" "payments" {
+  config = {
+    port = 9090
+  }
+}
+`)
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+
+	SetupHCLConfig(nil, nil, nil)
+	require.Equal(t, 1, calls)
+}
+
+func TestRegisterPluginTypeWithBuiltinTypeReturnsError(t *testing.T) {
+	err := RegisterPluginType(plugins.Schema{Type: TypeContainer})
+	require.Error(t, err)
+
+	err = RegisterPluginType(plugins.Schema{Type: "module"})
+	require.Error(t, err)
+}
+
+func TestPluginResourceIsParsed(t *testing.T) {
+	setupPluginType(t)
+
+	require.Equal(t, "service_mock", PluginTypes()[0].Type)
+
+	setupState(t, "")
+
+	f := filepath.Join(t.TempDir(), "main.hcl")
+	err := ioutil.WriteFile(f, []byte(`
+resource "service_mock" "payments" {
+  config = {
+    port    = 9090
+    verbose = true
+    name    = "payments"
+  }
+}
+`), 0644)
+	require.NoError(t, err)
+
+	c, err := SetupHCLConfig(nil, nil, nil).ParseFile(f)
+	require.NoError(t, err)
+
+	r, err := c.FindResource("resource.service_mock.payments")
+	require.NoError(t, err)
+
+	p := r.(*Plugin)
+	require.Equal(t, "service_mock", p.Type)
+	require.Equal(t, "9090", p.Config["port"])
+	require.Equal(t, "true", p.Config["verbose"])
+}
+
+func TestPluginResourceWithMissingRequiredAttributeReturnsError(t *testing.T) {
+	setupPluginType(t)
+
+	err := parsePluginConfig(t, `
+resource "service_mock" "payments" {
+  config = {
+    name = "payments"
+  }
+}
+`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "config attribute port")
+}
+
+func TestPluginResourceWithUnknownAttributeReturnsError(t *testing.T) {
+	setupPluginType(t)
+
+	err := parsePluginConfig(t, `
+resource "service_mock" "payments" {
+  config = {
+    port  = 9090
+    image = "mock"
+  }
+}
+`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "image is not supported")
+}
+
+func TestPluginResourceWithInvalidTypeReturnsError(t *testing.T) {
+	setupPluginType(t)
+
+	err := parsePluginConfig(t, `
+resource "service_mock" "payments" {
+  config = {
+    port = "abc"
+  }
+}
+`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "must be a number")
+}
+
+func TestPluginResourceSetsOutputsFromState(t *testing.T) {
+	setupPluginType(t)
+
+	setupState(t, `
+{
+  "blueprint": null,
+  "resources": [
+	{
+      "id": "resource.service_mock.payments",
+      "name": "payments",
+      "type": "service_mock",
+      "config": {"port": "9090"},
+      "outputs": {"address": "localhost:9090"}
+	}
+  ]
+}`)
+
+	f := filepath.Join(t.TempDir(), "main.hcl")
+	err := ioutil.WriteFile(f, []byte(`
+resource "service_mock" "payments" {
+  config = {
+    port = 9090
+  }
+}
+`), 0644)
+	require.NoError(t, err)
+
+	c, err := SetupHCLConfig(nil, nil, nil).ParseFile(f)
+	require.NoError(t, err)
+
+	r, err := c.FindResource("resource.service_mock.payments")
+	require.NoError(t, err)
+	require.Equal(t, "localhost:9090", r.(*Plugin).Outputs["address"])
+}
@@ -9,11 +9,15 @@ const TypeRandomNumber string = "random_number"
 type RandomNumber struct {
 	types.ResourceMetadata `hcl:",remain"`
 
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
 	Minimum int `hcl:"minimum" json:"minimum"`
 	Maximum int `hcl:"maximum" json:"maximum"`
 
 	// Output parameters
-	Value int `hcl:"value,optional" json:"value"`
+	Value int `hcl:"value,optional" json:"value" state:"true"`
 }
 
 func (c *RandomNumber) Process() error {
@@ -0,0 +1,144 @@
+package resources
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/shipyard-run/hclconfig/types"
+)
+
+const (
+	defaultRetryAttempts   = 3
+	defaultRetryBackoff    = 1 * time.Second
+	defaultRetryMaxBackoff = 30 * time.Second
+)
+
+// Retry defines how the creation of a resource is retried when the provider
+// returns an error, the backoff between attempts doubles after every failed
+// attempt until it reaches max_backoff
+//
+//	retry {
+//	  attempts    = 5
+//	  backoff     = "2s"
+//	  max_backoff = "20s"
+//	}
+type Retry struct {
+	// Attempts is the total number of times create is attempted, default 3
+	Attempts int `hcl:"attempts,optional" json:"attempts,omitempty"`
+
+	// Backoff is the time to wait before the first retry, default 1s
+	Backoff string `hcl:"backoff,optional" json:"backoff,omitempty"`
+
+	// MaxBackoff is the maximum time to wait between attempts, default 30s
+	MaxBackoff string `hcl:"max_backoff,optional" json:"max_backoff,omitempty"`
+}
+
+// Validate the retry policy and return an error when it is not valid
+func (r *Retry) Validate() error {
+	if r.Attempts < 0 {
+		return fmt.Errorf("attempts must be greater than 0")
+	}
+
+	if r.Backoff != "" {
+		if _, err := time.ParseDuration(r.Backoff); err != nil {
+			return fmt.Errorf("unable to parse backoff %s: %s", r.Backoff, err)
+		}
+	}
+
+	if r.MaxBackoff != "" {
+		if _, err := time.ParseDuration(r.MaxBackoff); err != nil {
+			return fmt.Errorf("unable to parse max_backoff %s: %s", r.MaxBackoff, err)
+		}
+	}
+
+	return nil
+}
+
+// MaxAttempts returns the number of times create should be attempted
+func (r *Retry) MaxAttempts() int {
+	if r.Attempts == 0 {
+		return defaultRetryAttempts
+	}
+
+	return r.Attempts
+}
+
+// BackoffFor returns the time to wait after the given failed attempt, the
+// first attempt is 1
+func (r *Retry) BackoffFor(attempt int) time.Duration {
+	backoff := defaultRetryBackoff
+	if d, err := time.ParseDuration(r.Backoff); err == nil {
+		backoff = d
+	}
+
+	max := defaultRetryMaxBackoff
+	if d, err := time.ParseDuration(r.MaxBackoff); err == nil {
+		max = d
+	}
+
+	for i := 1; i < attempt && backoff < max; i++ {
+		backoff = backoff * 2
+	}
+
+	if backoff > max {
+		return max
+	}
+
+	return backoff
+}
+
+// helmRetryBackoff is the time helm waited between attempts before retries
+// were handled by the engine
+const helmRetryBackoff = "5s"
+
+// RetryForResource returns the retry policy for the given resource, nil is
+// returned when the resource does not define a retry block. Helm charts
+// define retry as the number of attempts which is mapped to a policy.
+func RetryForResource(r types.Resource) *Retry {
+	if h, ok := r.(*Helm); ok {
+		if h.Retry <= 1 {
+			return nil
+		}
+
+		return &Retry{Attempts: h.Retry, Backoff: helmRetryBackoff, MaxBackoff: helmRetryBackoff}
+	}
+
+	f := resourceField(r, "Retry")
+	if !f.IsValid() {
+		return nil
+	}
+
+	rt, _ := f.Interface().(*Retry)
+
+	return rt
+}
+
+// TimeoutForResource returns the maximum time the engine allows for the
+// creation of the given resource, an empty string is returned when the
+// resource does not define a timeout
+func TimeoutForResource(r types.Resource) string {
+	f := resourceField(r, "Timeout")
+	if !f.IsValid() {
+		return ""
+	}
+
+	t, _ := f.Interface().(string)
+
+	return t
+}
+
+// resourceField returns the named field from the resource struct, the
+// returned value is not valid when the resource does not have the field
+func resourceField(r types.Resource, name string) reflect.Value {
+	v := reflect.ValueOf(r)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+
+	return v.FieldByName(name)
+}
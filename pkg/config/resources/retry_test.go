@@ -0,0 +1,56 @@
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shipyard-run/hclconfig/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryValidateWithInvalidDurationReturnsError(t *testing.T) {
+	require.NoError(t, (&Retry{Attempts: 3, Backoff: "1s", MaxBackoff: "10s"}).Validate())
+	require.Error(t, (&Retry{Attempts: -1}).Validate())
+	require.Error(t, (&Retry{Backoff: "abc"}).Validate())
+	require.Error(t, (&Retry{MaxBackoff: "abc"}).Validate())
+}
+
+func TestRetryMaxAttemptsReturnsDefault(t *testing.T) {
+	require.Equal(t, 3, (&Retry{}).MaxAttempts())
+	require.Equal(t, 5, (&Retry{Attempts: 5}).MaxAttempts())
+}
+
+func TestRetryBackoffForDoublesUntilMaxBackoff(t *testing.T) {
+	r := &Retry{Backoff: "1s", MaxBackoff: "5s"}
+
+	require.Equal(t, 1*time.Second, r.BackoffFor(1))
+	require.Equal(t, 2*time.Second, r.BackoffFor(2))
+	require.Equal(t, 4*time.Second, r.BackoffFor(3))
+	require.Equal(t, 5*time.Second, r.BackoffFor(4))
+	require.Equal(t, 5*time.Second, r.BackoffFor(40))
+}
+
+func TestTimeoutForResourceReturnsTimeout(t *testing.T) {
+	c := &Container{Timeout: "30s"}
+	h := &Helm{ResourceMetadata: types.ResourceMetadata{Type: TypeHelm}, Timeout: "30s"}
+
+	require.Equal(t, "30s", TimeoutForResource(c))
+	require.Equal(t, "30s", TimeoutForResource(h))
+	require.Equal(t, "", TimeoutForResource(&Network{}))
+}
+
+func TestRetryForResourceMapsHelmRetry(t *testing.T) {
+	h := &Helm{ResourceMetadata: types.ResourceMetadata{Type: TypeHelm}}
+	require.Nil(t, RetryForResource(h))
+
+	h.Retry = 1
+	require.Nil(t, RetryForResource(h))
+
+	h.Retry = 3
+	r := RetryForResource(h)
+	require.NotNil(t, r)
+	require.NoError(t, r.Validate())
+	require.Equal(t, 3, r.MaxAttempts())
+	require.Equal(t, 5*time.Second, r.BackoffFor(1))
+	require.Equal(t, 5*time.Second, r.BackoffFor(2))
+}
@@ -1,6 +1,10 @@
 package resources
 
-import "github.com/shipyard-run/hclconfig/types"
+import (
+	"fmt"
+
+	"github.com/shipyard-run/hclconfig/types"
+)
 
 // TypeSidecar is the resource string for a Sidecar resource
 const TypeSidecar string = "sidecar"
@@ -10,6 +14,10 @@ type Sidecar struct {
 	// embedded type holding name, etc
 	types.ResourceMetadata `hcl:",remain"`
 
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
 	Target string `hcl:"target" json:"target"`
 
 	Image       Image             `hcl:"image,block" json:"image"`                          // image to use for the container
@@ -20,6 +28,19 @@ type Sidecar struct {
 
 	Privileged bool `hcl:"privileged,optional" json:"privileged,omitempty"` // run the container in privileged mode?
 
+	// kernel and security options, the hostname can not be set as a sidecar
+	// shares the network of the target container
+	Labels         map[string]string `hcl:"labels,optional" json:"labels,omitempty"`                     // labels to add to the container
+	CapAdd         []string          `hcl:"cap_add,optional" json:"cap_add,omitempty"`                   // kernel capabilities to add to the container e.g. NET_ADMIN
+	CapDrop        []string          `hcl:"cap_drop,optional" json:"cap_drop,omitempty"`                 // kernel capabilities to remove from the container
+	Sysctls        map[string]string `hcl:"sysctls,optional" json:"sysctls,omitempty"`                   // kernel parameters to set in the container, network parameters are shared with the target
+	Ulimits        []Ulimit          `hcl:"ulimit,block" json:"ulimits,omitempty"`                       // resource limits for the processes in the container
+	ShmSize        int               `hcl:"shm_size,optional" json:"shm_size,omitempty"`                 // size of /dev/shm in MB
+	Devices        []Device          `hcl:"device,block" json:"devices,omitempty"`                       // host devices to add to the container
+	SecurityOpt    []string          `hcl:"security_opt,optional" json:"security_opt,omitempty"`         // security options e.g. seccomp=unconfined, apparmor=profile
+	Init           bool              `hcl:"init,optional" json:"init,omitempty"`                         // run an init process inside the container to reap zombie processes
+	ReadOnlyRootfs bool              `hcl:"read_only_rootfs,optional" json:"read_only_rootfs,omitempty"` // mount the root filesystem of the container as read only
+
 	// resource constraints
 	Resources *Resources `hcl:"resources,block" json:"resources,omitempty"` // resource constraints for the container
 
@@ -32,10 +53,15 @@ type Sidecar struct {
 
 	// FQDN is the fully qualified domain name for the container, this can be used
 	// to access the container from other sources
-	FQDN string `hcl:"fqdn,optional" json:"fqdn,omitempty"`
+	FQDN string `hcl:"fqdn,optional" json:"fqdn,omitempty" state:"true"`
 }
 
 func (c *Sidecar) Process() error {
+	err := validateKernelOptions(c.Ulimits, c.Devices)
+	if err != nil {
+		return fmt.Errorf("invalid config for sidecar %s: %s", c.ID, err)
+	}
+
 	// process volumes
 	for i, v := range c.Volumes {
 		// make sure mount paths are absolute when type is bind
@@ -54,3 +54,14 @@ func TestSidecarLoadsValuesFromState(t *testing.T) {
 
 	require.Equal(t, "fqdn.mine", docs.FQDN)
 }
+
+func TestSidecarProcessWithInvalidUlimitReturnsError(t *testing.T) {
+	c := &Sidecar{
+		ResourceMetadata: types.ResourceMetadata{ID: "resource.sidecar.test"},
+		Ulimits:          []Ulimit{{Name: "nproc", Soft: 200, Hard: 100}},
+	}
+
+	err := c.Process()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "resource.sidecar.test")
+}
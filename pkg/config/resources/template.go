@@ -13,6 +13,10 @@ const TypeTemplate string = "template"
 type Template struct {
 	types.ResourceMetadata `hcl:",remain"`
 
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
 	Source      string      `hcl:"source" json:"source"`                          // Source template to be processed as string
 	Destination string      `hcl:"destination" json:"destination"`                // Destination filename to write
 	Variables   interface{} `hcl:"variables,optional" json:"variables,omitempty"` // Variables to be processed in the template
@@ -0,0 +1,37 @@
+package resources
+
+import "github.com/shipyard-run/hclconfig/types"
+
+// TypeVolume is the string resource type for Volume resources
+const TypeVolume string = "volume"
+
+// DockerVolume defines a Docker volume which can be mounted by containers
+type DockerVolume struct {
+	// embedded type holding name, etc
+	types.ResourceMetadata `hcl:",remain"`
+
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
+	// Output parameters
+
+	// VolumeName is the name of the volume as created in Docker
+	VolumeName string `hcl:"volume_name,optional" json:"volume_name,omitempty" state:"true"`
+}
+
+func (c *DockerVolume) Process() error {
+	// do we have an existing resource in the state?
+	// if so we need to set any computed resources for dependents
+	cfg, err := LoadState()
+	if err == nil {
+		// try and find the resource in the state
+		r, _ := cfg.FindResource(c.ID)
+		if r != nil {
+			kstate := r.(*DockerVolume)
+			c.VolumeName = kstate.VolumeName
+		}
+	}
+
+	return nil
+}
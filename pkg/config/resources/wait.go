@@ -0,0 +1,81 @@
+package resources
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shipyard-run/hclconfig/types"
+)
+
+// TypeWait is the resource string for a Wait resource
+const TypeWait string = "wait"
+
+// Wait is a resource which blocks until the health checks pass, resources
+// which depend on the wait are not created until the checks are healthy
+//
+//	resource "wait" "database" {
+//	  target = resource.container.database.id
+//
+//	  health_check {
+//	    timeout = "60s"
+//	    exec    = ["pg_isready", "-U", "postgres"]
+//	  }
+//	}
+type Wait struct {
+	types.ResourceMetadata `hcl:",remain"`
+
+	Lifecycle *Lifecycle `hcl:"lifecycle,block" json:"lifecycle,omitempty"`
+	Retry     *Retry     `hcl:"retry,block" json:"retry,omitempty"`
+	Timeout   string     `hcl:"timeout,optional" json:"timeout,omitempty"`
+
+	// Target is the id of the container or sidecar for exec and Docker checks,
+	// the k8s_cluster for Kubernetes checks or the nomad_cluster for Nomad
+	// checks. HTTP and TCP checks do not need a target.
+	Target string `hcl:"target,optional" json:"target,omitempty"`
+
+	// HealthCheck defines the conditions which must hold
+	HealthCheck *HealthCheck `hcl:"health_check,block" json:"health_check,omitempty"`
+}
+
+func (w *Wait) Process() error {
+	hc := w.HealthCheck
+	if hc == nil {
+		return fmt.Errorf("resource %s must define a health_check block", w.ID)
+	}
+
+	if _, err := time.ParseDuration(hc.Timeout); err != nil {
+		return fmt.Errorf("unable to parse health_check timeout %s for resource %s: %s", hc.Timeout, w.ID, err)
+	}
+
+	targetType := ""
+	if w.Target != "" {
+		fqrn, err := types.ParseFQRN(w.Target)
+		if err != nil || fqrn.Resource == "" {
+			return fmt.Errorf("target %s for resource %s is not a valid resource id", w.Target, w.ID)
+		}
+
+		targetType = fqrn.Type
+	}
+
+	container := len(hc.Exec) > 0 || hc.DockerHealth
+	kubernetes := len(hc.Pods) > 0 || len(hc.Services) > 0 || len(hc.Deployments) > 0 || len(hc.StatefulSets) > 0 || len(hc.Jobs) > 0
+	nomad := len(hc.NomadJobs) > 0
+
+	if hc.HTTP == "" && hc.TCP == "" && !container && !kubernetes && !nomad {
+		return fmt.Errorf("health_check for resource %s does not define any checks", w.ID)
+	}
+
+	if container && targetType != TypeContainer && targetType != TypeSidecar {
+		return fmt.Errorf("exec and docker_healthcheck checks for resource %s require a container or sidecar target", w.ID)
+	}
+
+	if kubernetes && targetType != TypeK8sCluster {
+		return fmt.Errorf("Kubernetes checks for resource %s require a k8s_cluster target", w.ID)
+	}
+
+	if nomad && targetType != TypeNomadCluster {
+		return fmt.Errorf("nomad_jobs checks for resource %s require a nomad_cluster target", w.ID)
+	}
+
+	return nil
+}
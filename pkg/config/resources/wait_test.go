@@ -0,0 +1,85 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/shipyard-run/hclconfig/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitProcessWithoutHealthCheckReturnsError(t *testing.T) {
+	w := &Wait{ResourceMetadata: types.ResourceMetadata{ID: "resource.wait.db"}}
+
+	err := w.Process()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "health_check")
+}
+
+func TestWaitProcessWithoutChecksReturnsError(t *testing.T) {
+	w := &Wait{
+		ResourceMetadata: types.ResourceMetadata{ID: "resource.wait.db"},
+		HealthCheck:      &HealthCheck{Timeout: "30s"},
+	}
+
+	err := w.Process()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "does not define any checks")
+}
+
+func TestWaitProcessWithHTTPCheckAndNoTarget(t *testing.T) {
+	w := &Wait{
+		ResourceMetadata: types.ResourceMetadata{ID: "resource.wait.api"},
+		HealthCheck:      &HealthCheck{Timeout: "30s", HTTP: "http://localhost:8080"},
+	}
+
+	require.NoError(t, w.Process())
+}
+
+func TestWaitProcessWithExecCheckRequiresContainerTarget(t *testing.T) {
+	w := &Wait{
+		ResourceMetadata: types.ResourceMetadata{ID: "resource.wait.db"},
+		HealthCheck:      &HealthCheck{Timeout: "30s", Exec: []string{"pg_isready"}},
+	}
+
+	require.Error(t, w.Process())
+
+	w.Target = "resource.k8s_cluster.dev"
+	require.Error(t, w.Process())
+
+	w.Target = "resource.container.database"
+	require.NoError(t, w.Process())
+}
+
+func TestWaitProcessWithKubernetesCheckRequiresClusterTarget(t *testing.T) {
+	w := &Wait{
+		ResourceMetadata: types.ResourceMetadata{ID: "resource.wait.consul"},
+		Target:           "resource.container.database",
+		HealthCheck:      &HealthCheck{Timeout: "30s", Deployments: []string{"consul/injector"}},
+	}
+
+	require.Error(t, w.Process())
+
+	w.Target = "resource.k8s_cluster.dev"
+	require.NoError(t, w.Process())
+}
+
+func TestWaitProcessWithNomadCheckRequiresClusterTarget(t *testing.T) {
+	w := &Wait{
+		ResourceMetadata: types.ResourceMetadata{ID: "resource.wait.redis"},
+		HealthCheck:      &HealthCheck{Timeout: "30s", NomadJobs: []string{"redis"}},
+	}
+
+	require.Error(t, w.Process())
+
+	w.Target = "resource.nomad_cluster.dev"
+	require.NoError(t, w.Process())
+}
+
+func TestWaitProcessWithInvalidTimeoutReturnsError(t *testing.T) {
+	w := &Wait{
+		ResourceMetadata: types.ResourceMetadata{ID: "resource.wait.api"},
+		HealthCheck:      &HealthCheck{Timeout: "abc", HTTP: "http://localhost:8080"},
+	}
+
+	require.Error(t, w.Process())
+}
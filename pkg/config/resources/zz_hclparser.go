@@ -7,6 +7,7 @@ import (
 
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/shipyard-run/hclconfig"
+	"github.com/shipyard-run/hclconfig/types"
 )
 
 // setupHCLConfig configures the HCLConfig package and registers the custom types
@@ -19,24 +20,15 @@ func SetupHCLConfig(callback hclconfig.ProcessCallback, variables map[string]str
 	p := hclconfig.NewParser(cfg)
 
 	// Register the types
-	p.RegisterType(TypeCertificateCA, &CertificateCA{})
-	p.RegisterType(TypeCertificateLeaf, &CertificateLeaf{})
-	p.RegisterType(TypeContainer, &Container{})
-	p.RegisterType(TypeCopy, &Copy{})
-	p.RegisterType(TypeDocs, &Docs{})
-	p.RegisterType(TypeRemoteExec, &RemoteExec{})
-	p.RegisterType(TypeHelm, &Helm{})
-	p.RegisterType(TypeImageCache, &ImageCache{})
-	p.RegisterType(TypeIngress, &Ingress{})
-	p.RegisterType(TypeK8sCluster, &K8sCluster{})
-	p.RegisterType(TypeK8sConfig, &K8sConfig{})
-	p.RegisterType(TypeLocalExec, &LocalExec{})
-	p.RegisterType(TypeNetwork, &Network{})
-	p.RegisterType(TypeNomadCluster, &NomadCluster{})
-	p.RegisterType(TypeNomadJob, &NomadJob{})
-	p.RegisterType(TypeRandomNumber, &RandomNumber{})
-	p.RegisterType(TypeSidecar, &Sidecar{})
-	p.RegisterType(TypeTemplate, &Template{})
+	for t, r := range builtinTypes() {
+		p.RegisterType(t, r)
+	}
+
+	// Register the types provided by plugins
+	loadPlugins()
+	for _, t := range PluginTypes() {
+		p.RegisterType(t.Type, &Plugin{})
+	}
 
 	// Register the custom functions
 	p.RegisterFunction("jumppad", customHCLFuncJumppad)
@@ -50,6 +42,35 @@ func SetupHCLConfig(callback hclconfig.ProcessCallback, variables map[string]str
 	return p
 }
 
+// builtinTypes returns the resource types provided by jumppad
+func builtinTypes() types.RegisteredTypes {
+	bt := types.DefaultTypes()
+
+	bt[TypeBlueprint] = &Blueprint{}
+	bt[TypeCertificateCA] = &CertificateCA{}
+	bt[TypeCertificateLeaf] = &CertificateLeaf{}
+	bt[TypeContainer] = &Container{}
+	bt[TypeCopy] = &Copy{}
+	bt[TypeDocs] = &Docs{}
+	bt[TypeRemoteExec] = &RemoteExec{}
+	bt[TypeHelm] = &Helm{}
+	bt[TypeImageCache] = &ImageCache{}
+	bt[TypeIngress] = &Ingress{}
+	bt[TypeK8sCluster] = &K8sCluster{}
+	bt[TypeK8sConfig] = &K8sConfig{}
+	bt[TypeLocalExec] = &LocalExec{}
+	bt[TypeNetwork] = &Network{}
+	bt[TypeNomadCluster] = &NomadCluster{}
+	bt[TypeNomadJob] = &NomadJob{}
+	bt[TypeRandomNumber] = &RandomNumber{}
+	bt[TypeSidecar] = &Sidecar{}
+	bt[TypeTemplate] = &Template{}
+	bt[TypeVolume] = &DockerVolume{}
+	bt[TypeWait] = &Wait{}
+
+	return bt
+}
+
 func customHCLFuncJumppad() (string, error) {
 	return utils.JumppadHome(), nil
 }
@@ -2,15 +2,15 @@ package resources
 
 import (
 	"fmt"
-	"io/ioutil"
-	"os"
+	"strings"
 
-	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/shipyard-run/hclconfig"
+	"github.com/shipyard-run/hclconfig/types"
 )
 
+// LoadState loads the state from the current state backend
 func LoadState() (*hclconfig.Config, error) {
-	d, err := ioutil.ReadFile(utils.StatePath())
+	d, err := GetStateBackend().Load()
 	if err != nil {
 		return hclconfig.NewConfig(), fmt.Errorf("unable to read state file: %s", err)
 	}
@@ -24,6 +24,7 @@ func LoadState() (*hclconfig.Config, error) {
 	return c, nil
 }
 
+// SaveState writes the config to the current state backend
 func SaveState(c *hclconfig.Config) error {
 	// save the state regardless of error
 	d, err := c.ToJSON()
@@ -31,14 +32,78 @@ func SaveState(c *hclconfig.Config) error {
 		return fmt.Errorf("unable to serialize config to JSON: %s", err)
 	}
 
-	err = os.MkdirAll(utils.StateDir(), os.ModePerm)
+	return GetStateBackend().Save(d)
+}
+
+// RemoveState removes the state from the current state backend
+func RemoveState() error {
+	return GetStateBackend().Remove()
+}
+
+// MoveResource renames the resource with the id from to the id to without
+// modifying any other attributes, dependencies on the resource are updated
+// to use the new id
+func MoveResource(c *hclconfig.Config, from, to string) error {
+	r, err := c.FindResource(from)
+	if err != nil {
+		return fmt.Errorf("unable to find resource %s in the state", from)
+	}
+
+	fqrn, err := types.ParseFQRN(to)
+	if err != nil {
+		return fmt.Errorf("invalid resource id %s: %s", to, err)
+	}
+
+	if fqrn.Type != r.Metadata().Type || fqrn.Attribute != "" {
+		return fmt.Errorf("unable to move %s to %s, the new id must be a resource of type %s", from, to, r.Metadata().Type)
+	}
+
+	if _, err := c.FindResource(fqrn.String()); err == nil {
+		return fmt.Errorf("unable to move %s to %s, a resource with the new id already exists", from, to)
+	}
+
+	old := types.FQDNFromResource(r).String()
+
+	err = c.RemoveResource(r)
 	if err != nil {
-		return fmt.Errorf("unable to create directory for state file '%s', error: %s", utils.StateDir(), err)
+		return fmt.Errorf("unable to remove resource %s from the state: %s", from, err)
 	}
 
-	err = ioutil.WriteFile(utils.StatePath(), d, os.ModePerm)
+	r.Metadata().Name = fqrn.Resource
+	r.Metadata().Module = fqrn.Module
+	r.Metadata().ID = fqrn.String()
+
+	err = c.AppendResource(r)
 	if err != nil {
-		return fmt.Errorf("unable to write state file '%s', error: %s", utils.StatePath(), err)
+		return fmt.Errorf("unable to add resource %s to the state: %s", to, err)
+	}
+
+	// dependencies are relative to the module of the dependent resource
+	for _, dr := range c.Resources {
+		for i, d := range dr.Metadata().DependsOn {
+			df, err := types.ParseFQRN(d)
+			if err != nil {
+				continue
+			}
+
+			dep := df.AppendParentModule(dr.Metadata().Module)
+			dep.Attribute = ""
+
+			if dep.String() != old {
+				continue
+			}
+
+			nf := *fqrn
+			if m := dr.Metadata().Module; m != "" {
+				if nf.Module == m {
+					nf.Module = ""
+				} else if strings.HasPrefix(nf.Module, m+".") {
+					nf.Module = strings.TrimPrefix(nf.Module, m+".")
+				}
+			}
+
+			dr.Metadata().DependsOn[i] = nf.String()
+		}
 	}
 
 	return nil
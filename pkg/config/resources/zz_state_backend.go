@@ -0,0 +1,418 @@
+package resources
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+)
+
+// StateEnvName is the environment variable used to select the state backend
+// when the --state flag is not set
+const StateEnvName = "JUMPPAD_STATE"
+
+// DefaultStateEnvironment is the environment used by the directory backend
+// when the address does not specify one
+const DefaultStateEnvironment = "default"
+
+// StateBackend stores the serialized state and the lock which prevents
+// concurrent modification of the state
+type StateBackend interface {
+	// Load returns the serialized state, when no state exists an error
+	// wrapping os.ErrNotExist is returned
+	Load() ([]byte, error)
+
+	// Save replaces the state with the given serialized state
+	Save(d []byte) error
+
+	// Remove deletes the state, removing a state which does not exist is
+	// not an error
+	Remove() error
+
+	// Lock acquires the lock on the state for the given owner, when the
+	// lock is held by another owner ErrStateLocked is returned
+	Lock(l *StateLock) error
+
+	// ReadLock returns the current lock on the state, when the state is
+	// not locked an error wrapping os.ErrNotExist is returned
+	ReadLock() (*StateLock, error)
+
+	// Unlock removes the lock on the state when it is held by the given
+	// owner, when the lock is held by another owner ErrStateLocked is
+	// returned. A nil owner removes the lock regardless of the owner.
+	Unlock(l *StateLock) error
+}
+
+var stateBackend StateBackend = &FileStateBackend{}
+var stateBackendLock sync.Mutex
+
+// SetStateBackend sets the backend used to load and save the state
+func SetStateBackend(b StateBackend) {
+	stateBackendLock.Lock()
+	defer stateBackendLock.Unlock()
+
+	stateBackend = b
+}
+
+// GetStateBackend returns the backend used to load and save the state
+func GetStateBackend() StateBackend {
+	stateBackendLock.Lock()
+	defer stateBackendLock.Unlock()
+
+	return stateBackend
+}
+
+// IsLocalStateBackend returns true when the state is stored in the default
+// local directory of the workspace, the state of other workspaces can only be
+// found when the local backend is used
+func IsLocalStateBackend() bool {
+	b, ok := GetStateBackend().(*FileStateBackend)
+
+	return ok && b.Dir == ""
+}
+
+// NewStateBackend returns the backend for the given address:
+//
+//	local                              the state in $HOME/.jumppad/state, the default
+//	http://state.example.com/env/ci   a remote state, see HTTPStateBackend
+//	dir:/mnt/states?env=ci             the state in a sub directory of /mnt/states
+//	                                   for each environment
+func NewStateBackend(address string) (StateBackend, error) {
+	if address == "" || address == "local" {
+		return &FileStateBackend{}, nil
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid state backend address %s: %s", address, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return NewHTTPStateBackend(address), nil
+
+	case "dir":
+		path := u.Opaque
+		if path == "" {
+			path = u.Host + u.Path
+		}
+
+		if path == "" {
+			return nil, fmt.Errorf("invalid state backend address %s, the directory must be specified, e.g. dir:/mnt/states?env=ci", address)
+		}
+
+		env := u.Query().Get("env")
+		if env == "" {
+			env = DefaultStateEnvironment
+		}
+
+		return NewDirectoryStateBackend(path, env)
+	}
+
+	return nil, fmt.Errorf("invalid state backend address %s, the address must be local, an http(s) url or dir:<path>", address)
+}
+
+// FileStateBackend stores the state as a JSON file in a local directory,
+// previous versions of the state are kept in the history sub directory
+type FileStateBackend struct {
+	// Dir containing the state, when empty the default state directory
+	// $HOME/.jumppad/state is used
+	Dir string
+}
+
+// NewDirectoryStateBackend returns a backend which stores the state for each
+// environment in its own sub directory of base. Base can be a shared
+// location such as a network mount so that teams can share environments.
+func NewDirectoryStateBackend(base, env string) (*FileStateBackend, error) {
+	if env == "" || strings.ContainsAny(env, `/\`) || env == "." || env == ".." {
+		return nil, fmt.Errorf("invalid state environment %q, the environment must be a valid directory name", env)
+	}
+
+	abs, err := filepath.Abs(base)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine absolute path for state directory %s: %s", base, err)
+	}
+
+	return &FileStateBackend{Dir: filepath.Join(abs, env)}, nil
+}
+
+// StateDir returns the directory containing the state
+func (b *FileStateBackend) StateDir() string {
+	if b.Dir == "" {
+		return utils.StateDir()
+	}
+
+	return b.Dir
+}
+
+// StatePath returns the full path of the state file
+func (b *FileStateBackend) StatePath() string {
+	if b.Dir == "" {
+		return utils.StatePath()
+	}
+
+	return filepath.Join(b.Dir, "state.json")
+}
+
+// HistoryDir returns the directory containing the previous versions of the
+// state
+func (b *FileStateBackend) HistoryDir() string {
+	if b.Dir == "" {
+		return utils.StateHistoryDir()
+	}
+
+	return filepath.Join(b.Dir, "history")
+}
+
+func (b *FileStateBackend) lockPath() string {
+	if b.Dir == "" {
+		return utils.StateLockPath()
+	}
+
+	return filepath.Join(b.Dir, "state.lock")
+}
+
+// Load implements StateBackend
+func (b *FileStateBackend) Load() ([]byte, error) {
+	return ioutil.ReadFile(b.StatePath())
+}
+
+// Save implements StateBackend, the state is written to a temporary file
+// which is renamed so that the state file is never partially written
+func (b *FileStateBackend) Save(d []byte) error {
+	err := os.MkdirAll(b.StateDir(), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("unable to create directory for state file '%s', error: %s", b.StateDir(), err)
+	}
+
+	f, err := ioutil.TempFile(b.StateDir(), "state-*.json")
+	if err != nil {
+		return fmt.Errorf("unable to create temporary state file in '%s', error: %s", b.StateDir(), err)
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(d)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("unable to write state file '%s', error: %s", f.Name(), err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("unable to write state file '%s', error: %s", f.Name(), err)
+	}
+
+	err = os.Rename(f.Name(), b.StatePath())
+	if err != nil {
+		return fmt.Errorf("unable to write state file '%s', error: %s", b.StatePath(), err)
+	}
+
+	return nil
+}
+
+// Remove implements StateBackend
+func (b *FileStateBackend) Remove() error {
+	err := os.Remove(b.StatePath())
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to remove state file '%s', error: %s", b.StatePath(), err)
+	}
+
+	return nil
+}
+
+// stateLockGracePeriod is the time after which a lock file which can not be
+// read is considered to have been left behind by a process which crashed
+const stateLockGracePeriod = 10 * time.Second
+
+// stateLockMode is the permission of the lock file
+const stateLockMode = 0644
+
+// errInvalidStateLock is returned when the lock file can not be parsed
+var errInvalidStateLock = errors.New("invalid state lock")
+
+// linkFile creates a hard link, replaced in tests to simulate file systems
+// which do not support hard links
+var linkFile = os.Link
+
+// Lock implements StateBackend, the lock is written to a temporary file which
+// is linked into place, linking fails when the lock file exists so that only
+// one process can hold the lock and the lock file is never partially written.
+// On file systems which do not support hard links, such as some network
+// mounts, the lock file is created exclusively and written in place, a lock
+// left partially written by a crash is replaced after stateLockGracePeriod.
+func (b *FileStateBackend) Lock(l *StateLock) error {
+	err := os.MkdirAll(b.StateDir(), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("unable to create directory for state lock '%s', error: %s", b.StateDir(), err)
+	}
+
+	d, err := json.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("unable to serialize state lock: %s", err)
+	}
+
+	tmp := fmt.Sprintf("%s.%s", b.lockPath(), newStateLockID())
+
+	err = ioutil.WriteFile(tmp, d, stateLockMode)
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("unable to write state lock '%s', error: %s", tmp, err)
+	}
+	defer os.Remove(tmp)
+
+	for {
+		err := createStateLockFile(tmp, b.lockPath())
+		if err == nil {
+			return nil
+		}
+
+		if !os.IsExist(err) {
+			return fmt.Errorf("unable to create state lock '%s', error: %s", b.lockPath(), err)
+		}
+
+		existing, err := b.ReadLock()
+		if err != nil {
+			// the lock may have been removed between creating and reading
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			if errors.Is(err, errInvalidStateLock) && b.removeInvalidLock() {
+				continue
+			}
+
+			return err
+		}
+
+		return ErrStateLocked{Lock: existing}
+	}
+}
+
+// removeInvalidLock removes a lock file which can not be parsed and is older
+// than stateLockGracePeriod, returns true when the lock has been removed or no
+// longer exists
+func (b *FileStateBackend) removeInvalidLock() bool {
+	fi, err := os.Stat(b.lockPath())
+	if err != nil {
+		return os.IsNotExist(err)
+	}
+
+	if time.Since(fi.ModTime()) < stateLockGracePeriod {
+		return false
+	}
+
+	// move the lock before checking it again so that a lock which has been
+	// replaced by another process is never removed
+	claimed := fmt.Sprintf("%s.%s", b.lockPath(), newStateLockID())
+
+	err = os.Rename(b.lockPath(), claimed)
+	if err != nil {
+		return os.IsNotExist(err)
+	}
+	defer os.Remove(claimed)
+
+	_, err = readStateLockFile(claimed)
+	if !errors.Is(err, errInvalidStateLock) {
+		createStateLockFile(claimed, b.lockPath())
+	}
+
+	return true
+}
+
+// ReadLock implements StateBackend
+func (b *FileStateBackend) ReadLock() (*StateLock, error) {
+	return readStateLockFile(b.lockPath())
+}
+
+// Unlock implements StateBackend, before the owner is checked the lock file
+// is moved to a unique path so that a lock which has been replaced by another
+// process between reading and removing it is never removed
+func (b *FileStateBackend) Unlock(l *StateLock) error {
+	if l == nil {
+		err := os.Remove(b.lockPath())
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to remove state lock '%s', error: %s", b.lockPath(), err)
+		}
+
+		return nil
+	}
+
+	claimed := fmt.Sprintf("%s.%s", b.lockPath(), newStateLockID())
+
+	err := os.Rename(b.lockPath(), claimed)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("unable to remove state lock '%s', error: %s", b.lockPath(), err)
+	}
+	defer os.Remove(claimed)
+
+	existing, err := readStateLockFile(claimed)
+	if err != nil || !existing.heldBy(l) {
+		// restore the lock, linking fails when a new lock has been
+		// created since the lock was moved
+		createStateLockFile(claimed, b.lockPath())
+
+		if err != nil {
+			return err
+		}
+
+		return ErrStateLocked{Lock: existing}
+	}
+
+	return nil
+}
+
+// createStateLockFile creates the lock file at path with the contents of src,
+// an error for which os.IsExist is true is returned when the lock file exists
+func createStateLockFile(src, path string) error {
+	err := linkFile(src, path)
+	if err == nil || os.IsExist(err) {
+		return err
+	}
+
+	// hard links are not supported, create the lock file exclusively
+	d, rerr := ioutil.ReadFile(src)
+	if rerr != nil {
+		return rerr
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, stateLockMode)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(d)
+	f.Close()
+
+	if err != nil {
+		os.Remove(path)
+	}
+
+	return err
+}
+
+func readStateLockFile(path string) (*StateLock, error) {
+	d, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read state lock: %w", err)
+	}
+
+	l := &StateLock{}
+	err = json.Unmarshal(d, l)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal state lock: %w: %s", errInvalidStateLock, err)
+	}
+
+	return l, nil
+}
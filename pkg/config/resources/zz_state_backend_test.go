@@ -0,0 +1,285 @@
+package resources
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shipyard-run/hclconfig"
+	"github.com/shipyard-run/hclconfig/types"
+	"github.com/stretchr/testify/require"
+)
+
+// testStateServer is an in memory implementation of the http state backend
+// contract
+type testStateServer struct {
+	sync.Mutex
+	state []byte
+	lock  []byte
+}
+
+func (s *testStateServer) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	d, _ := ioutil.ReadAll(r.Body)
+
+	if r.URL.Path == "/envs/ci/lock" {
+		if s.lock == nil {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		rw.Write(s.lock)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if s.state == nil {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		rw.Write(s.state)
+	case http.MethodPut:
+		s.state = d
+	case http.MethodDelete:
+		s.state = nil
+	case "LOCK":
+		if s.lock != nil {
+			rw.WriteHeader(http.StatusLocked)
+			rw.Write(s.lock)
+			return
+		}
+
+		s.lock = d
+	case "UNLOCK":
+		if len(d) > 0 && s.lock != nil {
+			owner := &StateLock{}
+			current := &StateLock{}
+			json.Unmarshal(d, owner)
+			json.Unmarshal(s.lock, current)
+
+			if !current.heldBy(owner) {
+				rw.WriteHeader(http.StatusLocked)
+				rw.Write(s.lock)
+				return
+			}
+		}
+
+		s.lock = nil
+	}
+}
+
+func setupHTTPStateBackend(t *testing.T) (*testStateServer, *HTTPStateBackend) {
+	s := &testStateServer{}
+	ts := httptest.NewServer(s)
+
+	b := NewHTTPStateBackend(ts.URL + "/envs/ci")
+	SetStateBackend(b)
+
+	t.Cleanup(func() {
+		ts.Close()
+		SetStateBackend(&FileStateBackend{})
+	})
+
+	return s, b
+}
+
+func testBackendStateConfig() *hclconfig.Config {
+	c := hclconfig.NewConfig()
+	c.AppendResource(&Network{
+		ResourceMetadata: types.ResourceMetadata{
+			Name:       "test",
+			Type:       TypeNetwork,
+			ID:         "resource.network.test",
+			Properties: map[string]interface{}{},
+		},
+		Subnet: "10.5.0.0/16",
+	})
+
+	return c
+}
+
+func TestNewStateBackendReturnsBackendForAddress(t *testing.T) {
+	b, err := NewStateBackend("")
+	require.NoError(t, err)
+	require.Equal(t, &FileStateBackend{}, b)
+
+	b, err = NewStateBackend("local")
+	require.NoError(t, err)
+	require.Equal(t, &FileStateBackend{}, b)
+
+	b, err = NewStateBackend("https://state.example.com/envs/ci/")
+	require.NoError(t, err)
+	require.Equal(t, "https://state.example.com/envs/ci", b.(*HTTPStateBackend).Address)
+
+	b, err = NewStateBackend("dir:/mnt/states?env=ci")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join("/mnt/states", "ci"), b.(*FileStateBackend).Dir)
+
+	b, err = NewStateBackend("dir:///mnt/states")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join("/mnt/states", DefaultStateEnvironment), b.(*FileStateBackend).Dir)
+}
+
+func TestNewStateBackendWithInvalidAddressReturnsError(t *testing.T) {
+	_, err := NewStateBackend("ftp://state.example.com")
+	require.Error(t, err)
+
+	_, err = NewStateBackend("dir:?env=ci")
+	require.Error(t, err)
+
+	_, err = NewStateBackend("dir:/mnt/states?env=../ci")
+	require.Error(t, err)
+}
+
+func TestIsLocalStateBackend(t *testing.T) {
+	t.Cleanup(func() { SetStateBackend(&FileStateBackend{}) })
+
+	SetStateBackend(&FileStateBackend{})
+	require.True(t, IsLocalStateBackend())
+
+	SetStateBackend(&FileStateBackend{Dir: t.TempDir()})
+	require.False(t, IsLocalStateBackend())
+
+	SetStateBackend(NewHTTPStateBackend("https://state.example.com/envs/ci"))
+	require.False(t, IsLocalStateBackend())
+}
+
+func TestDirectoryStateBackendKeepsStateForEachEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	t.Cleanup(func() { SetStateBackend(&FileStateBackend{}) })
+
+	ci, err := NewDirectoryStateBackend(dir, "ci")
+	require.NoError(t, err)
+
+	demo, err := NewDirectoryStateBackend(dir, "demo")
+	require.NoError(t, err)
+
+	SetStateBackend(ci)
+	err = SaveState(testBackendStateConfig())
+	require.NoError(t, err)
+
+	require.FileExists(t, filepath.Join(dir, "ci", "state.json"))
+
+	SetStateBackend(demo)
+	_, err = LoadState()
+	require.Error(t, err)
+
+	SetStateBackend(ci)
+	c, err := LoadState()
+	require.NoError(t, err)
+
+	_, err = c.FindResource("resource.network.test")
+	require.NoError(t, err)
+}
+
+func TestHTTPStateBackendSavesAndLoadsState(t *testing.T) {
+	s, _ := setupHTTPStateBackend(t)
+
+	_, err := LoadState()
+	require.Error(t, err)
+
+	err = SaveState(testBackendStateConfig())
+	require.NoError(t, err)
+	require.NotNil(t, s.state)
+
+	c, err := LoadState()
+	require.NoError(t, err)
+
+	r, err := c.FindResource("resource.network.test")
+	require.NoError(t, err)
+	require.Equal(t, "10.5.0.0/16", r.(*Network).Subnet)
+
+	err = RemoveState()
+	require.NoError(t, err)
+	require.Nil(t, s.state)
+}
+
+func TestHTTPStateBackendLocksState(t *testing.T) {
+	s, _ := setupHTTPStateBackend(t)
+
+	unlock, err := LockState()
+	require.NoError(t, err)
+
+	l, err := ReadStateLock()
+	require.NoError(t, err)
+	require.Equal(t, os.Getpid(), l.PID)
+
+	// nested locks by the same process succeed
+	nested, err := LockState()
+	require.NoError(t, err)
+	nested()
+
+	require.NotNil(t, s.lock)
+
+	unlock()
+
+	require.Nil(t, s.lock)
+
+	_, err = ReadStateLock()
+	require.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestHTTPStateBackendReturnsErrorWhenLockedByOtherHost(t *testing.T) {
+	s, _ := setupHTTPStateBackend(t)
+
+	s.lock, _ = json.Marshal(&StateLock{PID: 1, Hostname: "ci-runner", Created: time.Now()})
+
+	_, err := LockState()
+	require.Error(t, err)
+	require.IsType(t, ErrStateLocked{}, err)
+	require.Contains(t, err.Error(), "ci-runner")
+
+	err = UnlockState()
+	require.NoError(t, err)
+
+	unlock, err := LockState()
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestHTTPStateBackendUnlockDoesNotRemoveLockHeldByOtherOwner(t *testing.T) {
+	s, b := setupHTTPStateBackend(t)
+
+	s.lock, _ = json.Marshal(&StateLock{ID: "runner", PID: 1, Hostname: "ci-runner", Created: time.Now()})
+
+	err := b.Unlock(&StateLock{ID: "other", PID: 1, Hostname: "ci-runner"})
+	require.Error(t, err)
+	require.IsType(t, ErrStateLocked{}, err)
+	require.NotNil(t, s.lock)
+}
+
+func TestHTTPStateBackendReturnsErrorForUnexpectedStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("boom"))
+	}))
+	defer ts.Close()
+
+	b := NewHTTPStateBackend(ts.URL)
+
+	_, err := b.Load()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "500")
+	require.Contains(t, err.Error(), "boom")
+
+	err = b.Save([]byte("{}"))
+	require.Error(t, err)
+}
+
+func TestStateHistoryWithHTTPBackendReturnsError(t *testing.T) {
+	setupHTTPStateBackend(t)
+
+	_, err := StateHistory()
+	require.Error(t, err)
+}
@@ -0,0 +1,152 @@
+package resources
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/shipyard-run/hclconfig"
+)
+
+// StateHistoryLimit is the maximum number of previous versions of the state
+// that are kept, the oldest versions are removed first
+var StateHistoryLimit = 10
+
+var stateHistoryFile = regexp.MustCompile(`^state\.(\d+)\.json$`)
+
+// StateVersion is a previous version of the state
+type StateVersion struct {
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+	Path    string    `json:"path"`
+}
+
+// StateHistory returns the previous versions of the state ordered from the
+// oldest to the newest, the history is only kept by file state backends
+func StateHistory() ([]StateVersion, error) {
+	fb, ok := GetStateBackend().(*FileStateBackend)
+	if !ok {
+		return nil, fmt.Errorf("state history is only supported by the local and dir state backends")
+	}
+
+	return fileStateHistory(fb)
+}
+
+func fileStateHistory(fb *FileStateBackend) ([]StateVersion, error) {
+	files, err := ioutil.ReadDir(fb.HistoryDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []StateVersion{}, nil
+		}
+
+		return nil, fmt.Errorf("unable to read state history '%s', error: %s", fb.HistoryDir(), err)
+	}
+
+	versions := []StateVersion{}
+	for _, f := range files {
+		m := stateHistoryFile.FindStringSubmatch(f.Name())
+		if m == nil {
+			continue
+		}
+
+		v, _ := strconv.Atoi(m[1])
+
+		versions = append(versions, StateVersion{
+			Version: v,
+			Created: f.ModTime(),
+			Path:    filepath.Join(fb.HistoryDir(), f.Name()),
+		})
+	}
+
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+
+	return versions, nil
+}
+
+// LoadStateVersion loads the given previous version of the state
+func LoadStateVersion(version int) (*hclconfig.Config, error) {
+	versions, err := StateHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range versions {
+		if v.Version != version {
+			continue
+		}
+
+		d, err := ioutil.ReadFile(v.Path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read state file: %s", err)
+		}
+
+		p := SetupHCLConfig(nil, nil, nil)
+		c, err := p.UnmarshalJSON(d)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal state file: %s", err)
+		}
+
+		return c, nil
+	}
+
+	return nil, fmt.Errorf("state version %d does not exist", version)
+}
+
+// ArchiveState copies the current state to the history so that it can be
+// restored with rollback. ArchiveState is called before resources are
+// created or destroyed, commands which only edit the state do not archive
+// it. Versions older than the history limit are removed. The history is only
+// kept by file state backends.
+func ArchiveState() error {
+	fb, ok := GetStateBackend().(*FileStateBackend)
+	if !ok {
+		return nil
+	}
+
+	d, err := fb.Load()
+	if err != nil {
+		// no previous state to archive
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("unable to read state file '%s', error: %s", fb.StatePath(), err)
+	}
+
+	err = os.MkdirAll(fb.HistoryDir(), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("unable to create directory for state history '%s', error: %s", fb.HistoryDir(), err)
+	}
+
+	versions, err := fileStateHistory(fb)
+	if err != nil {
+		return err
+	}
+
+	next := 1
+	if len(versions) > 0 {
+		next = versions[len(versions)-1].Version + 1
+	}
+
+	path := filepath.Join(fb.HistoryDir(), fmt.Sprintf("state.%d.json", next))
+
+	err = ioutil.WriteFile(path, d, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("unable to write state history '%s', error: %s", path, err)
+	}
+
+	versions = append(versions, StateVersion{Version: next, Path: path})
+
+	// remove the oldest versions
+	for len(versions) > StateHistoryLimit {
+		os.Remove(versions[0].Path)
+		versions = versions[1:]
+	}
+
+	return nil
+}
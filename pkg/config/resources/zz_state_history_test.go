@@ -0,0 +1,106 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/shipyard-run/hclconfig"
+	"github.com/shipyard-run/hclconfig/types"
+	"github.com/stretchr/testify/require"
+)
+
+func testStateConfig(name string) *hclconfig.Config {
+	c := hclconfig.NewConfig()
+	c.AppendResource(&Network{
+		ResourceMetadata: types.ResourceMetadata{
+			Name:       name,
+			Type:       TypeNetwork,
+			ID:         "resource.network." + name,
+			Properties: map[string]interface{}{},
+		},
+	})
+
+	return c
+}
+
+func TestArchiveStateArchivesCurrentState(t *testing.T) {
+	setupState(t, "")
+
+	// no state to archive
+	err := ArchiveState()
+	require.NoError(t, err)
+
+	versions, err := StateHistory()
+	require.NoError(t, err)
+	require.Len(t, versions, 0)
+
+	err = SaveState(testStateConfig("one"))
+	require.NoError(t, err)
+
+	err = ArchiveState()
+	require.NoError(t, err)
+
+	versions, err = StateHistory()
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+
+	c, err := LoadStateVersion(versions[0].Version)
+	require.NoError(t, err)
+
+	_, err = c.FindResource("resource.network.one")
+	require.NoError(t, err)
+}
+
+func TestSaveStateDoesNotArchiveState(t *testing.T) {
+	setupState(t, "")
+
+	// commands like taint and state rm save the state, this must not push
+	// the versions created by up out of the history
+	for _, n := range []string{"one", "two", "three"} {
+		err := SaveState(testStateConfig(n))
+		require.NoError(t, err)
+	}
+
+	versions, err := StateHistory()
+	require.NoError(t, err)
+	require.Len(t, versions, 0)
+}
+
+func TestArchiveStateRemovesVersionsOverTheLimit(t *testing.T) {
+	setupState(t, "")
+
+	limit := StateHistoryLimit
+	StateHistoryLimit = 2
+	t.Cleanup(func() { StateHistoryLimit = limit })
+
+	for _, n := range []string{"one", "two", "three", "four"} {
+		err := SaveState(testStateConfig(n))
+		require.NoError(t, err)
+
+		err = ArchiveState()
+		require.NoError(t, err)
+	}
+
+	versions, err := StateHistory()
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, 3, versions[0].Version)
+	require.Equal(t, 4, versions[1].Version)
+}
+
+func TestArchiveStateWithHTTPBackendDoesNothing(t *testing.T) {
+	s, _ := setupHTTPStateBackend(t)
+
+	err := SaveState(testStateConfig("one"))
+	require.NoError(t, err)
+	require.NotNil(t, s.state)
+
+	err = ArchiveState()
+	require.NoError(t, err)
+}
+
+func TestLoadStateVersionWithUnknownVersionReturnsError(t *testing.T) {
+	setupState(t, "")
+
+	_, err := LoadStateVersion(1)
+	require.Error(t, err)
+}
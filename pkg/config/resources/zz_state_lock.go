@@ -0,0 +1,129 @@
+package resources
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// StateLock is the information written to the lock file, it identifies the
+// process which currently holds the lock on the state
+type StateLock struct {
+	// ID is a random value which identifies the owner of the lock, process
+	// ids are reused so the pid and hostname alone do not identify the owner
+	ID       string    `json:"id,omitempty"`
+	PID      int       `json:"pid"`
+	Hostname string    `json:"hostname"`
+	Created  time.Time `json:"created"`
+}
+
+// ErrStateLocked is returned when the state is locked by another process
+type ErrStateLocked struct {
+	Lock *StateLock
+}
+
+func (e ErrStateLocked) Error() string {
+	return fmt.Sprintf(
+		"state is locked by process %d on host %s since %s, if this process is no longer running remove the lock with 'jumppad state unlock'",
+		e.Lock.PID,
+		e.Lock.Hostname,
+		e.Lock.Created.Format(time.RFC3339),
+	)
+}
+
+// LockState acquires an advisory lock on the state, the returned function
+// releases the lock.
+// If the lock is held by a process on this host that is no longer running
+// the lock is considered stale and is replaced, the stale lock is only
+// removed when it has not been replaced by another process. If the lock is already held
+// by the current process the returned function does not release the lock,
+// this allows nested operations to lock the state.
+func LockState() (func(), error) {
+	b := GetStateBackend()
+
+	hostname, _ := os.Hostname()
+
+	l := &StateLock{
+		ID:       newStateLockID(),
+		PID:      os.Getpid(),
+		Hostname: hostname,
+		Created:  time.Now(),
+	}
+
+	for {
+		err := b.Lock(l)
+		if err == nil {
+			return func() { b.Unlock(l) }, nil
+		}
+
+		le := ErrStateLocked{}
+		if !errors.As(err, &le) {
+			return nil, err
+		}
+
+		if le.Lock.Hostname == hostname && le.Lock.PID == l.PID {
+			return func() {}, nil
+		}
+
+		if !le.Lock.Stale() {
+			return nil, le
+		}
+
+		// the process holding the lock is no longer running, when another
+		// process has already replaced the stale lock try to lock again
+		err = b.Unlock(le.Lock)
+		if err != nil && !errors.As(err, &le) {
+			return nil, fmt.Errorf("unable to remove stale state lock: %s", err)
+		}
+	}
+}
+
+// ReadStateLock returns the current lock on the state, if the state is not
+// locked an error wrapping os.ErrNotExist is returned
+func ReadStateLock() (*StateLock, error) {
+	return GetStateBackend().ReadLock()
+}
+
+// UnlockState removes the lock on the state regardless of the process that
+// holds it
+func UnlockState() error {
+	return GetStateBackend().Unlock(nil)
+}
+
+// Stale returns true when the process holding the lock is no longer running.
+// Locks held by processes on other hosts can not be checked and are never
+// considered stale.
+func (l *StateLock) Stale() bool {
+	hostname, _ := os.Hostname()
+	if l.Hostname != hostname {
+		return false
+	}
+
+	p, err := os.FindProcess(l.PID)
+	if err != nil {
+		return true
+	}
+
+	// signal 0 checks for the existence of the process without sending
+	// a signal
+	err = p.Signal(syscall.Signal(0))
+
+	return errors.Is(err, os.ErrProcessDone) || errors.Is(err, syscall.ESRCH)
+}
+
+// heldBy returns true when the lock is held by the same owner as the given
+// lock
+func (l *StateLock) heldBy(o *StateLock) bool {
+	return l.ID == o.ID && l.PID == o.PID && l.Hostname == o.Hostname
+}
+
+func newStateLockID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
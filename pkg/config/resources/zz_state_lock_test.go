@@ -0,0 +1,244 @@
+package resources
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func writeStateLock(t *testing.T, l *StateLock) {
+	d, err := json.Marshal(l)
+	require.NoError(t, err)
+
+	os.MkdirAll(utils.StateDir(), os.ModePerm)
+	err = ioutil.WriteFile(utils.StateLockPath(), d, os.ModePerm)
+	require.NoError(t, err)
+}
+
+func TestLockStateCreatesLockFile(t *testing.T) {
+	setupState(t, "")
+
+	unlock, err := LockState()
+	require.NoError(t, err)
+
+	l, err := ReadStateLock()
+	require.NoError(t, err)
+	require.Equal(t, os.Getpid(), l.PID)
+
+	unlock()
+
+	require.NoFileExists(t, utils.StateLockPath())
+}
+
+func TestLockStateReturnsErrorWhenLockedByOtherProcess(t *testing.T) {
+	setupState(t, "")
+
+	// the parent process of the test is running
+	hostname, _ := os.Hostname()
+	writeStateLock(t, &StateLock{PID: os.Getppid(), Hostname: hostname, Created: time.Now()})
+
+	_, err := LockState()
+	require.Error(t, err)
+	require.IsType(t, ErrStateLocked{}, err)
+	require.Contains(t, err.Error(), hostname)
+}
+
+func TestLockStateReturnsErrorWhenLockedByOtherHost(t *testing.T) {
+	setupState(t, "")
+
+	writeStateLock(t, &StateLock{PID: os.Getpid(), Hostname: "other-host", Created: time.Now()})
+
+	_, err := LockState()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "other-host")
+}
+
+func TestLockStateReplacesStaleLock(t *testing.T) {
+	setupState(t, "")
+
+	// a pid which is larger than the maximum allowed pid will never be running
+	hostname, _ := os.Hostname()
+	writeStateLock(t, &StateLock{PID: 1 << 30, Hostname: hostname, Created: time.Now()})
+
+	unlock, err := LockState()
+	require.NoError(t, err)
+	defer unlock()
+
+	l, err := ReadStateLock()
+	require.NoError(t, err)
+	require.Equal(t, os.Getpid(), l.PID)
+}
+
+func TestUnlockDoesNotRemoveLockHeldByOtherOwner(t *testing.T) {
+	setupState(t, "")
+
+	unlock, err := LockState()
+	require.NoError(t, err)
+
+	// the lock has been taken over by another process
+	hostname, _ := os.Hostname()
+	writeStateLock(t, &StateLock{ID: "other", PID: os.Getppid(), Hostname: hostname, Created: time.Now()})
+
+	unlock()
+
+	l, err := ReadStateLock()
+	require.NoError(t, err)
+	require.Equal(t, "other", l.ID)
+
+	// the lock file must not be left behind at the temporary path
+	files, err := filepath.Glob(utils.StateLockPath() + ".*")
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+func TestStaleLockIsOnlyTakenOverByOneOwner(t *testing.T) {
+	setupState(t, "")
+
+	hostname, _ := os.Hostname()
+	stale := &StateLock{ID: "stale", PID: 1 << 30, Hostname: hostname, Created: time.Now()}
+	writeStateLock(t, stale)
+
+	b := GetStateBackend()
+
+	wg := sync.WaitGroup{}
+	owners := make(chan string, 10)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func(l *StateLock) {
+			defer wg.Done()
+
+			for {
+				err := b.Lock(l)
+				if err == nil {
+					owners <- l.ID
+					return
+				}
+
+				le := ErrStateLocked{}
+				if !errors.As(err, &le) || !le.Lock.heldBy(stale) {
+					return
+				}
+
+				b.Unlock(le.Lock)
+			}
+		}(&StateLock{ID: newStateLockID(), PID: os.Getpid(), Hostname: hostname, Created: time.Now()})
+	}
+
+	wg.Wait()
+	close(owners)
+
+	require.Len(t, owners, 1)
+
+	l, err := ReadStateLock()
+	require.NoError(t, err)
+	require.Equal(t, <-owners, l.ID)
+}
+
+func TestLockStateIsReentrantForCurrentProcess(t *testing.T) {
+	setupState(t, "")
+
+	unlock, err := LockState()
+	require.NoError(t, err)
+
+	nestedUnlock, err := LockState()
+	require.NoError(t, err)
+
+	// the nested unlock must not release the lock
+	nestedUnlock()
+	require.FileExists(t, utils.StateLockPath())
+
+	unlock()
+	require.NoFileExists(t, utils.StateLockPath())
+}
+
+func TestUnlockStateRemovesLock(t *testing.T) {
+	setupState(t, "")
+
+	writeStateLock(t, &StateLock{PID: os.Getpid(), Hostname: "other-host", Created: time.Now()})
+
+	err := UnlockState()
+	require.NoError(t, err)
+
+	require.NoFileExists(t, utils.StateLockPath())
+}
+
+func TestLockStateReplacesInvalidLockAfterGracePeriod(t *testing.T) {
+	setupState(t, "")
+
+	// a process crashed while writing the lock
+	os.MkdirAll(utils.StateDir(), os.ModePerm)
+	err := ioutil.WriteFile(utils.StateLockPath(), []byte{}, os.ModePerm)
+	require.NoError(t, err)
+
+	old := time.Now().Add(-2 * stateLockGracePeriod)
+	os.Chtimes(utils.StateLockPath(), old, old)
+
+	unlock, err := LockState()
+	require.NoError(t, err)
+	defer unlock()
+
+	l, err := ReadStateLock()
+	require.NoError(t, err)
+	require.Equal(t, os.Getpid(), l.PID)
+}
+
+func TestLockStateReturnsErrorWhenInvalidLockIsNew(t *testing.T) {
+	setupState(t, "")
+
+	os.MkdirAll(utils.StateDir(), os.ModePerm)
+	err := ioutil.WriteFile(utils.StateLockPath(), []byte(`{"id": "abc`), os.ModePerm)
+	require.NoError(t, err)
+
+	_, err = LockState()
+	require.ErrorIs(t, err, errInvalidStateLock)
+	require.FileExists(t, utils.StateLockPath())
+}
+
+func TestLockStateCreatesLockFileWithoutExecutePermission(t *testing.T) {
+	setupState(t, "")
+
+	unlock, err := LockState()
+	require.NoError(t, err)
+	defer unlock()
+
+	fi, err := os.Stat(utils.StateLockPath())
+	require.NoError(t, err)
+	require.Zero(t, fi.Mode().Perm()&0133)
+}
+
+func TestLockWithoutHardLinksCreatesLockExclusively(t *testing.T) {
+	setupState(t, "")
+
+	linkFile = func(oldname, newname string) error {
+		return &os.LinkError{Op: "link", Old: oldname, New: newname, Err: errors.New("operation not supported")}
+	}
+	t.Cleanup(func() { linkFile = os.Link })
+
+	b := GetStateBackend()
+	hostname, _ := os.Hostname()
+
+	err := b.Lock(&StateLock{ID: "first", PID: os.Getpid(), Hostname: hostname, Created: time.Now()})
+	require.NoError(t, err)
+
+	err = b.Lock(&StateLock{ID: "second", PID: os.Getpid(), Hostname: hostname, Created: time.Now()})
+	require.IsType(t, ErrStateLocked{}, err)
+
+	l, err := ReadStateLock()
+	require.NoError(t, err)
+	require.Equal(t, "first", l.ID)
+
+	// the temporary files are removed
+	files, err := filepath.Glob(utils.StateLockPath() + ".*")
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
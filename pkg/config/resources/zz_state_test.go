@@ -0,0 +1,111 @@
+package resources
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/shipyard-run/hclconfig"
+	"github.com/shipyard-run/hclconfig/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveStateWritesStateWithoutTemporaryFiles(t *testing.T) {
+	setupState(t, "")
+
+	c := hclconfig.NewConfig()
+	c.AppendResource(&Network{
+		ResourceMetadata: types.ResourceMetadata{
+			Name:       "test",
+			Type:       TypeNetwork,
+			ID:         "resource.network.test",
+			Properties: map[string]interface{}{},
+		},
+	})
+
+	err := SaveState(c)
+	require.NoError(t, err)
+
+	files, err := ioutil.ReadDir(utils.StateDir())
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "state.json", files[0].Name())
+
+	sc, err := LoadState()
+	require.NoError(t, err)
+
+	_, err = sc.FindResource("resource.network.test")
+	require.NoError(t, err)
+}
+
+func TestMoveResourceRenamesResourceAndDependencies(t *testing.T) {
+	setupState(t, testMoveState)
+
+	c, err := LoadState()
+	require.NoError(t, err)
+
+	err = MoveResource(c, "resource.network.cloud", "resource.network.onprem")
+	require.NoError(t, err)
+
+	_, err = c.FindResource("resource.network.cloud")
+	require.Error(t, err)
+
+	r, err := c.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+	require.Equal(t, "10.15.0.0/16", r.(*Network).Subnet)
+
+	ic, err := c.FindResource("resource.image_cache.default")
+	require.NoError(t, err)
+	require.Equal(t, []string{"resource.network.onprem"}, ic.Metadata().DependsOn)
+}
+
+func TestMoveResourceWithDifferentTypeReturnsError(t *testing.T) {
+	setupState(t, testMoveState)
+
+	c, err := LoadState()
+	require.NoError(t, err)
+
+	err = MoveResource(c, "resource.network.cloud", "resource.container.cloud")
+	require.Error(t, err)
+}
+
+func TestMoveResourceToExistingResourceReturnsError(t *testing.T) {
+	setupState(t, testMoveState)
+
+	c, err := LoadState()
+	require.NoError(t, err)
+
+	err = MoveResource(c, "resource.network.cloud", "resource.network.dc1")
+	require.Error(t, err)
+}
+
+var testMoveState = `
+{
+  "resources": [
+	{
+      "name": "cloud",
+      "subnet": "10.15.0.0/16",
+      "properties": {
+				"status": "created"
+			},
+      "type": "network"
+	},
+	{
+      "name": "dc1",
+      "subnet": "10.16.0.0/16",
+      "properties": {
+				"status": "created"
+			},
+      "type": "network"
+	},
+	{
+      "name": "default",
+      "type": "image_cache",
+      "properties": {
+				"status": "created"
+			},
+			"depends_on": ["resource.network.cloud"]
+	}
+  ]
+}
+`
@@ -0,0 +1,214 @@
+package plugins
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// StartTimeout is the time a plugin has to write the handshake
+var StartTimeout = 10 * time.Second
+
+// Client runs a plugin and calls the plugin over gRPC, Client implements
+// ResourceProvider
+type Client struct {
+	name  string
+	token string
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	conn  *grpc.ClientConn
+	log   hclog.Logger
+}
+
+// Start runs the plugin at the given path and connects to it
+func Start(path string, l hclog.Logger) (*Client, error) {
+	c := &Client{
+		name: filepath.Base(path),
+		log:  l.Named(filepath.Base(path)),
+	}
+
+	t := make([]byte, 16)
+	_, err := rand.Read(t)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate token for plugin %s: %s", c.name, err)
+	}
+
+	c.token = hex.EncodeToString(t)
+
+	c.cmd = exec.Command(path)
+	c.cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", TokenEnvName, c.token))
+	c.cmd.Stderr = c.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug})
+
+	// the plugin exits when stdin is closed, this ensures the plugin does
+	// not outlive jumppad
+	c.stdin, err = c.cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("unable to start plugin %s: %s", c.name, err)
+	}
+
+	stdout, err := c.cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("unable to start plugin %s: %s", c.name, err)
+	}
+
+	err = c.cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("unable to start plugin %s: %s", c.name, err)
+	}
+
+	addr, err := c.handshake(stdout)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StartTimeout)
+	defer cancel()
+
+	c.conn, err = grpc.DialContext(ctx, addr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("unable to connect to plugin %s at %s: %s", c.name, addr, err)
+	}
+
+	return c, nil
+}
+
+// Name of the plugin
+func (c *Client) Name() string {
+	return c.name
+}
+
+// handshake reads the address of the gRPC server from the plugins stdout,
+// anything the plugin writes to stdout after the handshake is logged
+func (c *Client) handshake(stdout io.Reader) (string, error) {
+	r := bufio.NewReader(stdout)
+
+	line := make(chan string, 1)
+	go func() {
+		l, _ := r.ReadString('\n')
+		line <- l
+
+		// keep reading so that the plugin does not block writing to stdout
+		s := bufio.NewScanner(r)
+		for s.Scan() {
+			c.log.Debug(s.Text())
+		}
+	}()
+
+	var l string
+	select {
+	case l = <-line:
+	case <-time.After(StartTimeout):
+		return "", fmt.Errorf("plugin %s did not write the handshake within %s", c.name, StartTimeout)
+	}
+
+	parts := strings.Split(strings.TrimSpace(l), "|")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("plugin %s wrote an invalid handshake %q, expected version|network|address", c.name, strings.TrimSpace(l))
+	}
+
+	v, err := strconv.Atoi(parts[0])
+	if err != nil || v != ProtocolVersion {
+		return "", fmt.Errorf("plugin %s uses protocol version %s, jumppad supports version %d", c.name, parts[0], ProtocolVersion)
+	}
+
+	switch parts[1] {
+	case "tcp":
+		return parts[2], nil
+	case "unix":
+		return fmt.Sprintf("unix://%s", parts[2]), nil
+	}
+
+	return "", fmt.Errorf("plugin %s uses an unsupported network %s, must be tcp or unix", c.name, parts[1])
+}
+
+// Schema implements Provider
+func (c *Client) Schema(ctx context.Context) ([]Schema, error) {
+	resp := schemaResponse{}
+	err := c.invoke(ctx, "Schema", struct{}{}, &resp)
+	return resp.Types, err
+}
+
+// Create implements ResourceProvider
+func (c *Client) Create(ctx context.Context, r Resource) (map[string]string, error) {
+	resp := createResponse{}
+	err := c.invoke(ctx, "Create", r, &resp)
+	return resp.Outputs, err
+}
+
+// Destroy implements ResourceProvider
+func (c *Client) Destroy(ctx context.Context, r Resource) error {
+	return c.invoke(ctx, "Destroy", r, &struct{}{})
+}
+
+// Refresh implements ResourceProvider
+func (c *Client) Refresh(ctx context.Context, r Resource) (string, error) {
+	resp := refreshResponse{}
+	err := c.invoke(ctx, "Refresh", r, &resp)
+	return resp.Drift, err
+}
+
+// Lookup implements ResourceProvider
+func (c *Client) Lookup(ctx context.Context, r Resource) ([]string, error) {
+	resp := lookupResponse{}
+	err := c.invoke(ctx, "Lookup", r, &resp)
+	return resp.IDs, err
+}
+
+// Close stops the plugin
+func (c *Client) Close() error {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+
+	c.stdin.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.cmd.Wait()
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(5 * time.Second):
+		c.log.Debug("Plugin did not exit, killing process")
+		return c.cmd.Process.Kill()
+	}
+}
+
+func (c *Client) invoke(ctx context.Context, method string, in, out interface{}) error {
+	req, err := toStruct(in)
+	if err != nil {
+		return fmt.Errorf("unable to encode request for plugin %s: %s", c.name, err)
+	}
+
+	resp := &structpb.Struct{}
+	err = c.conn.Invoke(withToken(ctx, c.token), fmt.Sprintf("/%s/%s", serviceName, method), req, resp)
+	if err != nil {
+		return fmt.Errorf("plugin %s returned an error for %s: %s", c.name, method, status.Convert(err).Message())
+	}
+
+	err = fromStruct(resp, out)
+	if err != nil {
+		return fmt.Errorf("unable to decode response from plugin %s: %s", c.name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package plugins
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// Manager runs the plugins in a directory and returns the plugin which
+// provides a resource type
+type Manager struct {
+	clients []*Client
+	types   map[string]*Client
+	schemas map[string]Schema
+	log     hclog.Logger
+}
+
+// Load starts all the plugins in the given directory, plugins which fail
+// to start or return an invalid schema are logged and ignored so that a
+// broken plugin does not prevent jumppad from running. When the directory
+// does not exist no plugins are loaded.
+func Load(dir string, l hclog.Logger) (*Manager, error) {
+	m := &Manager{
+		types:   map[string]*Client{},
+		schemas: map[string]Schema{},
+		log:     l,
+	}
+
+	paths, err := Discover(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range paths {
+		l.Debug("Loading plugin", "path", p)
+
+		c, err := Start(p, l)
+		if err != nil {
+			l.Error("Unable to start plugin", "path", p, "error", err)
+			continue
+		}
+
+		err = m.add(c)
+		if err != nil {
+			l.Error("Unable to load plugin", "path", p, "error", err)
+			c.Close()
+			continue
+		}
+	}
+
+	return m, nil
+}
+
+// Discover returns the paths of the executables in the given directory
+func Discover(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		if runtime.GOOS == "windows" {
+			if !strings.EqualFold(filepath.Ext(e.Name()), ".exe") {
+				continue
+			}
+		} else if e.Mode().Perm()&0111 == 0 {
+			continue
+		}
+
+		paths = append(paths, filepath.Join(dir, e.Name()))
+	}
+
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// add registers the types provided by the plugin
+func (m *Manager) add(c *Client) error {
+	ctx, cancel := context.WithTimeout(context.Background(), StartTimeout)
+	defer cancel()
+
+	schemas, err := c.Schema(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range schemas {
+		err := s.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, s := range schemas {
+		if existing, ok := m.types[s.Type]; ok {
+			m.log.Warn("Resource type is provided by more than one plugin, ignoring", "type", s.Type, "plugin", c.Name(), "existing", existing.Name())
+			continue
+		}
+
+		m.types[s.Type] = c
+		m.schemas[s.Type] = s
+	}
+
+	m.clients = append(m.clients, c)
+
+	return nil
+}
+
+// Provider returns the plugin which provides the resource type, nil is
+// returned when no plugin provides the type
+func (m *Manager) Provider(resourceType string) ResourceProvider {
+	if c, ok := m.types[resourceType]; ok {
+		return c
+	}
+
+	return nil
+}
+
+// Schemas returns the resource types provided by all plugins sorted by type
+func (m *Manager) Schemas() []Schema {
+	s := []Schema{}
+	for _, v := range m.schemas {
+		s = append(s, v)
+	}
+
+	sort.Slice(s, func(i, j int) bool {
+		return s[i].Type < s[j].Type
+	})
+
+	return s
+}
+
+// Close stops all the plugins
+func (m *Manager) Close() {
+	for _, c := range m.clients {
+		err := c.Close()
+		if err != nil {
+			m.log.Debug("Unable to stop plugin", "plugin", c.Name(), "error", err)
+		}
+	}
+}
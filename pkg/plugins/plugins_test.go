@@ -0,0 +1,233 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const testPluginEnv = "JUMPPAD_TEST_PLUGIN"
+
+// TestMain runs the test binary as a plugin when started by the tests
+func TestMain(m *testing.M) {
+	if os.Getenv(testPluginEnv) == "1" {
+		err := Serve(&testProvider{})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+type testProvider struct{}
+
+func (p *testProvider) Schema(ctx context.Context) ([]Schema, error) {
+	return []Schema{
+		{
+			Type: "service_mock",
+			Attributes: []Attribute{
+				{Name: "port", Type: AttributeTypeNumber, Required: true},
+			},
+		},
+	}, nil
+}
+
+func (p *testProvider) Create(ctx context.Context, r Resource) (map[string]string, error) {
+	if r.Config["port"] == "0" {
+		return nil, fmt.Errorf("port must be greater than 0")
+	}
+
+	return map[string]string{"address": "localhost:" + r.Config["port"]}, nil
+}
+
+func (p *testProvider) Destroy(ctx context.Context, r Resource) error {
+	return nil
+}
+
+func (p *testProvider) Refresh(ctx context.Context, r Resource) (string, error) {
+	if r.Outputs["address"] == "" {
+		return "mock is not running", nil
+	}
+
+	return "", nil
+}
+
+func (p *testProvider) Lookup(ctx context.Context, r Resource) ([]string, error) {
+	return []string{r.ID}, nil
+}
+
+// setupPluginDir returns a plugins directory containing a plugin which runs
+// the test binary
+func setupPluginDir(t *testing.T, script string) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts are not supported on windows")
+	}
+
+	t.Setenv(testPluginEnv, "1")
+
+	dir := t.TempDir()
+
+	if script == "" {
+		script = fmt.Sprintf("#!/bin/sh\nexec %q -test.run=^$\n", os.Args[0])
+	}
+
+	err := ioutil.WriteFile(filepath.Join(dir, "mock"), []byte(script), 0755)
+	require.NoError(t, err)
+
+	return dir
+}
+
+func TestDiscoverReturnsExecutables(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executable permissions are not supported on windows")
+	}
+
+	dir := t.TempDir()
+	ioutil.WriteFile(filepath.Join(dir, "mock"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(dir, ".hidden"), []byte(""), 0755)
+	os.MkdirAll(filepath.Join(dir, "data"), 0755)
+
+	p, err := Discover(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "mock")}, p)
+}
+
+func TestDiscoverWithMissingDirReturnsNothing(t *testing.T) {
+	p, err := Discover(filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+	require.Empty(t, p)
+}
+
+func TestLoadStartsPluginsAndRegistersTypes(t *testing.T) {
+	dir := setupPluginDir(t, "")
+
+	m, err := Load(dir, hclog.NewNullLogger())
+	require.NoError(t, err)
+	defer m.Close()
+
+	s := m.Schemas()
+	require.Len(t, s, 1)
+	require.Equal(t, "service_mock", s[0].Type)
+	require.Equal(t, "port", s[0].Attributes[0].Name)
+
+	require.Nil(t, m.Provider("container"))
+
+	p := m.Provider("service_mock")
+	require.NotNil(t, p)
+
+	r := Resource{
+		ID:     "resource.service_mock.payments",
+		Name:   "payments",
+		Type:   "service_mock",
+		Config: map[string]string{"port": "9090"},
+	}
+
+	o, err := p.Create(context.Background(), r)
+	require.NoError(t, err)
+	require.Equal(t, "localhost:9090", o["address"])
+
+	drift, err := p.Refresh(context.Background(), r)
+	require.NoError(t, err)
+	require.Equal(t, "mock is not running", drift)
+
+	r.Outputs = o
+	drift, err = p.Refresh(context.Background(), r)
+	require.NoError(t, err)
+	require.Empty(t, drift)
+
+	ids, err := p.Lookup(context.Background(), r)
+	require.NoError(t, err)
+	require.Equal(t, []string{"resource.service_mock.payments"}, ids)
+
+	err = p.Destroy(context.Background(), r)
+	require.NoError(t, err)
+}
+
+func TestPluginErrorsAreReturned(t *testing.T) {
+	dir := setupPluginDir(t, "")
+
+	m, err := Load(dir, hclog.NewNullLogger())
+	require.NoError(t, err)
+	defer m.Close()
+
+	_, err = m.Provider("service_mock").Create(context.Background(), Resource{Config: map[string]string{"port": "0"}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "port must be greater than 0")
+}
+
+func TestLoadIgnoresPluginsWithInvalidHandshake(t *testing.T) {
+	old := StartTimeout
+	StartTimeout = time.Second
+	t.Cleanup(func() { StartTimeout = old })
+
+	dir := setupPluginDir(t, "#!/bin/sh\necho 'hello'\nexec cat > /dev/null\n")
+
+	m, err := Load(dir, hclog.NewNullLogger())
+	require.NoError(t, err)
+	defer m.Close()
+
+	require.Empty(t, m.Schemas())
+}
+
+func TestServeRejectsRequestsWithoutToken(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer w.Close()
+
+	hs := make(chan string, 1)
+	pr, pw, err := os.Pipe()
+	require.NoError(t, err)
+
+	go serve(&testProvider{}, "secret", r, pw)
+	go func() {
+		c := &Client{name: "test", log: hclog.NewNullLogger()}
+		addr, _ := c.handshake(pr)
+		hs <- addr
+	}()
+
+	addr := <-hs
+	require.NotEmpty(t, addr)
+
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	err = conn.Invoke(withToken(context.Background(), "wrong"), fmt.Sprintf("/%s/Schema", serviceName), &structpb.Struct{}, &structpb.Struct{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	resp := &structpb.Struct{}
+	err = conn.Invoke(withToken(context.Background(), "secret"), fmt.Sprintf("/%s/Schema", serviceName), &structpb.Struct{}, resp)
+	require.NoError(t, err)
+
+	sr := schemaResponse{}
+	err = fromStruct(resp, &sr)
+	require.NoError(t, err)
+	require.Equal(t, "service_mock", sr.Types[0].Type)
+}
+
+func TestSchemaValidate(t *testing.T) {
+	require.NoError(t, Schema{Type: "service_mock", Attributes: []Attribute{{Name: "port", Type: "number"}, {Name: "name"}}}.Validate())
+
+	require.Error(t, Schema{Type: "Service-Mock"}.Validate())
+	require.Error(t, Schema{Type: "mock", Attributes: []Attribute{{Name: ""}}}.Validate())
+	require.Error(t, Schema{Type: "mock", Attributes: []Attribute{{Name: "port"}, {Name: "port"}}}.Validate())
+	require.Error(t, Schema{Type: "mock", Attributes: []Attribute{{Name: "port", Type: "list"}}}.Validate())
+}
@@ -0,0 +1,287 @@
+// Package plugins implements external resource providers which run as a
+// separate process and communicate with jumppad over gRPC.
+//
+// A plugin is an executable in $HOME/.jumppad/plugins, when jumppad starts it
+// runs each plugin, the plugin listens on a local address and writes the
+// handshake line to stdout:
+//
+//	1|tcp|127.0.0.1:34523
+//
+// The handshake contains the protocol version, the network and the address
+// of the gRPC server. Jumppad then calls Schema to discover the resource
+// types that the plugin provides. The plugin must exit when its stdin is
+// closed.
+//
+// The messages are google.protobuf.Struct values containing the JSON
+// representation of the types in this package, see plugin.proto. Plugins
+// written in Go can use Serve to implement the protocol.
+//
+// The config of a resource is sent as a map of strings, the schema types
+// are used to validate the values but number and bool values must be parsed
+// by the plugin. The outputs returned by Create are stored in the state and
+// passed back to the plugin, they can not be referenced by other resources
+// in the configuration.
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ProtocolVersion is the version of the plugin protocol, plugins which
+// advertise a different version are not loaded
+const ProtocolVersion = 1
+
+// TokenEnvName is the environment variable containing the token that jumppad
+// sends with every request, plugins must reject requests without the token
+const TokenEnvName = "JUMPPAD_PLUGIN_TOKEN"
+
+const serviceName = "jumppad.plugins.v1.Provider"
+const tokenHeader = "x-jumppad-plugin-token"
+
+// Attribute types which can be used in a Schema
+const (
+	AttributeTypeString = "string"
+	AttributeTypeNumber = "number"
+	AttributeTypeBool   = "bool"
+)
+
+// Schema defines a resource type provided by a plugin
+type Schema struct {
+	// Type of the resource e.g. service_mock, the type is used in the
+	// resource stanza: resource "service_mock" "payments" {}
+	Type string `json:"type"`
+
+	// Attributes which can be set in the config block of the resource
+	Attributes []Attribute `json:"attributes,omitempty"`
+}
+
+// Attribute is a value which can be set in the config block of a resource
+type Attribute struct {
+	Name string `json:"name"`
+
+	// Type of the attribute, string, number or bool, defaults to string.
+	// The type is used to validate the value, all values are sent to the
+	// plugin as strings.
+	Type string `json:"type,omitempty"`
+
+	Required    bool   `json:"required,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Validate returns an error when the schema is not valid
+func (s Schema) Validate() error {
+	if !regexp.MustCompile(`^[a-z][a-z0-9_]*$`).MatchString(s.Type) {
+		return fmt.Errorf("invalid resource type %q, types must start with a-z and contain only a-z, 0-9 and _", s.Type)
+	}
+
+	names := map[string]bool{}
+	for _, a := range s.Attributes {
+		if a.Name == "" {
+			return fmt.Errorf("resource type %s has an attribute without a name", s.Type)
+		}
+
+		if names[a.Name] {
+			return fmt.Errorf("resource type %s defines the attribute %s more than once", s.Type, a.Name)
+		}
+
+		names[a.Name] = true
+
+		switch a.Type {
+		case "", AttributeTypeString, AttributeTypeNumber, AttributeTypeBool:
+		default:
+			return fmt.Errorf("attribute %s for resource type %s has an invalid type %s, must be string, number or bool", a.Name, s.Type, a.Type)
+		}
+	}
+
+	return nil
+}
+
+// Resource is sent to the plugin for every operation
+type Resource struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Module string `json:"module,omitempty"`
+
+	// Config contains the attributes set in the config block of the resource
+	Config map[string]string `json:"config,omitempty"`
+
+	// Outputs returned by the plugin when the resource was created
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+// ResourceProvider is implemented by plugins for the resource types they
+// advertise, the methods match the jumppad provider lifecycle
+type ResourceProvider interface {
+	// Create the resource, the returned outputs are stored in the state and
+	// sent with later requests for the resource, they can not be referenced
+	// by other resources
+	Create(ctx context.Context, r Resource) (map[string]string, error)
+
+	// Destroy the resource
+	Destroy(ctx context.Context, r Resource) error
+
+	// Refresh checks the resource, when the resource has changed outside of
+	// jumppad the reason is returned and the resource is re-created
+	Refresh(ctx context.Context, r Resource) (string, error)
+
+	// Lookup returns the ids of any objects which exist for the resource
+	Lookup(ctx context.Context, r Resource) ([]string, error)
+}
+
+// Provider is implemented by plugins
+type Provider interface {
+	ResourceProvider
+
+	// Schema returns the resource types provided by the plugin
+	Schema(ctx context.Context) ([]Schema, error)
+}
+
+type schemaResponse struct {
+	Types []Schema `json:"types"`
+}
+
+type createResponse struct {
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+type refreshResponse struct {
+	Drift string `json:"drift,omitempty"`
+}
+
+type lookupResponse struct {
+	IDs []string `json:"ids,omitempty"`
+}
+
+// toStruct converts a value to the message sent over the wire
+func toStruct(v interface{}) (*structpb.Struct, error) {
+	d, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	m := map[string]interface{}{}
+	err = json.Unmarshal(d, &m)
+	if err != nil {
+		return nil, err
+	}
+
+	return structpb.NewStruct(m)
+}
+
+// fromStruct converts a message received over the wire to a value
+func fromStruct(s *structpb.Struct, v interface{}) error {
+	d, err := json.Marshal(s.AsMap())
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(d, v)
+}
+
+// handler returns the gRPC method handler which decodes the request and
+// passes it to fn
+func handler(method string, fn func(ctx context.Context, p Provider, in *structpb.Struct) (interface{}, error)) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := &structpb.Struct{}
+		err := dec(in)
+		if err != nil {
+			return nil, err
+		}
+
+		call := func(ctx context.Context, req interface{}) (interface{}, error) {
+			out, err := fn(ctx, srv.(Provider), req.(*structpb.Struct))
+			if err != nil {
+				return nil, err
+			}
+
+			return toStruct(out)
+		}
+
+		if interceptor == nil {
+			return call(ctx, in)
+		}
+
+		return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: fmt.Sprintf("/%s/%s", serviceName, method)}, call)
+	}
+}
+
+func resourceFrom(in *structpb.Struct) (Resource, error) {
+	r := Resource{}
+	err := fromStruct(in, &r)
+	return r, err
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*Provider)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Schema",
+			Handler: handler("Schema", func(ctx context.Context, p Provider, in *structpb.Struct) (interface{}, error) {
+				s, err := p.Schema(ctx)
+				return schemaResponse{Types: s}, err
+			}),
+		},
+		{
+			MethodName: "Create",
+			Handler: handler("Create", func(ctx context.Context, p Provider, in *structpb.Struct) (interface{}, error) {
+				r, err := resourceFrom(in)
+				if err != nil {
+					return nil, err
+				}
+
+				o, err := p.Create(ctx, r)
+				return createResponse{Outputs: o}, err
+			}),
+		},
+		{
+			MethodName: "Destroy",
+			Handler: handler("Destroy", func(ctx context.Context, p Provider, in *structpb.Struct) (interface{}, error) {
+				r, err := resourceFrom(in)
+				if err != nil {
+					return nil, err
+				}
+
+				return struct{}{}, p.Destroy(ctx, r)
+			}),
+		},
+		{
+			MethodName: "Refresh",
+			Handler: handler("Refresh", func(ctx context.Context, p Provider, in *structpb.Struct) (interface{}, error) {
+				r, err := resourceFrom(in)
+				if err != nil {
+					return nil, err
+				}
+
+				d, err := p.Refresh(ctx, r)
+				return refreshResponse{Drift: d}, err
+			}),
+		},
+		{
+			MethodName: "Lookup",
+			Handler: handler("Lookup", func(ctx context.Context, p Provider, in *structpb.Struct) (interface{}, error) {
+				r, err := resourceFrom(in)
+				if err != nil {
+					return nil, err
+				}
+
+				ids, err := p.Lookup(ctx, r)
+				return lookupResponse{IDs: ids}, err
+			}),
+		},
+	},
+	Metadata: "plugin.proto",
+}
+
+// withToken adds the token to the outgoing request
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, tokenHeader, token)
+}
@@ -0,0 +1,73 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Serve runs the gRPC server for the plugin and blocks until jumppad closes
+// the plugins stdin. Serve should be called from the main function of the
+// plugin:
+//
+//	func main() {
+//		err := plugins.Serve(&MockProvider{})
+//		if err != nil {
+//			fmt.Fprintln(os.Stderr, err)
+//			os.Exit(1)
+//		}
+//	}
+//
+// Anything the plugin writes to stderr is added to the jumppad log.
+func Serve(p Provider) error {
+	token := os.Getenv(TokenEnvName)
+	if token == "" {
+		return fmt.Errorf("%s is not set, plugins must be started by jumppad", TokenEnvName)
+	}
+
+	return serve(p, token, os.Stdin, os.Stdout)
+}
+
+func serve(p Provider, token string, stdin io.Reader, stdout io.Writer) error {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("unable to listen for connections: %s", err)
+	}
+
+	s := grpc.NewServer(grpc.UnaryInterceptor(tokenInterceptor(token)))
+	s.RegisterService(&serviceDesc, p)
+
+	// stop the server when jumppad exits or closes stdin
+	go func() {
+		io.Copy(ioutil.Discard, stdin)
+		s.Stop()
+	}()
+
+	_, err = fmt.Fprintf(stdout, "%d|%s|%s\n", ProtocolVersion, l.Addr().Network(), l.Addr().String())
+	if err != nil {
+		l.Close()
+		return fmt.Errorf("unable to write handshake: %s", err)
+	}
+
+	return s.Serve(l)
+}
+
+// tokenInterceptor rejects requests which do not contain the token
+func tokenInterceptor(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if v := md.Get(tokenHeader); len(v) != 1 || v[0] != token {
+			return nil, status.Error(codes.Unauthenticated, "invalid plugin token")
+		}
+
+		return h(ctx, req)
+	}
+}
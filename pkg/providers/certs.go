@@ -40,7 +40,7 @@ func NewCertificateLeaf(co *resources.CertificateLeaf, l hclog.Logger) *Certific
 	return &CertificateLeaf{co, l}
 }
 
-func (c *CertificateCA) Create() error {
+func (c *CertificateCA) Create(ctx context.Context) error {
 	c.log.Info("Creating CA Certificate", "ref", c.config.Name)
 
 	directory := strings.Replace(c.config.Module, ".", "_", -1)
@@ -120,7 +120,7 @@ func (c *CertificateCA) Create() error {
 	return nil
 }
 
-func (c *CertificateCA) Destroy() error {
+func (c *CertificateCA) Destroy(ctx context.Context) error {
 	c.log.Info("Destroy CA Certificate", "ref", c.config.Name)
 
 	return destroy(c.config.Name, c.config.Output, c.log)
@@ -130,13 +130,18 @@ func (c *CertificateCA) Lookup() ([]string, error) {
 	return nil, nil
 }
 
-func (c *CertificateCA) Refresh() error {
+func (c *CertificateCA) Refresh(ctx context.Context) error {
 	c.log.Info("Refresh CA Certificate", "ref", c.config.Name)
 
-	return nil
+	return c.CheckDrift()
+}
+
+// CheckDrift checks that the certificate and key files still exist
+func (c *CertificateCA) CheckDrift() error {
+	return checkFilesExist(filePaths(c.config.PrivateKey, c.config.PublicKeyPEM, c.config.PublicKeySSH, c.config.Cert)...)
 }
 
-func (c *CertificateLeaf) Create() error {
+func (c *CertificateLeaf) Create(ctx context.Context) error {
 	c.log.Info("Creating Leaf Certificate", "ref", c.config.Name)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -232,24 +237,41 @@ func (c *CertificateLeaf) Create() error {
 	return err
 }
 
-func (c *CertificateLeaf) Destroy() error {
+func (c *CertificateLeaf) Destroy(ctx context.Context) error {
 	c.log.Info("Destroy Leaf Certificate", "ref", c.config.Name)
 
 	directory := strings.Replace(c.config.Module, ".", "_", -1)
 	directory = path.Join(c.config.Output, directory)
 	os.MkdirAll(directory, os.ModePerm)
 
-	return destroy(c.config.Name, directory, c.log)
+	return destroy(fmt.Sprintf("%s-leaf", c.config.Name), directory, c.log)
 }
 
 func (c *CertificateLeaf) Lookup() ([]string, error) {
 	return nil, nil
 }
 
-func (c *CertificateLeaf) Refresh() error {
+func (c *CertificateLeaf) Refresh(ctx context.Context) error {
 	c.log.Info("Refresh Leaf Certificate", "ref", c.config.Name)
 
-	return nil
+	return c.CheckDrift()
+}
+
+// CheckDrift checks that the certificate and key files still exist
+func (c *CertificateLeaf) CheckDrift() error {
+	return checkFilesExist(filePaths(c.config.PrivateKey, c.config.PublicKeyPEM, c.config.PublicKeySSH, c.config.Cert)...)
+}
+
+// filePaths returns the paths of the given files ignoring any nil files
+func filePaths(files ...*resources.File) []string {
+	paths := []string{}
+	for _, f := range files {
+		if f != nil {
+			paths = append(paths, f.Path)
+		}
+	}
+
+	return paths
 }
 
 func destroy(name, output string, log hclog.Logger) error {
@@ -1,20 +1,21 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"path"
 	"testing"
 
 	"github.com/hashicorp/go-hclog"
-	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/shipyard-run/hclconfig/types"
 	"github.com/stretchr/testify/require"
 )
 
 func setupCACert(t *testing.T) (*resources.CertificateCA, *CertificateCA) {
 	dir := t.TempDir()
 
-	cc := config.NewCertificateCA("test")
+	cc := &resources.CertificateCA{ResourceMetadata: types.ResourceMetadata{Name: "test", Type: resources.TypeCertificateCA}}
 	cc.Output = dir
 
 	p := NewCertificateCA(cc, hclog.NewNullLogger())
@@ -22,17 +23,17 @@ func setupCACert(t *testing.T) (*resources.CertificateCA, *CertificateCA) {
 	return cc, p
 }
 
-func setupLeafCert(t *testing.T) (*config.CertificateLeaf, *CertificateLeaf) {
+func setupLeafCert(t *testing.T) (*resources.CertificateLeaf, *CertificateLeaf) {
 	dir := t.TempDir()
 
-	cc := config.NewCertificateCA("root")
+	cc := &resources.CertificateCA{ResourceMetadata: types.ResourceMetadata{Name: "root", Type: resources.TypeCertificateCA}}
 	p := NewCertificateCA(cc, hclog.NewNullLogger())
 
 	cc.Output = dir
-	err := p.Create()
+	err := p.Create(context.Background())
 	require.NoError(t, err)
 
-	cl := config.NewCertificateLeaf("test")
+	cl := &resources.CertificateLeaf{ResourceMetadata: types.ResourceMetadata{Name: "test", Type: resources.TypeCertificateLeaf}}
 	cl.Output = dir
 	cl.IPAddresses = []string{"127.0.0.1"}
 	cl.DNSNames = []string{"localhost"}
@@ -47,7 +48,7 @@ func setupLeafCert(t *testing.T) (*config.CertificateLeaf, *CertificateLeaf) {
 func TestGeneratesValidCA(t *testing.T) {
 	c, p := setupCACert(t)
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	require.NoError(t, err)
 
 	require.FileExists(t, path.Join(c.Output, fmt.Sprintf("%s.cert", c.Name)))
@@ -57,10 +58,10 @@ func TestGeneratesValidCA(t *testing.T) {
 func TestDestroyCleansUpCA(t *testing.T) {
 	c, p := setupCACert(t)
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	require.NoError(t, err)
 
-	err = p.Destroy()
+	err = p.Destroy(context.Background())
 	require.NoError(t, err)
 
 	require.NoFileExists(t, path.Join(c.Output, fmt.Sprintf("%s.cert", c.Name)))
@@ -70,22 +71,22 @@ func TestDestroyCleansUpCA(t *testing.T) {
 func TestGeneratesValidLeaf(t *testing.T) {
 	c, p := setupLeafCert(t)
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	require.NoError(t, err)
 
-	require.FileExists(t, path.Join(c.Output, fmt.Sprintf("%s.cert", c.Name)))
-	require.FileExists(t, path.Join(c.Output, fmt.Sprintf("%s.key", c.Name)))
+	require.FileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf.cert", c.Name)))
+	require.FileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf.key", c.Name)))
 }
 
 func TestDestroyCleansUpLeaf(t *testing.T) {
 	c, p := setupLeafCert(t)
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	require.NoError(t, err)
 
-	err = p.Destroy()
+	err = p.Destroy(context.Background())
 	require.NoError(t, err)
 
-	require.NoFileExists(t, path.Join(c.Output, fmt.Sprintf("%s.cert", c.Name)))
-	require.NoFileExists(t, path.Join(c.Output, fmt.Sprintf("%s.key", c.Name)))
+	require.NoFileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf.cert", c.Name)))
+	require.NoFileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf.key", c.Name)))
 }
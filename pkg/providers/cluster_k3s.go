@@ -2,6 +2,7 @@ package providers
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -45,12 +46,12 @@ func NewK8sCluster(c *resources.K8sCluster, cc clients.ContainerTasks, kc client
 }
 
 // Create implements interface method to create a cluster of the specified type
-func (c *K8sCluster) Create() error {
-	return c.createK3s()
+func (c *K8sCluster) Create(ctx context.Context) error {
+	return c.createK3s(ctx)
 }
 
 // Destroy implements interface method to destroy a cluster
-func (c *K8sCluster) Destroy() error {
+func (c *K8sCluster) Destroy(ctx context.Context) error {
 	return c.destroyK3s()
 }
 
@@ -59,13 +60,18 @@ func (c *K8sCluster) Lookup() ([]string, error) {
 	return c.client.FindContainerIDs(utils.FQDN(fmt.Sprintf("server.%s", c.config.Name), c.config.Module, c.config.Type))
 }
 
-func (c *K8sCluster) Refresh() error {
+func (c *K8sCluster) Refresh(ctx context.Context) error {
 	c.log.Info("Refresh Kubernetes Cluster", "ref", c.config.Name)
 
-	return nil
+	return c.CheckDrift()
+}
+
+// CheckDrift checks that the server container still exists
+func (c *K8sCluster) CheckDrift() error {
+	return checkContainersExist(c.client, utils.FQDN(fmt.Sprintf("server.%s", c.config.Name), c.config.Module, c.config.Type))
 }
 
-func (c *K8sCluster) createK3s() error {
+func (c *K8sCluster) createK3s(ctx context.Context) error {
 	// create a named log
 	c.log = c.log.Named(c.config.Name)
 
@@ -82,7 +88,7 @@ func (c *K8sCluster) createK3s() error {
 	}
 
 	// pull the container image
-	err = c.client.PullImage(*c.config.Image, false)
+	err = c.client.PullImage(ctx, *c.config.Image, false)
 	if err != nil {
 		return err
 	}
@@ -151,7 +157,7 @@ func (c *K8sCluster) createK3s() error {
 
 	if sv.Check(v) {
 		// load the CA from a file
-		ca, err := ioutil.ReadFile(filepath.Join(utils.CertsDir(""), "/root.cert"))
+		ca, err := ioutil.ReadFile(filepath.Join(utils.ConnectorCertsDir(), "/root.cert"))
 		if err != nil {
 			return fmt.Errorf("unable to read root CA for proxy: %s", err)
 		}
@@ -258,7 +264,7 @@ func (c *K8sCluster) createK3s() error {
 	}
 
 	// wait for the server to start
-	err = c.waitForStart(id)
+	err = c.waitForStart(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -306,7 +312,7 @@ func (c *K8sCluster) createK3s() error {
 	}
 
 	// ensure essential pods have started before announcing the resource is available
-	err = c.kubeClient.HealthCheckPods([]string{"app=local-path-provisioner", "k8s-app=kube-dns"}, startTimeout)
+	err = c.kubeClient.HealthCheckPods(ctx, []string{"app=local-path-provisioner", "k8s-app=kube-dns"}, startTimeout)
 	if err != nil {
 		// fetch the logs from the container before exit
 		lr, lerr := c.client.ContainerLogs(id, true, true)
@@ -323,7 +329,7 @@ func (c *K8sCluster) createK3s() error {
 	// import the images to the servers container d instance
 	// importing images means that k3s does not need to pull from a remote docker hub
 	if c.config.CopyImages != nil && len(c.config.CopyImages) > 0 {
-		err := c.ImportLocalDockerImages(utils.ImageVolumeName, id, c.config.CopyImages, false)
+		err := c.ImportLocalDockerImages(ctx, utils.ImageVolumeName, id, c.config.CopyImages, false)
 		if err != nil {
 			return xerrors.Errorf("unable to importing Docker images: %w", err)
 		}
@@ -331,10 +337,10 @@ func (c *K8sCluster) createK3s() error {
 
 	// start the connectorService
 	c.log.Debug("Deploying connector")
-	return c.deployConnector(c.config.ConnectorPort, c.config.ConnectorPort+1)
+	return c.deployConnector(ctx, c.config.ConnectorPort, c.config.ConnectorPort+1)
 }
 
-func (c *K8sCluster) waitForStart(id string) error {
+func (c *K8sCluster) waitForStart(ctx context.Context, id string) error {
 	start := time.Now()
 
 	for {
@@ -361,7 +367,11 @@ func (c *K8sCluster) waitForStart(id string) error {
 		}
 
 		// wait and try again
-		time.Sleep(1 * time.Second)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(1 * time.Second):
+		}
 	}
 
 	return nil
@@ -430,9 +440,9 @@ func (c *K8sCluster) changeServerAddressInK8sConfig(addr, origFile, newFile stri
 
 // deployConnector deploys the connector service to the cluster
 // once it has started
-func (c *K8sCluster) deployConnector(grpcPort, httpPort int) error {
+func (c *K8sCluster) deployConnector(ctx context.Context, grpcPort, httpPort int) error {
 	// generate the certificates for the service
-	cb, err := c.connector.GetLocalCertBundle(utils.CertsDir(""))
+	cb, err := c.connector.GetLocalCertBundle(utils.ConnectorCertsDir())
 	if err != nil {
 		return fmt.Errorf("unable to fetch root certificates for ingress: %s", err)
 	}
@@ -499,13 +509,13 @@ func (c *K8sCluster) deployConnector(grpcPort, httpPort int) error {
 	}
 
 	// deploy the application config
-	err = c.kubeClient.Apply(files, true)
+	err = c.kubeClient.Apply(ctx, files, true)
 	if err != nil {
 		return fmt.Errorf("unable to apply configuration: %s", err)
 	}
 
 	// wait for it to start
-	c.kubeClient.HealthCheckPods([]string{"app=connector"}, 60*time.Second)
+	err = c.kubeClient.HealthCheckPods(ctx, []string{"app=connector"}, 60*time.Second)
 	if err != nil {
 		return fmt.Errorf("timeout waiting for connector to start: %s", err)
 	}
@@ -514,7 +524,7 @@ func (c *K8sCluster) deployConnector(grpcPort, httpPort int) error {
 }
 
 // ImportLocalDockerImages fetches Docker images stored on the local client and imports them into the cluster
-func (c *K8sCluster) ImportLocalDockerImages(name string, id string, images []resources.Image, force bool) error {
+func (c *K8sCluster) ImportLocalDockerImages(ctx context.Context, name string, id string, images []resources.Image, force bool) error {
 	imgs := []string{}
 
 	for _, i := range images {
@@ -523,7 +533,7 @@ func (c *K8sCluster) ImportLocalDockerImages(name string, id string, images []re
 			continue
 		}
 
-		err := c.client.PullImage(i, false)
+		err := c.client.PullImage(ctx, i, false)
 		if err != nil {
 			return err
 		}
@@ -541,7 +551,7 @@ func (c *K8sCluster) ImportLocalDockerImages(name string, id string, images []re
 	for _, i := range imagesFile {
 		// execute the command to import the image
 		// write any command output to the logger
-		err = c.client.ExecuteCommand(id, []string{"ctr", "image", "import", i}, nil, "/", "", "", c.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}))
+		err = c.client.ExecuteCommand(ctx, id, []string{"ctr", "image", "import", i}, nil, "/", "", "", c.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}))
 		if err != nil {
 			return err
 		}
@@ -13,21 +13,23 @@ import (
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/mohae/deepcopy"
+	"github.com/shipyard-run/hclconfig"
+	"github.com/shipyard-run/hclconfig/types"
 	"github.com/stretchr/testify/mock"
 	assert "github.com/stretchr/testify/require"
 )
 
 // setupClusterMocks sets up a happy path for mocks
 func setupClusterMocks(t *testing.T) (
-	*config.K8sCluster, *clients.MockContainerTasks, *clients.MockKubernetes, *clients.ConnectorMock) {
+	*resources.K8sCluster, *clients.MockContainerTasks, *clients.MockKubernetes, *clients.ConnectorMock) {
 
 	md := &clients.MockContainerTasks{}
-	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{}, nil)
+	md.On("FindContainerIDs", mock.Anything).Return([]string{}, nil)
 	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
-	md.On("CreateVolume", mock.Anything, mock.Anything).Return("123", nil)
+	md.On("CreateVolume", mock.Anything).Return("123", nil)
 	md.On("CreateContainer", mock.Anything).Return("containerid", nil)
 	md.On("ContainerLogs", mock.Anything, true, true).Return(
 		ioutil.NopCloser(bytes.NewBufferString("Running kubelet")),
@@ -38,7 +40,10 @@ func setupClusterMocks(t *testing.T) (
 	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	md.On("RemoveContainer", mock.Anything, mock.Anything).Return(nil)
 	md.On("RemoveVolume", mock.Anything).Return(nil)
-	md.On("DetachNetwork", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	md.On("DetachNetwork", mock.Anything, mock.Anything).Return(nil)
+	md.On("ListNetworks", mock.Anything).Return([]resources.NetworkAttachment{
+		resources.NetworkAttachment{ID: "resource.network.cloud", Name: "cloud", AssignedAddress: "10.5.0.2"},
+	})
 
 	md.On("EngineInfo").Return(&clients.EngineInfo{StorageDriver: "overlay2"})
 
@@ -48,7 +53,7 @@ func setupClusterMocks(t *testing.T) (
 	os.Setenv(utils.HomeEnvName(), tmpDir)
 
 	// create the fake cert
-	certfile := filepath.Join(utils.CertsDir(""), "/root.cert")
+	certfile := filepath.Join(utils.ConnectorCertsDir(), "/root.cert")
 	cf, err := os.Create(certfile)
 	if err != nil {
 		panic(err)
@@ -83,12 +88,12 @@ func setupClusterMocks(t *testing.T) (
 	).Return(&clients.CertBundle{}, nil)
 
 	// copy the config
-	cc := deepcopy.Copy(clusterConfig).(*config.K8sCluster)
-	cn := deepcopy.Copy(clusterNetwork).(*config.Network)
+	cc := deepcopy.Copy(clusterConfig).(*resources.K8sCluster)
+	cn := deepcopy.Copy(clusterNetwork).(*resources.Network)
 
-	c := config.New()
-	c.AddResource(cc)
-	c.AddResource(cn)
+	c := hclconfig.NewConfig()
+	c.AppendResource(cc)
+	c.AppendResource(cn)
 
 	t.Cleanup(func() {
 		os.Setenv(utils.HomeEnvName(), currentHome)
@@ -99,79 +104,69 @@ func setupClusterMocks(t *testing.T) (
 
 func TestClusterK3ErrorsWhenUnableToLookupIDs(t *testing.T) {
 	md := &clients.MockContainerTasks{}
-	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("boom"))
+	md.On("FindContainerIDs", mock.Anything).Return(nil, fmt.Errorf("boom"))
 
 	mk := &clients.MockKubernetes{}
 	p := NewK8sCluster(clusterConfig, md, mk, nil, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestClusterK3SetsEnvironment(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
-	cc.Version = ""
+	cc.Environment = map[string]string{"CUSTOM": "value"}
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
-	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*resources.Container)
 
-	assert.Equal(t, params.EnvVar["K3S_KUBECONFIG_OUTPUT"], "/output/kubeconfig.yaml")
-	assert.Equal(t, params.EnvVar["K3S_CLUSTER_SECRET"], "mysupersecret")
-	assert.Equal(t, params.EnvVar["HTTP_PROXY"], utils.HTTPProxyAddress())
-	assert.Equal(t, params.EnvVar["HTTPS_PROXY"], utils.HTTPSProxyAddress())
-	assert.Equal(t, params.EnvVar["NO_PROXY"], utils.ProxyBypass)
+	assert.Equal(t, params.Environment["K3S_KUBECONFIG_OUTPUT"], "/output/kubeconfig.yaml")
+	assert.Equal(t, params.Environment["K3S_CLUSTER_SECRET"], "mysupersecret")
+	assert.Equal(t, params.Environment["HTTP_PROXY"], utils.HTTPProxyAddress())
+	assert.Equal(t, params.Environment["HTTPS_PROXY"], utils.HTTPSProxyAddress())
+	assert.Equal(t, params.Environment["NO_PROXY"], utils.ProxyBypass+","+clusterNetwork.Subnet)
+	assert.Equal(t, params.Environment["CUSTOM"], "value")
 
-	assert.Equal(t, params.EnvVar["PROXY_CA"], "CA")
+	assert.Equal(t, params.Environment["PROXY_CA"], "CA")
 }
 
 func TestClusterK3DoesNotSetProxyEnvironmentWithWrongVersion(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
-	cc.Version = "v1.12.1"
+	cc.Image = &resources.Image{Name: "shipyardrun/k3s:v1.12.1"}
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
-	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*resources.Container)
 
-	assert.Empty(t, params.EnvVar["HTTP_PROXY"])
+	assert.Empty(t, params.Environment["HTTP_PROXY"])
 }
 
 func TestClusterK3ErrorsWhenClusterExists(t *testing.T) {
 	md := &clients.MockContainerTasks{}
-	md.On("FindContainerIDs", "server."+clusterConfig.Name, mock.Anything).Return([]string{"abc"}, nil)
+	md.On("FindContainerIDs", utils.FQDN("server."+clusterConfig.Name, "", clusterConfig.Type)).Return([]string{"abc"}, nil)
 
 	mk := &clients.MockKubernetes{}
 	p := NewK8sCluster(clusterConfig, md, mk, nil, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
-func TestClusterK3PullsImageUsingBase(t *testing.T) {
-	cc, md, mk, mc := setupClusterMocks(t)
-	cc.Version = ""
-
-	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
-
-	err := p.Create()
-	assert.NoError(t, err)
-	md.AssertCalled(t, "PullImage", config.Image{Name: "shipyardrun/k3s:" + k3sBaseVersion}, false)
-}
-
-func TestClusterK3PullsImageUsingCustom(t *testing.T) {
+func TestClusterK3PullsImage(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
-	md.AssertCalled(t, "PullImage", config.Image{Name: "shipyardrun/k3s:v1.0.0"}, false)
+	md.AssertCalled(t, "PullImage", resources.Image{Name: "shipyardrun/k3s:v1.26.3"}, false)
 }
 
 func TestClusterK3CreatesANewVolume(t *testing.T) {
@@ -179,7 +174,7 @@ func TestClusterK3CreatesANewVolume(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "CreateVolume", utils.ImageVolumeName)
 }
@@ -188,11 +183,11 @@ func TestClusterK3FailsWhenUnableToCreatesANewVolume(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
 	removeOn(&md.Mock, "CreateVolume")
-	md.On("CreateVolume", mock.Anything, mock.Anything).Return("", fmt.Errorf("boom"))
+	md.On("CreateVolume", mock.Anything).Return("", fmt.Errorf("boom"))
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 	md.AssertCalled(t, "CreateVolume", utils.ImageVolumeName)
 }
@@ -202,15 +197,15 @@ func TestClusterK3CreatesAServer(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
-	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*resources.Container)
 
 	// validate the basic details for the server container
 	assert.Contains(t, params.Name, "server")
 	assert.Contains(t, params.Image.Name, "shipyardrun")
-	assert.Equal(t, clusterNetwork.Name, params.Networks[0].Name)
+	assert.Equal(t, clusterNetwork.ID, params.Networks[0].ID)
 	assert.True(t, params.Privileged)
 
 	// validate that the volume is correctly set
@@ -221,43 +216,44 @@ func TestClusterK3CreatesAServer(t *testing.T) {
 	// validate the API port is set
 	localPort, _ := strconv.Atoi(params.Ports[0].Local)
 	hostPort, _ := strconv.Atoi(params.Ports[0].Host)
-	assert.GreaterOrEqual(t, localPort, utils.MinRandomPort)
-	assert.LessOrEqual(t, localPort, utils.MaxRandomPort)
+	assert.Equal(t, cc.APIPort, localPort)
 	assert.Equal(t, hostPort, localPort)
 	assert.Equal(t, "tcp", params.Ports[0].Protocol)
 
 	localPort, _ = strconv.Atoi(params.Ports[1].Local)
 	hostPort, _ = strconv.Atoi(params.Ports[1].Host)
-	assert.GreaterOrEqual(t, hostPort, 30000)
+	assert.GreaterOrEqual(t, hostPort, utils.MinRandomPort)
+	assert.LessOrEqual(t, hostPort, utils.MaxRandomPort)
 	assert.Equal(t, "tcp", params.Ports[1].Protocol)
 
 	localPort2, _ := strconv.Atoi(params.Ports[2].Local)
 	hostPort2, _ := strconv.Atoi(params.Ports[2].Host)
 	assert.Equal(t, localPort2, localPort+1)
-	assert.GreaterOrEqual(t, hostPort2, 30000)
+	assert.Equal(t, hostPort2, hostPort+1)
 	assert.Equal(t, "tcp", params.Ports[2].Protocol)
 
 	// validate the command
 	assert.Equal(t, "server", params.Command[0])
 	assert.Contains(t, params.Command[1], params.Ports[0].Local)
 	assert.Contains(t, params.Command[2], "--kube-proxy-arg=conntrack-max-per-core=0")
-	assert.Contains(t, params.Command[3], "--no-deploy=traefik")
+	assert.Contains(t, params.Command[3], "--disable=traefik")
 	assert.Contains(t, params.Command[4], "--snapshotter=overlayfs")
-	assert.Contains(t, params.Command[5], "--tls-san=server.test.k8s-cluster.shipyard.run")
+	assert.Contains(t, params.Command[5], "--tls-san=server."+utils.FQDN(cc.Name, "", cc.Type))
+	assert.Contains(t, params.Command[6], "--token=mysupersecret")
 }
 
 func TestClusterK3CreatesAServerWithAdditionalPorts(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
-	cc.Ports = []config.Port{{Local: "8080", Remote: "8080", Host: "8080"}}
-	cc.PortRanges = []config.PortRange{{Range: "8000-9000", EnableHost: true}}
+	cc.Ports = []resources.Port{{Local: "8080", Remote: "8080", Host: "8080"}}
+	cc.PortRanges = []resources.PortRange{{Range: "8000-9000", EnableHost: true}}
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
-	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*resources.Container)
 
 	localPort, _ := strconv.Atoi(params.Ports[3].Local)
 	hostPort, _ := strconv.Atoi(params.Ports[3].Host)
@@ -278,25 +274,42 @@ func TestClusterK3sErrorsIfServerNOTStart(t *testing.T) {
 	)
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
+
+	st := startTimeout
 	startTimeout = 10 * time.Millisecond // reset the startTimeout, do not want to wait 120s
+	t.Cleanup(func() { startTimeout = st })
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
+func TestClusterK3sSetsNetworkAddresses(t *testing.T) {
+	cc, md, mk, mc := setupClusterMocks(t)
+
+	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, "10.5.0.2", cc.Networks[0].AssignedAddress)
+	assert.Equal(t, "cloud", cc.Networks[0].Name)
+	assert.Equal(t, utils.GetDockerIP(), cc.ExternalIP)
+}
+
 func TestClusterK3sDownloadsConfig(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 	_, kubePath, _ := utils.CreateKubeConfigPath(cc.Name)
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CopyFromContainer")[0].Arguments
 	assert.Equal(t, "containerid", params.String(0))
 	assert.Equal(t, "/output/kubeconfig.yaml", params.String(1))
 	assert.Equal(t, kubePath, params.String(2))
+	assert.Equal(t, kubePath, cc.KubeConfig)
 }
 
 func TestClusterK3sRaisesErrorWhenUnableToDownloadConfig(t *testing.T) {
@@ -307,7 +320,7 @@ func TestClusterK3sRaisesErrorWhenUnableToDownloadConfig(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -323,7 +336,7 @@ func TestClusterK3sSetsServerInConfig(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	// check the kubeconfig file for docker uses a network ip not localhost
@@ -340,36 +353,14 @@ func TestClusterK3sSetsServerInConfig(t *testing.T) {
 	assert.Contains(t, string(d), "https://"+utils.GetDockerIP())
 }
 
-func TestClusterK3sCreatesDockerConfig(t *testing.T) {
-	cc, md, mk, mc := setupClusterMocks(t)
-
-	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
-
-	err := p.Create()
-	assert.NoError(t, err)
-
-	// check the kubeconfig file for docker uses a network ip not localhost
-
-	// check file has been written
-	_, _, dockerPath := utils.CreateKubeConfigPath(clusterConfig.Name)
-	f, err := os.Open(dockerPath)
-	assert.NoError(t, err)
-	defer f.Close()
-
-	// check file contains docker ip
-	d, err := ioutil.ReadAll(f)
-	assert.NoError(t, err)
-	assert.Contains(t, string(d), fmt.Sprintf("server.%s", utils.FQDN(clusterConfig.Name, string(clusterConfig.Type))))
-}
-
 func TestClusterK3sCreatesKubeClient(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
-	mk.AssertCalled(t, "SetConfig", mock.Anything)
+	mk.AssertCalled(t, "SetConfig", cc.KubeConfig)
 }
 
 func TestClusterK3sErrorsWhenFailedToCreateKubeClient(t *testing.T) {
@@ -380,7 +371,7 @@ func TestClusterK3sErrorsWhenFailedToCreateKubeClient(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -389,7 +380,7 @@ func TestClusterK3sWaitsForPods(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	mk.AssertCalled(t, "HealthCheckPods", []string{"app=local-path-provisioner", "k8s-app=kube-dns"}, startTimeout)
 }
@@ -402,37 +393,22 @@ func TestClusterK3sErrorsWhenWaitsForPodsFail(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
-func TestClusterK3sStreamsLogsWhenRunning(t *testing.T) {
-	cc, md, mk, mc := setupClusterMocks(t)
-
-	mk.On("GetPodLogs", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
-	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
-
-	err := p.Create()
-	assert.NoError(t, err)
-
-	logReader, err := mk.GetPodLogs(context.TODO(), mock.Anything, mock.Anything)
-	assert.NoError(t, err)
-	assert.NotEmpty(t, logReader)
-	assert.NoError(t, logReader.Close())
-}
-
 func TestClusterK3sImportDockerImagesDoesNothingWhenEmpty(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
-	cc.Images[0].Name = ""
+	cc.CopyImages[0].Name = ""
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertNumberOfCalls(t, "PullImage", 2)
-	md.AssertNotCalled(t, "PullImage", cc.Images[0], false)
-	md.AssertCalled(t, "PullImage", cc.Images[1], false)
+	md.AssertNotCalled(t, "PullImage", cc.CopyImages[0], false)
+	md.AssertCalled(t, "PullImage", cc.CopyImages[1], false)
 }
 
 func TestClusterK3sImportDockerImagesPullsImages(t *testing.T) {
@@ -440,11 +416,11 @@ func TestClusterK3sImportDockerImagesPullsImages(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertNumberOfCalls(t, "PullImage", 3)
-	md.AssertCalled(t, "PullImage", cc.Images[0], false)
-	md.AssertCalled(t, "PullImage", cc.Images[1], false)
+	md.AssertCalled(t, "PullImage", cc.CopyImages[0], false)
+	md.AssertCalled(t, "PullImage", cc.CopyImages[1], false)
 }
 
 func TestClusterK3sImportDockerCopiesImages(t *testing.T) {
@@ -452,7 +428,7 @@ func TestClusterK3sImportDockerCopiesImages(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "CopyLocalDockerImagesToVolume", []string{"consul:1.6.1", "vault:1.6.1"}, utils.FQDNVolumeName(utils.ImageVolumeName), false)
 }
@@ -460,11 +436,11 @@ func TestClusterK3sImportDockerCopiesImages(t *testing.T) {
 func TestClusterK3sImportDockerCopyImageFailReturnsError(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 	removeOn(&md.Mock, "CopyLocalDockerImagesToVolume")
-	md.On("CopyLocalDockerImagesToVolume", mock.Anything, mock.Anything, mock.Anything).Return("", fmt.Errorf("boom"))
+	md.On("CopyLocalDockerImagesToVolume", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("boom"))
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -472,10 +448,10 @@ func TestClusterK3sImportDockerRunsExecCommand(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
-	err := p.Create()
+	err := p.Create(context.Background())
 
 	assert.NoError(t, err)
-	md.AssertCalled(t, "ExecuteCommand", "containerid", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	md.AssertCalled(t, "ExecuteCommand", "containerid", []string{"ctr", "image", "import", "/images/file.tar.gz"}, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestClusterK3sImportDockerExecFailReturnsError(t *testing.T) {
@@ -485,7 +461,7 @@ func TestClusterK3sImportDockerExecFailReturnsError(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -494,7 +470,7 @@ func TestClusterK3sGeneratesCertsForConnector(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	mc.AssertCalled(t, "GetLocalCertBundle", mock.Anything)
@@ -504,30 +480,16 @@ func TestClusterK3sGeneratesCertsForConnector(t *testing.T) {
 		mock.Anything,
 		mock.Anything,
 		mock.Anything,
-		mock.Anything,
+		utils.CertsDir(cc.Name),
 	)
 }
 
-func TestClusterK3sGeneratesCertsForDeployment(t *testing.T) {
-	cc, md, mk, mc := setupClusterMocks(t)
-
-	cp := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
-
-	err := cp.Create()
-	assert.NoError(t, err)
-
-	//args := getCalls(&mk.Mock, "Apply")[0]
-	//path := strings.Split(args.Arguments[0].([]string)[0], "/")
-
-	mc.AssertCalled(t, "GenerateLeafCert", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
-}
-
 func TestClusterK3sDeploysConnector(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	files := []string{
@@ -552,7 +514,7 @@ func TestClusterK3sWaitsForConnectorStart(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	mk.AssertCalled(t, "HealthCheckPods", []string{"app=connector"}, 60*time.Second)
@@ -564,30 +526,30 @@ func TestClusterK3sDestroyGetsIDr(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
-	md.AssertCalled(t, "FindContainerIDs", "server."+clusterConfig.Name, clusterConfig.Type)
+	md.AssertCalled(t, "FindContainerIDs", utils.FQDN("server."+clusterConfig.Name, "", clusterConfig.Type))
 }
 
 func TestClusterK3sDestroyWithFindIDErrorReturnsError(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 	removeOn(&md.Mock, "FindContainerIDs")
-	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("boom"))
+	md.On("FindContainerIDs", mock.Anything).Return(nil, fmt.Errorf("boom"))
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.Error(t, err)
 }
 
 func TestClusterK3sDestroyWithNoIDReturns(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 	removeOn(&md.Mock, "FindContainerIDs")
-	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return(nil, nil)
+	md.On("FindContainerIDs", mock.Anything).Return(nil, nil)
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 	md.AssertNotCalled(t, "RemoveContainer", mock.Anything, mock.Anything)
 }
@@ -595,36 +557,36 @@ func TestClusterK3sDestroyWithNoIDReturns(t *testing.T) {
 func TestClusterK3sDestroyRemovesContainer(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 	removeOn(&md.Mock, "FindContainerIDs")
-	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"found"}, nil)
+	md.On("FindContainerIDs", mock.Anything).Return([]string{"found"}, nil)
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
-	md.AssertCalled(t, "RemoveContainer", mock.Anything, false)
+	md.AssertCalled(t, "RemoveContainer", "found", false)
 }
 
 func TestClusterK3sDestroyRemovesConfig(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 	removeOn(&md.Mock, "FindContainerIDs")
-	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"found"}, nil)
+	md.On("FindContainerIDs", mock.Anything).Return([]string{"found"}, nil)
 
-	_, dir := utils.GetClusterConfig(string(cc.Info().Type) + "." + cc.Info().Name)
+	_, kubePath, _ := utils.CreateKubeConfigPath(cc.Name)
 
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "RemoveContainer", mock.Anything, false)
 
-	assert.NoDirExists(t, dir)
+	assert.NoFileExists(t, kubePath)
 }
 
 func TestLookupReturnsIDs(t *testing.T) {
 	cc, md, mk, mc := setupClusterMocks(t)
 	p := NewK8sCluster(cc, md, mk, nil, mc, hclog.NewNullLogger())
 	removeOn(&md.Mock, "FindContainerIDs")
-	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"found"}, nil)
+	md.On("FindContainerIDs", mock.Anything).Return([]string{"found"}, nil)
 
 	ids, err := p.Lookup()
 
@@ -632,17 +594,20 @@ func TestLookupReturnsIDs(t *testing.T) {
 	assert.Equal(t, []string{"found"}, ids)
 }
 
-var clusterNetwork = config.NewNetwork("cloud")
+var clusterNetwork = &resources.Network{
+	ResourceMetadata: types.ResourceMetadata{ID: "resource.network.cloud", Name: "cloud", Type: resources.TypeNetwork},
+	Subnet:           "10.5.0.0/16",
+}
 
-var clusterConfig = &config.K8sCluster{
-	ResourceInfo: config.ResourceInfo{Name: "test", Type: config.TypeK8sCluster},
-	Driver:       "k3s",
-	Version:      "v1.0.0",
-	Images: []config.Image{
-		config.Image{Name: "consul:1.6.1"},
-		config.Image{Name: "vault:1.6.1"},
+var clusterConfig = &resources.K8sCluster{
+	ResourceMetadata: types.ResourceMetadata{ID: "resource.k8s_cluster.test", Name: "test", Type: resources.TypeK8sCluster},
+	Image:            &resources.Image{Name: "shipyardrun/k3s:v1.26.3"},
+	APIPort:          443,
+	CopyImages: []resources.Image{
+		resources.Image{Name: "consul:1.6.1"},
+		resources.Image{Name: "vault:1.6.1"},
 	},
-	Networks: []config.NetworkAttachment{config.NetworkAttachment{Name: "cloud"}},
+	Networks: []resources.NetworkAttachment{resources.NetworkAttachment{ID: "resource.network.cloud"}},
 }
 
 var kubeconfig = `
@@ -36,12 +36,12 @@ func NewNomadCluster(c *resources.NomadCluster, cc clients.ContainerTasks, hc cl
 }
 
 // Create implements interface method to create a cluster of the specified type
-func (c *NomadCluster) Create() error {
-	return c.createNomad()
+func (c *NomadCluster) Create(ctx context.Context) error {
+	return c.createNomad(ctx)
 }
 
 // Destroy implements interface method to destroy a cluster
-func (c *NomadCluster) Destroy() error {
+func (c *NomadCluster) Destroy(ctx context.Context) error {
 	return c.destroyNomad()
 }
 
@@ -71,28 +71,14 @@ func (c *NomadCluster) Lookup() ([]string, error) {
 
 // Refresh is called when `up` is run and the resource has been marked as created
 // checks the nodes are healthy and replaces if needed.
-func (c *NomadCluster) Refresh() error {
+func (c *NomadCluster) Refresh(ctx context.Context) error {
 	c.log.Info("Refresh Nomad Cluster", "ref", c.config.ID)
 
-	c.log.Debug("Checking health of server node", "ref", c.config.ID, "server", c.config.ServerFQRN)
-
-	ids, _ := c.client.FindContainerIDs(c.config.ServerFQRN)
-	if len(ids) == 1 {
-		c.log.Debug("Server node exists", "ref", c.config.ID, "server", c.config.ServerFQRN, "id", ids[0])
-	}
-
-	// find any nodes that have crashed or have been deleted
-	for _, cl := range c.config.ClientFQRN {
-		c.log.Debug("Checking health of client nodes", "ref", c.config.ID, "client", cl)
-
-		ids, _ := c.client.FindContainerIDs(cl)
-		if len(ids) == 1 {
-			c.log.Debug("Client node healthy", "ref", c.config.ID, "client", cl, "id", ids[0])
-		} else {
-			c.log.Debug("Client node does not exist", "ref", c.config.ID, "client", cl)
-			// recreate the node
-			c.config.ClientFQRN = removeElement(c.config.ClientFQRN, cl)
-		}
+	// nodes that have crashed or have been deleted require the cluster to
+	// be re-created
+	err := c.CheckDrift()
+	if err != nil {
+		return err
 	}
 
 	// Has the number of clients nodes changed and are we scaling down?
@@ -124,8 +110,12 @@ func (c *NomadCluster) Refresh() error {
 
 		wg.Wait()
 
-		c.nomadClient.SetConfig(fmt.Sprintf("http://%s", c.config.ExternalIP), c.config.APIPort, c.config.ClientNodes+1)
-		err := c.nomadClient.HealthCheckAPI(startTimeout)
+		nc, err := c.nomadClient.SetConfig(fmt.Sprintf("http://%s", c.config.ExternalIP), c.config.APIPort, c.config.ClientNodes+1)
+		if err != nil {
+			return err
+		}
+
+		err = nc.HealthCheckAPI(ctx, startTimeout)
 		if err != nil {
 			return err
 		}
@@ -153,8 +143,12 @@ func (c *NomadCluster) Refresh() error {
 			c.log.Debug("Successfully created client node", "ref", c.config.ID, "client", fqdn)
 		}
 
-		c.nomadClient.SetConfig(fmt.Sprintf("http://%s", c.config.ExternalIP), c.config.APIPort, c.config.ClientNodes+1)
-		err := c.nomadClient.HealthCheckAPI(startTimeout)
+		nc, err := c.nomadClient.SetConfig(fmt.Sprintf("http://%s", c.config.ExternalIP), c.config.APIPort, c.config.ClientNodes+1)
+		if err != nil {
+			return err
+		}
+
+		err = nc.HealthCheckAPI(ctx, startTimeout)
 		if err != nil {
 			return err
 		}
@@ -164,6 +158,11 @@ func (c *NomadCluster) Refresh() error {
 	return nil
 }
 
+// CheckDrift checks that the server and client nodes still exist
+func (c *NomadCluster) CheckDrift() error {
+	return checkContainersExist(c.client, append([]string{c.config.ServerFQRN}, c.config.ClientFQRN...)...)
+}
+
 func removeElement(s []string, item string) []string {
 	// find the element
 	index := -1
@@ -182,7 +181,7 @@ func removeElement(s []string, item string) []string {
 	return append(s[:index], s[index+1:]...)
 }
 
-func (c *NomadCluster) createNomad() error {
+func (c *NomadCluster) createNomad(ctx context.Context) error {
 	c.log.Info("Creating Cluster", "ref", c.config.ID)
 
 	// check the client nodes do not already exist
@@ -208,7 +207,7 @@ func (c *NomadCluster) createNomad() error {
 	}
 
 	// pull the container image
-	err = c.client.PullImage(*c.config.Image, false)
+	err = c.client.PullImage(ctx, *c.config.Image, false)
 	if err != nil {
 		return err
 	}
@@ -280,8 +279,12 @@ func (c *NomadCluster) createNomad() error {
 	}
 
 	// ensure all client nodes are up
-	c.nomadClient.SetConfig(fmt.Sprintf("http://%s", c.config.ExternalIP), c.config.APIPort, clientNodes)
-	err = c.nomadClient.HealthCheckAPI(startTimeout)
+	nc, err := c.nomadClient.SetConfig(fmt.Sprintf("http://%s", c.config.ExternalIP), c.config.APIPort, clientNodes)
+	if err != nil {
+		return err
+	}
+
+	err = nc.HealthCheckAPI(ctx, startTimeout)
 	if err != nil {
 		return err
 	}
@@ -290,7 +293,7 @@ func (c *NomadCluster) createNomad() error {
 	// importing images means that Nomad does not need to pull from a remote docker hub
 	if c.config.CopyImages != nil && len(c.config.CopyImages) > 0 {
 		// import into the server
-		err := c.ImportLocalDockerImages("images", serverID, c.config.CopyImages, false)
+		err := c.ImportLocalDockerImages(ctx, "images", serverID, c.config.CopyImages, false)
 		if err != nil {
 			return xerrors.Errorf("Error importing Docker images: %w", err)
 		}
@@ -301,7 +304,7 @@ func (c *NomadCluster) createNomad() error {
 		var importErr error
 		for _, id := range clientIDs {
 			go func(id string) {
-				err := c.ImportLocalDockerImages("images", id, c.config.CopyImages, false)
+				err := c.ImportLocalDockerImages(ctx, "images", id, c.config.CopyImages, false)
 				clWait.Done()
 				if err != nil {
 					cMutex.Lock()
@@ -317,7 +320,7 @@ func (c *NomadCluster) createNomad() error {
 		}
 	}
 
-	err = c.deployConnector()
+	err = c.deployConnector(nc)
 	if err != nil {
 		return fmt.Errorf("unable to deploy Connector: %s", err)
 	}
@@ -537,7 +540,7 @@ func (c *NomadCluster) createClientNode(id string, image, volumeID, serverID str
 
 func (c *NomadCluster) appendProxyEnv(cc *resources.Container) error {
 	// load the CA from a file
-	ca, err := ioutil.ReadFile(filepath.Join(utils.CertsDir(""), "/root.cert"))
+	ca, err := ioutil.ReadFile(filepath.Join(utils.ConnectorCertsDir(), "/root.cert"))
 	if err != nil {
 		return fmt.Errorf("unable to read root CA for proxy: %s", err)
 	}
@@ -564,12 +567,12 @@ func (c *NomadCluster) appendProxyEnv(cc *resources.Container) error {
 	return nil
 }
 
-func (c *NomadCluster) deployConnector() error {
+func (c *NomadCluster) deployConnector(nc clients.Nomad) error {
 	c.log.Debug("Deploying connector", "ref", c.config.ID)
 
 	// generate the certificates
 	// generate the certificates for the service
-	cb, err := c.connector.GetLocalCertBundle(utils.CertsDir(""))
+	cb, err := c.connector.GetLocalCertBundle(utils.ConnectorCertsDir())
 	if err != nil {
 		return fmt.Errorf("unable to fetch root certificates for ingress: %s", err)
 	}
@@ -623,7 +626,7 @@ func (c *NomadCluster) deployConnector() error {
 	ioutil.WriteFile(connectorDeployment, []byte(config), os.ModePerm)
 
 	// deploy the file
-	err = c.nomadClient.Create([]string{connectorDeployment})
+	err = nc.Create([]string{connectorDeployment})
 	if err != nil {
 		return fmt.Errorf("unable to run Connector deployment: %s", err)
 	}
@@ -639,7 +642,7 @@ func (c *NomadCluster) deployConnector() error {
 			break
 		}
 
-		ok, lastError = c.nomadClient.JobRunning("connector")
+		ok, lastError = nc.JobRunning("connector")
 		if err != nil {
 			lastError = fmt.Errorf("unable to check Connector deployment health: %s", err)
 			continue
@@ -656,7 +659,7 @@ func (c *NomadCluster) deployConnector() error {
 }
 
 // ImportLocalDockerImages fetches Docker images stored on the local client and imports them into the cluster
-func (c *NomadCluster) ImportLocalDockerImages(name string, id string, images []resources.Image, force bool) error {
+func (c *NomadCluster) ImportLocalDockerImages(ctx context.Context, name string, id string, images []resources.Image, force bool) error {
 	imgs := []string{}
 
 	for _, i := range images {
@@ -665,7 +668,7 @@ func (c *NomadCluster) ImportLocalDockerImages(name string, id string, images []
 			continue
 		}
 
-		err := c.client.PullImage(i, false)
+		err := c.client.PullImage(ctx, i, false)
 		if err != nil {
 			return err
 		}
@@ -683,7 +686,7 @@ func (c *NomadCluster) ImportLocalDockerImages(name string, id string, images []
 	// execute the command to import the image
 	// write any command output to the logger
 	for _, i := range imagesFile {
-		err = c.client.ExecuteCommand(id, []string{"docker", "load", "-i", i}, nil, "/", "", "", c.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}))
+		err = c.client.ExecuteCommand(ctx, id, []string{"docker", "load", "-i", i}, nil, "/", "", "", c.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}))
 		if err != nil {
 			return err
 		}
@@ -748,7 +751,7 @@ func (c *NomadCluster) destroyNode(id string) error {
 		// remove from the networks
 		for _, n := range nets {
 			c.log.Debug("Detaching container from network", "ref", c.config.ID, "id", i, "network", n.Metadata().Name)
-			err := c.client.DetachNetwork(n.Metadata().Name, i)
+			err := c.client.DetachNetwork(utils.FQDNNetworkName(n.Metadata().Name), i)
 			if err != nil {
 				c.log.Error("Unable to detach network", "ref", c.config.ID, "network", n.Metadata().Name, "error", err)
 			}
@@ -1,7 +1,7 @@
 package providers
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -13,152 +13,149 @@ import (
 	"github.com/hashicorp/go-hclog"
 
 	"github.com/jumppad-labs/jumppad/pkg/clients"
-	"github.com/jumppad-labs/jumppad/pkg/clients/mocks"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/mohae/deepcopy"
+	"github.com/shipyard-run/hclconfig"
+	"github.com/shipyard-run/hclconfig/types"
 	"github.com/stretchr/testify/mock"
 	assert "github.com/stretchr/testify/require"
 )
 
-// setupClusterMocks sets up a happy path for mocks
-func setupNomadClusterMocks(t *testing.T) (*config.NomadCluster, *clients.MockContainerTasks, *mocks.MockNomad) {
+// setupNomadClusterMocks sets up a happy path for mocks
+func setupNomadClusterMocks(t *testing.T) (*resources.NomadCluster, *clients.MockContainerTasks, *clients.MockNomad, *clients.ConnectorMock) {
 
 	md := &clients.MockContainerTasks{}
-	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{}, nil)
+	md.On("FindContainerIDs", mock.Anything).Return([]string{}, nil)
 	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
-	md.On("CreateVolume", mock.Anything, mock.Anything).Return("123", nil)
+	md.On("CreateVolume", mock.Anything).Return("123", nil)
 	md.On("CreateContainer", mock.Anything).Return("containerid", nil)
-	md.On("ContainerLogs", mock.Anything, true, true).Return(
-		ioutil.NopCloser(bytes.NewBufferString("Running kubelet")),
-		nil,
-	)
-	md.On("CopyFromContainer", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	md.On("CopyLocalDockerImagesToVolume", mock.Anything, mock.Anything, mock.Anything).Return([]string{"file.tar.gz"}, nil)
 	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	md.On("RemoveContainer", mock.Anything, mock.Anything).Return(nil)
 	md.On("RemoveVolume", mock.Anything).Return(nil)
-	md.On("DetachNetwork", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	md.On("DetachNetwork", mock.Anything, mock.Anything).Return(nil)
 
-	mh := &mocks.MockNomad{}
-	mh.On("SetConfig", mock.Anything, mock.Anything).Return(nil)
+	mh := &clients.MockNomad{}
+	mh.On("SetConfig", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mh.On("HealthCheckAPI", mock.Anything).Return(nil)
+	mh.On("Create", mock.Anything).Return(nil)
+	mh.On("JobRunning", "connector").Return(true, nil)
+
+	mc := &clients.ConnectorMock{}
+	mc.On("GetLocalCertBundle", mock.Anything).Return(&clients.CertBundle{}, nil)
+	mc.On("GenerateLeafCert",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(&clients.CertBundle{}, nil)
 
 	// set the home folder to a temp folder
 	tmpDir := t.TempDir()
-	currentHome := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
+	currentHome := os.Getenv(utils.HomeEnvName())
+	os.Setenv(utils.HomeEnvName(), tmpDir)
 
-	cafile := filepath.Join(utils.CertsDir(""), "root.cert")
+	cafile := filepath.Join(utils.ConnectorCertsDir(), "root.cert")
 	ioutil.WriteFile(cafile, []byte("CA"), os.ModePerm)
 
 	// copy the config
-	cc := deepcopy.Copy(clusterNomadConfig).(*config.NomadCluster)
-	cn := *clusterNetwork
+	cc := deepcopy.Copy(clusterNomadConfig).(*resources.NomadCluster)
+	cn := deepcopy.Copy(clusterNetwork).(*resources.Network)
 
-	c := config.New()
-	c.AddResource(cc)
-	c.AddResource(&cn)
+	c := hclconfig.NewConfig()
+	c.AppendResource(cc)
+	c.AppendResource(cn)
 
 	t.Cleanup(func() {
-		os.Setenv("HOME", currentHome)
+		os.Setenv(utils.HomeEnvName(), currentHome)
 	})
 
-	return cc, md, mh
+	return cc, md, mh, mc
 }
 
 func TestClusterNomadErrorsWhenUnableToLookupIDs(t *testing.T) {
 	md := &clients.MockContainerTasks{}
-	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("boom"))
+	md.On("FindContainerIDs", mock.Anything).Return(nil, fmt.Errorf("boom"))
 
-	p := NewNomadCluster(clusterNomadConfig, md, nil, hclog.NewNullLogger())
+	p := NewNomadCluster(clusterNomadConfig, md, nil, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestClusterNomadErrorsWhenClusterExists(t *testing.T) {
 	md := &clients.MockContainerTasks{}
-	md.On("FindContainerIDs", "server."+clusterNomadConfig.Name, mock.Anything).Return([]string{"abc"}, nil)
+	md.On("FindContainerIDs", utils.FQDN("server."+clusterNomadConfig.Name, "", clusterNomadConfig.Type)).Return([]string{"abc"}, nil)
 
-	p := NewNomadCluster(clusterNomadConfig, md, nil, hclog.NewNullLogger())
+	p := NewNomadCluster(clusterNomadConfig, md, nil, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
-func TestClusterNomadErrorsWhenWorkerNodesExist(t *testing.T) {
-	cc, md, _ := setupNomadClusterMocks(t)
+func TestClusterNomadErrorsWhenClientNodesExist(t *testing.T) {
+	cc, md, mh, mc := setupNomadClusterMocks(t)
 	cc.ClientNodes = 3
 	removeOn(&md.Mock, "FindContainerIDs")
-	md.On("FindContainerIDs", "1.client."+clusterNomadConfig.Name, mock.Anything).Return([]string{"abc"}, nil)
+	md.On("FindContainerIDs", utils.FQDN("1.client."+clusterNomadConfig.Name, "", clusterNomadConfig.Type)).Return([]string{"abc"}, nil)
 
-	p := NewNomadCluster(cc, md, nil, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestClusterNomadPullsImage(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
+	cc, md, mh, mc := setupNomadClusterMocks(t)
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
-	md.AssertCalled(t, "PullImage", config.Image{Name: "shipyardrun/nomad:v1.0.0"}, false)
-}
-
-func TestClusterNomadPullsImageWithDefault(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
-	cc.Version = "" // reset the version
-
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
-
-	err := p.Create()
-	assert.NoError(t, err)
-	md.AssertCalled(t, "PullImage", config.Image{Name: "shipyardrun/nomad:" + nomadBaseVersion}, false)
+	md.AssertCalled(t, "PullImage", resources.Image{Name: "shipyardrun/nomad:1.4.0"}, false)
 }
 
 func TestClusterNomadCreatesANewVolume(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
+	cc, md, mh, mc := setupNomadClusterMocks(t)
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "CreateVolume", utils.ImageVolumeName)
 }
 
 func TestClusterNomadFailsWhenUnableToCreatesANewVolume(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
+	cc, md, mh, mc := setupNomadClusterMocks(t)
 
 	removeOn(&md.Mock, "CreateVolume")
-	md.On("CreateVolume", mock.Anything, mock.Anything).Return("", fmt.Errorf("boom"))
+	md.On("CreateVolume", mock.Anything).Return("", fmt.Errorf("boom"))
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 	md.AssertCalled(t, "CreateVolume", utils.ImageVolumeName)
 }
 
 func TestClusterNomadCreatesAServer(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
+	cc, md, mh, mc := setupNomadClusterMocks(t)
 
-	cc.Volumes = []config.Volume{config.Volume{Source: "./files", Destination: "/files"}}
+	cc.Volumes = []resources.Volume{resources.Volume{Source: "./files", Destination: "/files"}}
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
-	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*resources.Container)
 
 	// validate the basic details for the server container
 	assert.Contains(t, params.Name, "server")
 	assert.Contains(t, params.Image.Name, "nomad")
-	assert.Equal(t, clusterNetwork.Name, params.Networks[0].Name)
+	assert.Equal(t, clusterNetwork.ID, params.Networks[0].ID)
 	assert.True(t, params.Privileged)
 
 	// validate that the volume is correctly set
@@ -167,7 +164,7 @@ func TestClusterNomadCreatesAServer(t *testing.T) {
 	assert.Equal(t, "volume", params.Volumes[0].Type)
 
 	// validate that the config volume has been added
-	assert.Contains(t, params.Volumes[1].Source, "test/server_config.hcl")
+	assert.Contains(t, params.Volumes[1].Source, "test/config/server_config.hcl")
 	assert.Equal(t, "/etc/nomad.d/config.hcl", params.Volumes[1].Destination)
 
 	// validate that the consul config is added
@@ -179,55 +176,56 @@ func TestClusterNomadCreatesAServer(t *testing.T) {
 	assert.Equal(t, "/files", params.Volumes[3].Destination)
 
 	// validate the API port is set
-	intLocal, _ := strconv.Atoi(params.Ports[0].Local)
-	intHost, _ := strconv.Atoi(params.Ports[0].Host)
-	assert.GreaterOrEqual(t, intLocal, 4646)
-	assert.GreaterOrEqual(t, intHost, utils.MinRandomPort)
-	assert.LessOrEqual(t, intHost, utils.MaxRandomPort)
+	assert.Equal(t, "4646", params.Ports[0].Local)
+	assert.Equal(t, fmt.Sprintf("%d", cc.APIPort), params.Ports[0].Host)
 	assert.Equal(t, "tcp", params.Ports[0].Protocol)
 
-	// validate the Connector port is set
-	intLocal, _ = strconv.Atoi(params.Ports[1].Local)
-	intHost, _ = strconv.Atoi(params.Ports[1].Host)
-	assert.GreaterOrEqual(t, intLocal, 19090)
+	// validate the Connector ports are set
+	intLocal, _ := strconv.Atoi(params.Ports[1].Local)
+	intHost, _ := strconv.Atoi(params.Ports[1].Host)
+	assert.Equal(t, cc.ConnectorPort, intLocal)
 	assert.GreaterOrEqual(t, intHost, utils.MinRandomPort)
 	assert.LessOrEqual(t, intHost, utils.MaxRandomPort)
-	assert.Equal(t, "tcp", params.Ports[0].Protocol)
+	assert.Equal(t, "tcp", params.Ports[1].Protocol)
+
+	intLocal, _ = strconv.Atoi(params.Ports[2].Local)
+	assert.Equal(t, cc.ConnectorPort+1, intLocal)
 }
 
 func TestClusterNomadCreatesClientNodes(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
+	cc, md, mh, mc := setupNomadClusterMocks(t)
 	cc.ClientNodes = 3
 
-	cc.Volumes = []config.Volume{config.Volume{Source: "./files", Destination: "/files"}}
+	cc.Volumes = []resources.Volume{resources.Volume{Source: "./files", Destination: "/files"}}
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	md.AssertNumberOfCalls(t, "CreateContainer", 4)
+	assert.Len(t, cc.ClientFQRN, 3)
 }
 
 func TestClusterNomadCreatesClientNodesWithCorrectDetails(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
+	cc, md, mh, mc := setupNomadClusterMocks(t)
 	cc.ClientNodes = 1
 
-	cc.Volumes = []config.Volume{config.Volume{Source: "./files", Destination: "/files"}}
+	cc.Volumes = []resources.Volume{resources.Volume{Source: "./files", Destination: "/files"}}
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	md.AssertNumberOfCalls(t, "CreateContainer", 2)
 
-	params := getCalls(&md.Mock, "CreateContainer")[1].Arguments[0].(*config.Container)
+	params := getCalls(&md.Mock, "CreateContainer")[1].Arguments[0].(*resources.Container)
 
-	// validate the basic details for the server container
-	assert.Contains(t, params.Name, "1.client.test")
+	// validate the basic details for the client container
+	assert.Contains(t, params.Name, ".client.test")
 	assert.Contains(t, params.Image.Name, "nomad")
-	assert.Equal(t, clusterNetwork.Name, params.Networks[0].Name)
+	assert.Equal(t, clusterNetwork.ID, params.Networks[0].ID)
 	assert.True(t, params.Privileged)
 
 	// validate that the volume is correctly set
@@ -236,7 +234,7 @@ func TestClusterNomadCreatesClientNodesWithCorrectDetails(t *testing.T) {
 	assert.Equal(t, "volume", params.Volumes[0].Type)
 
 	// validate that the config volume has been added
-	assert.Contains(t, params.Volumes[1].Source, "test/client_config.hcl")
+	assert.Contains(t, params.Volumes[1].Source, "test/config/client_config.hcl")
 	assert.Equal(t, "/etc/nomad.d/config.hcl", params.Volumes[1].Destination)
 
 	// validate that the consul config is added
@@ -248,96 +246,87 @@ func TestClusterNomadCreatesClientNodesWithCorrectDetails(t *testing.T) {
 	assert.Equal(t, "/files", params.Volumes[3].Destination)
 }
 
-func TestClusterNomadSetsNodeCountInConfig(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
-	cc.ClientNodes = 10
-
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
-
-	err := p.Create()
-	assert.NoError(t, err)
-
-	conf, _ := utils.GetClusterConfig(string(config.TypeNomadCluster) + "." + cc.Name)
-	assert.Equal(t, cc.ClientNodes, conf.NodeCount)
-}
-
 func TestClusterNomadHealthChecksAPI(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
+	cc, md, mh, mc := setupNomadClusterMocks(t)
+	cc.ClientNodes = 2
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
-	startTimeout = 10 * time.Millisecond // reset the startTimeout, do not want to wait 120s
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
+	// the server and the two clients
+	mh.AssertCalled(t, "SetConfig", "http://"+utils.GetDockerIP(), cc.APIPort, 3)
 	mh.AssertCalled(t, "HealthCheckAPI", mock.Anything)
 }
 
 func TestClusterNomadErrorsIfHealthFails(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
+	cc, md, mh, mc := setupNomadClusterMocks(t)
 
 	removeOn(&mh.Mock, "HealthCheckAPI")
-	mh.On("HealthCheckAPI", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+	mh.On("HealthCheckAPI", mock.Anything).Return(fmt.Errorf("boom"))
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
+
+	st := startTimeout
 	startTimeout = 10 * time.Millisecond // reset the startTimeout, do not want to wait 120s
+	t.Cleanup(func() { startTimeout = st })
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestClusterNomadImportDockerImagesDoesNothingWhenNameEmpty(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
-	cc.Images[0].Name = ""
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	cc, md, mh, mc := setupNomadClusterMocks(t)
+	cc.CopyImages[0].Name = ""
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertNumberOfCalls(t, "PullImage", 2)
-	md.AssertNotCalled(t, "PullImage", cc.Images[0], false)
-	md.AssertCalled(t, "PullImage", cc.Images[1], false)
+	md.AssertNotCalled(t, "PullImage", cc.CopyImages[0], false)
+	md.AssertCalled(t, "PullImage", cc.CopyImages[1], false)
 }
 
 func TestClusterNomadImportDockerImagesPullsImages(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
+	cc, md, mh, mc := setupNomadClusterMocks(t)
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertNumberOfCalls(t, "PullImage", 3)
-	md.AssertCalled(t, "PullImage", cc.Images[0], false)
-	md.AssertCalled(t, "PullImage", cc.Images[1], false)
+	md.AssertCalled(t, "PullImage", cc.CopyImages[0], false)
+	md.AssertCalled(t, "PullImage", cc.CopyImages[1], false)
 }
 
 func TestClusterNomadImportDockerCopiesImages(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
+	cc, md, mh, mc := setupNomadClusterMocks(t)
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
-	md.AssertCalled(t, "CopyLocalDockerImagesToVolume", []string{"consul:1.6.1", "vault:1.6.1"}, "images.volume.shipyard.run", false)
+	md.AssertCalled(t, "CopyLocalDockerImagesToVolume", []string{"consul:1.6.1", "vault:1.6.1"}, utils.FQDNVolumeName("images"), false)
 }
 
 func TestClusterNomadImportDockerCopyImageFailReturnsError(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
+	cc, md, mh, mc := setupNomadClusterMocks(t)
 	removeOn(&md.Mock, "CopyLocalDockerImagesToVolume")
 	md.On("CopyLocalDockerImagesToVolume", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("boom"))
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestClusterNomadImportDockerRunsExecCommand(t *testing.T) {
-	//TODO implement the docker import command
-	cc, md, mh := setupNomadClusterMocks(t)
+	cc, md, mh, mc := setupNomadClusterMocks(t)
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	importCommand := []string{"docker", "load", "-i", "file.tar.gz"}
@@ -345,146 +334,143 @@ func TestClusterNomadImportDockerRunsExecCommand(t *testing.T) {
 }
 
 func TestClusterNomadImportDockerExecFailReturnsError(t *testing.T) {
-	//TODO implement the docker import command
-	cc, md, mh := setupNomadClusterMocks(t)
+	cc, md, mh, mc := setupNomadClusterMocks(t)
 	removeOn(&md.Mock, "ExecuteCommand")
 	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestClusterNomadSetsEnvironmentOnServerAndClient(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
-	cc.Version = ""
+	cc, md, mh, mc := setupNomadClusterMocks(t)
 	cc.ClientNodes = 1
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
-	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
-
-	assert.Equal(t, params.EnvVar["HTTP_PROXY"], utils.HTTPProxyAddress())
-	assert.Equal(t, params.EnvVar["HTTPS_PROXY"], utils.HTTPSProxyAddress())
-	assert.Equal(t, params.EnvVar["NO_PROXY"], utils.ProxyBypass)
-	assert.Equal(t, params.EnvVar["PROXY_CA"], "CA")
-
-	params = getCalls(&md.Mock, "CreateContainer")[1].Arguments[0].(*config.Container)
+	for _, c := range getCalls(&md.Mock, "CreateContainer") {
+		params := c.Arguments[0].(*resources.Container)
 
-	assert.Equal(t, params.EnvVar["HTTP_PROXY"], utils.HTTPProxyAddress())
-	assert.Equal(t, params.EnvVar["HTTPS_PROXY"], utils.HTTPSProxyAddress())
-	assert.Equal(t, params.EnvVar["NO_PROXY"], utils.ProxyBypass)
-	assert.Equal(t, params.EnvVar["PROXY_CA"], "CA")
+		assert.Equal(t, params.Environment["HTTP_PROXY"], utils.HTTPProxyAddress())
+		assert.Equal(t, params.Environment["HTTPS_PROXY"], utils.HTTPSProxyAddress())
+		assert.Equal(t, params.Environment["NO_PROXY"], utils.ProxyBypass+","+clusterNetwork.Subnet)
+		assert.Equal(t, params.Environment["PROXY_CA"], "CA")
+	}
 }
 
-func TestClusterNomadDoesNotSetProxyEnvironmentWithWrongVersion(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
-	cc.Version = "v0.11.7"
-	cc.ClientNodes = 1
+func TestClusterNomadDeploysConnector(t *testing.T) {
+	cc, md, mh, mc := setupNomadClusterMocks(t)
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
-	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
-	assert.Empty(t, params.EnvVar["HTTP_PROXY"])
+	mc.AssertCalled(t, "GenerateLeafCert", mock.Anything, mock.Anything, mock.Anything, mock.Anything, utils.CertsDir(cc.ID))
 
-	params = getCalls(&md.Mock, "CreateContainer")[1].Arguments[0].(*config.Container)
-	assert.Empty(t, params.EnvVar["HTTP_PROXY"])
-}
+	files := getCalls(&mh.Mock, "Create")[0].Arguments[0].([]string)
+	assert.Len(t, files, 1)
+	assert.Equal(t, "connector.nomad", filepath.Base(files[0]))
 
-// Destroy Tests
-func TestClusterNomadDestroyGetsIDs(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
-	cc.ClientNodes = 3
-
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
-
-	err := p.Destroy()
-	assert.NoError(t, err)
-	md.AssertCalled(t, "FindContainerIDs", "server."+clusterNomadConfig.Name, clusterNomadConfig.Type)
-	md.AssertCalled(t, "FindContainerIDs", "1.client."+clusterNomadConfig.Name, clusterNomadConfig.Type)
-	md.AssertCalled(t, "FindContainerIDs", "2.client."+clusterNomadConfig.Name, clusterNomadConfig.Type)
-	md.AssertCalled(t, "FindContainerIDs", "3.client."+clusterNomadConfig.Name, clusterNomadConfig.Type)
+	mh.AssertCalled(t, "JobRunning", "connector")
 }
 
-func TestClusterNomadDestroyWithFindIDErrorReturnsError(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
-	removeOn(&md.Mock, "FindContainerIDs")
-	md.On("FindContainerIDs", "server."+clusterNomadConfig.Name, mock.Anything).Return(nil, fmt.Errorf("boom"))
+func TestClusterNomadDeployConnectorFailReturnsError(t *testing.T) {
+	cc, md, mh, mc := setupNomadClusterMocks(t)
+	removeOn(&mh.Mock, "Create")
+	mh.On("Create", mock.Anything).Return(fmt.Errorf("boom"))
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
-func TestClusterNomadDestroyWithFindIDClientNodeErrorReturnsError(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
-	cc.ClientNodes = 1
-	removeOn(&md.Mock, "FindContainerIDs")
-	md.On("FindContainerIDs", "server."+clusterNomadConfig.Name, mock.Anything).Return(nil, nil)
-	md.On("FindContainerIDs", "1.client."+clusterNomadConfig.Name, mock.Anything).Return(nil, fmt.Errorf("boom"))
+// Destroy Tests
+func TestClusterNomadDestroyGetsIDs(t *testing.T) {
+	cc, md, mh, mc := setupNomadClusterMocks(t)
+	cc.ServerFQRN = "server.test"
+	cc.ClientFQRN = []string{"1.client.test", "2.client.test"}
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Destroy()
-	assert.Error(t, err)
+	err := p.Destroy(context.Background())
+	assert.NoError(t, err)
+	md.AssertCalled(t, "FindContainerIDs", "server.test")
+	md.AssertCalled(t, "FindContainerIDs", "1.client.test")
+	md.AssertCalled(t, "FindContainerIDs", "2.client.test")
 }
 
 func TestClusterNomadDestroyWithNoIDReturns(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
+	cc, md, mh, mc := setupNomadClusterMocks(t)
 	removeOn(&md.Mock, "FindContainerIDs")
-	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return(nil, nil)
+	md.On("FindContainerIDs", mock.Anything).Return(nil, nil)
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
-	md.AssertNotCalled(t, "RemoveContainer", mock.Anything)
+	md.AssertNotCalled(t, "RemoveContainer", mock.Anything, mock.Anything)
 }
 
-func TestClusterNomadDestroyRemovesContainer(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
-	cc.ClientNodes = 3
+func TestClusterNomadDestroyRemovesContainersAndDetachesNetworks(t *testing.T) {
+	cc, md, mh, mc := setupNomadClusterMocks(t)
+	cc.ServerFQRN = "server.test"
+	cc.ClientFQRN = []string{"1.client.test", "2.client.test", "3.client.test"}
 	removeOn(&md.Mock, "FindContainerIDs")
-	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"found"}, nil)
+	md.On("FindContainerIDs", mock.Anything).Return([]string{"found"}, nil)
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 	md.AssertNumberOfCalls(t, "RemoveContainer", 4)
+	md.AssertNumberOfCalls(t, "DetachNetwork", 4)
+	md.AssertCalled(t, "DetachNetwork", "cloud", "found")
 }
 
-func TestClusterNomadDestroyRemovesConfig(t *testing.T) {
-	cc, md, mh := setupNomadClusterMocks(t)
+func TestClusterNomadDestroyRemoveErrorReturnsError(t *testing.T) {
+	cc, md, mh, mc := setupNomadClusterMocks(t)
+	cc.ServerFQRN = "server.test"
 	removeOn(&md.Mock, "FindContainerIDs")
-	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"found"}, nil)
+	md.On("FindContainerIDs", mock.Anything).Return([]string{"found"}, nil)
+	removeOn(&md.Mock, "RemoveContainer")
+	md.On("RemoveContainer", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	_, dir := utils.GetClusterConfig(string(cc.Info().Type) + "." + cc.Info().Name)
+	err := p.Destroy(context.Background())
+	assert.Error(t, err)
+}
+
+func TestClusterNomadDestroyRemovesConfig(t *testing.T) {
+	cc, md, mh, mc := setupNomadClusterMocks(t)
+	cc.ConfigDir = t.TempDir()
+	removeOn(&md.Mock, "FindContainerIDs")
+	md.On("FindContainerIDs", mock.Anything).Return([]string{"found"}, nil)
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, mc, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "RemoveContainer", mock.Anything, mock.Anything)
 
-	assert.NoDirExists(t, dir)
+	assert.NoDirExists(t, cc.ConfigDir)
 }
 
-var clusterNomadConfig = &config.NomadCluster{
-	ResourceInfo: config.ResourceInfo{Name: "test", Type: config.TypeNomadCluster},
-	Version:      "v1.0.0",
-	Images: []config.Image{
-		config.Image{Name: "consul:1.6.1"},
-		config.Image{Name: "vault:1.6.1"},
+var clusterNomadConfig = &resources.NomadCluster{
+	ResourceMetadata: types.ResourceMetadata{ID: "resource.nomad_cluster.test", Name: "test", Type: resources.TypeNomadCluster},
+	Image:            &resources.Image{Name: "shipyardrun/nomad:1.4.0"},
+	APIPort:          4646,
+	CopyImages: []resources.Image{
+		resources.Image{Name: "consul:1.6.1"},
+		resources.Image{Name: "vault:1.6.1"},
 	},
-	Networks:     []config.NetworkAttachment{config.NetworkAttachment{Name: "cloud"}},
+	Networks:     []resources.NetworkAttachment{resources.NetworkAttachment{ID: "resource.network.cloud"}},
 	ConsulConfig: "./files/consul_config.hcl",
 }
@@ -1,9 +1,11 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"github.com/docker/docker/api/types"
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources"
@@ -37,6 +39,16 @@ func NewContainerSidecar(cs *resources.Sidecar, cl clients.ContainerTasks, hc cl
 	co.HealthCheck = cs.HealthCheck
 	co.Image = &cs.Image
 	co.Privileged = cs.Privileged
+	co.Labels = cs.Labels
+	co.CapAdd = cs.CapAdd
+	co.CapDrop = cs.CapDrop
+	co.Sysctls = cs.Sysctls
+	co.Ulimits = cs.Ulimits
+	co.ShmSize = cs.ShmSize
+	co.Devices = cs.Devices
+	co.SecurityOpt = cs.SecurityOpt
+	co.Init = cs.Init
+	co.ReadOnlyRootfs = cs.ReadOnlyRootfs
 	co.Resources = cs.Resources
 	co.MaxRestartCount = cs.MaxRestartCount
 	co.FQRN = cs.FQDN
@@ -45,10 +57,10 @@ func NewContainerSidecar(cs *resources.Sidecar, cl clients.ContainerTasks, hc cl
 }
 
 // Create implements provider method and creates a Docker container with the given config
-func (c *Container) Create() error {
+func (c *Container) Create(ctx context.Context) error {
 	c.log.Info("Creating Container", "ref", c.config.ID)
 
-	err := c.internalCreate()
+	err := c.internalCreate(ctx)
 	if err != nil {
 		return err
 	}
@@ -66,35 +78,65 @@ func (c *Container) Lookup() ([]string, error) {
 	return c.client.FindContainerIDs(c.config.FQRN)
 }
 
-func (c *Container) Refresh() error {
+func (c *Container) Refresh(ctx context.Context) error {
 	c.log.Info("Refresh Container", "ref", c.config.Name)
 
-	return nil
+	return c.CheckDrift()
+}
+
+// CheckDrift checks that the container still exists
+func (c *Container) CheckDrift() error {
+	return checkContainersExist(c.client, c.config.FQRN)
+}
+
+// HealthCheck implements the HealthChecker interface
+func (c *Container) HealthCheck(ctx context.Context) error {
+	if c.config.HealthCheck == nil {
+		return nil
+	}
+
+	ids, err := c.Lookup()
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return fmt.Errorf("container %s does not exist", c.config.FQRN)
+	}
+
+	return c.runHealthChecks(ctx, ids[0])
 }
 
 // Destroy stops and removes the container
-func (c *Container) Destroy() error {
+func (c *Container) Destroy(ctx context.Context) error {
 	c.log.Info("Destroy Container", "ref", c.config.ID)
 
 	return c.internalDestroy()
 }
 
-func (c *Container) internalCreate() error {
+func (c *Container) internalCreate(ctx context.Context) error {
 	// do we need to build an image
 	if c.config.Build != nil {
+		// the checksum is normally set by the engine when the resource
+		// checksum is generated, only calculate it when it is missing
+		if c.config.Build.Checksum == "" {
+			cs, err := c.config.Build.ComputeChecksum()
+			if err != nil {
+				return xerrors.Errorf("Unable to read build context: %w", err)
+			}
 
-		if c.config.Build.Tag == "" {
-			c.config.Build.Tag = "latest"
+			c.config.Build.Checksum = cs
 		}
 
 		c.log.Debug(
 			"Building image",
 			"context", c.config.Build.Context,
 			"dockerfile", c.config.Build.DockerFile,
-			"image", fmt.Sprintf("jumppad.dev/localcache/%s:%s", c.config.Name, c.config.Build.Tag),
+			"target", c.config.Build.Target,
+			"image", fmt.Sprintf("jumppad.dev/localcache/%s:%s", c.config.Name, c.config.Build.ImageTag()),
 		)
 
-		name, err := c.client.BuildContainer(c.config, false)
+		name, err := c.client.BuildContainer(ctx, c.config, false)
 		if err != nil {
 			return xerrors.Errorf("Unable to build image: %w", err)
 		}
@@ -103,7 +145,7 @@ func (c *Container) internalCreate() error {
 		c.config.Image = &resources.Image{Name: name}
 	} else {
 		// pull any images needed for this container
-		err := c.client.PullImage(*c.config.Image, false)
+		err := c.client.PullImage(ctx, *c.config.Image, false)
 		if err != nil {
 			c.log.Error("Error pulling container image", "ref", c.config.ID, "image", c.config.Image.Name)
 
@@ -138,25 +180,133 @@ func (c *Container) internalCreate() error {
 		return nil
 	}
 
-	// check the health of the container
-	if hc := c.config.HealthCheck.HTTP; hc != "" {
-		d, err := time.ParseDuration(c.config.HealthCheck.Timeout)
-		if err != nil {
-			return err
-		}
+	return c.runHealthChecks(ctx, id)
+}
+
+// healthCheckInterval is the time to wait between attempts of the exec and
+// Docker health checks
+var healthCheckInterval = 1 * time.Second
+
+// runHealthChecks runs all the health checks defined for the container,
+// every check must pass before the shared timeout elapses
+func (c *Container) runHealthChecks(ctx context.Context, id string) error {
+	hc := c.config.HealthCheck
+	if hc.HTTP == "" && hc.TCP == "" && len(hc.Exec) == 0 && !hc.DockerHealth {
+		return nil
+	}
+
+	d, err := time.ParseDuration(hc.Timeout)
+	if err != nil {
+		return err
+	}
 
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	// check the health of the container
+	if hc.HTTP != "" {
 		// do we have custom status codes, if not use 200
-		codes := c.config.HealthCheck.HTTPSuccessCodes
+		codes := hc.HTTPSuccessCodes
 		if codes == nil {
 			codes = []int{200}
 		}
 
-		return c.httpClient.HealthCheckHTTP(hc, codes, d)
+		err := c.httpClient.HealthCheckHTTP(ctx, hc.HTTP, codes, d)
+		if err != nil {
+			return healthCheckError(ctx, d, err)
+		}
+	}
+
+	if hc.TCP != "" {
+		err := c.httpClient.HealthCheckTCP(ctx, hc.TCP, d)
+		if err != nil {
+			return healthCheckError(ctx, d, err)
+		}
+	}
+
+	if len(hc.Exec) > 0 {
+		err := c.healthCheckExec(ctx, id, hc.Exec)
+		if err != nil {
+			return healthCheckError(ctx, d, err)
+		}
+	}
+
+	if hc.DockerHealth {
+		err := c.healthCheckDocker(ctx, id)
+		if err != nil {
+			return healthCheckError(ctx, d, err)
+		}
 	}
 
 	return nil
 }
 
+// healthCheckExec runs the command in the container until it exits with
+// the code 0
+func (c *Container) healthCheckExec(ctx context.Context, id string, command []string) error {
+	c.log.Debug("Performing exec health check", "ref", c.config.ID, "command", command)
+
+	for {
+		err := c.client.ExecuteCommand(ctx, id, command, nil, "/", "", "", nil)
+		if err == nil {
+			c.log.Debug("Health check complete", "ref", c.config.ID, "command", command)
+			return nil
+		}
+
+		c.log.Debug("Exec health check failed, retrying", "ref", c.config.ID, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return xerrors.Errorf("exec health check %v did not succeed: %w", command, err)
+		case <-time.After(healthCheckInterval):
+		}
+	}
+}
+
+// healthCheckDocker waits for the HEALTHCHECK defined by the image to
+// report the container as healthy
+func (c *Container) healthCheckDocker(ctx context.Context, id string) error {
+	c.log.Debug("Performing Docker health check", "ref", c.config.ID)
+
+	for {
+		info, err := c.client.ContainerInfo(id)
+		if err != nil {
+			return err
+		}
+
+		cj, ok := info.(types.ContainerJSON)
+		if !ok {
+			return fmt.Errorf("unable to read information for container %s", c.config.ID)
+		}
+
+		if cj.ContainerJSONBase == nil || cj.State == nil || cj.State.Health == nil {
+			return fmt.Errorf("container %s does not define a Docker HEALTHCHECK", c.config.ID)
+		}
+
+		status := cj.State.Health.Status
+		if status == types.Healthy {
+			c.log.Debug("Health check complete", "ref", c.config.ID)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Docker health check for container %s reported status %s", c.config.ID, status)
+		case <-time.After(healthCheckInterval):
+		}
+	}
+}
+
+// healthCheckError returns a timeout error when the shared timeout for the
+// health checks has elapsed
+func healthCheckError(ctx context.Context, timeout time.Duration, err error) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return xerrors.Errorf("health checks did not pass within %s: %w", timeout, err)
+	}
+
+	return err
+}
+
 func (c *Container) internalDestroy() error {
 	ids, err := c.Lookup()
 	if err != nil {
@@ -1,10 +1,12 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
+	dtypes "github.com/docker/docker/api/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/clients/mocks"
@@ -29,8 +31,9 @@ func TestContainerCreatesSuccessfully(t *testing.T) {
 
 	// check calls CreateContainer with the config
 	md.On("CreateContainer", cc).Once().Return("", nil)
+	md.On("ListNetworks", "").Return([]resources.NetworkAttachment{})
 
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	hc.AssertNotCalled(t, "HealthCheckHTTP", mock.Anything, mock.Anything)
@@ -40,42 +43,90 @@ func TestContainerSidecarCreatesContainerSuccessfully(t *testing.T) {
 	md := &clients.MockContainerTasks{}
 	hc := &mocks.MockHTTP{}
 
-	cc := &resources.Container{ResourceMetadata: types.ResourceMetadata{
+	cs := &resources.Sidecar{ResourceMetadata: types.ResourceMetadata{
+		Name: "tests",
+		Type: resources.TypeSidecar,
+	}}
+
+	cs.DependsOn = []string{"resource.network.test"}
+	cs.Target = "resource.container.app"
+	cs.Image = resources.Image{Name: "abc"}
+	cs.Volumes = []resources.Volume{resources.Volume{}}
+	cs.Command = []string{"hello"}
+	cs.Entrypoint = []string{"hello"}
+	cs.Environment = map[string]string{"hello": "world"}
+	cs.HealthCheck = &resources.HealthCheck{}
+	cs.Privileged = true
+	cs.Resources = &resources.Resources{}
+	cs.MaxRestartCount = 10
+
+	md.On("PullImage", cs.Image, false).Once().Return(nil)
+	md.On("CreateContainer", mock.Anything).Once().Return("", nil)
+	md.On("ListNetworks", "").Return([]resources.NetworkAttachment{})
+
+	c := NewContainerSidecar(cs, md, hc, hclog.NewNullLogger())
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	ac := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*resources.Container)
+
+	assert.Equal(t, cs.Name, ac.Name)
+	assert.Equal(t, cs.DependsOn, ac.DependsOn)
+	assert.Equal(t, cs.Target, ac.Networks[0].ID)
+	assert.Equal(t, cs.Volumes, ac.Volumes)
+	assert.Equal(t, cs.Command, ac.Command)
+	assert.Equal(t, cs.Entrypoint, ac.Entrypoint)
+	assert.Equal(t, cs.Environment, ac.Environment)
+	assert.Equal(t, cs.HealthCheck, ac.HealthCheck)
+	assert.Equal(t, cs.Image.Name, ac.Image.Name)
+	assert.Equal(t, cs.Privileged, ac.Privileged)
+	assert.Equal(t, cs.Resources, ac.Resources)
+	assert.Equal(t, cs.Type, ac.Type)
+	assert.Equal(t, cs.MaxRestartCount, ac.MaxRestartCount)
+}
+
+func TestContainerSidecarCopiesKernelOptions(t *testing.T) {
+	md := &clients.MockContainerTasks{}
+	hc := &mocks.MockHTTP{}
+
+	cs := &resources.Sidecar{ResourceMetadata: types.ResourceMetadata{
 		Name: "tests",
 	}}
 
-	cc.Depends = []string{"resource.network.test"}
-	cc.Image = &resources.Image{Name: "abc"}
-	cc.Volumes = []resources.Volume{resources.Volume{}}
-	cc.Command = []string{"hello"}
-	cc.Entrypoint = []string{"hello"}
-	cc.Env = map[string]string{"hello": "world"}
-	cc.HealthCheck = &resources.HealthCheck{}
-	cc.Privileged = true
-	cc.Resources = &resources.Resources{}
-	cc.MaxRestartCount = 10
-
-	md.On("PullImage", cc.Image, false).Once().Return(nil)
+	cs.Target = "resource.container.app"
+	cs.Image = resources.Image{Name: "abc"}
+	cs.Labels = map[string]string{"team": "network"}
+	cs.CapAdd = []string{"NET_ADMIN"}
+	cs.CapDrop = []string{"MKNOD"}
+	cs.Sysctls = map[string]string{"net.ipv4.ip_forward": "1"}
+	cs.Ulimits = []resources.Ulimit{{Name: "nofile", Soft: 1024, Hard: 1024}}
+	cs.ShmSize = 64
+	cs.Devices = []resources.Device{{Source: "/dev/net/tun"}}
+	cs.SecurityOpt = []string{"apparmor=unconfined"}
+	cs.Init = true
+	cs.ReadOnlyRootfs = true
+
+	md.On("PullImage", mock.Anything, false).Once().Return(nil)
 	md.On("CreateContainer", mock.Anything).Once().Return("", nil)
+	md.On("ListNetworks", "").Return([]resources.NetworkAttachment{})
 
-	c := NewContainerSidecar(cc, md, hc, hclog.NewNullLogger())
-	err := c.Create()
+	c := NewContainerSidecar(cs, md, hc, hclog.NewNullLogger())
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	ac := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*resources.Container)
 
-	assert.Equal(t, cc.Name, ac.Name)
-	assert.Equal(t, cc.Depends, ac.Depends)
-	assert.Equal(t, cc.Volumes, ac.Volumes)
-	assert.Equal(t, cc.Command, ac.Command)
-	assert.Equal(t, cc.Entrypoint, ac.Entrypoint)
-	assert.Equal(t, cc.Env, ac.Env)
-	assert.Equal(t, cc.HealthCheck, ac.HealthCheck)
-	assert.Equal(t, cc.Image.Name, ac.Image.Name)
-	assert.Equal(t, cc.Privileged, ac.Privileged)
-	assert.Equal(t, cc.Resources, ac.Resources)
-	assert.Equal(t, cc.Type, ac.Type)
-	assert.Equal(t, cc.MaxRestartCount, ac.MaxRestartCount)
+	assert.Equal(t, cs.Labels, ac.Labels)
+	assert.Equal(t, cs.CapAdd, ac.CapAdd)
+	assert.Equal(t, cs.CapDrop, ac.CapDrop)
+	assert.Equal(t, cs.Sysctls, ac.Sysctls)
+	assert.Equal(t, cs.Ulimits, ac.Ulimits)
+	assert.Equal(t, cs.ShmSize, ac.ShmSize)
+	assert.Equal(t, cs.Devices, ac.Devices)
+	assert.Equal(t, cs.SecurityOpt, ac.SecurityOpt)
+	assert.Equal(t, cs.Init, ac.Init)
+	assert.Equal(t, cs.ReadOnlyRootfs, ac.ReadOnlyRootfs)
+	assert.Empty(t, ac.Hostname)
 }
 
 func TestContainerRunsHTTPChecks(t *testing.T) {
@@ -94,10 +145,11 @@ func TestContainerRunsHTTPChecks(t *testing.T) {
 
 	md.On("PullImage", *cc.Image, false).Once().Return(nil)
 	md.On("CreateContainer", cc).Once().Return("", nil)
+	md.On("ListNetworks", "").Return([]resources.NetworkAttachment{})
 
 	hc.On("HealthCheckHTTP", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	hc.AssertCalled(t, "HealthCheckHTTP", "http://localhost:8500", []int{200}, 30*time.Second)
@@ -120,15 +172,141 @@ func TestContainerRunsHTTPChecksWithCustomStatusCodes(t *testing.T) {
 
 	md.On("PullImage", *cc.Image, false).Once().Return(nil)
 	md.On("CreateContainer", cc).Once().Return("", nil)
+	md.On("ListNetworks", "").Return([]resources.NetworkAttachment{})
 
 	hc.On("HealthCheckHTTP", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	hc.AssertCalled(t, "HealthCheckHTTP", "http://localhost:8500", []int{200, 429}, 30*time.Second)
 }
 
+func setupContainerHealthCheck(t *testing.T, hc *resources.HealthCheck) (*Container, *clients.MockContainerTasks, *mocks.MockHTTP) {
+	cc := &resources.Container{ResourceMetadata: types.ResourceMetadata{
+		ID:   "resource.container.tests",
+		Name: "tests",
+	}}
+	cc.Image = &resources.Image{}
+	cc.HealthCheck = hc
+
+	md := &clients.MockContainerTasks{}
+	hm := &mocks.MockHTTP{}
+
+	md.On("PullImage", *cc.Image, false).Once().Return(nil)
+	md.On("CreateContainer", cc).Once().Return("abc", nil)
+	md.On("ListNetworks", "abc").Return([]resources.NetworkAttachment{})
+
+	old := healthCheckInterval
+	healthCheckInterval = time.Millisecond
+	t.Cleanup(func() { healthCheckInterval = old })
+
+	return NewContainer(cc, md, hm, hclog.NewNullLogger()), md, hm
+}
+
+func TestContainerRunsTCPChecks(t *testing.T) {
+	c, _, hc := setupContainerHealthCheck(t, &resources.HealthCheck{
+		Timeout: "30s",
+		TCP:     "localhost:5432",
+	})
+
+	hc.On("HealthCheckTCP", mock.Anything, mock.Anything).Return(nil)
+
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	hc.AssertCalled(t, "HealthCheckTCP", "localhost:5432", 30*time.Second)
+}
+
+func TestContainerRunsExecChecksUntilSuccess(t *testing.T) {
+	c, md, _ := setupContainerHealthCheck(t, &resources.HealthCheck{
+		Timeout: "30s",
+		Exec:    []string{"pg_isready"},
+	})
+
+	md.On("ExecuteCommand", "abc", []string{"pg_isready"}, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once().Return(fmt.Errorf("exit code 1"))
+	md.On("ExecuteCommand", "abc", []string{"pg_isready"}, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertNumberOfCalls(t, "ExecuteCommand", 2)
+}
+
+func TestContainerExecCheckReturnsErrorOnTimeout(t *testing.T) {
+	c, md, _ := setupContainerHealthCheck(t, &resources.HealthCheck{
+		Timeout: "10ms",
+		Exec:    []string{"pg_isready"},
+	})
+
+	md.On("ExecuteCommand", "abc", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("exit code 1"))
+
+	err := c.Create(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "did not pass within 10ms")
+}
+
+func TestContainerRunsDockerChecksUntilHealthy(t *testing.T) {
+	c, md, _ := setupContainerHealthCheck(t, &resources.HealthCheck{
+		Timeout:      "30s",
+		DockerHealth: true,
+	})
+
+	starting := dtypes.ContainerJSON{ContainerJSONBase: &dtypes.ContainerJSONBase{State: &dtypes.ContainerState{Health: &dtypes.Health{Status: dtypes.Starting}}}}
+	healthy := dtypes.ContainerJSON{ContainerJSONBase: &dtypes.ContainerJSONBase{State: &dtypes.ContainerState{Health: &dtypes.Health{Status: dtypes.Healthy}}}}
+
+	md.On("ContainerInfo", "abc").Once().Return(starting, nil)
+	md.On("ContainerInfo", "abc").Once().Return(healthy, nil)
+
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertNumberOfCalls(t, "ContainerInfo", 2)
+}
+
+func TestContainerDockerCheckReturnsErrorWhenNoHealthCheck(t *testing.T) {
+	c, md, _ := setupContainerHealthCheck(t, &resources.HealthCheck{
+		Timeout:      "30s",
+		DockerHealth: true,
+	})
+
+	md.On("ContainerInfo", "abc").Return(dtypes.ContainerJSON{ContainerJSONBase: &dtypes.ContainerJSONBase{State: &dtypes.ContainerState{}}}, nil)
+
+	err := c.Create(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not define a Docker HEALTHCHECK")
+}
+
+func TestContainerDockerCheckReturnsErrorWhenInfoIsNotContainerJSON(t *testing.T) {
+	c, md, _ := setupContainerHealthCheck(t, &resources.HealthCheck{
+		Timeout:      "30s",
+		DockerHealth: true,
+	})
+
+	md.On("ContainerInfo", "abc").Return(nil, nil)
+
+	err := c.Create(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to read information")
+}
+
+func TestContainerHealthChecksStopAtFirstFailure(t *testing.T) {
+	c, md, hc := setupContainerHealthCheck(t, &resources.HealthCheck{
+		Timeout:      "30s",
+		HTTP:         "http://localhost:8500",
+		TCP:          "localhost:5432",
+		DockerHealth: true,
+	})
+
+	hc.On("HealthCheckHTTP", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	hc.On("HealthCheckTCP", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	err := c.Create(context.Background())
+	assert.Error(t, err)
+
+	md.AssertNotCalled(t, "ContainerInfo", mock.Anything)
+}
+
 func TestContainerDoesNOTCreateWhenPullImageFail(t *testing.T) {
 	cc := &resources.Container{ResourceMetadata: types.ResourceMetadata{
 		Name: "tests",
@@ -145,7 +323,7 @@ func TestContainerDoesNOTCreateWhenPullImageFail(t *testing.T) {
 	// check does not call CreateContainer with the config
 	md.On("CreateContainer", cc).Times(0)
 
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.Equal(t, imageErr, err)
 }
 
@@ -153,16 +331,17 @@ func TestContainerDestroysCorrectlyWhenContainerExists(t *testing.T) {
 	cc := &resources.Container{ResourceMetadata: types.ResourceMetadata{
 		Name: "tests",
 	}}
+	cc.FQRN = "tests.container.jumppad.dev"
 	cc.Networks = []resources.NetworkAttachment{resources.NetworkAttachment{Name: "cloud"}}
 	md := &clients.MockContainerTasks{}
 	hc := &mocks.MockHTTP{}
 	c := NewContainer(cc, md, hc, hclog.NewNullLogger())
 
-	md.On("FindContainerIDs", cc.Name, cc.Type).Return([]string{"abc"}, nil)
+	md.On("FindContainerIDs", cc.FQRN).Return([]string{"abc"}, nil)
 	md.On("RemoveContainer", "abc", false).Return(nil)
 	md.On("DetachNetwork", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	err := c.Destroy()
+	err := c.Destroy(context.Background())
 	assert.NoError(t, err)
 }
 
@@ -170,14 +349,15 @@ func TestContainerDoesNotDestroysWhenNotExists(t *testing.T) {
 	cc := &resources.Container{ResourceMetadata: types.ResourceMetadata{
 		Name: "tests",
 	}}
+	cc.FQRN = "tests.container.jumppad.dev"
 	cc.Networks = []resources.NetworkAttachment{resources.NetworkAttachment{Name: "cloud"}}
 	md := &clients.MockContainerTasks{}
 	hc := &mocks.MockHTTP{}
 	c := NewContainer(cc, md, hc, hclog.NewNullLogger())
 
-	md.On("FindContainerIDs", cc.Name, cc.Type).Return(nil, nil)
+	md.On("FindContainerIDs", cc.FQRN).Return(nil, nil)
 
-	err := c.Destroy()
+	err := c.Destroy(context.Background())
 	assert.NoError(t, err)
 	md.AssertNotCalled(t, "RemoveContainer")
 }
@@ -186,14 +366,15 @@ func TestContainerDoesNotDestroysWhenLookupError(t *testing.T) {
 	cc := &resources.Container{ResourceMetadata: types.ResourceMetadata{
 		Name: "tests",
 	}}
+	cc.FQRN = "tests.container.jumppad.dev"
 	cc.Networks = []resources.NetworkAttachment{resources.NetworkAttachment{Name: "cloud"}}
 	md := &clients.MockContainerTasks{}
 	hc := &mocks.MockHTTP{}
 	c := NewContainer(cc, md, hc, hclog.NewNullLogger())
 
-	md.On("FindContainerIDs", cc.Name, cc.Type).Return(nil, fmt.Errorf("boom"))
+	md.On("FindContainerIDs", cc.FQRN).Return(nil, fmt.Errorf("boom"))
 
-	err := c.Destroy()
+	err := c.Destroy(context.Background())
 	assert.Error(t, err)
 	md.AssertNotCalled(t, "RemoveContainer")
 }
@@ -202,12 +383,13 @@ func TestContainerLooksupIDs(t *testing.T) {
 	cc := &resources.Container{ResourceMetadata: types.ResourceMetadata{
 		Name: "tests",
 	}}
+	cc.FQRN = "tests.container.jumppad.dev"
 	cc.Networks = []resources.NetworkAttachment{resources.NetworkAttachment{Name: "cloud"}}
 	md := &clients.MockContainerTasks{}
 	hc := &mocks.MockHTTP{}
 	c := NewContainer(cc, md, hc, hclog.NewNullLogger())
 
-	md.On("FindContainerIDs", cc.Name, cc.Type).Return([]string{"abc"}, nil)
+	md.On("FindContainerIDs", cc.FQRN).Return([]string{"abc"}, nil)
 
 	ids, err := c.Lookup()
 	assert.NoError(t, err)
@@ -218,18 +400,20 @@ func TestContainerBuildsContainer(t *testing.T) {
 	cc := &resources.Container{ResourceMetadata: types.ResourceMetadata{
 		Name: "tests",
 	}}
-	cc.Build = &resources.Build{Context: "./", File: "./"}
+	cc.Build = &resources.Build{Context: "./", DockerFile: "./"}
 
 	md := &clients.MockContainerTasks{}
 	md.On("BuildContainer", mock.Anything, mock.Anything).Return("testimage", nil)
 	md.On("CreateContainer", cc).Once().Return("", nil)
+	md.On("ListNetworks", "").Return([]resources.NetworkAttachment{})
 
 	hc := &mocks.MockHTTP{}
 	c := NewContainer(cc, md, hc, hclog.NewNullLogger())
 
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	conf := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*resources.Container)
 	assert.Equal(t, "testimage", conf.Image.Name)
+	assert.Len(t, cc.Build.Checksum, 64)
 }
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"io/fs"
 	"os"
 	"strconv"
@@ -21,7 +22,7 @@ func NewCopy(co *resources.Copy, l hclog.Logger) *Copy {
 	return &Copy{l, co}
 }
 
-func (c *Copy) Create() error {
+func (c *Copy) Create(ctx context.Context) error {
 	c.log.Info("Creating Copy", "ref", c.config.Name, "source", c.config.Source, "destination", c.config.Destination, "perms", c.config.Permissions)
 
 	// Check source exists
@@ -85,7 +86,7 @@ func (c *Copy) Create() error {
 	return nil
 }
 
-func (c *Copy) Destroy() error {
+func (c *Copy) Destroy(ctx context.Context) error {
 	c.log.Info("Destroy Copy", "ref", c.config.Name)
 
 	for _, f := range c.config.CopiedFiles {
@@ -108,8 +109,13 @@ func (c *Copy) Lookup() ([]string, error) {
 	return nil, nil
 }
 
-func (c *Copy) Refresh() error {
+func (c *Copy) Refresh(ctx context.Context) error {
 	c.log.Info("Refresh Copied files", "ref", c.config.Name)
 
-	return nil
+	return c.CheckDrift()
+}
+
+// CheckDrift checks that the copied files still exist
+func (c *Copy) CheckDrift() error {
+	return checkFilesExist(c.config.CopiedFiles...)
 }
@@ -1,17 +1,19 @@
 package providers
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 	"path"
 	"testing"
 
 	"github.com/hashicorp/go-hclog"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/shipyard-run/hclconfig/types"
 	"github.com/stretchr/testify/require"
 )
 
-func setupCopy(t *testing.T) (*config.Copy, *Copy) {
+func setupCopy(t *testing.T) (*resources.Copy, *Copy) {
 	dir := t.TempDir()
 	inDir := path.Join(dir, "in")
 	outDir := path.Join(dir, "out")
@@ -24,7 +26,7 @@ func setupCopy(t *testing.T) (*config.Copy, *Copy) {
 	ioutil.WriteFile(path.Join(inDir, "file1.txt"), []byte("data"), 0755)
 	ioutil.WriteFile(path.Join(inDir, "file2.txt"), []byte("data"), 0755)
 
-	cc := config.NewCopy("tests")
+	cc := &resources.Copy{ResourceMetadata: types.ResourceMetadata{Name: "tests", Type: resources.TypeCopy}}
 	cc.Source = inDir
 	cc.Destination = outDir
 
@@ -36,7 +38,7 @@ func setupCopy(t *testing.T) (*config.Copy, *Copy) {
 func TestCopiesADirectory(t *testing.T) {
 	c, p := setupCopy(t)
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	require.NoError(t, err)
 
 	// check the destination
@@ -54,7 +56,7 @@ func TestCopiesASingleFile(t *testing.T) {
 	c.Source = path.Join(c.Source, "file1.txt")
 	c.Destination = path.Join(c.Destination, "file1.txt")
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	require.NoError(t, err)
 
 	// check the files
@@ -66,7 +68,7 @@ func TestCopiesADirectoryWithPermissions(t *testing.T) {
 	c, p := setupCopy(t)
 	c.Permissions = "0777"
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	require.NoError(t, err)
 
 	// check the destination
@@ -83,10 +85,10 @@ func TestCopiesADirectoryWithPermissions(t *testing.T) {
 func TestRemovesFiles(t *testing.T) {
 	c, p := setupCopy(t)
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	require.NoError(t, err)
 
-	err = p.Destroy()
+	err = p.Destroy(context.Background())
 	require.NoError(t, err)
 
 	require.NoFileExists(t, path.Join(c.Destination, "file1.txt"))
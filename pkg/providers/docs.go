@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 
 	hclog "github.com/hashicorp/go-hclog"
@@ -26,11 +27,11 @@ func NewDocs(c *resources.Docs, cc clients.ContainerTasks, l hclog.Logger) *Docs
 }
 
 // Create a new documentation container
-func (i *Docs) Create() error {
+func (i *Docs) Create(ctx context.Context) error {
 	i.log.Info("Creating Documentation", "ref", i.config.Name)
 
 	// create the documentation container
-	err := i.createDocsContainer()
+	err := i.createDocsContainer(ctx)
 	if err != nil {
 		return err
 	}
@@ -39,7 +40,7 @@ func (i *Docs) Create() error {
 }
 
 // Destroy the documentation container
-func (i *Docs) Destroy() error {
+func (i *Docs) Destroy(ctx context.Context) error {
 	i.log.Info("Destroy Documentation", "ref", i.config.Name)
 
 	// remove the docs
@@ -72,13 +73,18 @@ func (i *Docs) Lookup() ([]string, error) {
 	return []string{}, nil
 }
 
-func (c *Docs) Refresh() error {
+func (c *Docs) Refresh(ctx context.Context) error {
 	c.log.Info("Refresh Docs", "ref", c.config.Name)
 
-	return nil
+	return c.CheckDrift()
+}
+
+// CheckDrift checks that the documentation container still exists
+func (c *Docs) CheckDrift() error {
+	return checkContainersExist(c.client, c.config.FQDN)
 }
 
-func (i *Docs) createDocsContainer() error {
+func (i *Docs) createDocsContainer(ctx context.Context) error {
 	// create the container config
 	cc := &resources.Container{
 		ResourceMetadata: types.ResourceMetadata{
@@ -99,7 +105,7 @@ func (i *Docs) createDocsContainer() error {
 	}
 
 	// pull the docker image
-	err := i.client.PullImage(*cc.Image, false)
+	err := i.client.PullImage(ctx, *cc.Image, false)
 	if err != nil {
 		return err
 	}
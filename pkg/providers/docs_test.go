@@ -1,24 +1,25 @@
 package providers
 
 import (
+	"context"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"testing"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/shipyard-run/hclconfig/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func setupDocs(t *testing.T) (*Docs, *clients.MockContainerTasks) {
-	cc := config.NewDocs("tests")
-	cc.IndexTitle = "test"
-	cc.IndexPages = []string{"abc", "123"}
+	cc := &resources.Docs{ResourceMetadata: types.ResourceMetadata{Name: "tests", Type: resources.TypeDocs}}
 	cc.Path = "./docs"
+	cc.NavigationFile = "./docs/navigation.jsx"
+	cc.Port = 80
 
 	md := &clients.MockContainerTasks{}
 
@@ -42,100 +43,66 @@ func setupDocs(t *testing.T) (*Docs, *clients.MockContainerTasks) {
 func TestDocsPullsDocsContainer(t *testing.T) {
 	d, md := setupDocs(t)
 
-	err := d.Create()
+	err := d.Create(context.Background())
 	assert.NoError(t, err)
 
-	params := getCalls(&md.Mock, "PullImage")[0].Arguments[0].(config.Image)
+	params := getCalls(&md.Mock, "PullImage")[0].Arguments[0].(resources.Image)
 	assert.Equal(t, params.Name, docsImageName+":"+docsVersion)
 }
 
 func TestDocsMountsMarkdown(t *testing.T) {
 	d, md := setupDocs(t)
 
-	err := d.Create()
+	err := d.Create(context.Background())
 	assert.NoError(t, err)
 
-	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*resources.Container)
 
 	// check the config file has been generated
 	// this will be the second volume
 	assert.Equal(t, d.config.Path, params.Volumes[0].Source)
-	assert.Equal(t, "/shipyard/docs", params.Volumes[0].Destination)
+	assert.Equal(t, "/content", params.Volumes[0].Destination)
 }
 
-func TestDocsGeneratesDocusaurusConfig(t *testing.T) {
+func TestDocsMountsNavigationFile(t *testing.T) {
 	d, md := setupDocs(t)
 
-	err := d.Create()
+	err := d.Create(context.Background())
 	assert.NoError(t, err)
 
-	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*resources.Container)
 
-	// check the config file has been generated
-	// this will be the second volume
-	fp := params.Volumes[1].Source
-	assert.FileExists(t, fp)
-
-	// check the file is mounted correctly
-	assert.Equal(t, "/shipyard/sidebars.js", params.Volumes[1].Destination)
-
-	//check the file has been generated correctlly
-	data, err := ioutil.ReadFile(fp)
-	assert.Contains(t, string(data), `"abc",`)
-	assert.Contains(t, string(data), `"123"`)
+	// the navigation file is the second volume
+	assert.Equal(t, d.config.NavigationFile, params.Volumes[1].Source)
+	assert.Equal(t, "/config/navigation.jsx", params.Volumes[1].Destination)
 }
 
 func TestDocsSetsDocsPorts(t *testing.T) {
 	d, md := setupDocs(t)
 
-	err := d.Create()
-	assert.NoError(t, err)
-
-	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
-
-	// main port
-	assert.Equal(t, "80", params.Ports[0].Local)
-	assert.Equal(t, "80", params.Ports[0].Remote)
-	assert.Equal(t, fmt.Sprintf("%d", d.config.Port), params.Ports[0].Host)
-
-	// livereload
-	assert.Equal(t, "37950", params.Ports[1].Local)
-	assert.Equal(t, "37950", params.Ports[1].Remote)
-	assert.Equal(t, "37950", params.Ports[1].Host)
-}
-
-func TestDocsSetsDocsPortsWithCustomReload(t *testing.T) {
-	d, md := setupDocs(t)
-	d.config.LiveReloadPort = 30000
-
-	err := d.Create()
+	err := d.Create(context.Background())
 	assert.NoError(t, err)
 
-	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*resources.Container)
 
-	// main port
+	assert.Len(t, params.Ports, 1)
 	assert.Equal(t, "80", params.Ports[0].Local)
 	assert.Equal(t, "80", params.Ports[0].Remote)
 	assert.Equal(t, fmt.Sprintf("%d", d.config.Port), params.Ports[0].Host)
-
-	// livereload
-	assert.Equal(t, "37950", params.Ports[1].Local)
-	assert.Equal(t, "37950", params.Ports[1].Remote)
-	assert.Equal(t, "30000", params.Ports[1].Host)
 }
 
 func TestDocsSetsTerminalPorts(t *testing.T) {
 	d, md := setupDocs(t)
 
-	err := d.Create()
+	err := d.Create(context.Background())
 	assert.NoError(t, err)
 
-	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*resources.Container)
 
 	// main port
 	localIP, _ := utils.GetLocalIPAndHostname()
-	assert.Equal(t, localIP, params.EnvVar["TERMINAL_SERVER_IP"])
-	assert.Equal(t, "30003", params.EnvVar["TERMINAL_SERVER_PORT"])
+	assert.Equal(t, localIP, params.Environment["TERMINAL_SERVER_IP"])
+	assert.Equal(t, "30003", params.Environment["TERMINAL_SERVER_PORT"])
 }
 
 func TestDestroyRemovesContainers(t *testing.T) {
@@ -143,10 +110,10 @@ func TestDestroyRemovesContainers(t *testing.T) {
 	removeOn(&md.Mock, "FindContainerIDs")
 	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"abc"}, nil)
 
-	err := d.Create()
+	err := d.Create(context.Background())
 	assert.NoError(t, err)
 
-	err = d.Destroy()
+	err = d.Destroy(context.Background())
 	assert.NoError(t, err)
 
 	md.AssertNumberOfCalls(t, "FindContainerIDs", 1)
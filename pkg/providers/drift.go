@@ -0,0 +1,68 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+)
+
+// DriftError is returned by Refresh and CheckDrift when the real world
+// objects managed by a resource no longer exist or no longer match the state
+type DriftError struct {
+	Reason string
+}
+
+func (e DriftError) Error() string {
+	return fmt.Sprintf("resource has drifted: %s", e.Reason)
+}
+
+// DriftChecker is implemented by providers which can check that the real
+// world objects for a resource still match the state.
+// CheckDrift does not modify any objects, a DriftError is returned when the
+// resource has drifted, any other error means the check could not be completed.
+type DriftChecker interface {
+	CheckDrift() error
+}
+
+// checkContainersExist returns a DriftError if any of the containers with the
+// given names do not exist, empty names are ignored
+func checkContainersExist(client clients.ContainerTasks, names ...string) error {
+	for _, n := range names {
+		if n == "" {
+			continue
+		}
+
+		ids, err := client.FindContainerIDs(n)
+		if err != nil {
+			return fmt.Errorf("unable to lookup container %s: %s", n, err)
+		}
+
+		if len(ids) == 0 {
+			return DriftError{Reason: fmt.Sprintf("container %s does not exist", n)}
+		}
+	}
+
+	return nil
+}
+
+// checkFilesExist returns a DriftError if any of the given files do not
+// exist, empty paths are ignored
+func checkFilesExist(paths ...string) error {
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+
+		_, err := os.Stat(p)
+		if os.IsNotExist(err) {
+			return DriftError{Reason: fmt.Sprintf("file %s does not exist", p)}
+		}
+
+		if err != nil {
+			return fmt.Errorf("unable to check file %s: %s", p, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/stretchr/testify/mock"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestCheckContainersExistWithMissingContainerReturnsDriftError(t *testing.T) {
+	ct := &clients.MockContainerTasks{}
+	ct.On("FindContainerIDs", "one").Return([]string{"abc"}, nil)
+	ct.On("FindContainerIDs", "two").Return(nil, nil)
+
+	err := checkContainersExist(ct, "one", "", "two")
+	assert.True(t, errors.As(err, &DriftError{}))
+	assert.Contains(t, err.Error(), "two")
+}
+
+func TestCheckContainersExistWithLookupErrorReturnsError(t *testing.T) {
+	ct := &clients.MockContainerTasks{}
+	ct.On("FindContainerIDs", mock.Anything).Return(nil, fmt.Errorf("boom"))
+
+	err := checkContainersExist(ct, "one")
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &DriftError{}))
+}
+
+func TestCheckContainersExistWithContainersReturnsNil(t *testing.T) {
+	ct := &clients.MockContainerTasks{}
+	ct.On("FindContainerIDs", mock.Anything).Return([]string{"abc"}, nil)
+
+	err := checkContainersExist(ct, "one", "two")
+	assert.NoError(t, err)
+}
+
+func TestCheckFilesExistWithMissingFileReturnsDriftError(t *testing.T) {
+	dir := t.TempDir()
+	f := filepath.Join(dir, "exists")
+	os.WriteFile(f, []byte("test"), os.ModePerm)
+
+	assert.NoError(t, checkFilesExist(f, ""))
+
+	err := checkFilesExist(f, filepath.Join(dir, "missing"))
+	assert.True(t, errors.As(err, &DriftError{}))
+}
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"time"
@@ -25,7 +26,7 @@ func NewLocalExec(c *resources.LocalExec, ex clients.Command, l hclog.Logger) *L
 }
 
 // Create a new exec
-func (c *LocalExec) Create() error {
+func (c *LocalExec) Create(ctx context.Context) error {
 	c.log.Info("Locally executing script", "ref", c.config.Name, "command", c.config.Command)
 
 	// build the environment variables
@@ -63,7 +64,7 @@ func (c *LocalExec) Create() error {
 		Timeout:          d,
 	}
 
-	p, err := c.client.Execute(cc)
+	p, err := c.client.Execute(ctx, cc)
 
 	// set the output
 	c.config.Pid = p
@@ -78,7 +79,7 @@ func (c *LocalExec) Create() error {
 }
 
 // Destroy statisfies the interface method but is not implemented by LocalExec
-func (c *LocalExec) Destroy() error {
+func (c *LocalExec) Destroy(ctx context.Context) error {
 	if c.config.Daemon {
 		// attempt to destroy the process
 		c.log.Info("Stopping locally executing script", "ref", c.config.Name, "pid", c.config.Pid)
@@ -102,8 +103,22 @@ func (c *LocalExec) Lookup() ([]string, error) {
 	return []string{}, nil
 }
 
-func (c *LocalExec) Refresh() error {
+func (c *LocalExec) Refresh(ctx context.Context) error {
 	c.log.Info("Refresh Local Exec", "ref", c.config.Name)
 
+	return c.CheckDrift()
+}
+
+// CheckDrift checks that the process is still running when the command
+// runs as a daemon
+func (c *LocalExec) CheckDrift() error {
+	if !c.config.Daemon || c.config.Pid < 1 {
+		return nil
+	}
+
+	if !c.client.IsRunning(c.config.Pid) {
+		return DriftError{Reason: fmt.Sprintf("process %d is not running", c.config.Pid)}
+	}
+
 	return nil
 }
@@ -1,19 +1,21 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/shipyard-run/hclconfig/types"
 	"github.com/stretchr/testify/mock"
 	assert "github.com/stretchr/testify/require"
 )
 
-func testLocalExecSetupMocks() (*config.ExecLocal, *clients.CommandMock) {
+func testLocalExecSetupMocks() (*resources.LocalExec, *clients.CommandMock) {
 	el := *execLocalConfig
 	mc := &clients.CommandMock{}
 	mc.On("Execute", mock.Anything).Return(123, nil)
@@ -25,16 +27,16 @@ func testLocalExecSetupMocks() (*config.ExecLocal, *clients.CommandMock) {
 func TestExecLocalExecutesCommandSuccessfully(t *testing.T) {
 	c, mc := testLocalExecSetupMocks()
 
-	p := NewExecLocal(c, mc, hclog.Default())
+	p := NewLocalExec(c, mc, hclog.Default())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	mc.AssertCalled(t, "Execute", mock.Anything)
 
 	params := mc.Calls[0].Arguments[0].(clients.CommandConfig)
-	assert.Equal(t, c.Command, params.Command)
-	assert.Equal(t, c.Arguments, params.Args)
+	assert.Equal(t, c.Command[0], params.Command)
+	assert.Equal(t, c.Command[1:], params.Args)
 	assert.Equal(t, c.WorkingDirectory, params.WorkingDirectory)
 	assert.Equal(t, []string{"abc=123"}, params.Env)
 	assert.Equal(t, c.Daemon, params.RunInBackground)
@@ -46,9 +48,9 @@ func TestExecLocalExecutesCommandSuccessfully(t *testing.T) {
 func TestExecLocalExecutesCommandAndSetsPid(t *testing.T) {
 	c, mc := testLocalExecSetupMocks()
 
-	p := NewExecLocal(c, mc, hclog.Default())
+	p := NewLocalExec(c, mc, hclog.Default())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	assert.Equal(t, 123, c.Pid)
@@ -60,9 +62,9 @@ func TestExecLocalExecuteFailsReturnsError(t *testing.T) {
 	removeOn(&mc.Mock, "Execute")
 	mc.On("Execute", mock.Anything, mock.Anything).Return(0, fmt.Errorf("boom"))
 
-	p := NewExecLocal(c, mc, hclog.Default())
+	p := NewLocalExec(c, mc, hclog.Default())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -70,9 +72,9 @@ func TestExecLocalDestroyCallsStopWhenDaemon(t *testing.T) {
 	c, mc := testLocalExecSetupMocks()
 	c.Pid = 123
 
-	p := NewExecLocal(c, mc, hclog.Default())
+	p := NewLocalExec(c, mc, hclog.Default())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 
 	mc.AssertCalled(t, "Kill", 123)
@@ -83,19 +85,18 @@ func TestExecLocalDestroyNotCallsStopWhenNotDaemon(t *testing.T) {
 	c.Pid = 123
 	c.Daemon = false
 
-	p := NewExecLocal(c, mc, hclog.Default())
+	p := NewLocalExec(c, mc, hclog.Default())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 
 	mc.AssertNotCalled(t, "Kill", mock.Anything)
 }
 
-var execLocalConfig = &config.ExecLocal{
-	ResourceInfo:     config.ResourceInfo{Name: "test", Type: config.TypeExecLocal},
-	Command:          "mycommand",
-	Arguments:        []string{"foo", "bar"},
-	EnvVar:           map[string]string{"abc": "123"},
+var execLocalConfig = &resources.LocalExec{
+	ResourceMetadata: types.ResourceMetadata{Name: "test", Type: resources.TypeLocalExec},
+	Command:          []string{"mycommand", "foo", "bar"},
+	Environment:      map[string]string{"abc": "123"},
 	Daemon:           true,
 	WorkingDirectory: "./",
 }
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 
 	hclog "github.com/hashicorp/go-hclog"
@@ -25,7 +26,7 @@ func NewRemoteExec(c *resources.RemoteExec, ex clients.ContainerTasks, l hclog.L
 }
 
 // Create a new execution instance
-func (c *RemoteExec) Create() error {
+func (c *RemoteExec) Create(ctx context.Context) error {
 	c.log.Info("Remote executing command", "ref", c.config.Name, "command", c.config.Command, "image", c.config.Image)
 
 	/*
@@ -39,7 +40,7 @@ func (c *RemoteExec) Create() error {
 
 	if c.config.Target == "" {
 		// Not using existing target create new container
-		id, err := c.createRemoteExecContainer()
+		id, err := c.createRemoteExecContainer(ctx)
 		if err != nil {
 			return xerrors.Errorf("unable to create container for exec_remote.%s: %w", c.config.Name, err)
 		}
@@ -94,7 +95,7 @@ func (c *RemoteExec) Create() error {
 		group = c.config.RunAs.Group
 	}
 
-	err := c.client.ExecuteCommand(targetID, command, envs, c.config.WorkingDirectory, user, group, c.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}))
+	err := c.client.ExecuteCommand(ctx, targetID, command, envs, c.config.WorkingDirectory, user, group, c.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}))
 	if err != nil {
 		c.log.Error("Error executing command", "ref", c.config.Name, "image", c.config.Image, "command", c.config.Command)
 		err = xerrors.Errorf("Unable to execute command: in remote container: %w", err)
@@ -110,7 +111,7 @@ func (c *RemoteExec) Create() error {
 
 // Destroy satisfies the interface requirements but is not used as the
 // resource is not persistent
-func (c *RemoteExec) Destroy() error {
+func (c *RemoteExec) Destroy(ctx context.Context) error {
 	return nil
 }
 
@@ -120,13 +121,13 @@ func (c *RemoteExec) Lookup() ([]string, error) {
 	return []string{}, nil
 }
 
-func (c *RemoteExec) Refresh() error {
+func (c *RemoteExec) Refresh(ctx context.Context) error {
 	c.log.Info("Refresh Remote Exec", "ref", c.config.Name)
 
 	return nil
 }
 
-func (c *RemoteExec) createRemoteExecContainer() (string, error) {
+func (c *RemoteExec) createRemoteExecContainer(ctx context.Context) (string, error) {
 	// generate the ID for the new container based on the clock time and a string
 
 	cc := &resources.Container{
@@ -146,7 +147,7 @@ func (c *RemoteExec) createRemoteExecContainer() (string, error) {
 	cc.Volumes = c.config.Volumes
 
 	// pull any images needed for this container
-	err := c.client.PullImage(*cc.Image, false)
+	err := c.client.PullImage(ctx, *cc.Image, false)
 	if err != nil {
 		c.log.Error("Error pulling container image", "ref", cc.Name, "image", cc.Image.Name)
 
@@ -1,42 +1,45 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/shipyard-run/hclconfig"
+	"github.com/shipyard-run/hclconfig/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
-func testRemoteExecSetupMocks() (*config.ExecRemote, *config.Network, *clients.MockContainerTasks) {
+func testRemoteExecSetupMocks() (*resources.RemoteExec, *resources.Network, *clients.MockContainerTasks) {
 	md := &clients.MockContainerTasks{}
 	md.On("CreateContainer", mock.Anything).Return("1234", nil)
 	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
 	md.On("FindContainerIDs", mock.Anything).Return([]string{"1234"}, nil)
 	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	md.On("RemoveContainer", mock.Anything, true).Return(nil)
-	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"1234"}, nil)
-
-	trex := &config.ExecRemote{
-		Image:       &config.Image{Name: "tools:v1"},
-		Networks:    []config.NetworkAttachment{config.NetworkAttachment{Name: "wan"}},
-		Command:     "tail",
-		Arguments:   []string{"-f", "/dev/null"},
-		Environment: []config.KV{config.KV{Key: "abc", Value: "123"}},
+
+	trex := &resources.RemoteExec{
+		ResourceMetadata: types.ResourceMetadata{Name: "test", Type: resources.TypeRemoteExec},
+		Image:            &resources.Image{Name: "tools:v1"},
+		Networks:         []resources.NetworkAttachment{resources.NetworkAttachment{ID: "resource.network.wan"}},
+		Command:          []string{"tail", "-f", "/dev/null"},
+		Environment:      map[string]string{"abc": "123"},
 	}
 
-	net := config.NewNetwork("wan")
+	net := &resources.Network{ResourceMetadata: types.ResourceMetadata{ID: "resource.network.wan", Name: "wan", Type: resources.TypeNetwork}}
 
-	cont := config.NewContainer("test")
-	cont.Networks = []config.NetworkAttachment{config.NetworkAttachment{Name: "network.wan"}}
+	cont := &resources.Container{ResourceMetadata: types.ResourceMetadata{ID: "resource.container.test", Name: "test", Type: resources.TypeContainer}}
+	cont.Networks = []resources.NetworkAttachment{resources.NetworkAttachment{ID: "resource.network.wan"}}
 
-	c := config.New()
-	c.AddResource(net)
-	c.AddResource(trex)
-	c.AddResource(cont)
+	c := hclconfig.NewConfig()
+	c.AppendResource(net)
+	c.AppendResource(trex)
+	c.AppendResource(cont)
 
 	return trex, net, md
 }
@@ -47,7 +50,7 @@ func TestRemoteExecThrowsErrorIfScript(t *testing.T) {
 	trex.Script = "./script.sh"
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 */
@@ -56,7 +59,7 @@ func TestRemoteExecPullsImageWhenNoTarget(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "PullImage", mock.Anything, mock.Anything)
 }
@@ -68,7 +71,7 @@ func TestRemoteExecPullsImageReturnsErrorWhenError(t *testing.T) {
 
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -76,7 +79,7 @@ func TestRemoteExecCreatesContainerWhenNoTarget(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "CreateContainer", mock.Anything)
 }
@@ -88,28 +91,28 @@ func TestRemoteExecCreatesContainerFailsReturnError(t *testing.T) {
 
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestRemoteExecWithTargetLooksupID(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
-	trex.Target = "container.test"
+	trex.Target = "resource.container.test"
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
-	md.AssertCalled(t, "FindContainerIDs", "test", config.TypeContainer)
+	md.AssertCalled(t, "FindContainerIDs", utils.FQDN("test", "", resources.TypeContainer))
 }
 
 func TestRemoteExecWithTargetLooksupIDNotFoundReturnsError(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
-	trex.Target = "container.test"
+	trex.Target = "resource.container.test"
 	removeOn(&md.Mock, "FindContainerIDs")
-	md.On("FindContainerIDs", "test", config.TypeContainer).Return([]string{}, nil)
+	md.On("FindContainerIDs", utils.FQDN("test", "", resources.TypeContainer)).Return([]string{}, nil)
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -117,7 +120,7 @@ func TestRemoteExecExecutesCommand(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 
@@ -126,21 +129,20 @@ func TestRemoteExecExecutesCommand(t *testing.T) {
 	env := args[2].([]string)
 	wd := args[3].(string)
 
-	assert.Equal(t, trex.Command, params[0])
-	assert.Equal(t, trex.Arguments[0], params[1])
+	assert.Equal(t, trex.Command, params)
 	assert.Equal(t, trex.WorkingDirectory, wd)
-	assert.Contains(t, env, fmt.Sprintf("%s=%s", trex.Environment[0].Key, trex.Environment[0].Value))
+	assert.Contains(t, env, "abc=123")
 }
 
 func TestRemoteExecRunsAsUserWhenSpecified(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
-	trex.RunAs = &config.User{
+	trex.RunAs = &resources.User{
 		User:  "1010",
 		Group: "1011",
 	}
 
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	user := getCalls(&md.Mock, "ExecuteCommand")[0].Arguments[4].(string)
@@ -157,7 +159,7 @@ func TestRemoteExecExecutesCommandFailReturnsError(t *testing.T) {
 
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -165,7 +167,7 @@ func TestRemoteExecRemovesContainer(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "RemoveContainer", "1234", true)
 }
@@ -178,16 +180,16 @@ func TestRemoteExecRemovesContainer(t *testing.T) {
 
 		p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-		err := p.Create()
+		err := p.Create(context.Background())
 		assert.Error(t, err)
 	}
 */
 func TestRemoteExecDoesNOTRemovesContainerWhenTarget(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
-	trex.Target = "container.test"
+	trex.Target = "resource.container.test"
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertNotCalled(t, "RemoveContainer", mock.Anything)
 }
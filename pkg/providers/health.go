@@ -0,0 +1,11 @@
+package providers
+
+import "context"
+
+// HealthChecker is implemented by providers which can re-run the health
+// checks for a resource which has already been created.
+// HealthCheck blocks until the checks pass or the health check timeout
+// elapses, resources which do not define a health check are healthy.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
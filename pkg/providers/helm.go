@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"time"
 
 	hclog "github.com/hashicorp/go-hclog"
@@ -10,6 +11,10 @@ import (
 	"golang.org/x/xerrors"
 )
 
+// defaultHelmTimeout is the maximum time for the chart install when the
+// chart does not set a timeout
+const defaultHelmTimeout = 300 * time.Second
+
 type Helm struct {
 	config       *resources.Helm
 	kubeClient   clients.Kubernetes
@@ -24,7 +29,7 @@ func NewHelm(c *resources.Helm, kc clients.Kubernetes, hc clients.Helm, g client
 }
 
 // Create implements the provider Create method
-func (h *Helm) Create() error {
+func (h *Helm) Create(ctx context.Context) error {
 	h.log.Info("Creating Helm chart", "ref", h.config.ID)
 
 	// get the target cluster
@@ -74,76 +79,48 @@ func (h *Helm) Create() error {
 	// sanitize the chart name
 	newName, _ := utils.ReplaceNonURIChars(h.config.Name)
 
-	failCount := 0
-
-	to := time.Duration(300 * time.Second)
-	if h.config.Timeout != "" {
-		to, err = time.ParseDuration(h.config.Timeout)
-		if err != nil {
-			return xerrors.Errorf("unable to parse timeout duration: %w", err)
-		}
+	// the engine applies the timeout and retries set for the chart, charts
+	// which do not set a timeout are limited to the default for the install
+	ictx := ctx
+	if h.config.Timeout == "" {
+		var cancel context.CancelFunc
+		ictx, cancel = context.WithTimeout(ctx, defaultHelmTimeout)
+		defer cancel()
 	}
 
-	timeout := time.After(to)
-	errChan := make(chan error)
-	doneChan := make(chan struct{})
-
-	go func() {
-		for {
-			err = h.helmClient.Create(
-				kcPath,
-				newName,
-				h.config.Namespace,
-				h.config.CreateNamespace,
-				h.config.SkipCRDs,
-				h.config.Chart,
-				h.config.Version,
-				h.config.Values,
-				h.config.ValuesString)
-
-			if err == nil {
-				doneChan <- struct{}{}
-				break
-			}
-
-			failCount++
-
-			if failCount >= h.config.Retry {
-				errChan <- err
-			} else {
-				h.log.Debug("Chart apply failed, retrying", "error", err)
-				time.Sleep(5 * time.Second)
-			}
+	err = h.helmClient.Create(
+		ictx,
+		kcPath,
+		newName,
+		h.config.Namespace,
+		h.config.CreateNamespace,
+		h.config.SkipCRDs,
+		h.config.Chart,
+		h.config.Version,
+		h.config.Values,
+		h.config.ValuesString)
+
+	if err != nil {
+		if ctx.Err() == nil && ictx.Err() != nil {
+			return xerrors.Errorf("timeout waiting for helm chart to complete: %w", err)
 		}
-	}()
 
-	select {
-	case <-timeout:
-		return xerrors.Errorf("timeout waiting for helm chart to complete")
-	case createErr := <-errChan:
-		return createErr
-	case <-doneChan:
-		h.log.Debug("Helm chart applied", "ref", h.config.Name)
+		return err
 	}
 
-	// we can now health check the install
-	if h.config.HealthCheck != nil && len(h.config.HealthCheck.Pods) > 0 {
-		to, err := time.ParseDuration(h.config.HealthCheck.Timeout)
-		if err != nil {
-			return xerrors.Errorf("unable to parse health check duration: %w", err)
-		}
+	h.log.Debug("Helm chart applied", "ref", h.config.Name)
 
-		err = h.kubeClient.HealthCheckPods(h.config.HealthCheck.Pods, to)
-		if err != nil {
-			return xerrors.Errorf("health check failed after helm chart setup: %w", err)
-		}
+	// we can now health check the install
+	err = healthCheckKubernetes(ctx, h.kubeClient, h.config.HealthCheck, h.config.Namespace)
+	if err != nil {
+		return xerrors.Errorf("health check failed after helm chart setup: %w", err)
 	}
 
 	return nil
 }
 
 // Destroy implements the provider Destroy method
-func (h *Helm) Destroy() error {
+func (h *Helm) Destroy(ctx context.Context) error {
 	h.log.Info("Destroy Helm chart", "ref", h.config.Name)
 	kcPath, err := h.getKubeConfigPath()
 	if err != nil {
@@ -173,12 +150,36 @@ func (h *Helm) Lookup() ([]string, error) {
 	return []string{}, nil
 }
 
-func (c *Helm) Refresh() error {
+func (c *Helm) Refresh(ctx context.Context) error {
 	c.log.Info("Refresh Helm Chart", "ref", c.config.Name)
 
 	return nil
 }
 
+// HealthCheck implements the HealthChecker interface
+func (h *Helm) HealthCheck(ctx context.Context) error {
+	if h.config.HealthCheck == nil {
+		return nil
+	}
+
+	kcPath, err := h.getKubeConfigPath()
+	if err != nil {
+		return err
+	}
+
+	kc, err := h.kubeClient.SetConfig(kcPath)
+	if err != nil {
+		return xerrors.Errorf("unable to create Kubernetes client: %w", err)
+	}
+
+	namespace := h.config.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	return healthCheckKubernetes(ctx, kc, h.config.HealthCheck, namespace)
+}
+
 func (h *Helm) getKubeConfigPath() (string, error) {
 	target, err := h.config.ParentConfig.FindResource(h.config.Cluster)
 	if err != nil {
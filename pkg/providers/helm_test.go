@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -9,13 +10,15 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/clients/mocks"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/shipyard-run/hclconfig"
+	"github.com/shipyard-run/hclconfig/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
-func setupHelm() (*mocks.MockHelm, *clients.MockKubernetes, *mocks.Getter, *config.Config, *Helm) {
+func setupHelm() (*mocks.MockHelm, *clients.MockKubernetes, *mocks.Getter, *hclconfig.Config, *Helm) {
 	mh := &mocks.MockHelm{}
 	mh.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mh.On("Destroy", mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -28,15 +31,16 @@ func setupHelm() (*mocks.MockHelm, *clients.MockKubernetes, *mocks.Getter, *conf
 	mg := &mocks.Getter{}
 	mg.On("Get", mock.Anything, mock.Anything).Return(nil)
 
-	cl := config.NewK8sCluster("tester")
-	ch := config.NewHelm("test")
-	ch.ChartName = "test"
-	ch.Cluster = "k8s_cluster.tester"
+	cl := &resources.K8sCluster{ResourceMetadata: types.ResourceMetadata{ID: "resource.k8s_cluster.tester", Name: "tester", Type: resources.TypeK8sCluster}}
+	cl.KubeConfig = "/tmp/tester/kubeconfig.yaml"
+
+	ch := &resources.Helm{ResourceMetadata: types.ResourceMetadata{Name: "test", Type: resources.TypeHelm}}
+	ch.Cluster = "resource.k8s_cluster.tester"
 	ch.SkipCRDs = true
 
-	c := config.New()
-	c.AddResource(cl)
-	c.AddResource(ch)
+	c := hclconfig.NewConfig()
+	c.AppendResource(cl)
+	c.AppendResource(ch)
 
 	p := NewHelm(ch, kc, mh, mg, hclog.NewNullLogger())
 
@@ -44,33 +48,31 @@ func setupHelm() (*mocks.MockHelm, *clients.MockKubernetes, *mocks.Getter, *conf
 }
 
 func TestHelmCreateCantFindClusterReturnsError(t *testing.T) {
-	_, _, _, c, p := setupHelm()
-	c.RemoveResource(c.Resources[0])
+	_, _, _, _, p := setupHelm()
+	p.config.Cluster = "resource.k8s_cluster.missing"
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestHelmCreateSantisesChartName(t *testing.T) {
-	mh, _, _, c, p := setupHelm()
-	hc, _ := c.FindResource("helm.test")
-	hc.(*config.Helm).ChartName = "chart_test"
+	mh, _, _, _, p := setupHelm()
+	p.config.Name = "chart_test"
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	mh.AssertCalled(t, "Create", mock.Anything, "chart-test", mock.Anything, mock.Anything, true, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestHelmCreateGetsHelmRepo(t *testing.T) {
-	mh, _, mg, c, p := setupHelm()
-	hc, _ := c.FindResource("helm.test")
+	mh, _, mg, _, p := setupHelm()
 
-	hc.(*config.Helm).Repository = &config.HelmRepository{URL: "http://something.com", Name: "hashicorp"}
-	hc.(*config.Helm).Chart = "hashicorp/vault"
-	hc.(*config.Helm).Version = "v1.0.0"
+	p.config.Repository = &resources.HelmRepository{URL: "http://something.com", Name: "hashicorp"}
+	p.config.Chart = "hashicorp/vault"
+	p.config.Version = "v1.0.0"
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	mg.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
@@ -79,13 +81,12 @@ func TestHelmCreateGetsHelmRepo(t *testing.T) {
 }
 
 func TestHelmCreateGetsRemoteRepo(t *testing.T) {
-	mh, _, mg, c, p := setupHelm()
-	hc, _ := c.FindResource("helm.test")
-	hc.(*config.Helm).Chart = "github.com/shipyard-run/blueprints//vault-k8s"
+	mh, _, mg, _, p := setupHelm()
+	p.config.Chart = "github.com/shipyard-run/blueprints//vault-k8s"
 
-	helmFolder := filepath.Join(utils.JumppadHome(), "helm_charts", strings.Replace(hc.(*config.Helm).Chart, "//", "/", -1))
+	helmFolder := filepath.Join(utils.JumppadHome(), "helm_charts", strings.Replace(p.config.Chart, "//", "/", -1))
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	mg.AssertCalled(t, "Get", mock.Anything, helmFolder)
@@ -95,11 +96,10 @@ func TestHelmCreateGetsRemoteRepo(t *testing.T) {
 func TestHelmCreateSetsConfig(t *testing.T) {
 	_, kc, mg, _, p := setupHelm()
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
-	_, fp, _ := utils.CreateKubeConfigPath("tester")
-	kc.AssertCalled(t, "SetConfig", fp)
+	kc.AssertCalled(t, "SetConfig", "/tmp/tester/kubeconfig.yaml")
 	mg.AssertNotCalled(t, "Get")
 }
 
@@ -108,14 +108,14 @@ func TestHelmCreateConfigSetFailReturnsError(t *testing.T) {
 	removeOn(&kc.Mock, "SetConfig")
 	kc.On("SetConfig", mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestHelmCreateCallsCreateWithDefaultNamespace(t *testing.T) {
 	hm, _, _, _, p := setupHelm()
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	hm.AssertCalled(
@@ -137,7 +137,7 @@ func TestHelmCreateCallsCreateWithCustomNamespace(t *testing.T) {
 	hm, _, _, _, p := setupHelm()
 	p.config.Namespace = "custom"
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	hm.AssertCalled(
@@ -155,17 +155,18 @@ func TestHelmCreateCallsCreateWithCustomNamespace(t *testing.T) {
 	)
 }
 
-func TestHelmCreateCallCreateFailRetries(t *testing.T) {
+func TestHelmCreateCallCreateFailDoesNotRetry(t *testing.T) {
 	hm, _, _, _, p := setupHelm()
 	p.config.Retry = 2
 
+	// retries are handled by the engine
 	removeOn(&hm.Mock, "Create")
 	hm.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, true, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once().Return(fmt.Errorf("boom"))
 	hm.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, true, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
 
-	err := p.Create()
-	assert.NoError(t, err)
-	hm.AssertNumberOfCalls(t, "Create", 2)
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+	hm.AssertNumberOfCalls(t, "Create", 1)
 }
 
 func TestHelmCreateCallCreateFailReturnsError(t *testing.T) {
@@ -174,14 +175,14 @@ func TestHelmCreateCallCreateFailReturnsError(t *testing.T) {
 	removeOn(&hm.Mock, "Create")
 	hm.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, true, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestHelmDoesNotHealthChecksPodswhenNotSet(t *testing.T) {
 	_, kc, _, _, p := setupHelm()
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	kc.AssertNotCalled(t, "HealthCheckPods", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
@@ -189,9 +190,9 @@ func TestHelmDoesNotHealthChecksPodswhenNotSet(t *testing.T) {
 
 func TestHelmHealthChecksPodswhenSet(t *testing.T) {
 	_, kc, _, _, p := setupHelm()
-	p.config.HealthCheck = &config.HealthCheck{Timeout: "1s", Pods: []string{"consul=release"}}
+	p.config.HealthCheck = &resources.HealthCheck{Timeout: "1s", Pods: []string{"consul=release"}}
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	kc.AssertCalled(t, "HealthCheckPods", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
@@ -199,25 +200,25 @@ func TestHelmHealthChecksPodswhenSet(t *testing.T) {
 
 func TestHelmCreateHealthCheckPodsFailReturnsError(t *testing.T) {
 	_, kc, _, _, p := setupHelm()
-	p.config.HealthCheck = &config.HealthCheck{Timeout: "1s", Pods: []string{"consul=release"}}
+	p.config.HealthCheck = &resources.HealthCheck{Timeout: "1s", Pods: []string{"consul=release"}}
 	removeOn(&kc.Mock, "HealthCheckPods")
 	kc.On("HealthCheckPods", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 func TestHelmDestroyCantFindClusterReturnsError(t *testing.T) {
-	_, _, _, c, p := setupHelm()
-	c.RemoveResource(c.Resources[0])
+	_, _, _, _, p := setupHelm()
+	p.config.Cluster = "resource.k8s_cluster.missing"
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.Error(t, err)
 }
 
 func TestHelmDestroyCallsDestroyWithDefaultNamespace(t *testing.T) {
 	hm, _, _, _, p := setupHelm()
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 	hm.AssertCalled(t, "Destroy", mock.Anything, mock.Anything, "default")
 }
@@ -228,17 +229,16 @@ func TestHelmDestroyWithErrorSwallowsError(t *testing.T) {
 	removeOn(&hm.Mock, "Destroy")
 	hm.On("Destroy", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 	hm.AssertCalled(t, "Destroy", mock.Anything, mock.Anything, "custom")
 }
 
 func TestHelmDestroySantisesChartName(t *testing.T) {
-	mh, _, _, c, p := setupHelm()
-	hc, _ := c.FindResource("helm.test")
-	hc.(*config.Helm).ChartName = "chart_test"
+	mh, _, _, _, p := setupHelm()
+	p.config.Name = "chart_test"
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 
 	mh.AssertCalled(t, "Destroy", mock.Anything, "chart-test", mock.Anything)
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"path/filepath"
@@ -28,7 +29,7 @@ func NewImageCache(co *resources.ImageCache, cl clients.ContainerTasks, hc clien
 	return &ImageCache{co, cl, hc, l}
 }
 
-func (c *ImageCache) Create() error {
+func (c *ImageCache) Create(ctx context.Context) error {
 	c.log.Info("Creating ImageCache", "ref", c.config.Name)
 
 	// check the cache does not already exist
@@ -44,7 +45,7 @@ func (c *ImageCache) Create() error {
 
 	if len(ids) == 0 {
 		var err error
-		id, err = c.createImageCache(dependentNetworks)
+		id, err = c.createImageCache(ctx, dependentNetworks)
 		if err != nil {
 			return err
 		}
@@ -61,7 +62,7 @@ func (c *ImageCache) Create() error {
 	return c.reConfigureNetworks(id, dependentNetworks)
 }
 
-func (c *ImageCache) Destroy() error {
+func (c *ImageCache) Destroy(ctx context.Context) error {
 	c.log.Info("Destroy ImageCache", "ref", c.config.Name)
 
 	ids, err := c.Lookup()
@@ -78,17 +79,22 @@ func (c *ImageCache) Destroy() error {
 	return nil
 }
 
-func (c *ImageCache) Refresh() error {
+func (c *ImageCache) Refresh(ctx context.Context) error {
 	c.log.Info("Refresh Image Cache", "ref", c.config.Name)
 
-	return nil
+	return c.CheckDrift()
+}
+
+// CheckDrift checks that the image cache container still exists
+func (c *ImageCache) CheckDrift() error {
+	return checkContainersExist(c.client, utils.FQDN(c.config.Name, c.config.Module, c.config.Type))
 }
 
 func (c *ImageCache) Lookup() ([]string, error) {
 	return c.client.FindContainerIDs(utils.FQDN(c.config.Name, c.config.Module, c.config.Type))
 }
 
-func (c *ImageCache) createImageCache(networks []string) (string, error) {
+func (c *ImageCache) createImageCache(ctx context.Context, networks []string) (string, error) {
 	// Create the volume to store the cache
 	// if this volume exists it will not be recreated
 	volID, err := c.client.CreateVolume("images")
@@ -97,8 +103,8 @@ func (c *ImageCache) createImageCache(networks []string) (string, error) {
 	}
 
 	// copy the ca and key
-	cert := filepath.Join(utils.CertsDir(""), "root.cert")
-	key := filepath.Join(utils.CertsDir(""), "root.key")
+	cert := filepath.Join(utils.ConnectorCertsDir(), "root.cert")
+	key := filepath.Join(utils.ConnectorCertsDir(), "root.key")
 
 	_, err = c.client.CopyFilesToVolume(volID, []string{cert, key}, "/ca", true)
 	if err != nil {
@@ -106,7 +112,7 @@ func (c *ImageCache) createImageCache(networks []string) (string, error) {
 	}
 
 	// pull the container image
-	err = c.client.PullImage(resources.Image{Name: cacheImage}, false)
+	err = c.client.PullImage(ctx, resources.Image{Name: cacheImage}, false)
 	if err != nil {
 		return "", err
 	}
@@ -167,7 +173,7 @@ func (c *ImageCache) findDependentNetworks() []string {
 		}
 
 		if target.Metadata().Type == resources.TypeNetwork {
-			nets = append(nets, target.Metadata().Name)
+			nets = append(nets, utils.FQDNNetworkName(target.Metadata().Name))
 		}
 	}
 
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"encoding/json"
 	"path/filepath"
 	"testing"
@@ -9,16 +10,18 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/clients/mocks"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/shipyard-run/hclconfig"
+	htypes "github.com/shipyard-run/hclconfig/types"
 	"github.com/stretchr/testify/mock"
 	assert "github.com/stretchr/testify/require"
 )
 
-func setupImageCacheTests(t *testing.T) (*config.ImageCache, *clients.MockContainerTasks, *mocks.MockHTTP) {
-	c := config.New()
-	cc := config.NewImageCache("tests")
-	c.AddResource(cc)
+func setupImageCacheTests(t *testing.T) (*resources.ImageCache, *clients.MockContainerTasks, *mocks.MockHTTP) {
+	c := hclconfig.NewConfig()
+	cc := &resources.ImageCache{ResourceMetadata: htypes.ResourceMetadata{Name: "tests", Type: resources.TypeImageCache}}
+	c.AppendResource(cc)
 
 	md := &clients.MockContainerTasks{}
 	hc := &mocks.MockHTTP{}
@@ -39,7 +42,7 @@ func TestImageCacheCreateDoesNotCreateContainerWhenExists(t *testing.T) {
 	cc, md, hc := setupImageCacheTests(t)
 
 	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	removeOn(&md.Mock, "FindContainerIDs")
@@ -52,7 +55,7 @@ func TestImageCacheCreateCreatesVolume(t *testing.T) {
 	cc, md, hc := setupImageCacheTests(t)
 
 	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	md.AssertCalled(t, "CreateVolume", "images")
@@ -62,23 +65,23 @@ func TestImageCachePullsImage(t *testing.T) {
 	cc, md, hc := setupImageCacheTests(t)
 
 	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
-	md.AssertCalled(t, "PullImage", config.Image{Name: cacheImage}, false)
+	md.AssertCalled(t, "PullImage", resources.Image{Name: cacheImage}, false)
 }
 
 func TestImageCacheCreateAddsVolumes(t *testing.T) {
 	cc, md, hc := setupImageCacheTests(t)
 
 	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	md.AssertCalled(t, "CreateContainer", mock.Anything)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0]
-	conf := params.Arguments[0].(*config.Container)
+	conf := params.Arguments[0].(*resources.Container)
 
 	// check volumes
 	assert.Equal(t, utils.FQDNVolumeName("images"), conf.Volumes[0].Source)
@@ -90,28 +93,28 @@ func TestImageCacheCreateAddsEnvironmentVariables(t *testing.T) {
 	cc, md, hc := setupImageCacheTests(t)
 
 	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	md.AssertCalled(t, "CreateContainer", mock.Anything)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0]
-	conf := params.Arguments[0].(*config.Container)
+	conf := params.Arguments[0].(*resources.Container)
 
 	// check environment variables
-	assert.Equal(t, conf.EnvVar["CA_KEY_FILE"], "/cache/ca/root.key")
-	assert.Equal(t, conf.EnvVar["CA_CRT_FILE"], "/cache/ca/root.cert")
-	assert.Equal(t, conf.EnvVar["DOCKER_MIRROR_CACHE"], "/cache/docker")
-	assert.Equal(t, conf.EnvVar["ENABLE_MANIFEST_CACHE"], "true")
-	assert.Equal(t, conf.EnvVar["REGISTRIES"], "k8s.gcr.io gcr.io asia.gcr.io eu.gcr.io us.gcr.io quay.io ghcr.io docker.pkg.github.com")
-	assert.Equal(t, conf.EnvVar["ALLOW_PUSH"], "true")
+	assert.Equal(t, conf.Environment["CA_KEY_FILE"], "/cache/ca/root.key")
+	assert.Equal(t, conf.Environment["CA_CRT_FILE"], "/cache/ca/root.cert")
+	assert.Equal(t, conf.Environment["DOCKER_MIRROR_CACHE"], "/cache/docker")
+	assert.Equal(t, conf.Environment["ENABLE_MANIFEST_CACHE"], "true")
+	assert.Equal(t, conf.Environment["REGISTRIES"], "k8s.gcr.io gcr.io asia.gcr.io eu.gcr.io us.gcr.io quay.io ghcr.io docker.pkg.github.com")
+	assert.Equal(t, conf.Environment["ALLOW_PUSH"], "true")
 }
 
 func TestImageCacheCreateCopiesCerts(t *testing.T) {
 	cc, md, hc := setupImageCacheTests(t)
 
 	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	md.AssertCalled(t, "CreateContainer", mock.Anything)
@@ -122,8 +125,8 @@ func TestImageCacheCreateCopiesCerts(t *testing.T) {
 		"CopyFilesToVolume",
 		"images",
 		[]string{
-			filepath.Join(utils.CertsDir(""), "root.cert"),
-			filepath.Join(utils.CertsDir(""), "root.key"),
+			filepath.Join(utils.ConnectorCertsDir(), "root.cert"),
+			filepath.Join(utils.ConnectorCertsDir(), "root.key"),
 		},
 		"/ca",
 		true,
@@ -131,14 +134,14 @@ func TestImageCacheCreateCopiesCerts(t *testing.T) {
 }
 
 func TestImageCacheDetachesNetworksAndAttachesNew(t *testing.T) {
-	net1 := config.NewNetwork("one")
-	net2 := config.NewNetwork("two")
+	net1 := &resources.Network{ResourceMetadata: htypes.ResourceMetadata{Name: "one", Type: resources.TypeNetwork}}
+	net2 := &resources.Network{ResourceMetadata: htypes.ResourceMetadata{Name: "two", Type: resources.TypeNetwork}}
 
 	cc, md, hc := setupImageCacheTests(t)
-	cc.DependsOn = []string{"network.one", "network.two"}
+	cc.DependsOn = []string{"resource.network.one", "resource.network.two"}
 
-	cc.Config.AddResource(net1)
-	cc.Config.AddResource(net2)
+	cc.ParentConfig.(*hclconfig.Config).AppendResource(net1)
+	cc.ParentConfig.(*hclconfig.Config).AppendResource(net2)
 
 	containerJSON := &types.ContainerJSON{}
 	json.Unmarshal([]byte(cacheContainerInfoWithNetworks), containerJSON)
@@ -148,7 +151,7 @@ func TestImageCacheDetachesNetworksAndAttachesNew(t *testing.T) {
 	md.On("ContainerInfo", "abc").Once().Return(*containerJSON, nil)
 
 	c := NewImageCache(cc, md, hc, hclog.NewNullLogger())
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	// should detatch existing cloud network
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"net"
 
@@ -29,7 +30,7 @@ func NewIngress(
 	return &Ingress{c, cc, co, l}
 }
 
-func (c *Ingress) Create() error {
+func (c *Ingress) Create(ctx context.Context) error {
 	c.log.Info("Create Ingress", "ref", c.config.ID)
 
 	return c.exposeRemote()
@@ -42,7 +43,7 @@ func (c *Ingress) Create() error {
 }
 
 // Destroy satisfies the interface method but is not implemented by LocalExec
-func (c *Ingress) Destroy() error {
+func (c *Ingress) Destroy(ctx context.Context) error {
 	c.log.Info("Destroy Ingress", "ref", c.config.ID, "id", c.config.IngressID)
 
 	err := c.connector.RemoveService(c.config.IngressID)
@@ -62,7 +63,7 @@ func (c *Ingress) Lookup() ([]string, error) {
 	return []string{}, nil
 }
 
-func (c *Ingress) Refresh() error {
+func (c *Ingress) Refresh(ctx context.Context) error {
 	c.log.Info("Refresh Ingress", "ref", c.config.Name)
 
 	return nil
@@ -100,6 +101,11 @@ func (c *Ingress) exposeRemote() error {
 
 	switch r.Metadata().Type {
 	case resources.TypeK8sCluster:
+		err := requireTargetConfig(c.config.Target, "service", "namespace")
+		if err != nil {
+			return err
+		}
+
 		destAddr = fmt.Sprintf(
 			"%s.%s.svc:%s",
 			c.config.Target.Config["service"],
@@ -111,6 +117,11 @@ func (c *Ingress) exposeRemote() error {
 		connectorAddress = fmt.Sprintf("%s:%d", k8s.ExternalIP, k8s.ConnectorPort)
 
 	case resources.TypeNomadCluster:
+		err := requireTargetConfig(c.config.Target, "job", "group", "task")
+		if err != nil {
+			return err
+		}
+
 		destAddr = fmt.Sprintf(
 			"%s.%s.%s:%s",
 			c.config.Target.Config["job"],
@@ -121,6 +132,9 @@ func (c *Ingress) exposeRemote() error {
 
 		n3d := r.(*resources.NomadCluster)
 		connectorAddress = fmt.Sprintf("%s:%d", n3d.ExternalIP, n3d.ConnectorPort)
+
+	default:
+		return fmt.Errorf("unable to expose %s, ingress targets must be a %s or %s resource", c.config.Target.ID, resources.TypeK8sCluster, resources.TypeNomadCluster)
 	}
 
 	// sanitize the name to make it uri format
@@ -159,6 +173,18 @@ func (c *Ingress) exposeRemote() error {
 	return nil
 }
 
+// requireTargetConfig returns an error when the target config does not
+// contain the given keys, the keys are used to build the destination address
+func requireTargetConfig(t resources.TrafficTarget, keys ...string) error {
+	for _, k := range keys {
+		if t.Config[k] == "" {
+			return fmt.Errorf("unable to expose %s, target config parameter '%s' is required", t.ID, k)
+		}
+	}
+
+	return nil
+}
+
 // exposeK8sRemote exposes a remote kubernetes service to the local machine
 //func (c *Ingress) exposeK8sRemote() error {
 //	// get the target
@@ -1,288 +1,187 @@
 package providers
 
 import (
-	"os"
-	"strconv"
+	"context"
+	"fmt"
+	"net"
 	"testing"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/shipyard-run/hclconfig"
+	"github.com/shipyard-run/hclconfig/types"
 	"github.com/stretchr/testify/mock"
 	assert "github.com/stretchr/testify/require"
 )
 
-func testIngressCreateMockConnector(t *testing.T, name string) *clients.ConnectorMock {
-	h := os.Getenv(utils.HomeEnvName())
-	td := t.TempDir()
+func setupIngressTests(t *testing.T) (*resources.Ingress, *clients.ConnectorMock, *Ingress) {
+	md := &clients.MockContainerTasks{}
 
-	os.Setenv(utils.HomeEnvName(), td)
+	mc := &clients.ConnectorMock{}
+	mc.On("ExposeService", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("12345", nil)
+	mc.On("RemoveService", mock.Anything).Return(nil)
 
-	t.Cleanup(func() {
-		os.Setenv(utils.HomeEnvName(), h)
-	})
+	k8s := &resources.K8sCluster{ResourceMetadata: types.ResourceMetadata{ID: "resource.k8s_cluster.test", Name: "test", Type: resources.TypeK8sCluster}}
+	k8s.ExternalIP = "10.5.0.2"
+	k8s.ConnectorPort = 30001
 
-	utils.GetClusterConfig(string(config.TypeK8sCluster) + ".test")
+	nomad := &resources.NomadCluster{ResourceMetadata: types.ResourceMetadata{ID: "resource.nomad_cluster.test", Name: "test", Type: resources.TypeNomadCluster}}
+	nomad.ExternalIP = "10.5.0.3"
+	nomad.ConnectorPort = 31001
 
-	m := &clients.ConnectorMock{}
-	m.On("ExposeService", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("12345", nil)
-	m.On("RemoveService", mock.Anything).Return(nil)
+	ic := &resources.Ingress{ResourceMetadata: types.ResourceMetadata{Name: "local-http", Type: resources.TypeIngress}}
+	ic.Port = freeIngressPort(t)
+	ic.Target = resources.TrafficTarget{
+		ID:     "resource.k8s_cluster.test",
+		Port:   8080,
+		Config: map[string]string{"service": "api", "namespace": "default"},
+	}
 
-	return m
-}
-
-func TestIngressExposeLocalErrorsWhenUnableToFindDependencies(t *testing.T) {
-	md, c := testIngressCreateMocks()
-	mc := testIngressCreateMockConnector(t, testIngressExposeK8sLocalConfig.Name)
-
-	tc := testIngressExposeK8sLocalConfig
-	tc.Source.Config.Cluster = "blah"
-	c.AddResource(&tc)
-
-	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
-
-	err := p.Create()
-	assert.Error(t, err)
-}
-
-func TestIngressExposeLocalErrorsWhenInvalidName(t *testing.T) {
-	md, c := testIngressCreateMocks()
-	mc := testIngressCreateMockConnector(t, testIngressExposeK8sLocalConfig.Name)
+	c := hclconfig.NewConfig()
+	c.AppendResource(k8s)
+	c.AppendResource(nomad)
+	c.AppendResource(ic)
 
-	tc := testIngressExposeK8sLocalConfig
-	tc.Name = "connector"
-	c.AddResource(&tc)
-
-	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
-
-	err := p.Create()
-	assert.Error(t, err)
+	return ic, mc, NewIngress(ic, md, mc, hclog.NewNullLogger())
 }
 
-func TestIngressExposeLocalErrorsWhenInvalidPort(t *testing.T) {
-	md, c := testIngressCreateMocks()
-	mc := testIngressCreateMockConnector(t, testIngressExposeK8sLocalConfig.Name)
-
-	tc := testIngressExposeK8sLocalConfig
-	tc.Source.Config.Port = "abc"
-	c.AddResource(&tc)
-
-	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
+// freeIngressPort returns a port which is not in use on the local machine
+func freeIngressPort(t *testing.T) int {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
 
-	err := p.Create()
-	assert.Error(t, err)
+	return l.Addr().(*net.TCPAddr).Port
 }
 
-func TestIngressExposeLocalErrorsWhenPortReserved(t *testing.T) {
-	md, c := testIngressCreateMocks()
-	mc := testIngressCreateMockConnector(t, testIngressExposeK8sLocalConfig.Name)
-
-	tc := testIngressExposeK8sLocalConfig
-	tc.Source.Config.Port = "60000"
-	c.AddResource(&tc)
+func TestIngressErrorsWhenUnableToFindTarget(t *testing.T) {
+	ic, mc, p := setupIngressTests(t)
+	ic.Target.ID = "resource.k8s_cluster.missing"
 
-	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
-
-	err := p.Create()
-	assert.Error(t, err)
-
-	tc.Source.Config.Port = "60001"
-
-	p = NewIngress(&tc, md, mc, hclog.NewNullLogger())
-
-	err = p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
-}
-
-func TestIngressExposeLocalErrorsWhenInvalidAddress(t *testing.T) {
-	md, c := testIngressCreateMocks()
-	mc := testIngressCreateMockConnector(t, testIngressExposeK8sLocalConfig.Name)
-
-	tc := testIngressExposeK8sLocalConfig
-	tc.Destination.Config.Address = ""
-	c.AddResource(&tc)
 
-	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
-
-	err := p.Create()
-	assert.Error(t, err)
+	mc.AssertNotCalled(t, "ExposeService", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
-func TestIngressExposeLocalCallsExpose(t *testing.T) {
-	md, c := testIngressCreateMocks()
-	mc := testIngressCreateMockConnector(t, testIngressExposeK8sLocalConfig.Name)
-
-	tc := testIngressExposeK8sLocalConfig
-	c.AddResource(&tc)
-
-	clusterConfig, _ := utils.GetClusterConfig(testIngressExposeK8sLocalConfig.Source.Config.Cluster)
+func TestIngressErrorsWhenPortInUse(t *testing.T) {
+	ic, mc, p := setupIngressTests(t)
 
-	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
-
-	err := p.Create()
+	l, err := net.Listen("tcp", "0.0.0.0:0")
 	assert.NoError(t, err)
+	defer l.Close()
 
-	port, _ := strconv.Atoi(tc.Source.Config.Port)
+	ic.Port = l.Addr().(*net.TCPAddr).Port
 
-	mc.AssertCalled(t, "ExposeService",
-		tc.Name,
-		port,
-		clusterConfig.ConnectorAddress(utils.LocalContext),
-		tc.Destination.Config.Address+":"+tc.Destination.Config.Port,
-		"local")
+	err = p.Create(context.Background())
+	assert.Error(t, err)
 
-	assert.Equal(t, tc.Id, "12345")
+	mc.AssertNotCalled(t, "ExposeService", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
-func TestIngressExposeRemoteErrorsWhenUnableToFindDependencies(t *testing.T) {
-	md, c := testIngressCreateMocks()
-	mc := testIngressCreateMockConnector(t, "")
-
-	tc := testIngressExposesLocalK8sServiceConfig
-	tc.Destination.Config.Cluster = "blah"
-	c.AddResource(&tc)
+func TestIngressErrorsWhenTargetIsNotACluster(t *testing.T) {
+	ic, mc, p := setupIngressTests(t)
 
-	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
+	ct := &resources.Container{ResourceMetadata: types.ResourceMetadata{ID: "resource.container.test", Name: "test", Type: resources.TypeContainer}}
+	ic.ParentConfig.(*hclconfig.Config).AppendResource(ct)
+	ic.Target.ID = ct.ID
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
-}
-
-func TestIngressExposeRemoteErrorsWhenNoDestinationAddress(t *testing.T) {
-	md, c := testIngressCreateMocks()
-	mc := testIngressCreateMockConnector(t, "")
-
-	tc := testIngressExposesLocalK8sServiceConfig
-	tc.Destination.Config.Address = ""
-	c.AddResource(&tc)
-
-	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
 
-	err := p.Create()
-	assert.Error(t, err)
+	mc.AssertNotCalled(t, "ExposeService", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
-func TestIngressExposeRemoteErrorsWhenUnableToParsePort(t *testing.T) {
-	md, c := testIngressCreateMocks()
-	mc := testIngressCreateMockConnector(t, "")
-
-	tc := testIngressExposesLocalK8sServiceConfig
-	tc.Source.Config.Port = "sdf"
-	c.AddResource(&tc)
-
-	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
-
-	err := p.Create()
-	assert.Error(t, err)
+func TestIngressErrorsWhenInvalidDestinationAddress(t *testing.T) {
+	tests := map[string]resources.TrafficTarget{
+		"no service":   {ID: "resource.k8s_cluster.test", Port: 8080, Config: map[string]string{"namespace": "default"}},
+		"no namespace": {ID: "resource.k8s_cluster.test", Port: 8080, Config: map[string]string{"service": "api"}},
+		"no job":       {ID: "resource.nomad_cluster.test", Port: 9090, Config: map[string]string{"group": "web", "task": "api"}},
+		"no group":     {ID: "resource.nomad_cluster.test", Port: 9090, Config: map[string]string{"job": "example", "task": "api"}},
+		"no task":      {ID: "resource.nomad_cluster.test", Port: 9090, Config: map[string]string{"job": "example", "group": "web"}},
+	}
+
+	for name, target := range tests {
+		t.Run(name, func(t *testing.T) {
+			ic, mc, p := setupIngressTests(t)
+			ic.Target = target
+
+			err := p.Create(context.Background())
+			assert.Error(t, err)
+
+			mc.AssertNotCalled(t, "ExposeService", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
 }
 
-func TestIngressExposeRemoteErrorsWhenReservedPort(t *testing.T) {
-	md, c := testIngressCreateMocks()
-	mc := testIngressCreateMockConnector(t, "")
-
-	tc := testIngressExposesLocalK8sServiceConfig
-	tc.Source.Config.Port = "30001"
-	c.AddResource(&tc)
-
-	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
-
-	err := p.Create()
-	assert.Error(t, err)
+func TestIngressExposesKubernetesService(t *testing.T) {
+	ic, mc, p := setupIngressTests(t)
 
-	tc.Source.Config.Port = "30002"
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
 
-	p = NewIngress(&tc, md, mc, hclog.NewNullLogger())
+	mc.AssertCalled(t, "ExposeService",
+		"local-http",
+		ic.Port,
+		"10.5.0.2:30001",
+		"api.default.svc:8080",
+		"remote")
 
-	err = p.Create()
-	assert.Error(t, err)
+	assert.Equal(t, "12345", ic.IngressID)
+	assert.Equal(t, fmt.Sprintf("%s:%d", utils.GetDockerIP(), ic.Port), ic.Address)
 }
 
-func TestIngressExposeRemoteCallsExpose(t *testing.T) {
-	md, c := testIngressCreateMocks()
-	mc := testIngressCreateMockConnector(t, testIngressExposeK8sLocalConfig.Name)
+func TestIngressExposesKubernetesServiceWithNamedPort(t *testing.T) {
+	ic, mc, p := setupIngressTests(t)
+	ic.Target.NamedPort = "http"
 
-	tc := testIngressExposesLocalK8sServiceConfig
-	c.AddResource(&tc)
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
 
-	clusterConfig, _ := utils.GetClusterConfig(testIngressExposeK8sLocalConfig.Source.Config.Cluster)
+	mc.AssertCalled(t, "ExposeService",
+		"local-http",
+		ic.Port,
+		"10.5.0.2:30001",
+		"api.default.svc:http",
+		"remote")
+}
 
-	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
+func TestIngressExposesNomadTask(t *testing.T) {
+	ic, mc, p := setupIngressTests(t)
+	ic.Target = resources.TrafficTarget{
+		ID:     "resource.nomad_cluster.test",
+		Port:   9090,
+		Config: map[string]string{"job": "example", "group": "web", "task": "api"},
+	}
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
-	port, _ := strconv.Atoi(tc.Source.Config.Port)
-
 	mc.AssertCalled(t, "ExposeService",
-		tc.Name,
-		port,
-		clusterConfig.ConnectorAddress(utils.LocalContext),
-		tc.Destination.Config.Address+":"+tc.Destination.Config.Port,
+		"local-http",
+		ic.Port,
+		"10.5.0.3:31001",
+		"example.web.api:9090",
 		"remote")
-
-	assert.Equal(t, tc.Id, "12345")
 }
 
-func TestIngressDestroyCallsRemove(t *testing.T) {
-	md, _ := testIngressCreateMocks()
-	mc := testIngressCreateMockConnector(t, testIngressExposeK8sLocalConfig.Name)
+func TestIngressExposeErrorReturnsError(t *testing.T) {
+	_, mc, p := setupIngressTests(t)
+	removeOn(&mc.Mock, "ExposeService")
+	mc.On("ExposeService", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", fmt.Errorf("boom"))
 
-	tc := testIngressExposesLocalK8sServiceConfig
-	tc.Id = "12345"
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
 
-	p := NewIngress(&tc, md, mc, hclog.NewNullLogger())
+func TestIngressDestroyCallsRemove(t *testing.T) {
+	ic, mc, p := setupIngressTests(t)
+	ic.IngressID = "12345"
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 
 	mc.AssertCalled(t, "RemoveService", "12345")
 }
-
-var testIngressExposeK8sLocalConfig = config.Ingress{
-	ResourceInfo: config.ResourceInfo{
-		Name: "local-http",
-		Type: config.TypeIngress,
-	},
-	Source: config.Traffic{
-		Driver: "k8s",
-
-		Config: config.TrafficConfig{
-			Cluster: "k8s_cluster.test",
-			Port:    "12344",
-		},
-	},
-
-	Destination: config.Traffic{
-		Driver: "local",
-
-		Config: config.TrafficConfig{
-			Port:    "1234",
-			Address: "localhost",
-		},
-	},
-}
-
-var testIngressExposesLocalK8sServiceConfig = config.Ingress{
-	ResourceInfo: config.ResourceInfo{
-		Name: "local-http",
-		Type: config.TypeIngress,
-	},
-	Destination: config.Traffic{
-		Driver: "k8s",
-
-		Config: config.TrafficConfig{
-			Cluster: "k8s_cluster.test",
-			Port:    "1234",
-			Address: "localhost",
-		},
-	},
-
-	Source: config.Traffic{
-		Driver: "local",
-
-		Config: config.TrafficConfig{
-			Port: "12344",
-		},
-	},
-}
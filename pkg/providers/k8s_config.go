@@ -1,6 +1,9 @@
 package providers
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	hclog "github.com/hashicorp/go-hclog"
@@ -22,7 +25,7 @@ func NewK8sConfig(c *resources.K8sConfig, kc clients.Kubernetes, l hclog.Logger)
 }
 
 // Create the Kubernetes resources defined by the config
-func (c *K8sConfig) Create() error {
+func (c *K8sConfig) Create(ctx context.Context) error {
 	c.log.Info("Applying Kubernetes configuration", "ref", c.config.Name, "config", c.config.Paths)
 
 	err := c.setup()
@@ -30,29 +33,22 @@ func (c *K8sConfig) Create() error {
 		return err
 	}
 
-	err = c.client.Apply(c.config.Paths, c.config.WaitUntilReady)
+	err = c.client.Apply(ctx, c.config.Paths, c.config.WaitUntilReady)
 	if err != nil {
 		return err
 	}
 
 	// run any health checks
-	if c.config.HealthCheck != nil && len(c.config.HealthCheck.Pods) > 0 {
-		to, err := time.ParseDuration(c.config.HealthCheck.Timeout)
-		if err != nil {
-			return xerrors.Errorf("unable to parse healthcheck duration: %w", err)
-		}
-
-		err = c.client.HealthCheckPods(c.config.HealthCheck.Pods, to)
-		if err != nil {
-			return xerrors.Errorf("healthcheck failed after helm chart setup: %w", err)
-		}
+	err = healthCheckKubernetes(ctx, c.client, c.config.HealthCheck, "default")
+	if err != nil {
+		return xerrors.Errorf("healthcheck failed after Kubernetes config setup: %w", err)
 	}
 
 	return nil
 }
 
 // Destroy the Kubernetes resources defined by the config
-func (c *K8sConfig) Destroy() error {
+func (c *K8sConfig) Destroy(ctx context.Context) error {
 	c.log.Info("Destroy Kubernetes configuration", "ref", c.config.Name, "config", c.config.Paths)
 
 	err := c.setup()
@@ -72,12 +68,26 @@ func (c *K8sConfig) Lookup() ([]string, error) {
 	return []string{}, nil
 }
 
-func (c *K8sConfig) Refresh() error {
+func (c *K8sConfig) Refresh(ctx context.Context) error {
 	c.log.Info("Refresh Kubernetes configuration", "ref", c.config.Name)
 
 	return nil
 }
 
+// HealthCheck implements the HealthChecker interface
+func (c *K8sConfig) HealthCheck(ctx context.Context) error {
+	if c.config.HealthCheck == nil {
+		return nil
+	}
+
+	err := c.setup()
+	if err != nil {
+		return err
+	}
+
+	return healthCheckKubernetes(ctx, c.client, c.config.HealthCheck, "default")
+}
+
 func (c *K8sConfig) setup() error {
 	cluster, err := c.config.ParentConfig.FindResource(c.config.Cluster)
 	if err != nil {
@@ -92,3 +102,71 @@ func (c *K8sConfig) setup() error {
 
 	return nil
 }
+
+// healthCheckKubernetes runs the Kubernetes checks defined in the health check,
+// objects referenced without a namespace are checked in the given namespace
+func healthCheckKubernetes(ctx context.Context, kc clients.Kubernetes, hc *resources.HealthCheck, namespace string) error {
+	if hc == nil {
+		return nil
+	}
+
+	if len(hc.Pods) == 0 && len(hc.Services) == 0 && len(hc.Deployments) == 0 && len(hc.StatefulSets) == 0 && len(hc.Jobs) == 0 {
+		return nil
+	}
+
+	to, err := time.ParseDuration(hc.Timeout)
+	if err != nil {
+		return xerrors.Errorf("unable to parse health check duration: %w", err)
+	}
+
+	if len(hc.Pods) > 0 {
+		err := kc.HealthCheckPods(ctx, hc.Pods, to)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(hc.Services) > 0 {
+		err := kc.HealthCheckServices(ctx, withNamespace(hc.Services, namespace), to)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(hc.Deployments) > 0 {
+		err := kc.HealthCheckDeployments(ctx, withNamespace(hc.Deployments, namespace), to)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(hc.StatefulSets) > 0 {
+		err := kc.HealthCheckStatefulSets(ctx, withNamespace(hc.StatefulSets, namespace), to)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(hc.Jobs) > 0 {
+		err := kc.HealthCheckJobs(ctx, withNamespace(hc.Jobs, namespace), to)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// withNamespace adds the namespace to any names which do not specify one
+func withNamespace(names []string, namespace string) []string {
+	n := []string{}
+	for _, name := range names {
+		if !strings.Contains(name, "/") {
+			name = fmt.Sprintf("%s/%s", namespace, name)
+		}
+
+		n = append(n, name)
+	}
+
+	return n
+}
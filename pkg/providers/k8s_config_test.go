@@ -1,14 +1,17 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/shipyard-run/hclconfig"
+	"github.com/shipyard-run/hclconfig/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -19,14 +22,14 @@ func setupK8sConfig() (*clients.MockKubernetes, *K8sConfig) {
 	mk.On("Apply", mock.Anything, mock.Anything).Return(nil)
 	mk.On("Delete", mock.Anything, mock.Anything).Return(nil)
 
-	c := config.NewK8sCluster("testcluster")
-	kc := config.NewK8sConfig("config")
-	kc.Cluster = "k8s_cluster.testcluster"
+	c := &resources.K8sCluster{ResourceMetadata: types.ResourceMetadata{ID: "resource.k8s_cluster.testcluster", Name: "testcluster", Type: resources.TypeK8sCluster}}
+	kc := &resources.K8sConfig{ResourceMetadata: types.ResourceMetadata{ID: "resource.k8s_config.config", Name: "config", Type: resources.TypeK8sConfig}}
+	kc.Cluster = "resource.k8s_cluster.testcluster"
 	kc.Paths = []string{"/tmp/something"}
 
-	cc := config.New()
-	cc.AddResource(kc)
-	cc.AddResource(c)
+	cc := hclconfig.NewConfig()
+	cc.AppendResource(kc)
+	cc.AppendResource(c)
 
 	p := NewK8sConfig(kc, mk, hclog.Default())
 
@@ -36,7 +39,7 @@ func setupK8sConfig() (*clients.MockKubernetes, *K8sConfig) {
 func TestCreatesCorrectly(t *testing.T) {
 	mk, p := setupK8sConfig()
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	_, destPath, _ := utils.CreateKubeConfigPath("testcluster")
@@ -46,13 +49,13 @@ func TestCreatesCorrectly(t *testing.T) {
 
 func TestRunsHealthChecks(t *testing.T) {
 	mk, p := setupK8sConfig()
-	p.config.HealthCheck = &config.HealthCheck{
+	p.config.HealthCheck = &resources.HealthCheck{
 		Pods:    []string{"app=mine"},
 		Timeout: "60s",
 	}
 	mk.On("HealthCheckPods", mock.Anything, mock.Anything).Return(nil)
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	mk.AssertCalled(t, "HealthCheckPods", []string{"app=mine"}, 60*time.Second)
@@ -60,39 +63,80 @@ func TestRunsHealthChecks(t *testing.T) {
 
 func TestHealthCheckFailReturnsError(t *testing.T) {
 	mk, p := setupK8sConfig()
-	p.config.HealthCheck = &config.HealthCheck{
+	p.config.HealthCheck = &resources.HealthCheck{
 		Pods:    []string{"app=mine"},
 		Timeout: "60s",
 	}
 	mk.On("HealthCheckPods", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 
 	mk.AssertCalled(t, "HealthCheckPods", []string{"app=mine"}, 60*time.Second)
 }
 
+func TestHealthCheckKubernetesChecksObjectsInNamespace(t *testing.T) {
+	mk := &clients.MockKubernetes{}
+	mk.On("HealthCheckServices", mock.Anything, mock.Anything).Return(nil)
+	mk.On("HealthCheckDeployments", mock.Anything, mock.Anything).Return(nil)
+	mk.On("HealthCheckStatefulSets", mock.Anything, mock.Anything).Return(nil)
+	mk.On("HealthCheckJobs", mock.Anything, mock.Anything).Return(nil)
+
+	hc := &resources.HealthCheck{
+		Timeout:      "60s",
+		Services:     []string{"consul-ui", "vault/vault"},
+		Deployments:  []string{"injector"},
+		StatefulSets: []string{"server"},
+		Jobs:         []string{"init"},
+	}
+
+	err := healthCheckKubernetes(context.Background(), mk, hc, "consul")
+	assert.NoError(t, err)
+
+	mk.AssertCalled(t, "HealthCheckServices", []string{"consul/consul-ui", "vault/vault"}, 60*time.Second)
+	mk.AssertCalled(t, "HealthCheckDeployments", []string{"consul/injector"}, 60*time.Second)
+	mk.AssertCalled(t, "HealthCheckStatefulSets", []string{"consul/server"}, 60*time.Second)
+	mk.AssertCalled(t, "HealthCheckJobs", []string{"consul/init"}, 60*time.Second)
+	mk.AssertNotCalled(t, "HealthCheckPods", mock.Anything, mock.Anything)
+}
+
+func TestHealthCheckKubernetesFailReturnsError(t *testing.T) {
+	mk := &clients.MockKubernetes{}
+	mk.On("HealthCheckServices", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	hc := &resources.HealthCheck{
+		Timeout:     "60s",
+		Services:    []string{"consul-ui"},
+		Deployments: []string{"injector"},
+	}
+
+	err := healthCheckKubernetes(context.Background(), mk, hc, "default")
+	assert.Error(t, err)
+
+	mk.AssertNotCalled(t, "HealthCheckDeployments", mock.Anything, mock.Anything)
+}
+
 func TestCreateSetupErrorReturnsError(t *testing.T) {
 	mk, p := setupK8sConfig()
 	removeOn(&mk.Mock, "SetConfig")
 	mk.On("SetConfig", mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestCreateNoClusterErrorReturnsError(t *testing.T) {
 	_, p := setupK8sConfig()
-	p.config.Config.RemoveResource(p.config.Config.Resources[1])
+	p.config.Cluster = "resource.k8s_cluster.missing"
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestDestroysCorrectly(t *testing.T) {
 	mk, p := setupK8sConfig()
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 
 	mk.AssertCalled(t, "Delete", p.config.Paths)
@@ -103,6 +147,6 @@ func TestDestroySetupErrorReturnsError(t *testing.T) {
 	removeOn(&mk.Mock, "SetConfig")
 	mk.On("SetConfig", mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.Error(t, err)
 }
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/shipyard-run/hclconfig/types"
 	"github.com/stretchr/testify/mock"
 )
@@ -14,17 +16,27 @@ func New(c types.Resource) *MockProvider {
 	return &MockProvider{c: c}
 }
 
-func (m *MockProvider) Create() error {
+func (m *MockProvider) Create(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockProvider) Destroy(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockProvider) Refresh(ctx context.Context) error {
 	args := m.Called()
 	return args.Error(0)
 }
 
-func (m *MockProvider) Destroy() error {
+func (m *MockProvider) CheckDrift() error {
 	args := m.Called()
 	return args.Error(0)
 }
 
-func (m *MockProvider) Refresh() error {
+func (m *MockProvider) HealthCheck(ctx context.Context) error {
 	args := m.Called()
 	return args.Error(0)
 }
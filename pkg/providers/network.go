@@ -11,6 +11,7 @@ import (
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"golang.org/x/xerrors"
 )
 
@@ -27,7 +28,7 @@ func NewNetwork(co *resources.Network, cl clients.Docker, l hclog.Logger) *Netwo
 }
 
 // Create implements the provider interface method for creating new networks
-func (n *Network) Create() error {
+func (n *Network) Create(ctx context.Context) error {
 	n.log.Info("Creating Network", "ref", n.config.ID)
 
 	// validate the subnet
@@ -36,6 +37,8 @@ func (n *Network) Create() error {
 		return fmt.Errorf("Unable to create network %s, invalid subnet %s", n.config.Name, n.config.Subnet)
 	}
 
+	name := utils.FQDNNetworkName(n.config.Name)
+
 	// get all the networks
 	nets, err := n.getNetworks("")
 	if err != nil {
@@ -44,7 +47,7 @@ func (n *Network) Create() error {
 
 	// is the network name and subnet equal to one which already exists
 	for _, ne := range nets {
-		if ne.Name == n.config.Name {
+		if ne.Name == name {
 			for _, ci := range ne.IPAM.Config {
 				// check that the returned networks subnet matches the existing networks subnet
 				if ci.Subnet != n.config.Subnet {
@@ -86,7 +89,7 @@ func (n *Network) Create() error {
 }
 
 // Destroy implements the provider interface method for destroying networks
-func (n *Network) Destroy() error {
+func (n *Network) Destroy(ctx context.Context) error {
 	n.log.Info("Destroy Network", "ref", n.config.Name)
 
 	// check network exists if so remove
@@ -96,7 +99,7 @@ func (n *Network) Destroy() error {
 	}
 
 	if len(ids) == 1 {
-		return n.client.NetworkRemove(context.Background(), n.config.Name)
+		return n.client.NetworkRemove(context.Background(), ids[0])
 	}
 
 	return nil
@@ -104,24 +107,55 @@ func (n *Network) Destroy() error {
 
 // Lookup the ID for a network
 func (n *Network) Lookup() ([]string, error) {
-	nets, err := n.getNetworks(n.config.Name)
+	name := utils.FQDNNetworkName(n.config.Name)
+	nets, err := n.getNetworks(name)
 
 	if err != nil {
 		return nil, err
 	}
 
+	// Docker matches network names by prefix, only return the exact match
+	// so that networks in other workspaces are not returned
 	ids := []string{}
 	for _, n1 := range nets {
-		ids = append(ids, n1.ID)
+		if n1.Name == name {
+			ids = append(ids, n1.ID)
+		}
 	}
 
 	return ids, nil
 }
 
-func (c *Network) Refresh() error {
+func (c *Network) Refresh(ctx context.Context) error {
 	c.log.Info("Refresh Network", "ref", c.config.Name)
 
-	return nil
+	return c.CheckDrift()
+}
+
+// CheckDrift checks that the network still exists and has the configured subnet
+func (n *Network) CheckDrift() error {
+	name := utils.FQDNNetworkName(n.config.Name)
+	nets, err := n.getNetworks(name)
+	if err != nil {
+		return fmt.Errorf("unable to list networks: %s", err)
+	}
+
+	// Docker matches network names by prefix, only check the exact match
+	for _, ne := range nets {
+		if ne.Name != name {
+			continue
+		}
+
+		for _, ci := range ne.IPAM.Config {
+			if ci.Subnet != n.config.Subnet {
+				return DriftError{Reason: fmt.Sprintf("network %s has subnet %s, expected %s", n.config.Name, ci.Subnet, n.config.Subnet)}
+			}
+		}
+
+		return nil
+	}
+
+	return DriftError{Reason: fmt.Sprintf("network %s does not exist", n.config.Name)}
 }
 
 func (n *Network) createWithDriver(driver string) error {
@@ -139,11 +173,12 @@ func (n *Network) createWithDriver(driver string) error {
 		Labels: map[string]string{
 			"created_by": "shipyard",
 			"id":         n.config.ID,
+			"workspace":  utils.Workspace(),
 		},
 		Attachable: true,
 	}
 
-	_, err := n.client.NetworkCreate(context.Background(), n.config.Name, opts)
+	_, err := n.client.NetworkCreate(context.Background(), utils.FQDNNetworkName(n.config.Name), opts)
 
 	return err
 }
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -8,7 +9,9 @@ import (
 	"github.com/docker/docker/api/types/network"
 	hclog "github.com/hashicorp/go-hclog"
 	clients "github.com/jumppad-labs/jumppad/pkg/clients/mocks"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	htypes "github.com/shipyard-run/hclconfig/types"
 	"github.com/stretchr/testify/mock"
 	assert "github.com/stretchr/testify/require"
 )
@@ -21,7 +24,7 @@ var bridgeNetwork = types.NetworkResource{
 	},
 }
 
-func setupNetworkTests(c *config.Network) (*clients.MockDocker, *Network) {
+func setupNetworkTests(c *resources.Network) (*clients.MockDocker, *Network) {
 	md := &clients.MockDocker{}
 	md.On("NetworkCreate", mock.Anything, mock.Anything, mock.Anything).Return(types.NetworkCreateResponse{}, nil)
 	md.On("NetworkList", mock.Anything, mock.Anything).Return([]types.NetworkResource{bridgeNetwork}, nil)
@@ -30,14 +33,15 @@ func setupNetworkTests(c *config.Network) (*clients.MockDocker, *Network) {
 }
 
 func TestLookupReturnsID(t *testing.T) {
-	c := config.NewNetwork("testnet")
+	c := &resources.Network{ResourceMetadata: htypes.ResourceMetadata{Name: "testnet", Type: resources.TypeNetwork}}
 	c.Subnet = "10.1.2.0/24"
 
 	md, p := setupNetworkTests(c)
 	removeOn(&md.Mock, "NetworkList")
 	md.On("NetworkList", mock.Anything, mock.Anything).Return([]types.NetworkResource{
 		types.NetworkResource{
-			ID: "testnet",
+			ID:   "testnet",
+			Name: "testnet",
 			IPAM: network.IPAM{
 				Config: []network.IPAMConfig{network.IPAMConfig{Subnet: "10.1.2.0/24"}},
 			},
@@ -49,8 +53,28 @@ func TestLookupReturnsID(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "testnet", ids[0])
 }
+
+func TestLookupWithWorkspaceIgnoresNetworksInOtherWorkspaces(t *testing.T) {
+	utils.SetWorkspace("demo")
+	t.Cleanup(func() { utils.SetWorkspace(utils.DefaultWorkspace) })
+
+	c := &resources.Network{ResourceMetadata: htypes.ResourceMetadata{Name: "testnet", Type: resources.TypeNetwork}}
+	c.Subnet = "10.1.2.0/24"
+
+	md, p := setupNetworkTests(c)
+	removeOn(&md.Mock, "NetworkList")
+	md.On("NetworkList", mock.Anything, mock.Anything).Return([]types.NetworkResource{
+		types.NetworkResource{ID: "default", Name: "testnet"},
+		types.NetworkResource{ID: "demo", Name: "testnet.demo.jumppad.dev"},
+		types.NetworkResource{ID: "other", Name: "testnet.other.jumppad.dev"},
+	}, nil)
+
+	ids, err := p.Lookup()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"demo"}, ids)
+}
 func TestLookupFailReturnsError(t *testing.T) {
-	c := config.NewNetwork("testnet")
+	c := &resources.Network{ResourceMetadata: htypes.ResourceMetadata{Name: "testnet", Type: resources.TypeNetwork}}
 	c.Subnet = "10.1.2.0/24"
 
 	md, p := setupNetworkTests(c)
@@ -61,12 +85,12 @@ func TestLookupFailReturnsError(t *testing.T) {
 	assert.Error(t, err)
 }
 func TestNetworkCreatesCorrectly(t *testing.T) {
-	c := config.NewNetwork("testnet")
+	c := &resources.Network{ResourceMetadata: htypes.ResourceMetadata{Name: "testnet", Type: resources.TypeNetwork}}
 	c.Subnet = "10.1.2.0/24"
 
 	md, p := setupNetworkTests(c)
 
-	err := p.Create()
+	err := p.Create(context.Background())
 
 	assert.NoError(t, err)
 
@@ -80,10 +104,30 @@ func TestNetworkCreatesCorrectly(t *testing.T) {
 	assert.True(t, nco.Attachable)
 	assert.Equal(t, "bridge", nco.Driver)
 	assert.Equal(t, c.Subnet, nco.IPAM.Config[0].Subnet)
+	assert.Equal(t, utils.DefaultWorkspace, nco.Labels["workspace"])
+}
+
+func TestNetworkCreatesWithWorkspaceName(t *testing.T) {
+	utils.SetWorkspace("demo")
+	t.Cleanup(func() { utils.SetWorkspace(utils.DefaultWorkspace) })
+
+	c := &resources.Network{ResourceMetadata: htypes.ResourceMetadata{Name: "testnet", Type: resources.TypeNetwork}}
+	c.Subnet = "10.1.2.0/24"
+
+	md, p := setupNetworkTests(c)
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "NetworkCreate")[0].Arguments
+	nco := params[2].(types.NetworkCreate)
+
+	assert.Equal(t, "testnet.demo.jumppad.dev", params[1].(string))
+	assert.Equal(t, "demo", nco.Labels["workspace"])
 }
 
 func TestNetworkCreatesNatWhenNoBridge(t *testing.T) {
-	c := config.NewNetwork("testnet")
+	c := &resources.Network{ResourceMetadata: htypes.ResourceMetadata{Name: "testnet", Type: resources.TypeNetwork}}
 	c.Subnet = "10.1.2.0/24"
 
 	md, p := setupNetworkTests(c)
@@ -94,7 +138,7 @@ func TestNetworkCreatesNatWhenNoBridge(t *testing.T) {
 	md.On("NetworkCreate", mock.Anything, mock.Anything, mock.Anything).Once().Return(nil, fmt.Errorf("boom"))
 	md.On("NetworkCreate", mock.Anything, mock.Anything, mock.Anything).Once().Return(nil, nil)
 
-	p.Create()
+	p.Create(context.Background())
 
 	md.AssertNumberOfCalls(t, "NetworkCreate", 2)
 	params := md.Calls[2].Arguments
@@ -104,7 +148,7 @@ func TestNetworkCreatesNatWhenNoBridge(t *testing.T) {
 }
 
 func TestNetworkDoesNOTCreateWhenExists(t *testing.T) {
-	c := config.NewNetwork("testnet")
+	c := &resources.Network{ResourceMetadata: htypes.ResourceMetadata{Name: "testnet", Type: resources.TypeNetwork}}
 	c.Subnet = "10.1.2.0/24"
 
 	md, p := setupNetworkTests(c)
@@ -118,13 +162,13 @@ func TestNetworkDoesNOTCreateWhenExists(t *testing.T) {
 		}, bridgeNetwork,
 	}, nil)
 
-	p.Create()
+	p.Create(context.Background())
 
 	md.AssertNotCalled(t, "NetworkCreate", mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestCreateWithCorrectNameAndDifferentSubnetReturnsError(t *testing.T) {
-	c := config.NewNetwork("testnet")
+	c := &resources.Network{ResourceMetadata: htypes.ResourceMetadata{Name: "testnet", Type: resources.TypeNetwork}}
 	c.Subnet = "10.1.2.0/16"
 
 	md, p := setupNetworkTests(c)
@@ -138,12 +182,12 @@ func TestCreateWithCorrectNameAndDifferentSubnetReturnsError(t *testing.T) {
 		}, bridgeNetwork,
 	}, nil)
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestCreateWithOverlappingSubnetReturnsError(t *testing.T) {
-	c := config.NewNetwork("testnet")
+	c := &resources.Network{ResourceMetadata: htypes.ResourceMetadata{Name: "testnet", Type: resources.TypeNetwork}}
 	c.Subnet = "10.2.3.0/16"
 
 	md, p := setupNetworkTests(c)
@@ -157,6 +201,6 @@ func TestCreateWithOverlappingSubnetReturnsError(t *testing.T) {
 		}, bridgeNetwork,
 	}, nil)
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -24,71 +25,90 @@ func NewNomadJob(c *resources.NomadJob, hc clients.Nomad, l hclog.Logger) *Nomad
 }
 
 // Create the Nomad jobs defined by the config
-func (n *NomadJob) Create() error {
+func (n *NomadJob) Create(ctx context.Context) error {
 	n.log.Info("Create Nomad Job", "ref", n.config.Name, "files", n.config.Paths)
 
-	// find the cluster
-	cc, err := n.config.ParentConfig.FindResource(n.config.Cluster)
+	nc, err := n.clusterClient()
 	if err != nil {
 		return err
 	}
 
-	nomadCluster := cc.(*resources.NomadCluster)
-
-	// load the config
-	n.client.SetConfig(fmt.Sprintf("http://%s", nomadCluster.ExternalIP), nomadCluster.APIPort, nomadCluster.ClientNodes)
-
-	err = n.client.Create(n.config.Paths)
+	err = nc.Create(n.config.Paths)
 	if err != nil {
 		return xerrors.Errorf("Unable to create Nomad jobs: %w", err)
 	}
 
 	// if health check defined wait for jobs
 	if n.config.HealthCheck != nil {
-		st := time.Now()
 		dur, err := time.ParseDuration(n.config.HealthCheck.Timeout)
 		if err != nil {
 			return err
 		}
 
-		for _, j := range n.config.HealthCheck.NomadJobs {
-			for {
-				if time.Now().Sub(st) >= dur {
-					return xerrors.Errorf("Timeout waiting for job '%s' to start", j)
-				}
+		return healthCheckNomadJobs(ctx, nc, n.config.HealthCheck.NomadJobs, dur, n.log.With("ref", n.config.Name))
+	}
+
+	return nil
+}
 
-				n.log.Debug("Checking health for", "ref", n.config.Name, "job", j)
+// HealthCheck implements the HealthChecker interface
+func (n *NomadJob) HealthCheck(ctx context.Context) error {
+	if n.config.HealthCheck == nil {
+		return nil
+	}
 
-				s, err := n.client.JobRunning(j)
-				if err == nil && s == true {
-					n.log.Debug("Health passed for", "ref", n.config.Name, "job", j)
-					break
-				}
+	dur, err := time.ParseDuration(n.config.HealthCheck.Timeout)
+	if err != nil {
+		return err
+	}
+
+	nc, err := n.clusterClient()
+	if err != nil {
+		return err
+	}
+
+	return healthCheckNomadJobs(ctx, nc, n.config.HealthCheck.NomadJobs, dur, n.log.With("ref", n.config.Name))
+}
 
-				time.Sleep(1 * time.Second)
+// healthCheckNomadJobs waits for all the jobs to be running, the timeout is
+// shared between all jobs
+func healthCheckNomadJobs(ctx context.Context, client clients.Nomad, jobs []string, timeout time.Duration, l hclog.Logger) error {
+	st := time.Now()
+	for _, j := range jobs {
+		for {
+			if time.Now().Sub(st) >= timeout {
+				return xerrors.Errorf("Timeout waiting for job '%s' to start", j)
 			}
-		}
 
+			l.Debug("Checking health for", "job", j)
+
+			s, err := client.JobRunning(j)
+			if err == nil && s == true {
+				l.Debug("Health passed for", "job", j)
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(1 * time.Second):
+			}
+		}
 	}
 
 	return nil
 }
 
 // Destroy the Nomad jobs defined by the config
-func (n *NomadJob) Destroy() error {
+func (n *NomadJob) Destroy(ctx context.Context) error {
 	n.log.Info("Destroy Nomad Job", "ref", n.config.Name)
 
-	cc, err := n.config.ParentConfig.FindResource(n.config.Cluster)
+	nc, err := n.clusterClient()
 	if err != nil {
 		return err
 	}
 
-	nomadCluster := cc.(*resources.NomadCluster)
-
-	// load the config
-	n.client.SetConfig(fmt.Sprintf("http://%s", nomadCluster.ExternalIP), nomadCluster.APIPort, nomadCluster.ClientNodes)
-
-	err = n.client.Stop(n.config.Paths)
+	err = nc.Stop(n.config.Paths)
 	if err != nil {
 		n.log.Error("Unable to destroy Nomad job", "error", err)
 		return nil
@@ -102,10 +122,23 @@ func (n *NomadJob) Lookup() ([]string, error) {
 	return nil, nil
 }
 
-func (c *NomadJob) Refresh() error {
+func (c *NomadJob) Refresh(ctx context.Context) error {
 	c.log.Info("Refresh Nomad Job", "ref", c.config.Name)
 
 	return nil
 }
 
 // /v1/jobs/parse
+
+// clusterClient returns a Nomad client configured for the cluster the jobs
+// are deployed to
+func (n *NomadJob) clusterClient() (clients.Nomad, error) {
+	cc, err := n.config.ParentConfig.FindResource(n.config.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	nomadCluster := cc.(*resources.NomadCluster)
+
+	return n.client.SetConfig(fmt.Sprintf("http://%s", nomadCluster.ExternalIP), nomadCluster.APIPort, nomadCluster.ClientNodes)
+}
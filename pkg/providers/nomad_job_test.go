@@ -1,71 +1,85 @@
 package providers
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
-	"github.com/jumppad-labs/jumppad/pkg/clients/mocks"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/mohae/deepcopy"
+	"github.com/shipyard-run/hclconfig"
+	"github.com/shipyard-run/hclconfig/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
-func setupNomadJobMocks() (*config.NomadJob, *mocks.MockNomad) {
+func setupNomadJobMocks() (*resources.NomadJob, *clients.MockNomad) {
 	// copy the config
-	cc := *clusterNomadConfig
-	cn := *clusterNetwork
-	jc := *nomadJob
+	cc := deepcopy.Copy(clusterNomadConfig).(*resources.NomadCluster)
+	cn := deepcopy.Copy(clusterNetwork).(*resources.Network)
+	jc := deepcopy.Copy(nomadJob).(*resources.NomadJob)
 
-	c := config.New()
-	c.AddResource(&cc)
-	c.AddResource(&cn)
-	c.AddResource(&jc)
+	c := hclconfig.NewConfig()
+	c.AppendResource(cc)
+	c.AppendResource(cn)
+	c.AppendResource(jc)
 
-	mh := &mocks.MockNomad{}
-	mh.On("SetConfig", mock.Anything, mock.Anything).Return(nil)
-	mh.On("Create", mock.Anything, mock.Anything).Return(nil)
+	mh := &clients.MockNomad{}
+	mh.On("SetConfig", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mh.On("Create", mock.Anything).Return(nil)
 
-	return &jc, mh
+	return jc, mh
 }
 
-var nomadJob = &config.NomadJob{
-	ResourceInfo: config.ResourceInfo{Name: "test", Type: config.TypeNomadJob},
-	Cluster:      "nomad_cluster.test",
-	Paths:        []string{"./example.nomad"},
+var nomadJob = &resources.NomadJob{
+	ResourceMetadata: types.ResourceMetadata{Name: "test", Type: resources.TypeNomadJob},
+	Cluster:          "resource.nomad_cluster.test",
+	Paths:            []string{"./example.nomad"},
 }
 
 func TestNomadJobWithNonExistentClusterReturnsError(t *testing.T) {
 	jc, mh := setupNomadJobMocks()
-	jc.Config.Resources = jc.Config.Resources[1:]
+	jc.Cluster = "resource.nomad_cluster.missing"
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestNomadJobUnableToLoadConfigReturnsError(t *testing.T) {
 	jc, mh := setupNomadJobMocks()
-	jc.Config.Resources = jc.Config.Resources[1:]
+	jc.Cluster = "resource.nomad_cluster.missing"
 
 	removeOn(&mh.Mock, "SetConfig")
-	mh.On("SetConfig", mock.Anything).Return(fmt.Errorf("boom"))
+	mh.On("SetConfig", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestNomadJobCreateReturnsError(t *testing.T) {
 	jc, mh := setupNomadJobMocks()
 	removeOn(&mh.Mock, "Create")
-	mh.On("Create", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+	mh.On("Create", mock.Anything).Return(fmt.Errorf("boom"))
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -74,13 +88,13 @@ func TestNomadJobValidatesConfig(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 }
 
 func TestNomadJobHealthCheckInvalidDurationReturnsError(t *testing.T) {
 	jc, mh := setupNomadJobMocks()
-	jc.HealthCheck = &config.HealthCheck{
+	jc.HealthCheck = &resources.HealthCheck{
 		Timeout:   "1t",
 		NomadJobs: []string{"abc"},
 	}
@@ -89,13 +103,13 @@ func TestNomadJobHealthCheckInvalidDurationReturnsError(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestNomadJobHealthCheckReturnsErrorWhenNotHealthy(t *testing.T) {
 	jc, mh := setupNomadJobMocks()
-	jc.HealthCheck = &config.HealthCheck{
+	jc.HealthCheck = &resources.HealthCheck{
 		Timeout:   "3s",
 		NomadJobs: []string{"abc"},
 	}
@@ -103,14 +117,14 @@ func TestNomadJobHealthCheckReturnsErrorWhenNotHealthy(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 	mh.AssertNumberOfCalls(t, "JobRunning", 3)
 }
 
 func TestNomadJobHealthCheckReturnsErrorWhenHealthError(t *testing.T) {
 	jc, mh := setupNomadJobMocks()
-	jc.HealthCheck = &config.HealthCheck{
+	jc.HealthCheck = &resources.HealthCheck{
 		Timeout:   "3s",
 		NomadJobs: []string{"abc"},
 	}
@@ -118,14 +132,14 @@ func TestNomadJobHealthCheckReturnsErrorWhenHealthError(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 	mh.AssertNumberOfCalls(t, "JobRunning", 3)
 }
 
 func TestNomadJobHealthCheckReturnsOKHealthy(t *testing.T) {
 	jc, mh := setupNomadJobMocks()
-	jc.HealthCheck = &config.HealthCheck{
+	jc.HealthCheck = &resources.HealthCheck{
 		Timeout:   "3s",
 		NomadJobs: []string{"abc"},
 	}
@@ -133,18 +147,18 @@ func TestNomadJobHealthCheckReturnsOKHealthy(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	mh.AssertNumberOfCalls(t, "JobRunning", 1)
 }
 
 func TestNomadJobDestroyReturnsErrorWhenNoCluster(t *testing.T) {
 	jc, mh := setupNomadJobMocks()
-	jc.Config.Resources = jc.Config.Resources[1:]
+	jc.Cluster = "resource.nomad_cluster.missing"
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.Error(t, err)
 }
 
@@ -156,8 +170,126 @@ func TestNomadJobDestroyCallsStop(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Destroy()
+	err := p.Destroy(context.Background())
 	assert.NoError(t, err)
 
 	mh.AssertCalled(t, "Stop", jc.Paths)
 }
+
+// setupNomadAPI starts a fake Nomad API which only accepts and reports as
+// running the given job, requests for any other job fail
+func setupNomadAPI(t *testing.T, job string) (string, int) {
+	s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		d, _ := ioutil.ReadAll(r.Body)
+
+		switch r.URL.Path {
+		case "/v1/jobs/parse":
+			if !strings.Contains(string(d), fmt.Sprintf(`job \"%s\"`, job)) {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			fmt.Fprintf(rw, `{"ID": "%s"}`, job)
+		case "/v1/jobs":
+			if !strings.Contains(string(d), fmt.Sprintf(`"ID": "%s"`, job)) {
+				rw.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		case fmt.Sprintf("/v1/job/%s/allocations", job):
+			fmt.Fprint(rw, `[{"ClientStatus": "running"}]`)
+		default:
+			fmt.Fprint(rw, `[]`)
+		}
+	}))
+
+	t.Cleanup(s.Close)
+
+	host, port, _ := net.SplitHostPort(s.Listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+
+	return host, p
+}
+
+// setupSharedNomadJobs returns providers for two jobs deployed to different
+// clusters which share a single Nomad client
+func setupSharedNomadJobs(t *testing.T) []*NomadJob {
+	nc := clients.NewNomad(clients.NewHTTP(1*time.Millisecond, hclog.NewNullLogger()), 1*time.Millisecond, hclog.NewNullLogger())
+	c := hclconfig.NewConfig()
+
+	providers := []*NomadJob{}
+	for _, name := range []string{"one", "two"} {
+		host, port := setupNomadAPI(t, name)
+
+		cc := &resources.NomadCluster{ResourceMetadata: types.ResourceMetadata{Name: name, Type: resources.TypeNomadCluster}}
+		cc.ID = fmt.Sprintf("resource.nomad_cluster.%s", name)
+		cc.ExternalIP = host
+		cc.APIPort = port
+		c.AppendResource(cc)
+
+		jf := filepath.Join(t.TempDir(), "job.hcl")
+		ioutil.WriteFile(jf, []byte(fmt.Sprintf(`job "%s" {}`, name)), os.ModePerm)
+
+		jc := &resources.NomadJob{ResourceMetadata: types.ResourceMetadata{Name: name, Type: resources.TypeNomadJob}}
+		jc.ID = fmt.Sprintf("resource.nomad_job.%s", name)
+		jc.Cluster = cc.ID
+		jc.Paths = []string{jf}
+		jc.HealthCheck = &resources.HealthCheck{Timeout: "2s", NomadJobs: []string{name}}
+		c.AppendResource(jc)
+
+		providers = append(providers, NewNomadJob(jc, nc, hclog.NewNullLogger()))
+	}
+
+	return providers
+}
+
+// runConcurrently calls f for each of the providers in turn from 20
+// goroutines and returns the errors
+func runConcurrently(providers []*NomadJob, f func(p *NomadJob) error) []error {
+	wg := sync.WaitGroup{}
+	errs := make(chan error, 20)
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func(p *NomadJob) {
+			defer wg.Done()
+			errs <- f(p)
+		}(providers[i%len(providers)])
+	}
+
+	wg.Wait()
+	close(errs)
+
+	all := []error{}
+	for err := range errs {
+		all = append(all, err)
+	}
+
+	return all
+}
+
+func TestNomadJobHealthCheckWithSharedClientUsesOwnCluster(t *testing.T) {
+	providers := setupSharedNomadJobs(t)
+
+	errs := runConcurrently(providers, func(p *NomadJob) error {
+		return p.HealthCheck(context.Background())
+	})
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+}
+
+// the engine creates independent resources concurrently, run with -race to
+// detect providers mutating the shared client
+func TestNomadJobCreateConcurrentlyWithSharedClientSubmitsToOwnCluster(t *testing.T) {
+	providers := setupSharedNomadJobs(t)
+
+	errs := runConcurrently(providers, func(p *NomadJob) error {
+		return p.Create(context.Background())
+	})
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+}
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -19,12 +20,12 @@ func NewNull(c types.Resource, l hclog.Logger) *Null {
 	return &Null{c, l}
 }
 
-func (n *Null) Create() error {
+func (n *Null) Create(ctx context.Context) error {
 	n.log.Info(fmt.Sprintf("Creating %s", strings.Title(string(n.config.Metadata().Type))), "ref", n.config.Metadata().Name)
 	return nil
 }
 
-func (n *Null) Destroy() error {
+func (n *Null) Destroy(ctx context.Context) error {
 	return nil
 }
 
@@ -32,6 +33,6 @@ func (n *Null) Lookup() ([]string, error) {
 	return nil, nil
 }
 
-func (n *Null) Refresh() error {
+func (n *Null) Refresh(ctx context.Context) error {
 	return nil
 }
@@ -0,0 +1,83 @@
+package providers
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/plugins"
+)
+
+// Plugin is a provider which calls an external plugin to manage the resource
+type Plugin struct {
+	config *resources.Plugin
+	client plugins.ResourceProvider
+	log    hclog.Logger
+}
+
+// NewPlugin creates a provider for a resource provided by the given plugin
+func NewPlugin(c *resources.Plugin, cl plugins.ResourceProvider, l hclog.Logger) *Plugin {
+	return &Plugin{c, cl, l}
+}
+
+// Create implements the provider interface method
+func (p *Plugin) Create(ctx context.Context) error {
+	p.log.Info("Creating Plugin Resource", "ref", p.config.ID, "type", p.config.Type)
+
+	o, err := p.client.Create(ctx, p.resource())
+	if err != nil {
+		return err
+	}
+
+	p.config.Outputs = o
+
+	return nil
+}
+
+// Destroy implements the provider interface method
+func (p *Plugin) Destroy(ctx context.Context) error {
+	p.log.Info("Destroy Plugin Resource", "ref", p.config.ID, "type", p.config.Type)
+
+	return p.client.Destroy(ctx, p.resource())
+}
+
+// Refresh implements the provider interface method
+func (p *Plugin) Refresh(ctx context.Context) error {
+	p.log.Debug("Refresh Plugin Resource", "ref", p.config.ID, "type", p.config.Type)
+
+	return p.checkDrift(ctx)
+}
+
+// CheckDrift implements the DriftChecker interface
+func (p *Plugin) CheckDrift() error {
+	return p.checkDrift(context.Background())
+}
+
+// Lookup implements the provider interface method
+func (p *Plugin) Lookup() ([]string, error) {
+	return p.client.Lookup(context.Background(), p.resource())
+}
+
+func (p *Plugin) checkDrift(ctx context.Context) error {
+	reason, err := p.client.Refresh(ctx, p.resource())
+	if err != nil {
+		return err
+	}
+
+	if reason != "" {
+		return DriftError{Reason: reason}
+	}
+
+	return nil
+}
+
+func (p *Plugin) resource() plugins.Resource {
+	return plugins.Resource{
+		ID:      p.config.ID,
+		Name:    p.config.Name,
+		Type:    p.config.Type,
+		Module:  p.config.Module,
+		Config:  p.config.Config,
+		Outputs: p.config.Outputs,
+	}
+}
@@ -1,16 +1,21 @@
 package providers
 
-// Provider defines an interface to be implemented by providers
+import "context"
+
+// Provider defines an interface to be implemented by providers.
+// The context passed to Create, Destroy and Refresh is cancelled when the
+// user interrupts the operation, long running operations should return
+// as soon as possible once the context is done.
 type Provider interface {
 	// Create is called when a resource does not exist or creation has previously
 	// failed and 'up' is run
-	Create() error
+	Create(ctx context.Context) error
 
 	// Destroy is called when a resource is failed or created and 'down' is run
-	Destroy() error
+	Destroy(ctx context.Context) error
 
 	// Refresh is called when a resource is created and 'up' is run
-	Refresh() error
+	Refresh(ctx context.Context) error
 
 	// Lookup is a utility to determine the existence of a resource
 	Lookup() ([]string, error)
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"math/rand"
 
 	"github.com/hashicorp/go-hclog"
@@ -18,7 +19,7 @@ func NewRandomNumber(c *resources.RandomNumber, l hclog.Logger) *RandomNumber {
 	return &RandomNumber{c, l}
 }
 
-func (n *RandomNumber) Create() error {
+func (n *RandomNumber) Create(ctx context.Context) error {
 	n.log.Info("Creating random number", "ref", n.config.Metadata().ID)
 
 	rn := rand.Intn(n.config.Maximum-n.config.Minimum) + n.config.Minimum
@@ -29,7 +30,7 @@ func (n *RandomNumber) Create() error {
 	return nil
 }
 
-func (n *RandomNumber) Destroy() error {
+func (n *RandomNumber) Destroy(ctx context.Context) error {
 	return nil
 }
 
@@ -37,6 +38,6 @@ func (n *RandomNumber) Lookup() ([]string, error) {
 	return nil, nil
 }
 
-func (n *RandomNumber) Refresh() error {
+func (n *RandomNumber) Refresh(ctx context.Context) error {
 	return nil
 }
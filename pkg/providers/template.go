@@ -2,6 +2,7 @@ package providers
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -73,7 +74,7 @@ func castVar(v cty.Value) interface{} {
 }
 
 // Create a new template
-func (c *Template) Create() error {
+func (c *Template) Create(ctx context.Context) error {
 	c.log.Info("Generating template", "ref", c.config.ID, "output", c.config.Destination)
 	c.log.Debug("Template content", "ref", c.config.ID, "source", c.config.Source)
 
@@ -145,7 +146,7 @@ func (c *Template) Create() error {
 	return nil
 }
 
-func (c *Template) Destroy() error {
+func (c *Template) Destroy(ctx context.Context) error {
 	if _, err := os.Stat(c.config.Destination); !os.IsNotExist(err) {
 		err := os.RemoveAll(c.config.Destination)
 		if err != nil {
@@ -164,12 +165,17 @@ func (c *Template) Lookup() ([]string, error) {
 	return []string{}, nil
 }
 
+// CheckDrift checks that the rendered template still exists
+func (c *Template) CheckDrift() error {
+	return checkFilesExist(c.config.Destination)
+}
+
 // Refresh causes the template to be destroyed and recreated
-func (c *Template) Refresh() error {
+func (c *Template) Refresh(ctx context.Context) error {
 	c.log.Info("Refresh Template", "ref", c.config.ID)
 
-	c.Destroy()
-	return c.Create()
+	c.Destroy(ctx)
+	return c.Create(ctx)
 }
 
 // wraps the given string in quotes and returns
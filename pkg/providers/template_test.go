@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -8,11 +9,14 @@ import (
 	"testing"
 
 	"github.com/hashicorp/go-hclog"
-	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/shipyard-run/hclconfig/types"
 	assert "github.com/stretchr/testify/require"
 )
 
-func setupTemplate(t *testing.T, filename string) (*config.Template, *Template) {
+func setupTemplate(t *testing.T, filename string) (*resources.Template, *Template) {
 	tmpl := createTemplate(t, filename)
 
 	return tmpl, NewTemplate(tmpl, hclog.NewNullLogger())
@@ -22,7 +26,7 @@ func TestTemplateReturnsErrorWhenEmpty(t *testing.T) {
 	tmpl, provider := setupTemplate(t, "")
 	tmpl.Source = ""
 
-	err := provider.Create()
+	err := provider.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -30,7 +34,7 @@ func TestTemplateReturnsErrorWhenCantParse(t *testing.T) {
 	tmpl, provider := setupTemplate(t, "")
 	tmpl.Source = "template #{{ .Something"
 
-	err := provider.Create()
+	err := provider.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -41,7 +45,7 @@ func TestTemplateProcessesCorrectly(t *testing.T) {
 
 	tmpl, provider := setupTemplate(t, filename)
 
-	err := provider.Create()
+	err := provider.Create(context.Background())
 	assert.NoError(t, err)
 
 	d, err := ioutil.ReadFile(tmpl.Destination)
@@ -67,9 +71,9 @@ func TestTemplateProcessesCorrectly(t *testing.T) {
 
 func TestTemplateWriteSourceWhenNoVars(t *testing.T) {
 	tmpl, provider := setupTemplate(t, "")
-	provider.config.Vars = nil
+	provider.config.Variables = nil
 
-	err := provider.Create()
+	err := provider.Create(context.Background())
 	assert.NoError(t, err)
 
 	d, err := ioutil.ReadFile(tmpl.Destination)
@@ -86,7 +90,7 @@ func TestTemplateOverwritesExistingFile(t *testing.T) {
 	f.WriteString("Some text in the file")
 	f.Close()
 
-	err = provider.Create()
+	err = provider.Create(context.Background())
 	assert.NoError(t, err)
 
 	d, err := ioutil.ReadFile(tmpl.Destination)
@@ -103,37 +107,14 @@ func TestTemplateDestroyRemovesDestination(t *testing.T) {
 	f.WriteString("test")
 	f.Close()
 
-	err = provider.Destroy()
+	err = provider.Destroy(context.Background())
 	assert.NoError(t, err)
 
 	assert.NoFileExists(t, tmpl.Destination)
 }
 
-func createTemplate(t *testing.T, file string) *config.Template {
-	tmpl := fmt.Sprintf(`
-variable "bool_var" {
-	default = true
-}
-
-variable "num_var" {
-	default = 13
-}
-
-variable "str_var" {
-	default = "Abc"
-}
-
-variable "str_var_whitespace" {
-	default = " with spaces "
-}
-
-variable "other_ports" {
-	default = [2000,2001]
-}
-
-template "fetch_consul_resources" {
-  source = <<EOF
-
+func createTemplate(t *testing.T, file string) *resources.Template {
+	source := fmt.Sprintf(`
 	data_dir = "#{{ .Vars.data_dir }}"
 	log_level = "DEBUG"
 	node_name = "server"
@@ -172,16 +153,13 @@ template "fetch_consul_resources" {
 		quote_string = #{{ .Vars.string_var | quote }}
 		trim_spaces = "#{{ .Vars.string_var_whitespace | trim }}"
 	}
+`, file)
 
-	EOF
-
-	destination = "./out.txt"
-
-  vars = {
-		other_ports = var.other_ports
-		bool_var = var.bool_var
-		string_var = var.str_var
-		num_var = var.num_var
+	vars := `{
+		other_ports = [2000,2001]
+		bool_var = true
+		string_var = "Abc"
+		num_var = 13
 		data_dir = "something"
 		enabled = true
 		not_enabled = false
@@ -191,13 +169,16 @@ template "fetch_consul_resources" {
 			a = 1
 			ports = ["sfsf","sfsf"]
 		}
-		string_var_whitespace = var.str_var_whitespace
-  }
-}`, file)
+		string_var_whitespace = " with spaces "
+  }`
 
-	conf, _ := config.CreateConfigFromStrings(t, tmpl)
-	cr, err := conf.FindResource("template.fetch_consul_resources")
-	assert.NoError(t, err)
+	expr, diags := hclsyntax.ParseExpression([]byte(vars), "vars.hcl", hcl.Pos{Line: 1, Column: 1})
+	assert.False(t, diags.HasErrors())
 
-	return cr.(*config.Template)
+	return &resources.Template{
+		ResourceMetadata: types.ResourceMetadata{Name: "fetch_consul_resources", Type: resources.TypeTemplate},
+		Source:           source,
+		Destination:      path.Join(t.TempDir(), "out.txt"),
+		Variables:        &hcl.Attribute{Name: "vars", Expr: expr},
+	}
 }
@@ -0,0 +1,120 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+)
+
+// Volume is a provider for creating Docker volumes
+type Volume struct {
+	config *resources.DockerVolume
+	client clients.Docker
+	log    hclog.Logger
+}
+
+// NewVolume creates a new volume provider with the given config and Docker client
+func NewVolume(co *resources.DockerVolume, cl clients.Docker, l hclog.Logger) *Volume {
+	return &Volume{co, cl, l}
+}
+
+// Create implements the provider interface method for creating new volumes
+func (v *Volume) Create(ctx context.Context) error {
+	v.log.Info("Creating Volume", "ref", v.config.ID)
+
+	// volumes that have been imported keep their existing name
+	if v.config.VolumeName == "" {
+		v.config.VolumeName = utils.FQDNVolumeName(v.config.Name)
+	}
+
+	ids, err := v.Lookup()
+	if err != nil {
+		return fmt.Errorf("unable to list existing volumes: %s", err)
+	}
+
+	if len(ids) > 0 {
+		v.log.Debug("Volume already exists, skip creation", "ref", v.config.ID, "name", v.config.VolumeName)
+		return nil
+	}
+
+	_, err = v.client.VolumeCreate(context.Background(), volume.VolumeCreateBody{
+		Name:   v.config.VolumeName,
+		Driver: "local",
+		Labels: map[string]string{
+			"created_by": "jumppad",
+			"id":         v.config.ID,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create volume %s: %s", v.config.VolumeName, err)
+	}
+
+	return nil
+}
+
+// Destroy implements the provider interface method for destroying volumes
+func (v *Volume) Destroy(ctx context.Context) error {
+	v.log.Info("Destroy Volume", "ref", v.config.ID)
+
+	ids, err := v.Lookup()
+	if err != nil {
+		return fmt.Errorf("unable to list existing volumes: %s", err)
+	}
+
+	if len(ids) == 1 {
+		return v.client.VolumeRemove(context.Background(), v.config.VolumeName, true)
+	}
+
+	return nil
+}
+
+// Lookup the name of the volume
+func (v *Volume) Lookup() ([]string, error) {
+	if v.config.VolumeName == "" {
+		return nil, nil
+	}
+
+	args := filters.NewArgs()
+	args.Add("name", v.config.VolumeName)
+
+	vols, err := v.client.VolumeList(context.Background(), args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Docker matches volume names by prefix, only return the exact match
+	ids := []string{}
+	for _, vol := range vols.Volumes {
+		if vol.Name == v.config.VolumeName {
+			ids = append(ids, vol.Name)
+		}
+	}
+
+	return ids, nil
+}
+
+func (v *Volume) Refresh(ctx context.Context) error {
+	v.log.Info("Refresh Volume", "ref", v.config.Name)
+
+	return v.CheckDrift()
+}
+
+// CheckDrift checks that the volume still exists
+func (v *Volume) CheckDrift() error {
+	ids, err := v.Lookup()
+	if err != nil {
+		return fmt.Errorf("unable to list volumes: %s", err)
+	}
+
+	if v.config.VolumeName != "" && len(ids) == 0 {
+		return DriftError{Reason: fmt.Sprintf("volume %s does not exist", v.config.VolumeName)}
+	}
+
+	return nil
+}
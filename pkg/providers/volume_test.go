@@ -0,0 +1,86 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/volume"
+	hclog "github.com/hashicorp/go-hclog"
+	clients "github.com/jumppad-labs/jumppad/pkg/clients/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	htypes "github.com/shipyard-run/hclconfig/types"
+	"github.com/stretchr/testify/mock"
+	assert "github.com/stretchr/testify/require"
+)
+
+func setupVolumeTests(existing ...string) (*resources.DockerVolume, *clients.MockDocker, *Volume) {
+	c := &resources.DockerVolume{ResourceMetadata: htypes.ResourceMetadata{Name: "data", ID: "resource.volume.data"}}
+
+	vols := []*types.Volume{}
+	for _, v := range existing {
+		vols = append(vols, &types.Volume{Name: v})
+	}
+
+	md := &clients.MockDocker{}
+	md.On("VolumeList", mock.Anything, mock.Anything).Return(volume.VolumeListOKBody{Volumes: vols}, nil)
+	md.On("VolumeCreate", mock.Anything, mock.Anything).Return(types.Volume{}, nil)
+	md.On("VolumeRemove", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	return c, md, NewVolume(c, md, hclog.NewNullLogger())
+}
+
+func TestVolumeCreateCreatesVolume(t *testing.T) {
+	c, md, p := setupVolumeTests()
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "data.volume.jumppad.dev", c.VolumeName)
+
+	params := md.Calls[1].Arguments[1].(volume.VolumeCreateBody)
+	assert.Equal(t, "data.volume.jumppad.dev", params.Name)
+}
+
+func TestVolumeCreateWithExistingVolumeDoesNothing(t *testing.T) {
+	c, md, p := setupVolumeTests("data.volume.jumppad.dev")
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "data.volume.jumppad.dev", c.VolumeName)
+	md.AssertNotCalled(t, "VolumeCreate", mock.Anything, mock.Anything)
+}
+
+func TestVolumeDestroyRemovesImportedVolume(t *testing.T) {
+	c, md, p := setupVolumeTests("my-data-backup", "my-data")
+	c.VolumeName = "my-data"
+
+	err := p.Destroy(context.Background())
+	assert.NoError(t, err)
+	md.AssertCalled(t, "VolumeRemove", mock.Anything, "my-data", true)
+}
+
+func TestVolumeDestroyWithMissingVolumeDoesNothing(t *testing.T) {
+	c, md, p := setupVolumeTests("my-data-backup")
+	c.VolumeName = "my-data"
+
+	err := p.Destroy(context.Background())
+	assert.NoError(t, err)
+	md.AssertNotCalled(t, "VolumeRemove", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVolumeRefreshWithMissingVolumeReturnsDriftError(t *testing.T) {
+	c, _, p := setupVolumeTests("my-data-backup")
+	c.VolumeName = "my-data"
+
+	err := p.Refresh(context.Background())
+	assert.True(t, errors.As(err, &DriftError{}))
+}
+
+func TestVolumeRefreshWithExistingVolumeReturnsNil(t *testing.T) {
+	c, _, p := setupVolumeTests("my-data")
+	c.VolumeName = "my-data"
+
+	err := p.Refresh(context.Background())
+	assert.NoError(t, err)
+}
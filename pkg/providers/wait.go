@@ -0,0 +1,119 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/shipyard-run/hclconfig/types"
+	"golang.org/x/xerrors"
+)
+
+// Wait is a provider which blocks until the health checks for the resource
+// pass, no objects are created or destroyed
+type Wait struct {
+	config     *resources.Wait
+	client     clients.ContainerTasks
+	httpClient clients.HTTP
+	kubeClient clients.Kubernetes
+	nomad      clients.Nomad
+	log        hclog.Logger
+}
+
+// NewWait creates a provider which waits for the health checks to pass
+func NewWait(c *resources.Wait, cl clients.ContainerTasks, hc clients.HTTP, kc clients.Kubernetes, nc clients.Nomad, l hclog.Logger) *Wait {
+	return &Wait{c, cl, hc, kc, nc, l}
+}
+
+// Create implements the provider interface method and blocks until the
+// health checks pass
+func (w *Wait) Create(ctx context.Context) error {
+	w.log.Info("Waiting for health checks", "ref", w.config.ID)
+
+	return w.HealthCheck(ctx)
+}
+
+// Destroy implements the provider interface method, there is nothing to
+// destroy
+func (w *Wait) Destroy(ctx context.Context) error {
+	w.log.Info("Destroy Wait", "ref", w.config.ID)
+
+	return nil
+}
+
+// Lookup implements the provider interface method
+func (w *Wait) Lookup() ([]string, error) {
+	return nil, nil
+}
+
+// Refresh implements the provider interface method
+func (w *Wait) Refresh(ctx context.Context) error {
+	w.log.Debug("Refresh Wait", "ref", w.config.ID)
+
+	return nil
+}
+
+// HealthCheck implements the HealthChecker interface
+func (w *Wait) HealthCheck(ctx context.Context) error {
+	hc := w.config.HealthCheck
+	if hc == nil {
+		return nil
+	}
+
+	var target types.Resource
+	if w.config.Target != "" {
+		var err error
+		target, err = w.config.ParentConfig.FindResource(w.config.Target)
+		if err != nil {
+			return xerrors.Errorf("unable to find target %s: %w", w.config.Target, err)
+		}
+	}
+
+	// http, tcp, exec and Docker checks are run by the container provider
+	// using the health check from the wait resource
+	var err error
+	switch t := target.(type) {
+	case *resources.Container:
+		co := *t
+		co.HealthCheck = hc
+		err = NewContainer(&co, w.client, w.httpClient, w.log).HealthCheck(ctx)
+	case *resources.Sidecar:
+		sc := *t
+		sc.HealthCheck = hc
+		err = NewContainerSidecar(&sc, w.client, w.httpClient, w.log).HealthCheck(ctx)
+	default:
+		co := &resources.Container{ResourceMetadata: w.config.ResourceMetadata, HealthCheck: hc}
+		err = NewContainer(co, w.client, w.httpClient, w.log).runHealthChecks(ctx, "")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	switch t := target.(type) {
+	case *resources.K8sCluster:
+		kc, err := w.kubeClient.SetConfig(t.KubeConfig)
+		if err != nil {
+			return xerrors.Errorf("unable to create Kubernetes client: %w", err)
+		}
+
+		return healthCheckKubernetes(ctx, kc, hc, "default")
+	case *resources.NomadCluster:
+		to, err := time.ParseDuration(hc.Timeout)
+		if err != nil {
+			return err
+		}
+
+		nc, err := w.nomad.SetConfig(fmt.Sprintf("http://%s", t.ExternalIP), t.APIPort, t.ClientNodes)
+		if err != nil {
+			return xerrors.Errorf("unable to create Nomad client: %w", err)
+		}
+
+		return healthCheckNomadJobs(ctx, nc, hc.NomadJobs, to, w.log.With("ref", w.config.ID))
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package providers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/clients/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/shipyard-run/hclconfig"
+	"github.com/shipyard-run/hclconfig/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupWait(t *testing.T, target string, hc *resources.HealthCheck) (*Wait, *clients.MockContainerTasks, *mocks.MockHTTP, *clients.MockKubernetes) {
+	c := hclconfig.NewConfig()
+
+	db := &resources.Container{ResourceMetadata: types.ResourceMetadata{ID: "resource.container.database", Name: "database", Type: resources.TypeContainer}}
+	db.FQRN = "database.container.jumppad.dev"
+	c.AppendResource(db)
+
+	k8s := &resources.K8sCluster{ResourceMetadata: types.ResourceMetadata{ID: "resource.k8s_cluster.dev", Name: "dev", Type: resources.TypeK8sCluster}}
+	k8s.KubeConfig = "/tmp/kubeconfig.yaml"
+	c.AppendResource(k8s)
+
+	w := &resources.Wait{ResourceMetadata: types.ResourceMetadata{ID: "resource.wait.test", Name: "test", Type: resources.TypeWait}}
+	w.Target = target
+	w.HealthCheck = hc
+	c.AppendResource(w)
+
+	ct := &clients.MockContainerTasks{}
+	hm := &mocks.MockHTTP{}
+	km := &clients.MockKubernetes{}
+
+	old := healthCheckInterval
+	healthCheckInterval = time.Millisecond
+	t.Cleanup(func() { healthCheckInterval = old })
+
+	return NewWait(w, ct, hm, km, nil, hclog.NewNullLogger()), ct, hm, km
+}
+
+func TestWaitRunsHTTPChecksWithoutTarget(t *testing.T) {
+	w, _, hm, _ := setupWait(t, "", &resources.HealthCheck{Timeout: "30s", HTTP: "http://localhost:8080"})
+	hm.On("HealthCheckHTTP", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	err := w.Create(context.Background())
+	require.NoError(t, err)
+
+	hm.AssertCalled(t, "HealthCheckHTTP", "http://localhost:8080", []int{200}, 30*time.Second)
+}
+
+func TestWaitRunsExecChecksInTargetContainer(t *testing.T) {
+	w, ct, _, _ := setupWait(t, "resource.container.database", &resources.HealthCheck{Timeout: "30s", Exec: []string{"pg_isready"}})
+	ct.On("FindContainerIDs", "database.container.jumppad.dev").Return([]string{"abc"}, nil)
+	ct.On("ExecuteCommand", "abc", []string{"pg_isready"}, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	err := w.Create(context.Background())
+	require.NoError(t, err)
+
+	ct.AssertNumberOfCalls(t, "ExecuteCommand", 1)
+}
+
+func TestWaitReturnsErrorWhenTargetContainerDoesNotExist(t *testing.T) {
+	w, ct, _, _ := setupWait(t, "resource.container.database", &resources.HealthCheck{Timeout: "30s", Exec: []string{"pg_isready"}})
+	ct.On("FindContainerIDs", "database.container.jumppad.dev").Return([]string{}, nil)
+
+	err := w.Create(context.Background())
+	require.Error(t, err)
+}
+
+func TestWaitRunsKubernetesChecksInTargetCluster(t *testing.T) {
+	w, _, _, km := setupWait(t, "resource.k8s_cluster.dev", &resources.HealthCheck{Timeout: "30s", Deployments: []string{"web"}})
+	km.On("SetConfig", "/tmp/kubeconfig.yaml").Return(nil)
+	km.On("HealthCheckDeployments", mock.Anything, mock.Anything).Return(nil)
+
+	err := w.Create(context.Background())
+	require.NoError(t, err)
+
+	km.AssertCalled(t, "HealthCheckDeployments", []string{"default/web"}, 30*time.Second)
+}
@@ -0,0 +1,217 @@
+package shipyard
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/shipyard/constants"
+	"github.com/shipyard-run/hclconfig/types"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// resourceChecksum returns a hash of the user configurable attributes for
+// a resource. The embedded resource metadata, any computed attributes,
+// tagged with `state:"true"`, and the attributes listed in the lifecycle
+// ignore_changes of the resource are not included in the hash.
+func resourceChecksum(r types.Resource) (string, error) {
+	err := computeBuildChecksum(r)
+	if err != nil {
+		return "", fmt.Errorf("unable to generate checksum for resource %s: %s", r.Metadata().ID, err)
+	}
+
+	var ignore []string
+	if l := resources.LifecycleForResource(r); l != nil {
+		ignore = l.IgnoreChanges
+	}
+
+	return resourceChecksumIgnoring(r, ignore)
+}
+
+// resourceChecksumIgnoring returns a hash of the user configurable
+// attributes for a resource excluding the given attributes
+func resourceChecksumIgnoring(r types.Resource, ignore []string) (string, error) {
+	v := checksumValue(reflect.ValueOf(r))
+
+	// the build checksum is computed so it is skipped by checksumValue, add
+	// it so that changing the build context re-creates the container
+	if c, ok := r.(*resources.Container); ok && c.Build != nil {
+		if b, ok := v.(map[string]interface{})["build"].(map[string]interface{}); ok {
+			b["checksum"] = c.Build.Checksum
+		}
+	}
+
+	for _, i := range ignore {
+		removeChecksumPath(v, strings.Split(i, "."))
+	}
+
+	d, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("unable to generate checksum for resource %s: %s", r.Metadata().ID, err)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(d)), nil
+}
+
+// checksumValue converts the given value into a structure that can be
+// serialized to JSON, structs are converted into a map keyed by the hcl
+// attribute name
+func checksumValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	// attributes with the type interface{} are decoded as a raw hcl
+	// attribute, convert these into their literal value
+	if v.CanInterface() {
+		if a, ok := v.Interface().(*hcl.Attribute); ok && a != nil {
+			val, diags := a.Expr.Value(&hcl.EvalContext{})
+			if diags.HasErrors() {
+				return nil
+			}
+
+			d, err := ctyjson.Marshal(val, val.Type())
+			if err != nil {
+				return nil
+			}
+
+			return string(d)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+
+		return checksumValue(v.Elem())
+
+	case reflect.Struct:
+		m := map[string]interface{}{}
+		t := v.Type()
+
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := strings.Split(f.Tag.Get("hcl"), ",")[0]
+
+			// skip the embedded metadata, internal and computed fields, the
+			// lifecycle, retry and timeout do not change the resource
+			if f.Anonymous || f.PkgPath != "" || name == "" || skipChecksumField(name) || f.Tag.Get("state") == "true" {
+				continue
+			}
+
+			m[name] = checksumValue(v.Field(i))
+		}
+
+		return m
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+
+		l := []interface{}{}
+		for i := 0; i < v.Len(); i++ {
+			l = append(l, checksumValue(v.Index(i)))
+		}
+
+		return l
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+
+		m := map[string]interface{}{}
+		iter := v.MapRange()
+		for iter.Next() {
+			m[fmt.Sprint(iter.Key().Interface())] = checksumValue(iter.Value())
+		}
+
+		return m
+	}
+
+	if v.CanInterface() {
+		return v.Interface()
+	}
+
+	return nil
+}
+
+// computeBuildChecksum sets the checksum of the build context for
+// containers which build their image, the checksum is only calculated once
+// as reading the build context is expensive
+func computeBuildChecksum(r types.Resource) error {
+	c, ok := r.(*resources.Container)
+	if !ok || c.Build == nil || c.Build.Checksum != "" {
+		return nil
+	}
+
+	cs, err := c.Build.ComputeChecksum()
+	if err != nil {
+		return err
+	}
+
+	c.Build.Checksum = cs
+
+	return nil
+}
+
+// checksumChanged returns true when the checksum of the resource does not
+// match the checksum of the resource in the state. The state checksum may
+// have been generated before attributes were added to ignore_changes, in
+// this case the resource is compared to the attributes stored in the state.
+func checksumChanged(r, sr types.Resource, checksum string) bool {
+	stateChecksum, _ := sr.Metadata().Properties[constants.PropertyChecksum].(string)
+
+	if stateChecksum == "" || checksum == "" || stateChecksum == checksum {
+		return false
+	}
+
+	l := resources.LifecycleForResource(r)
+	if l == nil || len(l.IgnoreChanges) == 0 {
+		return true
+	}
+
+	sc, err := resourceChecksumIgnoring(sr, l.IgnoreChanges)
+
+	return err != nil || sc != checksum
+}
+
+// removeChecksumPath removes the attribute at the given path from the value
+// returned by checksumValue, paths into a list of blocks are removed from
+// every block
+func removeChecksumPath(v interface{}, path []string) {
+	if len(path) == 0 {
+		return
+	}
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			delete(t, path[0])
+			return
+		}
+
+		removeChecksumPath(t[path[0]], path[1:])
+
+	case []interface{}:
+		for _, i := range t {
+			removeChecksumPath(i, path)
+		}
+	}
+}
+
+func skipChecksumField(name string) bool {
+	switch name {
+	case "depends_on", "lifecycle", "retry", "timeout":
+		return true
+	}
+
+	return false
+}
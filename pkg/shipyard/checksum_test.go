@@ -0,0 +1,58 @@
+package shipyard
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func setupBuildChecksum(t *testing.T) *resources.Container {
+	dir := t.TempDir()
+	ioutil.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM alpine"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644)
+
+	return &resources.Container{Build: &resources.Build{Context: dir}}
+}
+
+func TestResourceChecksumComputesBuildChecksumOnce(t *testing.T) {
+	c := setupBuildChecksum(t)
+
+	cs, err := resourceChecksum(c)
+	require.NoError(t, err)
+	require.Len(t, c.Build.Checksum, 64)
+
+	// the build context is not read again once the checksum is set
+	bc := c.Build.Checksum
+	ioutil.WriteFile(filepath.Join(c.Build.Context, "main.go"), []byte("package main\n\nfunc main() {}"), 0644)
+
+	cs2, err := resourceChecksum(c)
+	require.NoError(t, err)
+	require.Equal(t, cs, cs2)
+	require.Equal(t, bc, c.Build.Checksum)
+}
+
+func TestResourceChecksumChangesWhenBuildContextChanges(t *testing.T) {
+	c := setupBuildChecksum(t)
+
+	cs, err := resourceChecksum(c)
+	require.NoError(t, err)
+
+	ioutil.WriteFile(filepath.Join(c.Build.Context, "main.go"), []byte("package main\n\nfunc main() {}"), 0644)
+
+	// a new run parses the config again so the checksum is not set
+	c2 := &resources.Container{Build: &resources.Build{Context: c.Build.Context}}
+
+	cs2, err := resourceChecksum(c2)
+	require.NoError(t, err)
+	require.NotEqual(t, cs, cs2)
+}
+
+func TestResourceChecksumWithMissingBuildContextReturnsError(t *testing.T) {
+	c := &resources.Container{Build: &resources.Build{Context: filepath.Join(t.TempDir(), "missing")}}
+
+	_, err := resourceChecksum(c)
+	require.Error(t, err)
+}
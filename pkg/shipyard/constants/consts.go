@@ -3,6 +3,14 @@ package constants
 // PropertyStatus is the key for the Metadata property that contains the status
 const PropertyStatus = "status"
 
+// PropertyChecksum is the key for the Metadata property that contains the
+// checksum of the user defined attributes for a resource
+const PropertyChecksum = "checksum"
+
+// PropertyAttempts is the key for the Metadata property that contains the
+// number of attempts the last create of the resource took
+const PropertyAttempts = "attempts"
+
 const (
 	// StatusCreated is set once the resource has been successfully created
 	StatusCreated = "created"
@@ -17,4 +25,13 @@ const (
 	// StatusDisabled indicates that the resources has been disabled and no
 	// resources have been created
 	StatusDisabled = "disabled"
+
+	// StatusCreating is set while the resource is being created, if this
+	// status is found in the state the apply was interrupted and the resource
+	// may have been partially created
+	StatusCreating = "creating"
+
+	// StatusDestroying is set while the resource is being destroyed, if this
+	// status is found in the state the destroy was interrupted
+	StatusDestroying = "destroying"
 )
@@ -4,10 +4,15 @@ import (
 
 	// "fmt"
 
+	"context"
+	"errors"
 	"fmt"
 	"log"
-	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	hclog "github.com/hashicorp/go-hclog"
@@ -28,18 +33,70 @@ import (
 //go:generate mockery --name Engine --filename engine.go
 type Engine interface {
 	GetClients() *clients.Clients
-	Apply(string) ([]types.Resource, error)
+	Apply(context.Context, string) ([]types.Resource, error)
 
 	// ApplyWithVariables applies a configuration file or directory containing
 	// configuration. Optionally the user can provide a map of variables which the configuration
 	// uses and / or a file containing variables.
-	ApplyWithVariables(path string, variables map[string]string, variablesFile string) ([]types.Resource, error)
+	// When the context is cancelled no further resources are created, resources
+	// which are being created are either completed or rolled back.
+	ApplyWithVariables(ctx context.Context, path string, variables map[string]string, variablesFile string) ([]types.Resource, error)
 	ParseConfig(string) ([]types.Resource, error)
 	ParseConfigWithVariables(string, map[string]string, string) ([]types.Resource, error)
-	Destroy() error
+	Destroy(context.Context) error
+
+	// Rollback re-applies the configuration recorded in the given version of
+	// the state history
+	Rollback(ctx context.Context, version int) ([]types.Resource, error)
+
+	// Subscribe returns a channel which receives the events published while
+	// resources are created or destroyed and a function which ends the
+	// subscription. The engine waits for subscribers to receive each event,
+	// subscribers must read from the channel until they unsubscribe.
+	Subscribe() (<-chan Event, func())
+
+	// Import adds an existing Docker object to the state as the resource
+	// with the given id
+	Import(id, ref string) (types.Resource, error)
+
+	// Refresh checks the resources in the state for drift, drifted resources
+	// are marked as tainted
+	Refresh() ([]Drift, error)
 	ResourceCount() int
 	ResourceCountForType(string) int
 	Blueprint() *resources.Blueprint
+
+	// SetOptions sets the runtime options for the engine
+	SetOptions(Options)
+
+	// Close stops any plugins which have been started by the engine
+	Close()
+}
+
+// Options defines the runtime options for the engine
+type Options struct {
+	// Parallelism is the maximum number of resources which are created or
+	// destroyed concurrently, a value of 0 does not limit concurrency
+	Parallelism int
+
+	// Targets restricts apply and destroy to the resources with the given
+	// ids, when empty all resources are applied or destroyed
+	Targets []string
+
+	// Force destroys and re-creates resources which have the lifecycle
+	// prevent_destroy set
+	Force bool
+
+	// ContinueOnError attempts to destroy every resource, by default no
+	// further resources are destroyed once a resource fails to destroy
+	ContinueOnError bool
+}
+
+// DefaultOptions returns the default runtime options for the engine
+func DefaultOptions() Options {
+	return Options{
+		Parallelism: 10,
+	}
 }
 
 // EngineImpl is responsible for creating and destroying resources
@@ -48,6 +105,25 @@ type EngineImpl struct {
 	config      *hclconfig.Config
 	log         hclog.Logger
 	getProvider getProviderFunc
+	options     Options
+
+	// stateLock ensures that the state is not modified or written by
+	// resources which are being created concurrently
+	stateLock sync.Mutex
+
+	// recreated contains the ids of resources that have been destroyed and
+	// created during the current apply, dependents of these resources
+	// must also be re-created
+	recreated     map[string]bool
+	recreatedLock sync.Mutex
+
+	// healthy contains the ids of resources whose health checks have passed
+	// during the current apply
+	healthy     map[string]bool
+	healthyLock sync.Mutex
+
+	// events delivers the progress of an Apply or Destroy to subscribers
+	events eventBus
 }
 
 // defines a function which is used for generating providers
@@ -75,7 +151,7 @@ func GenerateClients(l hclog.Logger) (*clients.Clients, error) {
 
 	bc := &clients.SystemImpl{}
 
-	il := clients.NewImageFileLog(utils.ImageCacheLog())
+	il := clients.NewImageFileLog("")
 
 	tgz := &clients.TarGz{}
 
@@ -107,6 +183,7 @@ func New(l hclog.Logger) (Engine, error) {
 	e := &EngineImpl{}
 	e.log = l
 	e.getProvider = generateProviderImpl
+	e.options = DefaultOptions()
 
 	// Set the standard writer to our logger as the DAG uses the standard library log.
 	log.SetOutput(l.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Trace}))
@@ -119,6 +196,15 @@ func New(l hclog.Logger) (Engine, error) {
 
 	e.clients = cl
 
+	// plugins are started when they are first needed, not every command
+	// parses the configuration
+	resources.SetPluginLoader(func() {
+		err := e.loadPlugins(utils.PluginsDir())
+		if err != nil {
+			e.log.Error("Unable to load plugins", "error", err)
+		}
+	})
+
 	return e, nil
 }
 
@@ -127,10 +213,42 @@ func (e *EngineImpl) GetClients() *clients.Clients {
 	return e.clients
 }
 
+// Blueprint returns the blueprint defined in the root of the configuration
+// which has been parsed or applied, nil is returned when the configuration
+// does not contain a blueprint
 func (e *EngineImpl) Blueprint() *resources.Blueprint {
+	if e.config == nil {
+		return nil
+	}
+
+	bps, err := e.config.FindResourcesByType(resources.TypeBlueprint)
+	if err != nil {
+		return nil
+	}
+
+	for _, bp := range bps {
+		// blueprints in modules do not describe the stack
+		if bp.Metadata().Module != "" || bp.Metadata().Disabled {
+			continue
+		}
+
+		return bp.(*resources.Blueprint)
+	}
+
 	return nil
 }
 
+// Subscribe returns a channel which receives the events published while
+// resources are created or destroyed
+func (e *EngineImpl) Subscribe() (<-chan Event, func()) {
+	return e.events.subscribe()
+}
+
+// SetOptions sets the runtime options for the engine
+func (e *EngineImpl) SetOptions(o Options) {
+	e.options = o
+}
+
 // ParseConfig parses the given Shipyard files and creating the resource types but does
 // not apply or destroy the resources.
 // This function can be used to check the validity of a configuration without making changes
@@ -158,13 +276,9 @@ func (e *EngineImpl) ParseConfigWithVariables(path string, vars map[string]strin
 		}
 	}
 
-	// load the state
-	c, err := resources.LoadState()
-	if err != nil {
-		e.log.Debug("unable to load state", "error", err)
-	}
-
-	e.config = c
+	// parsing does not merge the state, the returned resources only contain
+	// the resources defined in the configuration
+	e.config = hclconfig.NewConfig()
 
 	err = e.readAndProcessConfig(path, vars, variablesFile, func(r types.Resource) error {
 		e.config.AppendResource(r)
@@ -175,12 +289,12 @@ func (e *EngineImpl) ParseConfigWithVariables(path string, vars map[string]strin
 }
 
 // Apply the configuration and create or destroy the resources
-func (e *EngineImpl) Apply(path string) ([]types.Resource, error) {
-	return e.ApplyWithVariables(path, nil, "")
+func (e *EngineImpl) Apply(ctx context.Context, path string) ([]types.Resource, error) {
+	return e.ApplyWithVariables(ctx, path, nil, "")
 }
 
 // ApplyWithVariables applies the current config creating the resources
-func (e *EngineImpl) ApplyWithVariables(path string, vars map[string]string, variablesFile string) ([]types.Resource, error) {
+func (e *EngineImpl) ApplyWithVariables(ctx context.Context, path string, vars map[string]string, variablesFile string) ([]types.Resource, error) {
 	// abs paths
 	var err error
 	path, err = filepath.Abs(path)
@@ -197,12 +311,29 @@ func (e *EngineImpl) ApplyWithVariables(path string, vars map[string]string, var
 		}
 	}
 
+	// lock the state so that other processes can not modify it while
+	// resources are created
+	unlock, err := resources.LockState()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// keep a copy of the state before it is changed so that it can be
+	// restored with rollback
+	err = resources.ArchiveState()
+	if err != nil {
+		return nil, err
+	}
+
 	// load the state
 	c, err := resources.LoadState()
 	if err != nil {
 		e.log.Debug("unable to load state", "error", err)
 	}
 	e.config = c
+	e.recreated = map[string]bool{}
+	e.healthy = map[string]bool{}
 
 	// check to see we already have an image cache
 	_, err = e.config.FindResourcesByType(resources.TypeImageCache)
@@ -225,7 +356,7 @@ func (e *EngineImpl) ApplyWithVariables(path string, vars map[string]string, var
 		}
 
 		// create the cache
-		err := p.Create()
+		err := p.Create(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("unable to create image cache: %s", err)
 		}
@@ -239,8 +370,40 @@ func (e *EngineImpl) ApplyWithVariables(path string, vars map[string]string, var
 		resources.SaveState(e.config)
 	}
 
-	// finally we can process and create resources
-	processErr := e.readAndProcessConfig(path, vars, variablesFile, e.createCallback)
+	// parse the configuration to build the graph of resources, the resources
+	// are created by walking the graph once the config has been parsed
+	parsedConfig, processErr := e.parseConfig(path, vars, variablesFile, nil)
+
+	// when targets are set only the targets and their dependencies are
+	// created, all other resources in the state are left untouched
+	var targets map[string]bool
+	if processErr == nil && parsedConfig != nil && len(e.options.Targets) > 0 {
+		targets, processErr = targetResources(parsedConfig.Resources, e.options.Targets, false)
+	}
+
+	if processErr == nil {
+		processErr = e.createResources(ctx, parsedConfig, targets, func() (*hclconfig.Config, error) {
+			return e.parseConfig(path, vars, variablesFile, nil)
+		})
+	}
+
+	// process is not called for disabled resources, add manually
+	err = e.appendDisabledResources(parsedConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// process is not called for module resources, add manually
+	err = e.appendModuleResources(parsedConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// destroy any resources that might have been set to disabled
+	err = e.destroyDisabledResources(ctx, targets)
+	if err != nil {
+		processErr = err
+	}
 
 	// save the state regardless of error
 	stateErr := resources.SaveState(e.config)
@@ -264,9 +427,24 @@ func appendIfNotContains(existing []string, s string) []string {
 }
 
 // Destroy the resources defined by the state
-func (e *EngineImpl) Destroy() error {
+func (e *EngineImpl) Destroy(ctx context.Context) error {
 	e.log.Info("Destroying resources")
 
+	// lock the state so that other processes can not modify it while
+	// resources are destroyed
+	unlock, err := resources.LockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// keep a copy of the state before it is changed so that it can be
+	// restored with rollback
+	err = resources.ArchiveState()
+	if err != nil {
+		return err
+	}
+
 	// load the state
 	c, err := resources.LoadState()
 	if err != nil {
@@ -275,20 +453,236 @@ func (e *EngineImpl) Destroy() error {
 
 	e.config = c
 
+	// when targets are set only the targets and their dependents are
+	// destroyed, all other resources in the state are left untouched
+	var targets map[string]bool
+	if len(e.options.Targets) > 0 {
+		targets, err = targetResources(e.config.Resources, e.options.Targets, true)
+		if err != nil {
+			return err
+		}
+	}
+
 	// run through the graph and call the destroy callback
 	// disabled resources are not included in this callback
 	// image cache which is manually added by Apply process
 	// should have the correct dependency graph to be
 	// destroyed last
-	err = e.config.Process(e.destroyCallback, true)
+	err = e.destroyResources(ctx, targets)
 	if err != nil {
+		return err
+	}
 
-		// return the process error
-		return fmt.Errorf("error trying to call Destroy on provider: %s", err)
+	// resources which were not targeted remain in the state
+	if targets != nil {
+		return resources.SaveState(e.config)
 	}
 
 	// remove the state
-	return os.Remove(utils.StatePath())
+	return resources.RemoveState()
+}
+
+// Rollback re-applies the configuration recorded in the given version of the
+// state history. Resources which do not exist in the previous version are
+// destroyed, all other resources are created or updated to match the
+// previous version.
+func (e *EngineImpl) Rollback(ctx context.Context, version int) ([]types.Resource, error) {
+	e.log.Info("Rolling back resources", "version", version)
+
+	// lock the state so that other processes can not modify it while
+	// resources are rolled back
+	unlock, err := resources.LockState()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	previous, err := resources.LoadStateVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	// keep a copy of the state before it is changed, the version is loaded
+	// first as archiving may remove the oldest version
+	err = resources.ArchiveState()
+	if err != nil {
+		return nil, err
+	}
+
+	// load the state
+	c, err := resources.LoadState()
+	if err != nil {
+		e.log.Debug("unable to load state", "error", err)
+	}
+	e.config = c
+	e.recreated = map[string]bool{}
+	e.healthy = map[string]bool{}
+
+	// destroy the resources which do not exist in the previous version
+	removed := map[string]bool{}
+	for _, r := range e.config.Resources {
+		if !planResource(r) {
+			continue
+		}
+
+		if _, err := previous.FindResource(r.Metadata().ID); err != nil {
+			removed[r.Metadata().ID] = true
+		}
+	}
+
+	processErr := e.destroyResources(ctx, removed)
+
+	if processErr == nil {
+		// the previous resources contain the status at the time they were
+		// saved, the current status is taken from the state
+		for _, r := range previous.Resources {
+			delete(r.Metadata().Properties, constants.PropertyStatus)
+		}
+
+		// the previous resources have already been processed and do not
+		// need to be parsed again
+		processErr = e.createResources(ctx, previous, nil, func() (*hclconfig.Config, error) {
+			return previous, nil
+		})
+	}
+
+	// process is not called for disabled resources, add manually
+	err = e.appendDisabledResources(previous)
+	if err != nil {
+		return nil, err
+	}
+
+	// process is not called for module resources, add manually
+	err = e.appendModuleResources(previous)
+	if err != nil {
+		return nil, err
+	}
+
+	// destroy any resources that might have been set to disabled
+	err = e.destroyDisabledResources(ctx, nil)
+	if err != nil {
+		processErr = err
+	}
+
+	// save the state regardless of error
+	stateErr := resources.SaveState(e.config)
+	if stateErr != nil {
+		e.log.Info("Unable to save state", "error", stateErr)
+	}
+
+	return e.config.Resources, processErr
+}
+
+// destroyResources walks the dependency graph for the state in reverse and
+// calls the destroy callback for each resource. When a resource fails to
+// destroy, no further resources are destroyed unless the ContinueOnError
+// option is set. Resources which have prevent_destroy set are kept along with
+// the resources they depend on. When targets is not nil only the resources
+// contained in targets are destroyed. Once the context is cancelled no
+// further resources are destroyed. A DestroyError listing the resources
+// which have not been destroyed is returned when any resource fails.
+func (e *EngineImpl) destroyResources(ctx context.Context, targets map[string]bool) error {
+	sem := newSemaphore(e.options.Parallelism)
+
+	// the resources which have not been destroyed and the reason why
+	failed := map[string]bool{}
+	reasons := map[string]string{}
+	errs := []string{}
+	errLock := sync.Mutex{}
+
+	// stopped is set when a provider fails to destroy a resource, resources
+	// kept by prevent_destroy do not stop the destroy
+	stopped := false
+
+	// resources are removed from the state as they are destroyed, keep a
+	// copy so that dependencies can be checked
+	res := append([]types.Resource{}, e.config.Resources...)
+
+	// the callback never returns an error so that every resource is visited
+	// and the resources which are not destroyed can be reported, once a
+	// resource fails no further resources are destroyed unless continuing
+	// on error
+	e.config.Process(func(r types.Resource) error {
+		if targets != nil && !targets[r.Metadata().ID] {
+			return nil
+		}
+
+		sem.acquire()
+		defer sem.release()
+
+		errLock.Lock()
+		skip := ""
+		if !e.options.ContinueOnError {
+			if isDependency(r, res, failed) {
+				skip = "dependent failed to destroy"
+			} else if stopped {
+				skip = "destroy stopped after an earlier error"
+			}
+		}
+
+		if skip != "" {
+			failed[r.Metadata().ID] = true
+			reasons[r.Metadata().ID] = skip
+		}
+		errLock.Unlock()
+
+		if skip != "" {
+			e.log.Info("Skipping resource", "ref", r.Metadata().ID, "reason", skip)
+			e.publishEvent(EventSkipped, OperationDestroy, r, time.Time{}, skip, nil)
+
+			return nil
+		}
+
+		// resources which are not destroyed are treated as failed so that
+		// their dependencies are not destroyed
+		if ctx.Err() != nil {
+			e.log.Info("Skipping resource, operation cancelled", "ref", r.Metadata().ID)
+			e.publishEvent(EventSkipped, OperationDestroy, r, time.Time{}, "operation cancelled", nil)
+
+			errLock.Lock()
+			failed[r.Metadata().ID] = true
+			reasons[r.Metadata().ID] = "operation cancelled"
+			errLock.Unlock()
+
+			return nil
+		}
+
+		started := time.Now()
+		e.publishEvent(EventStarted, OperationDestroy, r, time.Time{}, "", nil)
+
+		err := e.destroyCallback(ctx, r)
+		if err != nil {
+			errLock.Lock()
+			failed[r.Metadata().ID] = true
+			reasons[r.Metadata().ID] = err.Error()
+			errs = append(errs, err.Error())
+			stopped = stopped || !errors.As(err, &preventDestroyError{})
+			errLock.Unlock()
+
+			e.publishEvent(EventFailed, OperationDestroy, r, started, "", err)
+
+			return nil
+		}
+
+		e.publishEvent(EventSucceeded, OperationDestroy, r, started, "", nil)
+
+		return nil
+	}, true)
+
+	// wrap the cancellation so that callers can detect the interrupt
+	if ctx.Err() != nil {
+		if len(errs) > 0 {
+			return fmt.Errorf("destroy cancelled: %w\n%s", ctx.Err(), strings.Join(errs, "\n"))
+		}
+
+		return fmt.Errorf("destroy cancelled: %w", ctx.Err())
+	}
+
+	if len(errs) > 0 {
+		return e.newDestroyError(res, reasons, errs)
+	}
+
+	return nil
 }
 
 // ResourceCount defines the number of resources in a plan
@@ -307,12 +701,28 @@ func (e *EngineImpl) ResourceCountForType(t string) int {
 }
 
 func (e *EngineImpl) readAndProcessConfig(path string, variables map[string]string, variablesFile string, callback hclconfig.ProcessCallback) error {
+	parsedConfig, parseError := e.parseConfig(path, variables, variablesFile, callback)
+
+	// process is not called for disabled resources, add manually
+	err := e.appendDisabledResources(parsedConfig)
+	if err != nil {
+		return parseError
+	}
+
+	// process is not called for module resources, add manually
+	err = e.appendModuleResources(parsedConfig)
+	if err != nil {
+		return parseError
+	}
 
-	var parseError error
-	var parsedConfig *hclconfig.Config
+	return parseError
+}
 
+// parseConfig parses the configuration at the given path, the callback is
+// called for every resource in the order defined by the dependency graph
+func (e *EngineImpl) parseConfig(path string, variables map[string]string, variablesFile string, callback hclconfig.ProcessCallback) (*hclconfig.Config, error) {
 	if path == "" {
-		return nil
+		return nil, nil
 	}
 
 	variablesFiles := []string{}
@@ -325,55 +735,158 @@ func (e *EngineImpl) readAndProcessConfig(path string, variables map[string]stri
 	if utils.IsHCLFile(path) {
 		// ParseFile processes the HCL, builds a graph of resources then calls
 		// the callback for each resource in order
-		//
-		// We are not using the returned config as the resources are added to the
-		// state on the callback
-		//
-		// If the callback returns an error we need to save the state and exit
-		parsedConfig, parseError = hclParser.ParseFile(path)
-	} else {
-		// ParseFolder processes the HCL, builds a graph of resources then calls
-		// the callback for each resource in order
-		//
-		// We are not using the returned config as the resources are added to the
-		// state on the callback
-		//
-		// If the callback returns an error we need to save the state and exit
-		parsedConfig, parseError = hclParser.ParseDirectory(path)
+		return hclParser.ParseFile(path)
 	}
 
-	// process is not called for disabled resources, add manually
-	err := e.appendDisabledResources(parsedConfig)
-	if err != nil {
-		return parseError
+	// ParseFolder processes the HCL, builds a graph of resources then calls
+	// the callback for each resource in order
+	return hclParser.ParseDirectory(path)
+}
+
+// createResources walks the dependency graph for the given config and calls
+// the create callback for each resource. Resources which do not depend on
+// each other are created concurrently up to the limit set by the parallelism
+// option. When a resource fails to create, only the resources which depend on
+// it are skipped. When targets is not nil only the resources contained in
+// targets are created.
+//
+// The parse function is used to parse the configuration again before a
+// resource which references other resources is created, this ensures that any
+// attributes computed when creating the dependencies are set. The parsed
+// configuration is shared by resources until another resource is created.
+func (e *EngineImpl) createResources(ctx context.Context, c *hclconfig.Config, targets map[string]bool, parse func() (*hclconfig.Config, error)) error {
+	if c == nil {
+		return nil
 	}
 
-	// process is not called for module resources, add manually
-	err = e.appendModuleResources(parsedConfig)
-	if err != nil {
-		return parseError
+	sem := newSemaphore(e.options.Parallelism)
+	linked := newLinkedConfig(parse)
+
+	failed := map[string]bool{}
+	errs := []string{}
+	errLock := sync.Mutex{}
+
+	fail := func(r types.Resource, err error) {
+		errLock.Lock()
+		defer errLock.Unlock()
+
+		failed[r.Metadata().ID] = true
+
+		// resources which were interrupted are reported by the cancellation
+		if err != nil && !errors.Is(err, context.Canceled) {
+			pe := hclconfig.ParserError{}
+			pe.Filename = r.Metadata().File
+			pe.Line = r.Metadata().Line
+			pe.Column = r.Metadata().Column
+			pe.Message = fmt.Sprintf(`unable to create resource "%s" %s`, r.Metadata().ID, err)
+
+			errs = append(errs, pe.Error())
+		}
 	}
 
-	// destroy an resouces that might have been set to disabled
-	err = e.destroyDisabledResources()
-	if err != nil {
-		return err
+	// the callback never returns an error as this would halt the processing
+	// of resources in unrelated branches of the graph
+	c.Process(func(r types.Resource) error {
+		if targets != nil && !targets[r.Metadata().ID] {
+			e.log.Debug("Skipping resource, not targeted", "ref", r.Metadata().ID)
+			return nil
+		}
+
+		errLock.Lock()
+		dep := findDependency(r, failed)
+		errLock.Unlock()
+
+		if dep != "" {
+			e.log.Info("Skipping resource, dependency failed", "ref", r.Metadata().ID, "dependency", dep)
+			e.publishEvent(EventSkipped, OperationCreate, r, time.Time{}, fmt.Sprintf("dependency %s failed", dep), nil)
+			fail(r, nil)
+
+			return nil
+		}
+
+		sem.acquire()
+		defer sem.release()
+
+		// no new resources are created once the operation is cancelled
+		if ctx.Err() != nil {
+			e.log.Info("Skipping resource, operation cancelled", "ref", r.Metadata().ID)
+			e.publishEvent(EventSkipped, OperationCreate, r, time.Time{}, "operation cancelled", nil)
+			fail(r, nil)
+
+			return nil
+		}
+
+		started := time.Now()
+		e.publishEvent(EventStarted, OperationCreate, r, time.Time{}, "", nil)
+
+		if len(r.Metadata().ResourceLinks) > 0 {
+			pc, err := linked.get()
+			if err != nil {
+				e.publishEvent(EventFailed, OperationCreate, r, started, "", err)
+				fail(r, err)
+				return nil
+			}
+
+			pr, err := pc.FindResource(r.Metadata().ID)
+			if err != nil {
+				e.publishEvent(EventFailed, OperationCreate, r, started, "", err)
+				fail(r, err)
+				return nil
+			}
+
+			r = pr
+		}
+
+		err := e.createCallback(ctx, r)
+		if err != nil {
+			e.publishEvent(EventFailed, OperationCreate, r, started, "", err)
+			fail(r, err)
+
+			return nil
+		}
+
+		linked.created()
+		e.publishEvent(EventSucceeded, OperationCreate, r, started, "", nil)
+
+		return nil
+	}, false)
+
+	// wrap the cancellation so that callers can detect the interrupt
+	if ctx.Err() != nil {
+		if len(errs) > 0 {
+			return fmt.Errorf("apply cancelled: %w\n%s", ctx.Err(), strings.Join(errs, "\n"))
+		}
+
+		return fmt.Errorf("apply cancelled: %w", ctx.Err())
 	}
 
-	return parseError
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+
+	return nil
 }
 
 // destroyDisabledResources destroys any resrouces that were created but
 // have subsequently been set to disabled
-func (e *EngineImpl) destroyDisabledResources() error {
+func (e *EngineImpl) destroyDisabledResources(ctx context.Context, targets map[string]bool) error {
 	// we need to check if we have any disabbled resroucea that are marked
 	// as created, this could be because the disabled state has changed
 	// these respurces should be destroyed
 
 	for _, r := range e.config.Resources {
+		if targets != nil && !targets[r.Metadata().ID] {
+			continue
+		}
+
 		if r.Metadata().Disabled &&
 			r.Metadata().Properties[constants.PropertyStatus] == constants.StatusCreated {
 
+			err := e.checkPreventDestroy(r)
+			if err != nil {
+				return err
+			}
+
 			p := e.getProvider(r, e.clients)
 			if p == nil {
 				r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
@@ -381,7 +894,7 @@ func (e *EngineImpl) destroyDisabledResources() error {
 			}
 
 			// call destroy
-			err := p.Destroy()
+			err = p.Destroy(ctx)
 			if err != nil {
 				r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
 				return fmt.Errorf("unable to destroy resource Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
@@ -445,31 +958,83 @@ func (e *EngineImpl) appendModuleResources(c *hclconfig.Config) error {
 	return nil
 }
 
-func (e *EngineImpl) createCallback(r types.Resource) error {
+func (e *EngineImpl) createCallback(ctx context.Context, r types.Resource) error {
 	p := e.getProvider(r, e.clients)
 	if p == nil {
 		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
 		return fmt.Errorf("unable to create provider for resource Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
 	}
 
+	// generate a checksum for the resource so that changes to the
+	// configuration can be detected
+	checksum, err := resourceChecksum(r)
+	if err != nil {
+		e.log.Debug("Unable to generate checksum", "ref", r.Metadata().ID, "error", err)
+	}
+
 	// we need to check if a resource exists in the state, if so the status
 	// should take precedence as all new resources will have an empty state
+	e.stateLock.Lock()
 	sr, err := e.config.FindResource(r.Metadata().ID)
 	if err == nil {
 		// set the current status to the state status
 		r.Metadata().Properties[constants.PropertyStatus] = sr.Metadata().Properties[constants.PropertyStatus]
 
+		// if the configuration has changed since the resource was created or
+		// a dependency has been re-created, the resource needs to be re-created
+		if r.Metadata().Properties[constants.PropertyStatus] == constants.StatusCreated {
+			if checksumChanged(r, sr, checksum) {
+				e.log.Info("Resource configuration changed, updating", "ref", r.Metadata().ID)
+				r.Metadata().Properties[constants.PropertyStatus] = constants.StatusTainted
+			} else if dep := e.recreatedDependency(r); dep != "" {
+				e.log.Info("Dependency re-created, updating", "ref", r.Metadata().ID, "dependency", dep)
+				r.Metadata().Properties[constants.PropertyStatus] = constants.StatusTainted
+			}
+		}
+
 		// remove the resource, we will add the new version to the state
-		err = e.config.RemoveResource(r)
+		err = e.config.RemoveResource(sr)
 		if err != nil {
+			e.stateLock.Unlock()
 			return fmt.Errorf(`unable to remove resource "%s" from state, %s`, r.Metadata().ID, err)
 		}
 	}
 
+	status, _ := r.Metadata().Properties[constants.PropertyStatus].(string)
+
+	// record the resource in the state before calling the provider, should
+	// the apply be interrupted the state contains any resources that may
+	// have been partially created
+	pending := pendingResource(r, constants.StatusCreating)
+	if status == constants.StatusCreated {
+		pending = pendingResource(r, constants.StatusCreated)
+	}
+
+	err = e.appendAndSaveState(pending)
+	e.stateLock.Unlock()
+
+	if err != nil {
+		return err
+	}
+
 	var providerError error
-	switch r.Metadata().Properties[constants.PropertyStatus] {
+
+	// resources whose real world objects no longer match the state are
+	// re-created
+	if status == constants.StatusCreated {
+		providerError = p.Refresh(ctx)
+
+		de := providers.DriftError{}
+		if errors.As(providerError, &de) {
+			e.log.Info("Resource has drifted, re-creating", "ref", r.Metadata().ID, "reason", de.Reason)
+
+			status = constants.StatusTainted
+			providerError = nil
+		}
+	}
+
+	switch status {
 	case constants.StatusCreated:
-		providerError = p.Refresh()
 		if providerError != nil {
 			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
 		}
@@ -478,25 +1043,102 @@ func (e *EngineImpl) createCallback(r types.Resource) error {
 	// PendingModification causes a resource to be
 	// destroyed before created
 	case constants.StatusTainted:
+		// keep the existing resource, the checksum from the state is kept
+		// so that the change is detected by the next apply
+		if err := e.checkPreventDestroy(r); err != nil {
+			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCreated
+			checksum, _ = sr.Metadata().Properties[constants.PropertyChecksum].(string)
+			status = constants.StatusCreated
+			providerError = err
+
+			break
+		}
+
+		if l := resources.LifecycleForResource(r); l != nil && l.CreateBeforeDestroy {
+			kept, err := e.replaceResource(ctx, p, r)
+			providerError = err
+
+			switch {
+			case kept:
+				// the existing resource is unchanged
+				r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCreated
+				checksum, _ = sr.Metadata().Properties[constants.PropertyChecksum].(string)
+				status = constants.StatusCreated
+			case err != nil:
+				r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
+				e.setRecreated(r)
+			default:
+				r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCreated
+				e.setRecreated(r)
+			}
+
+			break
+		}
+
+		fallthrough
+
+	// resources which were being created or destroyed when an apply was
+	// interrupted may be partially created and are treated as failed
+	case constants.StatusCreating, constants.StatusDestroying:
 		fallthrough
 
 	// Always attempt to destroy and re-create failed resources
 	case constants.StatusFailed:
-		providerError = p.Destroy()
+		// destroy hooks only run for resources which have been created
+		if status == constants.StatusTainted {
+			providerError = e.destroyWithHooks(ctx, p, r)
+		} else {
+			providerError = p.Destroy(ctx)
+		}
+
 		if providerError != nil {
 			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
 		}
 
+		// dependents of the resource need to be re-created
+		e.setRecreated(r)
+
 		fallthrough // failed resources should always attempt recreation
 
 	default:
 		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCreated
-		providerError = p.Create()
+		providerError = e.createWithHooks(ctx, p, r)
 		if providerError != nil {
 			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
 		}
 	}
 
+	// when the operation was cancelled while the resource was being
+	// created, roll back the partially created resource
+	rolledBack := false
+	if providerError != nil && ctx.Err() != nil && status != constants.StatusCreated {
+		rolledBack = e.rollbackResource(p, r)
+	}
+
+	// store the checksum so that changes can be detected on the next apply
+	if checksum != "" {
+		r.Metadata().Properties[constants.PropertyChecksum] = checksum
+	}
+
+	e.stateLock.Lock()
+	defer e.stateLock.Unlock()
+
+	// replace the pending resource with the result of the provider
+	err = e.config.RemoveResource(pending)
+	if err != nil {
+		return fmt.Errorf(`unable to remove resource "%s" from state, %s`, r.Metadata().ID, err)
+	}
+
+	// resources which have been rolled back no longer exist
+	if rolledBack {
+		err = resources.SaveState(e.config)
+		if err != nil {
+			return fmt.Errorf(`unable to save state for resource "%s", %s`, r.Metadata().ID, err)
+		}
+
+		return providerError
+	}
+
 	// add the resource to the state
 	err = e.config.AppendResource(r)
 	if err != nil {
@@ -514,41 +1156,302 @@ func (e *EngineImpl) createCallback(r types.Resource) error {
 
 			// reload the networks
 			np := e.getProvider(ic, e.clients)
-			np.Create()
+			np.Create(ctx)
 		} else {
 			e.log.Error("Unable to find Image Cache", "error", err)
 		}
 	}
 
+	// save the state so that the computed attributes for the resource can be
+	// used by any dependents
+	err = resources.SaveState(e.config)
+	if err != nil {
+		return fmt.Errorf(`unable to save state for resource "%s", %s`, r.Metadata().ID, err)
+	}
+
 	return providerError
 }
 
-func (e *EngineImpl) destroyCallback(r types.Resource) error {
+func (e *EngineImpl) destroyCallback(ctx context.Context, r types.Resource) error {
 	fqdn := types.FQDNFromResource(r)
 
 	// do nothing for disabled resources
 	if r.Metadata().Disabled {
 		e.log.Info("Skipping disabled resource", "fqdn", fqdn.String())
 
-		e.config.RemoveResource(r)
-		return nil
+		return e.removeAndSaveState(r)
 	}
 
 	p := e.getProvider(r, e.clients)
 
 	if p == nil {
-		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
+		e.setStatusAndSaveState(r, constants.StatusFailed)
 		return fmt.Errorf("unable to create provider for resource Name: %s, Type: %s", r.Metadata().Name, r.Metadata().Type)
 	}
 
-	err := p.Destroy()
+	err := e.checkPreventDestroy(r)
 	if err != nil {
-		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
+		return err
+	}
+
+	// record that the resource is being destroyed, should the destroy be
+	// interrupted the resource remains in the state
+	err = e.setStatusAndSaveState(r, constants.StatusDestroying)
+	if err != nil {
+		return err
+	}
+
+	err = e.destroyWithHooks(ctx, p, r)
+	if err != nil {
+		e.setStatusAndSaveState(r, constants.StatusFailed)
 		return fmt.Errorf("unable to destroy resource Name: %s, Type: %s, Error: %s", r.Metadata().Name, r.Metadata().Type, err)
 	}
 
 	// remove from the state only if not errored
+	return e.removeAndSaveState(r)
+}
+
+// createWithHooks waits for any dependencies which set wait_for_healthy,
+// runs the before_create hooks, creates the resource and runs the
+// after_create hooks
+func (e *EngineImpl) createWithHooks(ctx context.Context, p providers.Provider, r types.Resource) error {
+	err := e.waitForHealthyDependencies(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	err = e.runHooks(ctx, r, hookBeforeCreate)
+	if err != nil {
+		return err
+	}
+
+	err = e.createWithRetry(ctx, p, r)
+	if err != nil {
+		return err
+	}
+
+	// providers run the health checks when the resource is created
+	e.setHealthy(r)
+
+	return e.runHooks(ctx, r, hookAfterCreate)
+}
+
+// destroyWithHooks runs the before_destroy hooks, destroys the resource and
+// runs the after_destroy hooks
+func (e *EngineImpl) destroyWithHooks(ctx context.Context, p providers.Provider, r types.Resource) error {
+	err := e.runHooks(ctx, r, hookBeforeDestroy)
+	if err != nil {
+		return err
+	}
+
+	err = p.Destroy(ctx)
+	if err != nil {
+		return err
+	}
+
+	return e.runHooks(ctx, r, hookAfterDestroy)
+}
+
+// rollbackResource destroys a resource whose creation was interrupted,
+// returns true when the resource has been destroyed. The context used for
+// the original operation has been cancelled, the resource is destroyed
+// using a new context.
+func (e *EngineImpl) rollbackResource(p providers.Provider, r types.Resource) bool {
+	e.log.Info("Operation cancelled, rolling back resource", "ref", r.Metadata().ID)
+
+	err := p.Destroy(context.Background())
+	if err != nil {
+		e.log.Error("Unable to roll back resource", "ref", r.Metadata().ID, "error", err)
+		return false
+	}
+
+	return true
+}
+
+// pendingResource returns a copy of the resource with the given status, the
+// copy is added to the state while the provider modifies the original
+func pendingResource(r types.Resource, status string) types.Resource {
+	v := reflect.New(reflect.TypeOf(r).Elem())
+	v.Elem().Set(reflect.ValueOf(r).Elem())
+
+	pr := v.Interface().(types.Resource)
+
+	// the properties map is shared with the original and must be copied
+	props := map[string]interface{}{}
+	for k, v := range r.Metadata().Properties {
+		props[k] = v
+	}
+	props[constants.PropertyStatus] = status
+	pr.Metadata().Properties = props
+
+	return pr
+}
+
+// appendAndSaveState adds the resource to the state and writes the state to
+// disk, the caller must hold the state lock
+func (e *EngineImpl) appendAndSaveState(r types.Resource) error {
+	err := e.config.AppendResource(r)
+	if err != nil {
+		return fmt.Errorf(`unable add resource "%s" to state, %s`, r.Metadata().ID, err)
+	}
+
+	err = resources.SaveState(e.config)
+	if err != nil {
+		return fmt.Errorf(`unable to save state for resource "%s", %s`, r.Metadata().ID, err)
+	}
+
+	return nil
+}
+
+// setStatusAndSaveState sets the status for a resource in the state and
+// writes the state to disk
+func (e *EngineImpl) setStatusAndSaveState(r types.Resource, status string) error {
+	e.stateLock.Lock()
+	defer e.stateLock.Unlock()
+
+	r.Metadata().Properties[constants.PropertyStatus] = status
+
+	err := resources.SaveState(e.config)
+	if err != nil {
+		return fmt.Errorf(`unable to save state for resource "%s", %s`, r.Metadata().ID, err)
+	}
+
+	return nil
+}
+
+// removeAndSaveState removes the resource from the state and writes the
+// state to disk
+func (e *EngineImpl) removeAndSaveState(r types.Resource) error {
+	e.stateLock.Lock()
+	defer e.stateLock.Unlock()
+
 	e.config.RemoveResource(r)
 
+	err := resources.SaveState(e.config)
+	if err != nil {
+		return fmt.Errorf(`unable to save state for resource "%s", %s`, r.Metadata().ID, err)
+	}
+
 	return nil
 }
+
+// setRecreated records that the given resource has been destroyed and
+// created during the current apply
+func (e *EngineImpl) setRecreated(r types.Resource) {
+	e.recreatedLock.Lock()
+	defer e.recreatedLock.Unlock()
+
+	if e.recreated == nil {
+		e.recreated = map[string]bool{}
+	}
+
+	e.recreated[r.Metadata().ID] = true
+}
+
+// recreatedDependency returns the id of the first dependency of the given
+// resource that has been re-created during the current apply, if no
+// dependencies have been re-created an empty string is returned
+func (e *EngineImpl) recreatedDependency(r types.Resource) string {
+	e.recreatedLock.Lock()
+	defer e.recreatedLock.Unlock()
+
+	return findDependency(r, e.recreated)
+}
+
+// findDependency returns the id of the first dependency of the given
+// resource which is contained in ids
+func findDependency(r types.Resource, ids map[string]bool) string {
+	for _, d := range r.Metadata().DependsOn {
+		fqrn, err := types.ParseFQRN(d)
+		if err != nil {
+			continue
+		}
+
+		// dependencies are relative to the module of the resource
+		dep := fqrn.AppendParentModule(r.Metadata().Module)
+		dep.Attribute = ""
+
+		for id := range ids {
+			// when the dependency is a module, depend on all resources in the module
+			if dep.Type == types.TypeModule && strings.HasPrefix(id, dep.String()+".") {
+				return id
+			}
+
+			if id == dep.String() {
+				return id
+			}
+		}
+	}
+
+	return ""
+}
+
+// linkedConfig parses the configuration for resources which reference other
+// resources. The configuration is only parsed again when a resource has been
+// created since the last parse, resources which are ready to be created at
+// the same time share a single parse.
+type linkedConfig struct {
+	parse func() (*hclconfig.Config, error)
+
+	mu         sync.Mutex
+	config     *hclconfig.Config
+	generation int
+
+	// current is incremented each time a resource is created
+	current int64
+}
+
+func newLinkedConfig(parse func() (*hclconfig.Config, error)) *linkedConfig {
+	return &linkedConfig{parse: parse, generation: -1}
+}
+
+// created records that a resource has been created and the state changed
+func (l *linkedConfig) created() {
+	atomic.AddInt64(&l.current, 1)
+}
+
+// get returns the parsed configuration, the configuration is parsed when a
+// resource has been created since it was last parsed
+func (l *linkedConfig) get() (*hclconfig.Config, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	gen := int(atomic.LoadInt64(&l.current))
+	if l.config != nil && l.generation == gen {
+		return l.config, nil
+	}
+
+	c, err := l.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	l.config = c
+	l.generation = gen
+
+	return c, nil
+}
+
+// semaphore limits the number of concurrent operations, a semaphore
+// created with a limit of 0 does not limit concurrency
+type semaphore chan struct{}
+
+func newSemaphore(limit int) semaphore {
+	if limit < 1 {
+		return nil
+	}
+
+	return make(semaphore, limit)
+}
+
+func (s semaphore) acquire() {
+	if s != nil {
+		s <- struct{}{}
+	}
+}
+
+func (s semaphore) release() {
+	if s != nil {
+		<-s
+	}
+}
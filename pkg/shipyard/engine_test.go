@@ -1,6 +1,8 @@
 package shipyard
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -9,7 +11,9 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/hashicorp/go-hclog"
@@ -22,6 +26,7 @@ import (
 	"github.com/shipyard-run/hclconfig"
 	"github.com/shipyard-run/hclconfig/types"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	assert "github.com/stretchr/testify/require"
 )
@@ -94,6 +99,9 @@ func generateProviderMock(mp *[]*mocks.MockProvider, returnVals map[string]error
 		m.On("Create").Return(val)
 		m.On("Destroy").Return(val)
 		m.On("Refresh").Return(val)
+		m.On("CheckDrift").Return(nil)
+		m.On("HealthCheck").Return(nil)
+		m.On("Lookup").Return([]string{}, nil)
 
 		*mp = append(*mp, m)
 		return m
@@ -116,6 +124,41 @@ func testLoadState(t *testing.T, e *EngineImpl) *hclconfig.Config {
 	return c
 }
 
+// destroyAfterEvent blocks the Destroy of the resource with the given name
+// until the engine has published the destroy event of type et for the
+// resource with the given id, this orders resources which are destroyed
+// concurrently
+func destroyAfterEvent(t *testing.T, e *EngineImpl, name, id string, et EventType) {
+	events, unsubscribe := e.Subscribe()
+	t.Cleanup(unsubscribe)
+
+	published := make(chan struct{})
+	once := sync.Once{}
+
+	go func() {
+		for ev := range events {
+			if ev.Operation == OperationDestroy && ev.Resource == id && ev.Type == et {
+				once.Do(func() { close(published) })
+			}
+		}
+	}()
+
+	gp := e.getProvider
+	e.getProvider = func(c types.Resource, cc *clients.Clients) providers.Provider {
+		m := gp(c, cc).(*mocks.MockProvider)
+
+		if c.Metadata().Name == name {
+			for _, ec := range m.ExpectedCalls {
+				if ec.Method == "Destroy" {
+					ec.Run(func(args mock.Arguments) { <-published })
+				}
+			}
+		}
+
+		return m
+	}
+}
+
 func TestNewCreatesClients(t *testing.T) {
 	e, err := New(hclog.NewNullLogger())
 	assert.NoError(t, err)
@@ -136,7 +179,7 @@ func TestNewCreatesClients(t *testing.T) {
 func TestApplyWithSingleFile(t *testing.T) {
 	e, mp := setupTests(t, nil)
 
-	_, err := e.Apply("../../examples/single_file/container.hcl")
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
 	require.NoError(t, err)
 
 	require.Len(t, e.config.Resources, 5)
@@ -164,7 +207,7 @@ func TestApplyWithSingleFile(t *testing.T) {
 func TestApplyAddsImageCache(t *testing.T) {
 	e, _ := setupTests(t, nil)
 
-	_, err := e.Apply("../../examples/single_file/container.hcl")
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
 	require.NoError(t, err)
 
 	dc := e.ResourceCountForType(resources.TypeImageCache)
@@ -174,7 +217,7 @@ func TestApplyAddsImageCache(t *testing.T) {
 func TestApplyWithSingleFileAndVariables(t *testing.T) {
 	e, mp := setupTests(t, nil)
 
-	_, err := e.ApplyWithVariables("../../examples/single_file/container.hcl", nil, "../../examples/single_file/default.vars")
+	_, err := e.ApplyWithVariables(context.Background(), "../../examples/single_file/container.hcl", nil, "../../examples/single_file/default.vars")
 	require.NoError(t, err)
 	require.Len(t, e.config.Resources, 5)
 
@@ -192,7 +235,7 @@ func TestApplyWithSingleFileAndVariables(t *testing.T) {
 func TestApplyCallsProviderCreateForEachProvider(t *testing.T) {
 	e, mp := setupTests(t, nil)
 
-	_, err := e.Apply("../../examples/single_k3s_cluster")
+	_, err := e.Apply(context.Background(), "../../examples/single_k3s_cluster")
 	require.NoError(t, err)
 
 	// should have call create for each resource in the config
@@ -210,7 +253,7 @@ func TestApplyCallsProviderCreateForEachProvider(t *testing.T) {
 func TestApplyDoesNotCallsProviderCreateWhenInState(t *testing.T) {
 	e, mp := setupTestsWithState(t, nil, existingState)
 
-	_, err := e.Apply("../../examples/single_file")
+	_, err := e.Apply(context.Background(), "../../examples/single_file")
 	require.NoError(t, err)
 
 	// should only be called for resources that are not in the state
@@ -225,7 +268,7 @@ func TestApplyNotCallsProviderCreateForDisabledResources(t *testing.T) {
 	e, mp := setupTests(t, nil)
 
 	// contains 2 resources one is disabled
-	_, err := e.Apply("../../examples/disabled")
+	_, err := e.Apply(context.Background(), "../../examples/disabled")
 	require.NoError(t, err)
 
 	// should have call create for each provider
@@ -247,7 +290,7 @@ func TestApplyShouldNotAddDuplicateDisabledResources(t *testing.T) {
 	e, mp := setupTestsWithState(t, nil, disabledState)
 
 	// contains 2 resources one is disabled
-	_, err := e.Apply("../../examples/disabled")
+	_, err := e.Apply(context.Background(), "../../examples/disabled")
 	require.NoError(t, err)
 
 	// should have call create for each provider
@@ -270,7 +313,7 @@ func TestApplyShouldNotAddDuplicateDisabledResources(t *testing.T) {
 func TestApplySetsCreatedStatusForEachResource(t *testing.T) {
 	e, mp := setupTests(t, nil)
 
-	_, err := e.Apply("../../examples/single_file/container.hcl")
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
 	require.NoError(t, err)
 
 	require.Equal(t, 5, e.config.ResourceCount())
@@ -296,7 +339,7 @@ func TestApplySetsCreatedStatusForEachResource(t *testing.T) {
 func TestApplyCallsProviderGenerateErrorStopsExecution(t *testing.T) {
 	e, mp := setupTests(t, map[string]error{"onprem": fmt.Errorf("boom")})
 
-	_, err := e.Apply("../../examples/single_file/container.hcl")
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
 	require.Error(t, err)
 
 	// should have call create for each provider
@@ -322,10 +365,125 @@ func TestApplyCallsProviderGenerateErrorStopsExecution(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestApplyWithFailedResourceCreatesUnrelatedResources(t *testing.T) {
+	e, mp := setupTests(t, map[string]error{"consul_config": fmt.Errorf("boom")})
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "resource.template.consul_config")
+
+	// network, template and image cache, container and output are skipped
+	testAssertMethodCalled(t, mp, "Create", 4)
+
+	sf := testLoadState(t, e)
+
+	r, err := sf.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+
+	_, err = sf.FindResource("resource.container.consul")
+	require.Error(t, err)
+
+	_, err = sf.FindResource("output.consul_addr")
+	require.Error(t, err)
+}
+
+func setupTestsWithConcurrency(t *testing.T, parallelism int) (*EngineImpl, *int32) {
+	e, _ := setupTests(t, nil)
+	e.SetOptions(Options{Parallelism: parallelism})
+
+	running := int32(0)
+	max := int32(0)
+
+	// wrap the provider so that the number of concurrent calls to create
+	// can be recorded
+	gp := e.getProvider
+	e.getProvider = func(c types.Resource, cc *clients.Clients) providers.Provider {
+		m := gp(c, cc).(*mocks.MockProvider)
+		m.ExpectedCalls = nil
+
+		m.On("Create").Run(func(args mock.Arguments) {
+			r := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+
+			for {
+				cur := atomic.LoadInt32(&max)
+				if r <= cur || atomic.CompareAndSwapInt32(&max, cur, r) {
+					break
+				}
+			}
+
+			time.Sleep(100 * time.Millisecond)
+		}).Return(nil)
+
+		return m
+	}
+
+	return e, &max
+}
+
+func TestApplyCreatesIndependentResourcesConcurrently(t *testing.T) {
+	e, max := setupTestsWithConcurrency(t, 10)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	// network and template do not depend on each other
+	require.Equal(t, int32(2), atomic.LoadInt32(max))
+}
+
+func TestApplyLimitsConcurrencyToParallelism(t *testing.T) {
+	e, max := setupTestsWithConcurrency(t, 1)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	require.Equal(t, int32(1), atomic.LoadInt32(max))
+}
+
+func TestLinkedConfigParsesOnlyAfterResourceCreated(t *testing.T) {
+	parses := int32(0)
+	l := newLinkedConfig(func() (*hclconfig.Config, error) {
+		atomic.AddInt32(&parses, 1)
+		return hclconfig.NewConfig(), nil
+	})
+
+	// resources ready at the same time share a parse
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := l.get()
+			require.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+	require.Equal(t, int32(1), atomic.LoadInt32(&parses))
+
+	l.created()
+
+	_, err := l.get()
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&parses))
+}
+
+func TestLinkedConfigReturnsParseError(t *testing.T) {
+	l := newLinkedConfig(func() (*hclconfig.Config, error) {
+		return nil, fmt.Errorf("boom")
+	})
+
+	_, err := l.get()
+	require.Error(t, err)
+}
+
 func TestApplyCallsProviderDestroyAndCreateForFailedResources(t *testing.T) {
 	e, mp := setupTestsWithState(t, nil, failedState)
 
-	_, err := e.Apply("../../examples/single_file/container.hcl")
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
 	require.NoError(t, err)
 
 	// should have call create for each provider
@@ -336,7 +494,7 @@ func TestApplyCallsProviderDestroyAndCreateForFailedResources(t *testing.T) {
 func TestApplyCallsProviderDestroyForTaintedResources(t *testing.T) {
 	e, mp := setupTestsWithState(t, nil, taintedState)
 
-	_, err := e.Apply("../../examples/single_file/container.hcl")
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
 	require.NoError(t, err)
 
 	// should have call create for each provider
@@ -349,7 +507,7 @@ func TestApplyCallsProviderDestroyForDisabledResources(t *testing.T) {
 	// destroyed
 	e, mp := setupTestsWithState(t, nil, disabledAndCreatedState)
 
-	_, err := e.Apply(".")
+	_, err := e.Apply(context.Background(), ".")
 	require.NoError(t, err)
 
 	// get the disabled resource
@@ -368,7 +526,7 @@ func TestApplyCallsProviderDestroyForDisabledResources(t *testing.T) {
 func TestApplyCallsProviderRefreshForCreatedResources(t *testing.T) {
 	e, mp := setupTestsWithState(t, nil, existingState)
 
-	_, err := e.Apply("../../examples/single_file/container.hcl")
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
 	require.NoError(t, err)
 
 	// should only call one time as there is only one item in the state
@@ -379,7 +537,7 @@ func TestApplyCallsProviderRefreshForCreatedResources(t *testing.T) {
 func TestApplyCallsProviderRefreshWithErrorHaltsExecution(t *testing.T) {
 	e, mp := setupTestsWithState(t, map[string]error{"consul_config": fmt.Errorf("boom")}, existingState)
 
-	_, err := e.Apply("../../examples/single_file/container.hcl")
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
 	require.Error(t, err)
 
 	// should only call one time as there is only one item in the state
@@ -388,10 +546,300 @@ func TestApplyCallsProviderRefreshWithErrorHaltsExecution(t *testing.T) {
 	testAssertMethodCalled(t, mp, "Create", 2)
 }
 
+func TestApplySetsChecksumForEachResource(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	sf := testLoadState(t, e)
+
+	r, err := sf.FindResource("resource.container.consul")
+	require.NoError(t, err)
+	require.NotEmpty(t, r.Metadata().Properties[constants.PropertyChecksum])
+}
+
+func TestApplyRecreatesChangedResourcesAndDependents(t *testing.T) {
+	e, mp := setupTests(t, nil)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	// change the checksum for the network to simulate a change in config
+	sf := testLoadState(t, e)
+	r, err := sf.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+	r.Metadata().Properties[constants.PropertyChecksum] = "changed"
+	err = resources.SaveState(sf)
+	require.NoError(t, err)
+
+	*mp = []*mocks.MockProvider{}
+
+	_, err = e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	// the network, the container and the output that depend on the network
+	// should be re-created, the template is unchanged
+	testAssertMethodCalled(t, mp, "Destroy", 3)
+	testAssertMethodCalled(t, mp, "Refresh", 1)
+
+	// the image cache is updated when the network is re-created
+	testAssertMethodCalled(t, mp, "Create", 4)
+}
+
+func TestApplyDoesNotRecreateUnchangedResources(t *testing.T) {
+	e, mp := setupTests(t, nil)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	*mp = []*mocks.MockProvider{}
+
+	_, err = e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+	testAssertMethodCalled(t, mp, "Refresh", 4)
+
+	// the image cache is always updated with the networks
+	testAssertMethodCalled(t, mp, "Create", 1)
+}
+
+func TestApplyWithTargetCreatesTargetAndDependencies(t *testing.T) {
+	e, mp := setupTests(t, nil)
+	e.SetOptions(Options{Targets: []string{"resource.container.consul"}})
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	// network, template, container and the image cache which is created and
+	// then updated with the network
+	testAssertMethodCalled(t, mp, "Create", 5)
+
+	sf := testLoadState(t, e)
+
+	_, err = sf.FindResource("resource.container.consul")
+	require.NoError(t, err)
+
+	_, err = sf.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+
+	// output depends on the container but is not targeted
+	_, err = sf.FindResource("output.consul_addr")
+	require.Error(t, err)
+}
+
+func TestApplyWithTargetLeavesOtherResourcesInState(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, existingState)
+	e.SetOptions(Options{Targets: []string{"resource.template.consul_config"}})
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	// only the template should be refreshed
+	testAssertMethodCalled(t, mp, "Create", 0)
+	testAssertMethodCalled(t, mp, "Refresh", 1)
+
+	sf := testLoadState(t, e)
+	require.Len(t, sf.Resources, 4)
+}
+
+func TestApplyWithUnknownTargetReturnsError(t *testing.T) {
+	e, mp := setupTests(t, nil)
+	e.SetOptions(Options{Targets: []string{"resource.container.missing"}})
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "resource.container.missing")
+
+	// only the image cache is created
+	testAssertMethodCalled(t, mp, "Create", 1)
+}
+
+func TestApplyWithLockedStateReturnsError(t *testing.T) {
+	e, mp := setupTests(t, nil)
+
+	os.MkdirAll(utils.StateDir(), os.ModePerm)
+	err := ioutil.WriteFile(utils.StateLockPath(), []byte(`{"pid": 1, "hostname": "other-host"}`), os.ModePerm)
+	require.NoError(t, err)
+
+	_, err = e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "other-host")
+
+	testAssertMethodCalled(t, mp, "Create", 0)
+}
+
+func TestApplyReleasesStateLock(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	require.NoFileExists(t, utils.StateLockPath())
+}
+
+func TestApplySavesCreatingStatusBeforeCallingProvider(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	statuses := map[string]interface{}{}
+	statusLock := sync.Mutex{}
+
+	// record the status in the state file when the provider is called
+	gp := e.getProvider
+	e.getProvider = func(c types.Resource, cc *clients.Clients) providers.Provider {
+		m := gp(c, cc).(*mocks.MockProvider)
+		m.ExpectedCalls = nil
+
+		m.On("Create").Run(func(args mock.Arguments) {
+			// the state does not exist when the image cache is created
+			sf, _ := resources.LoadState()
+
+			r, err := sf.FindResource(c.Metadata().ID)
+			if err == nil {
+				statusLock.Lock()
+				statuses[c.Metadata().ID] = r.Metadata().Properties[constants.PropertyStatus]
+				statusLock.Unlock()
+			}
+		}).Return(nil)
+
+		return m
+	}
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	require.Equal(t, constants.StatusCreating, statuses["resource.network.onprem"])
+	require.Equal(t, constants.StatusCreating, statuses["resource.container.consul"])
+}
+
+func TestApplyRecreatesInterruptedResources(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, interruptedState)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	// network was being created when the apply was interrupted
+	testAssertMethodCalled(t, mp, "Destroy", 1)
+
+	sf := testLoadState(t, e)
+
+	r, err := sf.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestApplyWithCancelledContextDoesNotCreateResources(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, existingState)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := e.Apply(ctx, "../../examples/single_file/container.hcl")
+	require.ErrorIs(t, err, context.Canceled)
+
+	testAssertMethodCalled(t, mp, "Create", 0)
+	testAssertMethodCalled(t, mp, "Refresh", 0)
+
+	// the state is left unchanged
+	sf := testLoadState(t, e)
+	require.Len(t, sf.Resources, 4)
+}
+
+func TestApplyCancelledDuringCreateRollsBackResource(t *testing.T) {
+	e, mp := setupTests(t, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the template does not depend on the network and is created
+	// concurrently, cancel the apply while the network is being created
+	// once the template has been created
+	templateCreated := make(chan struct{})
+
+	gp := e.getProvider
+	e.getProvider = func(c types.Resource, cc *clients.Clients) providers.Provider {
+		m := gp(c, cc).(*mocks.MockProvider)
+
+		switch c.Metadata().Name {
+		case "consul_config":
+			m.ExpectedCalls = nil
+
+			m.On("Create").Run(func(args mock.Arguments) { close(templateCreated) }).Return(nil)
+		case "onprem":
+			m.ExpectedCalls = nil
+
+			m.On("Create").Run(func(args mock.Arguments) {
+				<-templateCreated
+				cancel()
+			}).Return(context.Canceled)
+			m.On("Destroy").Return(nil)
+		}
+
+		return m
+	}
+
+	_, err := e.Apply(ctx, "../../examples/single_file/container.hcl")
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotContains(t, err.Error(), "resource.network.onprem")
+
+	// the image cache, network and template are created, resources which
+	// depend on the network are not created
+	testAssertMethodCalled(t, mp, "Create", 3) // ImageCache is always created
+
+	for _, m := range *mp {
+		require.NotEqual(t, "consul", m.Config().Metadata().Name)
+	}
+
+	testAssertMethodCalled(t, mp, "Destroy", 1)
+
+	n := getProviderForResource(t, mp, "onprem")
+	require.Len(t, callsFor(n, "Destroy"), 1)
+
+	// the partially created network is removed from the state
+	sf := testLoadState(t, e)
+
+	_, err = sf.FindResource("resource.network.onprem")
+	require.Error(t, err)
+
+	_, err = sf.FindResource("resource.image_cache.default")
+	require.NoError(t, err)
+}
+
+func TestApplyCancelledDuringCreateWithRollbackErrorSetsStatusFailed(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gp := e.getProvider
+	e.getProvider = func(c types.Resource, cc *clients.Clients) providers.Provider {
+		m := gp(c, cc).(*mocks.MockProvider)
+
+		if c.Metadata().Name == "onprem" {
+			m.ExpectedCalls = nil
+
+			m.On("Create").Run(func(args mock.Arguments) { cancel() }).Return(context.Canceled)
+			m.On("Destroy").Return(fmt.Errorf("boom"))
+		}
+
+		return m
+	}
+
+	_, err := e.Apply(ctx, "../../examples/single_file/container.hcl")
+	require.ErrorIs(t, err, context.Canceled)
+
+	sf := testLoadState(t, e)
+
+	r, err := sf.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusFailed, r.Metadata().Properties[constants.PropertyStatus])
+}
+
 func TestDestroyCallsProviderDestroyForEachProvider(t *testing.T) {
 	e, mp := setupTestsWithState(t, nil, existingState)
 
-	err := e.Destroy()
+	err := e.Destroy(context.Background())
 	require.NoError(t, err)
 
 	// should have call create for each provider
@@ -405,7 +853,7 @@ func TestDestroyCallsProviderDestroyForEachProvider(t *testing.T) {
 func TestDestroyNotCallsProviderDestroyForResourcesDisabled(t *testing.T) {
 	e, mp := setupTestsWithState(t, nil, disabledState)
 
-	err := e.Destroy()
+	err := e.Destroy(context.Background())
 	require.NoError(t, err)
 
 	// should have call create for each provider
@@ -419,10 +867,15 @@ func TestDestroyNotCallsProviderDestroyForResourcesDisabled(t *testing.T) {
 func TestDestroyCallsProviderGenerateErrorStopsExecution(t *testing.T) {
 	e, mp := setupTestsWithState(t, map[string]error{"mycontainer": fmt.Errorf("boom")}, complexState)
 
-	err := e.Destroy()
+	// the image cache is destroyed concurrently with the container, ensure
+	// it has started before the container fails
+	destroyAfterEvent(t, e, "mycontainer", "resource.image_cache.default", EventStarted)
+
+	err := e.Destroy(context.Background())
 	require.Error(t, err)
 
-	// should have call destroy for each provider
+	// should have call destroy for the container and image cache, the
+	// template and network are not destroyed
 	testAssertMethodCalled(t, mp, "Destroy", 2)
 
 	// state should not be removed
@@ -432,13 +885,195 @@ func TestDestroyCallsProviderGenerateErrorStopsExecution(t *testing.T) {
 func TestDestroyFailSetsStatus(t *testing.T) {
 	e, _ := setupTestsWithState(t, map[string]error{"mycontainer": fmt.Errorf("boom")}, complexState)
 
-	err := e.Destroy()
+	err := e.Destroy(context.Background())
 	require.Error(t, err)
 
 	r, _ := e.config.FindResource("resource.container.mycontainer")
 	require.Equal(t, constants.StatusFailed, r.Metadata().Properties[constants.PropertyStatus])
 }
 
+func TestDestroyWithTargetDestroysTargetAndDependents(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, complexState)
+	e.SetOptions(Options{Targets: []string{"resource.network.cloud"}})
+
+	err := e.Destroy(context.Background())
+	require.NoError(t, err)
+
+	// network and the container that depends on it
+	testAssertMethodCalled(t, mp, "Destroy", 2)
+
+	// state should contain the resources that were not targeted
+	sf := testLoadState(t, e)
+	require.Len(t, sf.Resources, 2)
+
+	_, err = sf.FindResource("resource.template.mytemplate")
+	require.NoError(t, err)
+
+	_, err = sf.FindResource("resource.image_cache.default")
+	require.NoError(t, err)
+}
+
+func TestDestroyStopsDestroyingUnrelatedResourcesAfterError(t *testing.T) {
+	e, mp := setupTestsWithState(t, map[string]error{"default": fmt.Errorf("boom")}, complexState)
+
+	// the container is destroyed after the image cache has failed, the
+	// template which only the container depends on is not destroyed
+	destroyAfterEvent(t, e, "default", "resource.container.mycontainer", EventStarted)
+	destroyAfterEvent(t, e, "mycontainer", "resource.image_cache.default", EventFailed)
+
+	err := e.Destroy(context.Background())
+	require.Error(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 2)
+
+	tp := getProviderForResource(t, mp, "mytemplate")
+	require.Len(t, callsFor(tp, "Destroy"), 0)
+
+	de := DestroyError{}
+	require.True(t, errors.As(err, &de))
+	require.Len(t, de.Orphans, 3)
+
+	require.Equal(t, "resource.image_cache.default", de.Orphans[0].Resource)
+	require.Contains(t, de.Orphans[0].Reason, "boom")
+	require.Equal(t, "resource.network.cloud", de.Orphans[1].Resource)
+	require.Equal(t, "dependent failed to destroy", de.Orphans[1].Reason)
+	require.Equal(t, "resource.template.mytemplate", de.Orphans[2].Resource)
+	require.Equal(t, "destroy stopped after an earlier error", de.Orphans[2].Reason)
+}
+
+func TestDestroyWithContinueOnErrorDestroysUnrelatedResourcesAfterError(t *testing.T) {
+	e, mp := setupTestsWithState(t, map[string]error{"default": fmt.Errorf("boom")}, complexState)
+	e.SetOptions(Options{ContinueOnError: true})
+
+	destroyAfterEvent(t, e, "mycontainer", "resource.image_cache.default", EventFailed)
+
+	err := e.Destroy(context.Background())
+	require.Error(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 4)
+}
+
+func TestDestroyWithErrorSavesStateForDestroyedResources(t *testing.T) {
+	e, _ := setupTestsWithState(t, map[string]error{"mycontainer": fmt.Errorf("boom")}, complexState)
+	destroyAfterEvent(t, e, "mycontainer", "resource.image_cache.default", EventStarted)
+
+	err := e.Destroy(context.Background())
+	require.Error(t, err)
+
+	sf := testLoadState(t, e)
+
+	// image cache has been destroyed and removed from the state
+	_, err = sf.FindResource("resource.image_cache.default")
+	require.Error(t, err)
+
+	r, err := sf.FindResource("resource.container.mycontainer")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusFailed, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestDestroyWithCancelledContextKeepsState(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, complexState)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := e.Destroy(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+
+	sf := testLoadState(t, e)
+
+	r, err := sf.FindResource("resource.container.mycontainer")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestApplyArchivesPreviousState(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, existingState)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	// the state is saved after every resource but only archived once
+	versions, err := resources.StateHistory()
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+
+	c, err := resources.LoadStateVersion(1)
+	require.NoError(t, err)
+	require.Len(t, c.Resources, 4)
+}
+
+func TestApplyArchivesStateOncePerApply(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, existingState)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	// taint and state rm save the state without archiving it
+	c := testLoadState(t, e)
+	err = resources.SaveState(c)
+	require.NoError(t, err)
+
+	_, err = e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	versions, err := resources.StateHistory()
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+}
+
+func TestDestroyArchivesPreviousState(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, existingState)
+
+	err := e.Destroy(context.Background())
+	require.NoError(t, err)
+
+	versions, err := resources.StateHistory()
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+}
+
+func TestRollbackRestoresPreviousVersion(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, existingState)
+
+	os.MkdirAll(utils.StateHistoryDir(), os.ModePerm)
+	err := ioutil.WriteFile(filepath.Join(utils.StateHistoryDir(), "state.1.json"), []byte(complexState), os.ModePerm)
+	require.NoError(t, err)
+
+	_, err = e.Rollback(context.Background(), 1)
+	require.NoError(t, err)
+
+	// resources which are not in the previous version are destroyed
+	testAssertMethodCalled(t, mp, "Destroy", 2)
+
+	sf := testLoadState(t, e)
+	require.Len(t, sf.Resources, 4)
+
+	_, err = sf.FindResource("resource.container.container")
+	require.Error(t, err)
+
+	r, err := sf.FindResource("resource.container.mycontainer")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+
+	// the state before the rollback is archived
+	versions, err := resources.StateHistory()
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+}
+
+func TestRollbackWithUnknownVersionReturnsError(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, existingState)
+
+	_, err := e.Rollback(context.Background(), 3)
+	require.Error(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+	testAssertMethodCalled(t, mp, "Create", 0)
+}
+
 func TestParseConfig(t *testing.T) {
 	e, mp := setupTests(t, nil)
 
@@ -452,6 +1087,46 @@ func TestParseConfig(t *testing.T) {
 	testAssertMethodCalled(t, mp, "Destroy", 0)
 }
 
+func TestBlueprintReturnsNilWhenNotDefined(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	require.Nil(t, e.Blueprint())
+
+	_, err := e.ParseConfig("../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	require.Nil(t, e.Blueprint())
+}
+
+func TestBlueprintReturnsParsedBlueprint(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	_, err := e.ParseConfig("../../examples/blueprint")
+	require.NoError(t, err)
+
+	bp := e.Blueprint()
+	require.NotNil(t, bp)
+	require.Equal(t, "Consul", bp.Title)
+	require.Equal(t, ">= 0.5.0", bp.ShipyardVersion)
+	require.Equal(t, []string{"http://consul.container.jumppad.dev:8500"}, bp.BrowserWindows)
+}
+
+func TestApplySavesBlueprintInState(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	_, err := e.Apply(context.Background(), "../../examples/blueprint")
+	require.NoError(t, err)
+
+	require.NotNil(t, e.Blueprint())
+
+	sf := testLoadState(t, e)
+
+	r, err := sf.FindResource("resource.blueprint.consul")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+	require.Equal(t, "http://consul.container.jumppad.dev:8500", r.(*resources.Blueprint).Environment["CONSUL_HTTP_ADDR"])
+}
+
 func TestParseWithVariables(t *testing.T) {
 	e, mp := setupTests(t, nil)
 
@@ -665,3 +1340,25 @@ var disabledAndCreatedState = `
   ]
 }
 `
+
+var interruptedState = `
+{
+  "resources": [
+	{
+      "name": "onprem",
+      "properties": {
+				"status": "creating"
+			},
+      "subnet": "10.6.0.0/16",
+      "type": "network"
+	},
+	{
+      "name": "default",
+      "properties": {
+				"status": "created"
+			},
+      "type": "image_cache"
+	}
+  ]
+}
+`
@@ -0,0 +1,147 @@
+package shipyard
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/shipyard-run/hclconfig/types"
+)
+
+// EventType defines the type of an Event published by the engine
+type EventType string
+
+const (
+	// EventStarted is published when the engine starts to create or
+	// destroy a resource
+	EventStarted EventType = "started"
+
+	// EventSucceeded is published when a resource has been successfully
+	// created or destroyed
+	EventSucceeded EventType = "succeeded"
+
+	// EventFailed is published when a resource could not be created or
+	// destroyed
+	EventFailed EventType = "failed"
+
+	// EventSkipped is published when a resource is not created or destroyed
+	// because a dependency failed or the operation was cancelled
+	EventSkipped EventType = "skipped"
+)
+
+const (
+	// OperationCreate is the operation for events published by Apply
+	OperationCreate = "create"
+
+	// OperationDestroy is the operation for events published by Destroy
+	OperationDestroy = "destroy"
+)
+
+// Event describes a change to a resource during an Apply or Destroy
+type Event struct {
+	Type         EventType `json:"type"`
+	Operation    string    `json:"operation"`
+	Resource     string    `json:"resource"`
+	ResourceType string    `json:"resource_type"`
+	Time         time.Time `json:"time"`
+
+	// Duration is the time taken to create or destroy the resource, only
+	// set for succeeded and failed events
+	Duration time.Duration `json:"-"`
+
+	// Reason explains why a resource was skipped
+	Reason string `json:"reason,omitempty"`
+
+	// Error is the error returned when creating or destroying the resource
+	Error string `json:"error,omitempty"`
+}
+
+// MarshalJSON serializes the event, the duration is written in milliseconds
+func (e Event) MarshalJSON() ([]byte, error) {
+	type event Event
+
+	return json.Marshal(struct {
+		event
+		DurationMS int64 `json:"duration_ms,omitempty"`
+	}{event(e), e.Duration.Milliseconds()})
+}
+
+// eventBus delivers events to subscribers, the zero value is ready to use
+type eventBus struct {
+	mu          sync.RWMutex
+	subscribers map[*subscriber]bool
+}
+
+type subscriber struct {
+	events chan Event
+	done   chan struct{}
+}
+
+// subscribe returns a channel which receives all events published after
+// the call and a function which removes the subscription and closes the
+// channel
+func (b *eventBus) subscribe() (<-chan Event, func()) {
+	s := &subscriber{
+		events: make(chan Event, 64),
+		done:   make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	if b.subscribers == nil {
+		b.subscribers = map[*subscriber]bool{}
+	}
+	b.subscribers[s] = true
+	b.mu.Unlock()
+
+	once := sync.Once{}
+
+	return s.events, func() {
+		once.Do(func() {
+			// unblock any publish which is waiting on the subscriber before
+			// removing it
+			close(s.done)
+
+			b.mu.Lock()
+			delete(b.subscribers, s)
+			b.mu.Unlock()
+
+			close(s.events)
+		})
+	}
+}
+
+// publish sends the event to every subscriber, publish blocks until the
+// event has been queued for each subscriber or the subscriber is removed
+func (b *eventBus) publish(e Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for s := range b.subscribers {
+		select {
+		case s.events <- e:
+		case <-s.done:
+		}
+	}
+}
+
+// publishEvent publishes an event for the given resource
+func (e *EngineImpl) publishEvent(t EventType, op string, r types.Resource, started time.Time, reason string, err error) {
+	ev := Event{
+		Type:         t,
+		Operation:    op,
+		Resource:     r.Metadata().ID,
+		ResourceType: r.Metadata().Type,
+		Time:         time.Now(),
+		Reason:       reason,
+	}
+
+	if !started.IsZero() {
+		ev.Duration = ev.Time.Sub(started)
+	}
+
+	if err != nil {
+		ev.Error = err.Error()
+	}
+
+	e.events.publish(ev)
+}
@@ -0,0 +1,151 @@
+package shipyard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// collectEvents subscribes to the engine events, the returned function ends
+// the subscription and returns the received events
+func collectEvents(e *EngineImpl) func() []Event {
+	events, unsubscribe := e.Subscribe()
+
+	received := []Event{}
+	done := make(chan struct{})
+
+	go func() {
+		for ev := range events {
+			received = append(received, ev)
+		}
+
+		close(done)
+	}()
+
+	return func() []Event {
+		unsubscribe()
+		<-done
+
+		return received
+	}
+}
+
+func eventsFor(events []Event, id string) []Event {
+	evs := []Event{}
+	for _, ev := range events {
+		if ev.Resource == id {
+			evs = append(evs, ev)
+		}
+	}
+
+	return evs
+}
+
+func TestApplyPublishesEventsForEachResource(t *testing.T) {
+	e, _ := setupTests(t, nil)
+	stop := collectEvents(e)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	events := stop()
+
+	evs := eventsFor(events, "resource.container.consul")
+	require.Len(t, evs, 2)
+
+	require.Equal(t, EventStarted, evs[0].Type)
+	require.Equal(t, OperationCreate, evs[0].Operation)
+	require.Equal(t, "container", evs[0].ResourceType)
+
+	require.Equal(t, EventSucceeded, evs[1].Type)
+	require.Equal(t, OperationCreate, evs[1].Operation)
+	require.Empty(t, evs[1].Error)
+}
+
+func TestApplyPublishesFailedAndSkippedEvents(t *testing.T) {
+	e, _ := setupTests(t, map[string]error{"onprem": fmt.Errorf("boom")})
+	stop := collectEvents(e)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.Error(t, err)
+
+	events := stop()
+
+	evs := eventsFor(events, "resource.network.onprem")
+	require.Len(t, evs, 2)
+	require.Equal(t, EventFailed, evs[1].Type)
+	require.Equal(t, "boom", evs[1].Error)
+
+	// the container depends on the network
+	evs = eventsFor(events, "resource.container.consul")
+	require.Len(t, evs, 1)
+	require.Equal(t, EventSkipped, evs[0].Type)
+	require.Contains(t, evs[0].Reason, "resource.network.onprem")
+}
+
+func TestDestroyPublishesEventsForEachResource(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, existingState)
+	stop := collectEvents(e)
+
+	err := e.Destroy(context.Background())
+	require.NoError(t, err)
+
+	events := stop()
+
+	evs := eventsFor(events, "resource.network.cloud")
+	require.Len(t, evs, 2)
+	require.Equal(t, EventStarted, evs[0].Type)
+	require.Equal(t, OperationDestroy, evs[0].Operation)
+	require.Equal(t, EventSucceeded, evs[1].Type)
+}
+
+func TestEventMarshalsDurationInMilliseconds(t *testing.T) {
+	ev := Event{
+		Type:      EventFailed,
+		Operation: OperationCreate,
+		Resource:  "resource.container.consul",
+		Duration:  1500 * time.Millisecond,
+		Error:     "boom",
+	}
+
+	d, err := json.Marshal(ev)
+	require.NoError(t, err)
+
+	out := map[string]interface{}{}
+	err = json.Unmarshal(d, &out)
+	require.NoError(t, err)
+
+	require.Equal(t, "failed", out["type"])
+	require.Equal(t, "resource.container.consul", out["resource"])
+	require.Equal(t, float64(1500), out["duration_ms"])
+	require.Equal(t, "boom", out["error"])
+	require.NotContains(t, out, "reason")
+}
+
+func TestUnsubscribeUnblocksPublish(t *testing.T) {
+	b := eventBus{}
+	events, unsubscribe := b.subscribe()
+
+	// fill the buffer so that publish blocks
+	for i := 0; i < cap(events); i++ {
+		b.publish(Event{})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		b.publish(Event{})
+		close(done)
+	}()
+
+	unsubscribe()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publish did not return after unsubscribe")
+	}
+}
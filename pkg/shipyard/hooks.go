@@ -0,0 +1,152 @@
+package shipyard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/shipyard-run/hclconfig/types"
+)
+
+const (
+	hookBeforeCreate  = "before_create"
+	hookAfterCreate   = "after_create"
+	hookBeforeDestroy = "before_destroy"
+	hookAfterDestroy  = "after_destroy"
+)
+
+// defaultHookTimeout is the maximum time a hook can run when the hook does
+// not define a timeout
+var defaultHookTimeout = 300 * time.Second
+
+// runHooks executes the lifecycle hooks for the given stage of the resource
+// in order, an error is returned when a hook fails and the on_failure
+// behaviour of the hook is fail
+func (e *EngineImpl) runHooks(ctx context.Context, r types.Resource, stage string) error {
+	l := resources.LifecycleForResource(r)
+	if l == nil {
+		return nil
+	}
+
+	var hooks []resources.LifecycleHook
+
+	switch stage {
+	case hookBeforeCreate:
+		hooks = l.BeforeCreate
+	case hookAfterCreate:
+		hooks = l.AfterCreate
+	case hookBeforeDestroy:
+		hooks = l.BeforeDestroy
+	case hookAfterDestroy:
+		hooks = l.AfterDestroy
+	}
+
+	for i, h := range hooks {
+		err := h.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid %s hook for resource %s: %s", stage, r.Metadata().ID, err)
+		}
+
+		e.log.Info("Running lifecycle hook", "ref", r.Metadata().ID, "hook", stage, "command", h.Command, "target", h.Target)
+
+		err = e.runHook(ctx, r, h, fmt.Sprintf("%s_%d", stage, i))
+		if err == nil {
+			continue
+		}
+
+		if h.OnFailure == resources.HookOnFailureContinue {
+			e.log.Warn("Lifecycle hook failed, continuing", "ref", r.Metadata().ID, "hook", stage, "error", err)
+			continue
+		}
+
+		return fmt.Errorf("%s hook for resource %s failed: %s", stage, r.Metadata().ID, err)
+	}
+
+	return nil
+}
+
+func (e *EngineImpl) runHook(ctx context.Context, r types.Resource, h resources.LifecycleHook, name string) error {
+	timeout := defaultHookTimeout
+	if h.Timeout != "" {
+		// the timeout has been validated
+		timeout, _ = time.ParseDuration(h.Timeout)
+	}
+
+	envs := []string{}
+	for k, v := range h.Environment {
+		envs = append(envs, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	if h.Target == "" {
+		cc := clients.CommandConfig{
+			Command:          h.Command[0],
+			Args:             h.Command[1:],
+			Env:              envs,
+			WorkingDirectory: h.WorkingDirectory,
+			LogFilePath:      filepath.Join(utils.LogsDir(), fmt.Sprintf("hook_%s_%s.log", r.Metadata().ID, name)),
+			Timeout:          timeout,
+		}
+
+		_, err := e.clients.Command.Execute(ctx, cc)
+		return err
+	}
+
+	id, err := e.hookTargetID(r, h.Target)
+	if err != nil {
+		return err
+	}
+
+	// cancelling the context stops ExecuteCommand waiting for the command
+	// and writing its output
+	hctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err = e.clients.ContainerTasks.ExecuteCommand(hctx, id, h.Command, envs, h.WorkingDirectory, "", "", e.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}))
+	if err != nil && ctx.Err() == nil && errors.Is(hctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("command timed out after %s", timeout)
+	}
+
+	return err
+}
+
+// hookTargetID returns the id of the container for the hook target, targets
+// are relative to the module of the resource
+func (e *EngineImpl) hookTargetID(r types.Resource, target string) (string, error) {
+	t, err := types.ParseFQRN(target)
+	if err != nil {
+		return "", fmt.Errorf("invalid target %s: %s", target, err)
+	}
+
+	fqrn := t.AppendParentModule(r.Metadata().Module)
+
+	switch fqrn.Type {
+	case resources.TypeContainer, resources.TypeSidecar, resources.TypeK8sCluster, resources.TypeNomadCluster:
+	default:
+		return "", fmt.Errorf("invalid target %s, hooks can only target %s, %s, %s or %s resources", target, resources.TypeContainer, resources.TypeSidecar, resources.TypeK8sCluster, resources.TypeNomadCluster)
+	}
+
+	// commands targeting a cluster run in the server container
+	name := fqrn.Resource
+	if fqrn.Type == resources.TypeK8sCluster || fqrn.Type == resources.TypeNomadCluster {
+		name = fmt.Sprintf("server.%s", fqrn.Resource)
+	}
+
+	fqdn := utils.FQDN(name, fqrn.Module, fqrn.Type)
+
+	ids, err := e.clients.ContainerTasks.FindContainerIDs(fqdn)
+	if err != nil {
+		return "", fmt.Errorf("unable to find target %s: %s", target, err)
+	}
+
+	if len(ids) != 1 {
+		return "", fmt.Errorf("unable to find target %s", target)
+	}
+
+	return ids[0], nil
+}
@@ -0,0 +1,185 @@
+package shipyard
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/providers/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/shipyard/constants"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/shipyard-run/hclconfig/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupHookTests(t *testing.T, commandErr, execErr error) (*EngineImpl, *[]*mocks.MockProvider, *clients.CommandMock, *clients.MockContainerTasks) {
+	e, mp := setupTests(t, nil)
+
+	cm := &clients.CommandMock{}
+	cm.On("Execute", mock.Anything).Return(0, commandErr)
+
+	ct := &clients.MockContainerTasks{}
+	ct.On("FindContainerIDs", "consul.container.jumppad.dev").Return([]string{"abc123"}, nil)
+	ct.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(execErr)
+
+	e.clients.Command = cm
+	e.clients.ContainerTasks = ct
+
+	return e, mp, cm, ct
+}
+
+func TestApplyRunsCreateHooks(t *testing.T) {
+	e, mp, cm, ct := setupHookTests(t, nil, nil)
+
+	_, err := e.Apply(context.Background(), "../../examples/lifecycle")
+	require.NoError(t, err)
+
+	cm.AssertNumberOfCalls(t, "Execute", 1)
+	cc := cm.Calls[0].Arguments.Get(0).(clients.CommandConfig)
+	require.Equal(t, "echo", cc.Command)
+	require.Equal(t, []string{"creating network"}, cc.Args)
+	require.Equal(t, "hook_resource.network.onprem_before_create_0.log", filepath.Base(cc.LogFilePath))
+
+	ct.AssertCalled(t, "ExecuteCommand", "abc123", []string{"consul", "kv", "put", "ready", "true"}, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	n := getProviderForResource(t, mp, "onprem")
+	require.Len(t, callsFor(n, "Create"), 1)
+}
+
+func TestApplyWithFailedBeforeCreateHookDoesNotCreateResource(t *testing.T) {
+	e, mp, _, _ := setupHookTests(t, fmt.Errorf("boom"), nil)
+
+	_, err := e.Apply(context.Background(), "../../examples/lifecycle")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "before_create")
+
+	n := getProviderForResource(t, mp, "onprem")
+	require.Len(t, callsFor(n, "Create"), 0)
+
+	sf := testLoadState(t, e)
+
+	r, err := sf.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusFailed, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestApplyWithFailedAfterCreateHookSetsStatusFailed(t *testing.T) {
+	e, _, _, _ := setupHookTests(t, nil, fmt.Errorf("boom"))
+
+	_, err := e.Apply(context.Background(), "../../examples/lifecycle")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "after_create")
+
+	sf := testLoadState(t, e)
+
+	r, err := sf.FindResource("resource.container.consul")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusFailed, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestDestroyRunsDestroyHooksFromState(t *testing.T) {
+	e, _, cm, ct := setupHookTests(t, nil, nil)
+
+	_, err := e.Apply(context.Background(), "../../examples/lifecycle")
+	require.NoError(t, err)
+
+	err = e.Destroy(context.Background())
+	require.NoError(t, err)
+
+	ct.AssertCalled(t, "ExecuteCommand", "abc123", []string{"consul", "leave"}, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	// before_create and after_destroy for the network
+	cm.AssertNumberOfCalls(t, "Execute", 2)
+	cc := cm.Calls[1].Arguments.Get(0).(clients.CommandConfig)
+	require.Equal(t, []string{"network destroyed"}, cc.Args)
+}
+
+func TestDestroyWithFailedHookAndContinueDestroysResource(t *testing.T) {
+	e, mp, _, ct := setupHookTests(t, nil, nil)
+
+	_, err := e.Apply(context.Background(), "../../examples/lifecycle")
+	require.NoError(t, err)
+
+	ct.ExpectedCalls = nil
+	ct.On("FindContainerIDs", mock.Anything).Return([]string{"abc123"}, nil)
+	ct.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	err = e.Destroy(context.Background())
+	require.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 3)
+}
+
+func TestHookTargetingClusterRunsInServerContainer(t *testing.T) {
+	for _, typ := range []string{resources.TypeK8sCluster, resources.TypeNomadCluster} {
+		t.Run(typ, func(t *testing.T) {
+			e, _, _, ct := setupHookTests(t, nil, nil)
+
+			server := utils.FQDN("server.dev", "", typ)
+			ct.On("FindContainerIDs", server).Return([]string{"def456"}, nil)
+
+			n := &resources.Network{ResourceMetadata: types.ResourceMetadata{ID: "resource.network.onprem", Name: "onprem", Type: resources.TypeNetwork}}
+			h := resources.LifecycleHook{Command: []string{"kubectl", "get", "pods"}, Target: fmt.Sprintf("resource.%s.dev", typ)}
+
+			err := e.runHook(context.Background(), n, h, "after_create_0")
+			require.NoError(t, err)
+
+			ct.AssertCalled(t, "FindContainerIDs", server)
+			ct.AssertCalled(t, "ExecuteCommand", "def456", []string{"kubectl", "get", "pods"}, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestHookTimeoutCancelsCommand(t *testing.T) {
+	e, _, _, ct := setupHookTests(t, nil, nil)
+
+	bt := &blockingTasks{MockContainerTasks: ct, cancelled: make(chan struct{})}
+	e.clients.ContainerTasks = bt
+
+	n := &resources.Network{ResourceMetadata: types.ResourceMetadata{ID: "resource.network.onprem", Name: "onprem", Type: resources.TypeNetwork}}
+	h := resources.LifecycleHook{Command: []string{"sleep", "60"}, Target: "resource.container.consul", Timeout: "10ms"}
+
+	err := e.runHook(context.Background(), n, h, "after_create_0")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "timed out after 10ms")
+
+	select {
+	case <-bt.cancelled:
+	default:
+		t.Fatal("command was not cancelled")
+	}
+}
+
+// blockingTasks runs commands until the context is cancelled
+type blockingTasks struct {
+	*clients.MockContainerTasks
+	cancelled chan struct{}
+}
+
+func (b *blockingTasks) ExecuteCommand(ctx context.Context, id string, command []string, env []string, workingDirectory string, user, group string, writer io.Writer) error {
+	<-ctx.Done()
+	close(b.cancelled)
+
+	return ctx.Err()
+}
+
+func TestLifecycleDoesNotChangeChecksum(t *testing.T) {
+	n := &resources.Network{Subnet: "10.6.0.0/16"}
+
+	c1, err := resourceChecksum(n)
+	require.NoError(t, err)
+
+	n.Lifecycle = &resources.Lifecycle{
+		BeforeCreate: []resources.LifecycleHook{{Command: []string{"ls"}}},
+	}
+
+	c2, err := resourceChecksum(n)
+	require.NoError(t, err)
+
+	require.Equal(t, c1, c2)
+}
@@ -0,0 +1,225 @@
+package shipyard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	dtypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/shipyard/constants"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/shipyard-run/hclconfig/types"
+)
+
+// Import adds an existing Docker container, network or volume to the state
+// as the resource with the given id. ref is the Docker id or name of the
+// object to import. Once imported the resource is managed by jumppad as if
+// it had been created by jumppad.
+func (e *EngineImpl) Import(id, ref string) (types.Resource, error) {
+	fqrn, err := types.ParseFQRN(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource id %s: %s", id, err)
+	}
+
+	if fqrn.Attribute != "" || fqrn.Resource == "" {
+		return nil, fmt.Errorf("invalid resource id %s, the id must reference a resource", id)
+	}
+
+	e.log.Info("Importing resource", "ref", id, "docker", ref)
+
+	// lock the state so that other processes can not modify it while
+	// the resource is imported
+	unlock, err := resources.LockState()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// the state may not exist yet
+	c, err := resources.LoadState()
+	if err != nil {
+		e.log.Debug("unable to load state", "error", err)
+	}
+
+	if _, err := c.FindResource(fqrn.String()); err == nil {
+		return nil, fmt.Errorf("resource %s already exists in the state", fqrn.String())
+	}
+
+	var r types.Resource
+
+	switch fqrn.Type {
+	case resources.TypeContainer:
+		r, err = e.importContainer(ref)
+	case resources.TypeNetwork:
+		r, err = e.importNetwork(ref, fqrn.Resource)
+	case resources.TypeVolume:
+		r, err = e.importVolume(ref)
+	default:
+		return nil, fmt.Errorf("unable to import %s, only %s, %s and %s resources can be imported", id, resources.TypeContainer, resources.TypeNetwork, resources.TypeVolume)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.Metadata().ID = fqrn.String()
+	r.Metadata().Name = fqrn.Resource
+	r.Metadata().Type = fqrn.Type
+	r.Metadata().Module = fqrn.Module
+
+	// no checksum is set, the next apply adopts the configuration for the
+	// resource without re-creating it
+	r.Metadata().Properties[constants.PropertyStatus] = constants.StatusCreated
+
+	err = c.AppendResource(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to add resource %s to the state: %s", fqrn.String(), err)
+	}
+
+	err = resources.SaveState(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// importContainer creates a Container resource from an existing Docker
+// container
+func (e *EngineImpl) importContainer(ref string) (types.Resource, error) {
+	info, err := e.clients.ContainerTasks.ContainerInfo(ref)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find container %s: %s", ref, err)
+	}
+
+	cj, ok := info.(dtypes.ContainerJSON)
+	if !ok || cj.ContainerJSONBase == nil || cj.Config == nil {
+		return nil, fmt.Errorf("unable to read information for container %s", ref)
+	}
+
+	c := &resources.Container{
+		ResourceMetadata: types.ResourceMetadata{Properties: map[string]interface{}{}},
+		// the container keeps its existing name so that it can be found when
+		// it is destroyed
+		FQRN:       strings.TrimPrefix(cj.Name, "/"),
+		Image:      &resources.Image{Name: cj.Config.Image},
+		Entrypoint: cj.Config.Entrypoint,
+		Command:    cj.Config.Cmd,
+	}
+
+	if len(cj.Config.Env) > 0 {
+		c.Environment = map[string]string{}
+
+		for _, env := range cj.Config.Env {
+			parts := strings.SplitN(env, "=", 2)
+			if len(parts) == 2 {
+				c.Environment[parts[0]] = parts[1]
+			}
+		}
+	}
+
+	for _, m := range cj.Mounts {
+		v := resources.Volume{
+			Source:      m.Source,
+			Destination: m.Destination,
+			Type:        string(m.Type),
+			ReadOnly:    !m.RW,
+		}
+
+		// volumes are referenced by name not by their path on the host
+		if v.Type == "volume" {
+			v.Source = m.Name
+		}
+
+		c.Volumes = append(c.Volumes, v)
+	}
+
+	if hc := cj.HostConfig; hc != nil {
+		for p, bindings := range hc.PortBindings {
+			for _, b := range bindings {
+				c.Ports = append(c.Ports, resources.Port{
+					Local:    p.Port(),
+					Host:     b.HostPort,
+					Protocol: p.Proto(),
+				})
+			}
+		}
+
+		c.DNS = hc.DNS
+		c.Privileged = hc.Privileged
+		c.MaxRestartCount = hc.RestartPolicy.MaximumRetryCount
+
+		// docker specifies memory in bytes, jumppad megabytes
+		if hc.Memory > 0 || hc.CPUQuota > 0 {
+			c.Resources = &resources.Resources{
+				Memory: int(hc.Memory / 1000000),
+				CPU:    int(hc.CPUQuota / 100),
+			}
+		}
+	}
+
+	for _, n := range e.clients.ContainerTasks.ListNetworks(cj.ID) {
+		// the default Docker networks can not be managed as resources
+		if n.Name == "bridge" || n.Name == "host" || n.Name == "none" {
+			continue
+		}
+
+		// networks created by jumppad are labeled with the resource id,
+		// any other network is expected to be imported with its name
+		if n.ID == "" {
+			n.ID = fmt.Sprintf("resource.%s.%s", resources.TypeNetwork, n.Name)
+		}
+
+		c.Networks = append(c.Networks, n)
+	}
+
+	return c, nil
+}
+
+// importNetwork creates a Network resource from an existing Docker network
+func (e *EngineImpl) importNetwork(ref, name string) (types.Resource, error) {
+	n, err := e.clients.Docker.NetworkInspect(context.Background(), ref, dtypes.NetworkInspectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to find network %s: %s", ref, err)
+	}
+
+	// networks are managed using the name of the resource and the workspace
+	if n.Name != utils.FQDNNetworkName(name) {
+		return nil, fmt.Errorf("unable to import network %s, the Docker network %s must be named %s", ref, n.Name, utils.FQDNNetworkName(name))
+	}
+
+	nw := &resources.Network{
+		ResourceMetadata: types.ResourceMetadata{Properties: map[string]interface{}{}},
+	}
+
+	if len(n.IPAM.Config) > 0 {
+		nw.Subnet = n.IPAM.Config[0].Subnet
+	}
+
+	return nw, nil
+}
+
+// importVolume creates a Volume resource from an existing Docker volume
+func (e *EngineImpl) importVolume(ref string) (types.Resource, error) {
+	args := filters.NewArgs()
+	args.Add("name", ref)
+
+	vols, err := e.clients.Docker.VolumeList(context.Background(), args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list volumes: %s", err)
+	}
+
+	// Docker matches volume names by prefix, only use the exact match
+	for _, v := range vols.Volumes {
+		if v.Name == ref {
+			return &resources.DockerVolume{
+				ResourceMetadata: types.ResourceMetadata{Properties: map[string]interface{}{}},
+				VolumeName:       v.Name,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unable to find volume %s", ref)
+}
@@ -0,0 +1,186 @@
+package shipyard
+
+import (
+	"fmt"
+	"testing"
+
+	dtypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/go-connections/nat"
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	clientmocks "github.com/jumppad-labs/jumppad/pkg/clients/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/shipyard/constants"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupImportTests(t *testing.T) (*EngineImpl, *clients.MockContainerTasks, *clientmocks.MockDocker) {
+	e, _ := setupTests(t, nil)
+
+	ct := &clients.MockContainerTasks{}
+	dc := &clientmocks.MockDocker{}
+
+	e.clients.ContainerTasks = ct
+	e.clients.Docker = dc
+
+	info := dtypes.ContainerJSON{
+		ContainerJSONBase: &dtypes.ContainerJSONBase{
+			ID:   "abc123",
+			Name: "/db",
+			HostConfig: &container.HostConfig{
+				PortBindings: nat.PortMap{
+					"5432/tcp": []nat.PortBinding{{HostPort: "15432"}},
+				},
+				Privileged: true,
+			},
+		},
+		Config: &container.Config{
+			Image: "postgres:14",
+			Env:   []string{"POSTGRES_PASSWORD=secret"},
+		},
+		Mounts: []dtypes.MountPoint{
+			{Type: mount.TypeVolume, Name: "data", Source: "/var/lib/docker/volumes/data/_data", Destination: "/var/lib/postgresql/data", RW: true},
+		},
+	}
+
+	ct.On("ContainerInfo", "db").Return(info, nil)
+	ct.On("ContainerInfo", mock.Anything).Return(nil, fmt.Errorf("not found"))
+	ct.On("ListNetworks", "abc123").Return([]resources.NetworkAttachment{
+		{ID: "resource.network.local", Name: "local", AssignedAddress: "10.6.0.2"},
+		{Name: "bridge"},
+		{Name: "other"},
+	})
+
+	dc.On("NetworkInspect", mock.Anything, "local", mock.Anything).Return(
+		dtypes.NetworkResource{
+			Name: "local",
+			IPAM: network.IPAM{Config: []network.IPAMConfig{{Subnet: "10.6.0.0/16"}}},
+		},
+		nil,
+	)
+
+	dc.On("VolumeList", mock.Anything, mock.Anything).Return(
+		volume.VolumeListOKBody{Volumes: []*dtypes.Volume{{Name: "data-backup"}, {Name: "data"}}},
+		nil,
+	)
+
+	return e, ct, dc
+}
+
+func TestImportContainerAddsResourceToState(t *testing.T) {
+	e, _, _ := setupImportTests(t)
+
+	r, err := e.Import("resource.container.db", "db")
+	require.NoError(t, err)
+	require.Equal(t, "resource.container.db", r.Metadata().ID)
+
+	c, err := resources.LoadState()
+	require.NoError(t, err)
+
+	sr, err := c.FindResource("resource.container.db")
+	require.NoError(t, err)
+
+	cont := sr.(*resources.Container)
+	require.Equal(t, "db", cont.Name)
+	require.Equal(t, constants.StatusCreated, cont.Properties[constants.PropertyStatus])
+	require.Equal(t, "db", cont.FQRN)
+	require.Equal(t, "postgres:14", cont.Image.Name)
+	require.Equal(t, "secret", cont.Environment["POSTGRES_PASSWORD"])
+	require.True(t, cont.Privileged)
+
+	require.Len(t, cont.Ports, 1)
+	require.Equal(t, "5432", cont.Ports[0].Local)
+	require.Equal(t, "15432", cont.Ports[0].Host)
+	require.Equal(t, "tcp", cont.Ports[0].Protocol)
+
+	require.Len(t, cont.Volumes, 1)
+	require.Equal(t, "data", cont.Volumes[0].Source)
+	require.Equal(t, "volume", cont.Volumes[0].Type)
+	require.False(t, cont.Volumes[0].ReadOnly)
+
+	require.Len(t, cont.Networks, 2)
+	require.Equal(t, "resource.network.local", cont.Networks[0].ID)
+	require.Equal(t, "10.6.0.2", cont.Networks[0].AssignedAddress)
+	require.Equal(t, "resource.network.other", cont.Networks[1].ID)
+}
+
+func TestImportContainerInModuleSetsModule(t *testing.T) {
+	e, _, _ := setupImportTests(t)
+
+	r, err := e.Import("module.app.resource.container.db", "db")
+	require.NoError(t, err)
+	require.Equal(t, "app", r.Metadata().Module)
+	require.Equal(t, "module.app.resource.container.db", r.Metadata().ID)
+}
+
+func TestImportUnknownContainerReturnsError(t *testing.T) {
+	e, _, _ := setupImportTests(t)
+
+	_, err := e.Import("resource.container.db", "missing")
+	require.Error(t, err)
+}
+
+func TestImportExistingResourceReturnsError(t *testing.T) {
+	e, _, _ := setupImportTests(t)
+
+	_, err := e.Import("resource.container.db", "db")
+	require.NoError(t, err)
+
+	_, err = e.Import("resource.container.db", "db")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already exists")
+}
+
+func TestImportUnsupportedTypeReturnsError(t *testing.T) {
+	e, _, _ := setupImportTests(t)
+
+	_, err := e.Import("resource.k8s_cluster.k3s", "db")
+	require.Error(t, err)
+}
+
+func TestImportNetworkAddsResourceToState(t *testing.T) {
+	e, _, _ := setupImportTests(t)
+
+	_, err := e.Import("resource.network.local", "local")
+	require.NoError(t, err)
+
+	c, err := resources.LoadState()
+	require.NoError(t, err)
+
+	sr, err := c.FindResource("resource.network.local")
+	require.NoError(t, err)
+	require.Equal(t, "10.6.0.0/16", sr.(*resources.Network).Subnet)
+	require.Equal(t, constants.StatusCreated, sr.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestImportNetworkWithDifferentNameReturnsError(t *testing.T) {
+	e, _, _ := setupImportTests(t)
+
+	_, err := e.Import("resource.network.cloud", "local")
+	require.Error(t, err)
+}
+
+func TestImportVolumeAddsResourceToState(t *testing.T) {
+	e, _, _ := setupImportTests(t)
+
+	_, err := e.Import("resource.volume.data", "data")
+	require.NoError(t, err)
+
+	c, err := resources.LoadState()
+	require.NoError(t, err)
+
+	sr, err := c.FindResource("resource.volume.data")
+	require.NoError(t, err)
+	require.Equal(t, "data", sr.(*resources.DockerVolume).VolumeName)
+}
+
+func TestImportUnknownVolumeReturnsError(t *testing.T) {
+	e, _, _ := setupImportTests(t)
+
+	_, err := e.Import("resource.volume.data", "dat")
+	require.Error(t, err)
+}
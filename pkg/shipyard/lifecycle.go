@@ -0,0 +1,184 @@
+package shipyard
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/providers"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/shipyard-run/hclconfig/types"
+)
+
+// checkPreventDestroy returns an error when the resource has the lifecycle
+// prevent_destroy set and the engine has not been forced
+func (e *EngineImpl) checkPreventDestroy(r types.Resource) error {
+	l := resources.LifecycleForResource(r)
+	if l == nil || !l.PreventDestroy {
+		return nil
+	}
+
+	if e.options.Force {
+		e.log.Warn("Resource has prevent_destroy set, forcing destroy", "ref", r.Metadata().ID)
+		return nil
+	}
+
+	return preventDestroyError{r.Metadata().ID}
+}
+
+// preventDestroyError is returned when a resource is not destroyed because
+// it has the lifecycle prevent_destroy set
+type preventDestroyError struct {
+	id string
+}
+
+func (p preventDestroyError) Error() string {
+	return fmt.Sprintf("resource %s has lifecycle prevent_destroy set and can not be destroyed or re-created, use --force to override", p.id)
+}
+
+// replaceResource re-creates a container or sidecar, the replacement is
+// created before the existing container is removed. The existing container
+// is renamed so that the replacement can use its name, should creating the
+// replacement fail the existing container is restored and true is returned.
+//
+// The replacement and the existing container run at the same time, the
+// replacement can not be created when the container binds ports on the host.
+func (e *EngineImpl) replaceResource(ctx context.Context, p providers.Provider, r types.Resource) (bool, error) {
+	switch r.Metadata().Type {
+	case resources.TypeContainer, resources.TypeSidecar:
+	default:
+		e.log.Warn("Resource does not support create_before_destroy, destroying before create", "ref", r.Metadata().ID)
+
+		err := e.destroyWithHooks(ctx, p, r)
+		if err != nil {
+			return false, err
+		}
+
+		return false, e.createWithHooks(ctx, p, r)
+	}
+
+	fqdn := utils.FQDN(r.Metadata().Name, r.Metadata().Module, r.Metadata().Type)
+
+	ids, err := e.clients.ContainerTasks.FindContainerIDs(fqdn)
+	if err != nil {
+		return false, fmt.Errorf("unable to find existing container for resource %s: %s", r.Metadata().ID, err)
+	}
+
+	// nothing to replace
+	if len(ids) == 0 {
+		return false, e.createWithHooks(ctx, p, r)
+	}
+
+	if len(ids) > 1 {
+		return true, fmt.Errorf("unable to replace resource %s, found %d containers with the name %s", r.Metadata().ID, len(ids), fqdn)
+	}
+
+	// run the hooks while the existing container can still be targeted
+	err = e.runHooks(ctx, r, hookBeforeDestroy)
+	if err != nil {
+		return true, err
+	}
+
+	deposed := fmt.Sprintf("%s-deposed", fqdn)
+
+	e.log.Info("Creating replacement before destroying resource", "ref", r.Metadata().ID, "existing", ids[0])
+
+	err = e.clients.ContainerTasks.RenameContainer(ids[0], deposed)
+	if err != nil {
+		return true, fmt.Errorf("unable to rename existing container for resource %s: %s", r.Metadata().ID, err)
+	}
+
+	err = e.createWithHooks(ctx, p, r)
+	if err != nil {
+		e.log.Error("Unable to create replacement, restoring existing resource", "ref", r.Metadata().ID, "error", err)
+
+		// the context may have been cancelled
+		if derr := p.Destroy(context.Background()); derr != nil {
+			e.log.Error("Unable to remove replacement", "ref", r.Metadata().ID, "error", derr)
+		}
+
+		if rerr := e.clients.ContainerTasks.RenameContainer(ids[0], fqdn); rerr != nil {
+			e.log.Error("Unable to restore existing container", "ref", r.Metadata().ID, "container", ids[0], "error", rerr)
+			return false, err
+		}
+
+		return true, err
+	}
+
+	err = e.clients.ContainerTasks.RemoveContainer(ids[0], false)
+	if err != nil {
+		return false, fmt.Errorf("unable to remove replaced container %s for resource %s: %s", deposed, r.Metadata().ID, err)
+	}
+
+	return false, e.runHooks(ctx, r, hookAfterDestroy)
+}
+
+// waitForHealthyDependencies re-runs the health checks of the dependencies
+// of the resource which set lifecycle wait_for_healthy, dependencies which
+// have been created or checked during the current apply are not checked again
+func (e *EngineImpl) waitForHealthyDependencies(ctx context.Context, r types.Resource) error {
+	for _, d := range e.healthGatedDependencies(r) {
+		if e.isHealthy(d) {
+			continue
+		}
+
+		hc, ok := e.getProvider(d, e.clients).(providers.HealthChecker)
+		if !ok {
+			return fmt.Errorf("dependency %s has lifecycle wait_for_healthy set but %s resources do not support health checks", d.Metadata().ID, d.Metadata().Type)
+		}
+
+		e.log.Info("Waiting for dependency to be healthy", "ref", r.Metadata().ID, "dependency", d.Metadata().ID)
+
+		err := hc.HealthCheck(ctx)
+		if err != nil {
+			return fmt.Errorf("dependency %s is not healthy: %s", d.Metadata().ID, err)
+		}
+
+		e.setHealthy(d)
+	}
+
+	return nil
+}
+
+// healthGatedDependencies returns the dependencies of the resource from the
+// state which have lifecycle wait_for_healthy set
+func (e *EngineImpl) healthGatedDependencies(r types.Resource) []types.Resource {
+	e.stateLock.Lock()
+	defer e.stateLock.Unlock()
+
+	deps := []types.Resource{}
+	if e.config == nil {
+		return deps
+	}
+
+	for _, d := range e.config.Resources {
+		l := resources.LifecycleForResource(d)
+		if l == nil || !l.WaitForHealthy {
+			continue
+		}
+
+		if findDependency(r, map[string]bool{d.Metadata().ID: true}) != "" {
+			deps = append(deps, d)
+		}
+	}
+
+	return deps
+}
+
+func (e *EngineImpl) setHealthy(r types.Resource) {
+	e.healthyLock.Lock()
+	defer e.healthyLock.Unlock()
+
+	if e.healthy == nil {
+		e.healthy = map[string]bool{}
+	}
+
+	e.healthy[r.Metadata().ID] = true
+}
+
+func (e *EngineImpl) isHealthy(r types.Resource) bool {
+	e.healthyLock.Lock()
+	defer e.healthyLock.Unlock()
+
+	return e.healthy[r.Metadata().ID]
+}
@@ -0,0 +1,282 @@
+package shipyard
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/providers"
+	"github.com/jumppad-labs/jumppad/pkg/providers/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/shipyard/constants"
+	"github.com/shipyard-run/hclconfig/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var lifecycleOptionsPath = "../../examples/lifecycle_options"
+
+func setupLifecycleTests(t *testing.T) (*EngineImpl, *[]*mocks.MockProvider, *clients.MockContainerTasks) {
+	e, mp := setupTests(t, nil)
+
+	ct := &clients.MockContainerTasks{}
+	ct.On("FindContainerIDs", "consul.container.jumppad.dev").Return([]string{"old"}, nil)
+	ct.On("RenameContainer", mock.Anything, mock.Anything).Return(nil)
+	ct.On("RemoveContainer", mock.Anything, mock.Anything).Return(nil)
+
+	e.clients.ContainerTasks = ct
+
+	_, err := e.Apply(context.Background(), lifecycleOptionsPath)
+	require.NoError(t, err)
+
+	*mp = []*mocks.MockProvider{}
+
+	return e, mp, ct
+}
+
+func TestDestroyWithPreventDestroyReturnsErrorAndKeepsResource(t *testing.T) {
+	e, mp, _ := setupLifecycleTests(t)
+
+	err := e.Destroy(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "prevent_destroy")
+
+	d := getProviderForResource(t, mp, "consul")
+	require.Len(t, callsFor(d, "Destroy"), 1)
+
+	// the network is kept as the database depends on it
+	for _, m := range *mp {
+		if m.Config().Metadata().Name == "database" || m.Config().Metadata().Name == "onprem" {
+			require.Len(t, callsFor(m, "Destroy"), 0)
+		}
+	}
+
+	sf := testLoadState(t, e)
+
+	r, err := sf.FindResource("resource.container.database")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestDestroyWithPreventDestroyAndForceDestroysResource(t *testing.T) {
+	e, mp, _ := setupLifecycleTests(t)
+	e.SetOptions(Options{Force: true})
+
+	err := e.Destroy(context.Background())
+	require.NoError(t, err)
+
+	d := getProviderForResource(t, mp, "database")
+	require.Len(t, callsFor(d, "Destroy"), 1)
+}
+
+func TestApplyWithPreventDestroyDoesNotRecreateChangedResource(t *testing.T) {
+	e, mp, _ := setupLifecycleTests(t)
+
+	sf := testLoadState(t, e)
+	r, err := sf.FindResource("resource.container.database")
+	require.NoError(t, err)
+	checksum := r.Metadata().Properties[constants.PropertyChecksum]
+
+	_, err = e.ApplyWithVariables(context.Background(), lifecycleOptionsPath, map[string]string{"postgres_version": "16.0"}, "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "prevent_destroy")
+
+	d := getProviderForResource(t, mp, "database")
+	require.Len(t, callsFor(d, "Destroy"), 0)
+	require.Len(t, callsFor(d, "Create"), 0)
+
+	// the change is detected again by the next apply
+	sf = testLoadState(t, e)
+	r, err = sf.FindResource("resource.container.database")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+	require.Equal(t, checksum, r.Metadata().Properties[constants.PropertyChecksum])
+}
+
+func TestApplyWithPreventDestroyAndForceRecreatesChangedResource(t *testing.T) {
+	e, mp, _ := setupLifecycleTests(t)
+	e.SetOptions(Options{Force: true})
+
+	_, err := e.ApplyWithVariables(context.Background(), lifecycleOptionsPath, map[string]string{"postgres_version": "16.0"}, "")
+	require.NoError(t, err)
+
+	d := getProviderForResource(t, mp, "database")
+	require.Len(t, callsFor(d, "Destroy"), 1)
+	require.Len(t, callsFor(d, "Create"), 1)
+}
+
+func TestApplyWithIgnoreChangesDoesNotRecreateResource(t *testing.T) {
+	e, mp, ct := setupLifecycleTests(t)
+
+	_, err := e.ApplyWithVariables(context.Background(), lifecycleOptionsPath, map[string]string{"consul_token": "changed"}, "")
+	require.NoError(t, err)
+
+	d := getProviderForResource(t, mp, "consul")
+	require.Len(t, callsFor(d, "Create"), 0)
+	ct.AssertNotCalled(t, "RenameContainer", mock.Anything, mock.Anything)
+}
+
+func TestApplyWithCreateBeforeDestroyCreatesReplacementBeforeRemovingContainer(t *testing.T) {
+	e, mp, ct := setupLifecycleTests(t)
+
+	_, err := e.ApplyWithVariables(context.Background(), lifecycleOptionsPath, map[string]string{"consul_version": "1.15.0"}, "")
+	require.NoError(t, err)
+
+	d := getProviderForResource(t, mp, "consul")
+	require.Len(t, callsFor(d, "Create"), 1)
+	require.Len(t, callsFor(d, "Destroy"), 0)
+
+	ct.AssertCalled(t, "RenameContainer", "old", "consul.container.jumppad.dev-deposed")
+	ct.AssertCalled(t, "RemoveContainer", "old", false)
+	ct.AssertNotCalled(t, "RenameContainer", "old", "consul.container.jumppad.dev")
+}
+
+func TestApplyWithCreateBeforeDestroyRestoresContainerWhenReplacementFails(t *testing.T) {
+	e, mp, ct := setupLifecycleTests(t)
+
+	gp := e.getProvider
+	e.getProvider = func(c types.Resource, cc *clients.Clients) providers.Provider {
+		m := gp(c, cc).(*mocks.MockProvider)
+		if c.Metadata().Name == "consul" {
+			m.ExpectedCalls = nil
+			m.On("Refresh").Return(nil)
+			m.On("Create").Return(fmt.Errorf("boom"))
+			m.On("Destroy").Return(nil)
+		}
+
+		return m
+	}
+
+	_, err := e.ApplyWithVariables(context.Background(), lifecycleOptionsPath, map[string]string{"consul_version": "1.15.0"}, "")
+	require.Error(t, err)
+
+	// the failed replacement is removed
+	d := getProviderForResource(t, mp, "consul")
+	require.Len(t, callsFor(d, "Destroy"), 1)
+
+	ct.AssertCalled(t, "RenameContainer", "old", "consul.container.jumppad.dev")
+	ct.AssertNotCalled(t, "RemoveContainer", "old", false)
+
+	sf := testLoadState(t, e)
+	r, err := sf.FindResource("resource.container.consul")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestChecksumChangedUsesStateWhenIgnoreChangesAdded(t *testing.T) {
+	sr := &resources.Container{
+		ResourceMetadata: types.ResourceMetadata{Properties: map[string]interface{}{}},
+		Command:          []string{"consul"},
+		Environment:      map[string]string{"TOKEN": "abc"},
+	}
+
+	sc, err := resourceChecksum(sr)
+	require.NoError(t, err)
+	sr.Properties[constants.PropertyChecksum] = sc
+
+	r := &resources.Container{
+		Command:     []string{"consul"},
+		Environment: map[string]string{"TOKEN": "123"},
+	}
+
+	c, err := resourceChecksum(r)
+	require.NoError(t, err)
+	require.True(t, checksumChanged(r, sr, c))
+
+	r.Lifecycle = &resources.Lifecycle{IgnoreChanges: []string{"environment.TOKEN"}}
+
+	c, err = resourceChecksum(r)
+	require.NoError(t, err)
+	require.False(t, checksumChanged(r, sr, c))
+
+	r.Command = []string{"nomad"}
+
+	c, err = resourceChecksum(r)
+	require.NoError(t, err)
+	require.True(t, checksumChanged(r, sr, c))
+}
+
+var waitForHealthyPath = "../../examples/wait_for_healthy"
+
+// healthChecksFor returns the number of times the health check has been run
+// for the named resource
+func healthChecksFor(mp *[]*mocks.MockProvider, name string) int {
+	n := 0
+	for _, m := range *mp {
+		if m.Config().Metadata().Name == name {
+			n += len(callsFor(m, "HealthCheck"))
+		}
+	}
+
+	return n
+}
+
+func TestApplyDoesNotRecheckHealthOfDependencyCreatedInSameApply(t *testing.T) {
+	e, mp := setupTests(t, nil)
+
+	_, err := e.Apply(context.Background(), waitForHealthyPath)
+	require.NoError(t, err)
+
+	require.Equal(t, 0, healthChecksFor(mp, "database"))
+
+	d := getProviderForResource(t, mp, "worker")
+	require.Len(t, callsFor(d, "Create"), 1)
+}
+
+func TestApplyRechecksHealthOfUnchangedDependency(t *testing.T) {
+	e, mp := setupTests(t, nil)
+
+	_, err := e.Apply(context.Background(), waitForHealthyPath)
+	require.NoError(t, err)
+
+	*mp = []*mocks.MockProvider{}
+
+	_, err = e.ApplyWithVariables(context.Background(), waitForHealthyPath, map[string]string{"app_version": "2.0.0"}, "")
+	require.NoError(t, err)
+
+	require.Equal(t, 1, healthChecksFor(mp, "database"))
+
+	d := getProviderForResource(t, mp, "database")
+	require.Len(t, callsFor(d, "Create"), 0)
+
+	a := getProviderForResource(t, mp, "app")
+	require.Len(t, callsFor(a, "Create"), 1)
+}
+
+func TestApplyWithUnhealthyDependencyDoesNotCreateDependent(t *testing.T) {
+	e, mp := setupTests(t, nil)
+
+	_, err := e.Apply(context.Background(), waitForHealthyPath)
+	require.NoError(t, err)
+
+	*mp = []*mocks.MockProvider{}
+
+	// the health check for the database fails
+	gp := e.getProvider
+	e.getProvider = func(c types.Resource, cl *clients.Clients) providers.Provider {
+		p := gp(c, cl).(*mocks.MockProvider)
+		if c.Metadata().Name == "database" {
+			for _, ec := range p.ExpectedCalls {
+				if ec.Method == "HealthCheck" {
+					ec.ReturnArguments = mock.Arguments{fmt.Errorf("boom")}
+				}
+			}
+		}
+
+		return p
+	}
+
+	_, err = e.ApplyWithVariables(context.Background(), waitForHealthyPath, map[string]string{"app_version": "2.0.0"}, "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "resource.container.database is not healthy")
+
+	a := getProviderForResource(t, mp, "app")
+	require.Len(t, callsFor(a, "Create"), 0)
+
+	sf := testLoadState(t, e)
+
+	r, err := sf.FindResource("resource.container.app")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusFailed, r.Metadata().Properties[constants.PropertyStatus])
+}
@@ -3,6 +3,9 @@
 package mocks
 
 import (
+	context "context"
+
+	clients "github.com/jumppad-labs/jumppad/pkg/clients"
 	resources "github.com/jumppad-labs/jumppad/pkg/config/resources"
 	shipyard "github.com/jumppad-labs/jumppad/pkg/shipyard"
 	mock "github.com/stretchr/testify/mock"
@@ -15,25 +18,25 @@ type Engine struct {
 	mock.Mock
 }
 
-// Apply provides a mock function with given fields: _a0
-func (_m *Engine) Apply(_a0 string) ([]types.Resource, error) {
-	ret := _m.Called(_a0)
+// Apply provides a mock function with given fields: _a0, _a1
+func (_m *Engine) Apply(_a0 context.Context, _a1 string) ([]types.Resource, error) {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 []types.Resource
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) ([]types.Resource, error)); ok {
-		return rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]types.Resource, error)); ok {
+		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(string) []types.Resource); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, string) []types.Resource); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]types.Resource)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -41,25 +44,25 @@ func (_m *Engine) Apply(_a0 string) ([]types.Resource, error) {
 	return r0, r1
 }
 
-// ApplyWithVariables provides a mock function with given fields: path, variables, variablesFile
-func (_m *Engine) ApplyWithVariables(path string, variables map[string]string, variablesFile string) ([]types.Resource, error) {
-	ret := _m.Called(path, variables, variablesFile)
+// ApplyWithVariables provides a mock function with given fields: ctx, path, variables, variablesFile
+func (_m *Engine) ApplyWithVariables(ctx context.Context, path string, variables map[string]string, variablesFile string) ([]types.Resource, error) {
+	ret := _m.Called(ctx, path, variables, variablesFile)
 
 	var r0 []types.Resource
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, map[string]string, string) ([]types.Resource, error)); ok {
-		return rf(path, variables, variablesFile)
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]string, string) ([]types.Resource, error)); ok {
+		return rf(ctx, path, variables, variablesFile)
 	}
-	if rf, ok := ret.Get(0).(func(string, map[string]string, string) []types.Resource); ok {
-		r0 = rf(path, variables, variablesFile)
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]string, string) []types.Resource); ok {
+		r0 = rf(ctx, path, variables, variablesFile)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]types.Resource)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, map[string]string, string) error); ok {
-		r1 = rf(path, variables, variablesFile)
+	if rf, ok := ret.Get(1).(func(context.Context, string, map[string]string, string) error); ok {
+		r1 = rf(ctx, path, variables, variablesFile)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -83,13 +86,13 @@ func (_m *Engine) Blueprint() *resources.Blueprint {
 	return r0
 }
 
-// Destroy provides a mock function with given fields:
-func (_m *Engine) Destroy() error {
-	ret := _m.Called()
+// Destroy provides a mock function with given fields: _a0
+func (_m *Engine) Destroy(_a0 context.Context) error {
+	ret := _m.Called(_a0)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func() error); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(_a0)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -98,47 +101,123 @@ func (_m *Engine) Destroy() error {
 }
 
 // GetClients provides a mock function with given fields:
-func (_m *Engine) GetClients() *shipyard.Clients {
+func (_m *Engine) GetClients() *clients.Clients {
 	ret := _m.Called()
 
-	var r0 *shipyard.Clients
-	if rf, ok := ret.Get(0).(func() *shipyard.Clients); ok {
+	var r0 *clients.Clients
+	if rf, ok := ret.Get(0).(func() *clients.Clients); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*shipyard.Clients)
+			r0 = ret.Get(0).(*clients.Clients)
 		}
 	}
 
 	return r0
 }
 
+// Import provides a mock function with given fields: id, ref
+func (_m *Engine) Import(id string, ref string) (types.Resource, error) {
+	ret := _m.Called(id, ref)
+
+	var r0 types.Resource
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (types.Resource, error)); ok {
+		return rf(id, ref)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) types.Resource); ok {
+		r0 = rf(id, ref)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(types.Resource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(id, ref)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ParseConfig provides a mock function with given fields: _a0
-func (_m *Engine) ParseConfig(_a0 string) error {
+func (_m *Engine) ParseConfig(_a0 string) ([]types.Resource, error) {
 	ret := _m.Called(_a0)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
+	var r0 []types.Resource
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]types.Resource, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) []types.Resource); ok {
 		r0 = rf(_a0)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Resource)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // ParseConfigWithVariables provides a mock function with given fields: _a0, _a1, _a2
-func (_m *Engine) ParseConfigWithVariables(_a0 string, _a1 map[string]string, _a2 string) error {
+func (_m *Engine) ParseConfigWithVariables(_a0 string, _a1 map[string]string, _a2 string) ([]types.Resource, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, map[string]string, string) error); ok {
+	var r0 []types.Resource
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, map[string]string, string) ([]types.Resource, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(string, map[string]string, string) []types.Resource); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Resource)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(string, map[string]string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Refresh provides a mock function with given fields:
+func (_m *Engine) Refresh() ([]shipyard.Drift, error) {
+	ret := _m.Called()
+
+	var r0 []shipyard.Drift
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]shipyard.Drift, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []shipyard.Drift); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]shipyard.Drift)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // ResourceCount provides a mock function with given fields:
@@ -169,6 +248,70 @@ func (_m *Engine) ResourceCountForType(_a0 string) int {
 	return r0
 }
 
+// Rollback provides a mock function with given fields: ctx, version
+func (_m *Engine) Rollback(ctx context.Context, version int) ([]types.Resource, error) {
+	ret := _m.Called(ctx, version)
+
+	var r0 []types.Resource
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]types.Resource, error)); ok {
+		return rf(ctx, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []types.Resource); ok {
+		r0 = rf(ctx, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Resource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Subscribe provides a mock function with given fields:
+func (_m *Engine) Subscribe() (<-chan shipyard.Event, func()) {
+	ret := _m.Called()
+
+	var r0 <-chan shipyard.Event
+	var r1 func()
+	if rf, ok := ret.Get(0).(func() (<-chan shipyard.Event, func())); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() <-chan shipyard.Event); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan shipyard.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() func()); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(func())
+		}
+	}
+
+	return r0, r1
+}
+
+// SetOptions provides a mock function with given fields: _a0
+func (_m *Engine) SetOptions(_a0 shipyard.Options) {
+	_m.Called(_a0)
+}
+
+// Close provides a mock function with given fields:
+func (_m *Engine) Close() {
+	_m.Called()
+}
+
 type mockConstructorTestingTNewEngine interface {
 	mock.TestingT
 	Cleanup(func())
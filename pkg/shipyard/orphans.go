@@ -0,0 +1,70 @@
+package shipyard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shipyard-run/hclconfig/types"
+)
+
+// DestroyError is returned when one or more resources could not be
+// destroyed, the orphaned resources remain in the state
+type DestroyError struct {
+	Orphans []Orphan
+	Errors  []string
+}
+
+func (e DestroyError) Error() string {
+	return fmt.Sprintf("error trying to call Destroy on provider: %s", strings.Join(e.Errors, "\n"))
+}
+
+// Orphan is a resource which was not destroyed
+type Orphan struct {
+	Resource     string
+	ResourceType string
+
+	// IDs of the objects which still exist for the resource, for example
+	// Docker containers, networks or volumes
+	IDs []string
+
+	// Reason the resource was not destroyed
+	Reason string
+}
+
+// newDestroyError returns a DestroyError for the resources which were not
+// destroyed, the objects which still exist are looked up from the provider
+// of each resource
+func (e *EngineImpl) newDestroyError(res []types.Resource, reasons map[string]string, errs []string) DestroyError {
+	de := DestroyError{Errors: errs}
+
+	for _, r := range res {
+		reason, ok := reasons[r.Metadata().ID]
+		if !ok {
+			continue
+		}
+
+		o := Orphan{
+			Resource:     r.Metadata().ID,
+			ResourceType: r.Metadata().Type,
+			Reason:       reason,
+		}
+
+		if p := e.getProvider(r, e.clients); p != nil {
+			ids, err := p.Lookup()
+			if err != nil {
+				e.log.Debug("Unable to lookup objects for resource", "ref", r.Metadata().ID, "error", err)
+			}
+
+			o.IDs = ids
+		}
+
+		de.Orphans = append(de.Orphans, o)
+	}
+
+	sort.Slice(de.Orphans, func(i, j int) bool {
+		return de.Orphans[i].Resource < de.Orphans[j].Resource
+	})
+
+	return de
+}
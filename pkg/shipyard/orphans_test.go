@@ -0,0 +1,100 @@
+package shipyard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/providers"
+	"github.com/jumppad-labs/jumppad/pkg/providers/mocks"
+	"github.com/shipyard-run/hclconfig/types"
+	"github.com/stretchr/testify/require"
+)
+
+// setupOrphanTests applies the single file example and fails the destroy
+// of the consul container once the image cache has started to be destroyed
+func setupOrphanTests(t *testing.T) (*EngineImpl, *[]*mocks.MockProvider) {
+	e, mp := setupTests(t, nil)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	*mp = []*mocks.MockProvider{}
+
+	gp := e.getProvider
+	e.getProvider = func(c types.Resource, cc *clients.Clients) providers.Provider {
+		m := gp(c, cc).(*mocks.MockProvider)
+
+		if c.Metadata().Name == "consul" {
+			m.ExpectedCalls = nil
+			m.On("Destroy").Return(fmt.Errorf("boom"))
+			m.On("Lookup").Return([]string{"abc123"}, nil)
+		}
+
+		return m
+	}
+
+	// the image cache is destroyed concurrently with the container, ensure
+	// it has started before the container fails
+	destroyAfterEvent(t, e, "consul", "resource.image_cache.default", EventStarted)
+
+	return e, mp
+}
+
+func TestDestroyReturnsOrphansWhenResourceFails(t *testing.T) {
+	e, mp := setupOrphanTests(t)
+
+	err := e.Destroy(context.Background())
+	require.Error(t, err)
+
+	de := DestroyError{}
+	require.True(t, errors.As(err, &de))
+
+	// the network is not destroyed as the container depends on it
+	n := getProviderForResource(t, mp, "onprem")
+	require.Len(t, callsFor(n, "Destroy"), 0)
+
+	// the container and the network and template it depends on
+	require.Len(t, de.Orphans, 3)
+	require.Equal(t, "resource.container.consul", de.Orphans[0].Resource)
+	require.Equal(t, []string{"abc123"}, de.Orphans[0].IDs)
+	require.Contains(t, de.Orphans[0].Reason, "boom")
+	require.Equal(t, "resource.network.onprem", de.Orphans[1].Resource)
+	require.Equal(t, "dependent failed to destroy", de.Orphans[1].Reason)
+
+	sf := testLoadState(t, e)
+
+	_, err = sf.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+}
+
+func TestDestroyWithContinueOnErrorDestroysDependencies(t *testing.T) {
+	e, mp := setupOrphanTests(t)
+	e.SetOptions(Options{ContinueOnError: true})
+
+	err := e.Destroy(context.Background())
+	require.Error(t, err)
+
+	de := DestroyError{}
+	require.True(t, errors.As(err, &de))
+
+	n := getProviderForResource(t, mp, "onprem")
+	require.Len(t, callsFor(n, "Destroy"), 1)
+
+	require.Len(t, de.Orphans, 1)
+	require.Equal(t, "resource.container.consul", de.Orphans[0].Resource)
+
+	// only the failed resource remains in the state
+	sf := testLoadState(t, e)
+
+	_, err = sf.FindResource("resource.container.consul")
+	require.NoError(t, err)
+
+	_, err = sf.FindResource("resource.network.onprem")
+	require.Error(t, err)
+
+	_, err = sf.FindResource("resource.template.consul_config")
+	require.Error(t, err)
+}
@@ -0,0 +1,160 @@
+package shipyard
+
+import (
+	"fmt"
+
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/shipyard/constants"
+	"github.com/shipyard-run/hclconfig"
+	"github.com/shipyard-run/hclconfig/types"
+)
+
+// PlanAction defines the action that will be taken for a resource
+// when the configuration is applied
+type PlanAction string
+
+const (
+	// PlanActionCreate is set when the resource does not exist and will be created
+	PlanActionCreate PlanAction = "create"
+
+	// PlanActionRefresh is set when the resource exists and will be refreshed
+	PlanActionRefresh PlanAction = "refresh"
+
+	// PlanActionUpdate is set when the configuration for a resource, or one
+	// of its dependencies, has changed and the resource will be re-created
+	PlanActionUpdate PlanAction = "update"
+
+	// PlanActionRecreate is set when the resource is tainted or failed and
+	// will be destroyed before being created
+	PlanActionRecreate PlanAction = "recreate"
+
+	// PlanActionDestroy is set when the resource has been removed from the
+	// configuration or has been disabled
+	PlanActionDestroy PlanAction = "destroy"
+)
+
+// PlanItem defines the action for a single resource
+type PlanItem struct {
+	ID     string     `json:"id"`
+	Type   string     `json:"type"`
+	Action PlanAction `json:"action"`
+	Reason string     `json:"reason,omitempty"`
+}
+
+// Plan is the difference between the current state and the configuration
+type Plan struct {
+	Resources []PlanItem `json:"resources"`
+}
+
+// NewPlan compares the given resources parsed from configuration to the
+// state and returns the actions that will be taken when the configuration
+// is applied.
+func NewPlan(state *hclconfig.Config, config []types.Resource) *Plan {
+	p := &Plan{Resources: []PlanItem{}}
+
+	if state == nil {
+		state = hclconfig.NewConfig()
+	}
+
+	// map of resources in the config used to determine the resources that
+	// have been removed
+	inConfig := map[string]bool{}
+
+	// resources that will be re-created, dependents of these resources are
+	// also re-created
+	recreated := map[string]bool{}
+
+	for _, r := range config {
+		if !planResource(r) {
+			continue
+		}
+
+		inConfig[r.Metadata().ID] = true
+
+		status := ""
+		stateChecksum := ""
+		sr, err := state.FindResource(r.Metadata().ID)
+		if err == nil {
+			status, _ = sr.Metadata().Properties[constants.PropertyStatus].(string)
+			stateChecksum, _ = sr.Metadata().Properties[constants.PropertyChecksum].(string)
+		}
+
+		if r.Metadata().Disabled {
+			// only resources which have been created need to be destroyed
+			if status == constants.StatusCreated || status == constants.StatusFailed {
+				p.add(r, PlanActionDestroy, "disabled")
+			}
+
+			continue
+		}
+
+		switch status {
+		case constants.StatusCreated:
+			checksum, _ := resourceChecksum(r)
+
+			if stateChecksum != "" && checksum != "" && stateChecksum != checksum {
+				p.add(r, PlanActionUpdate, "configuration changed")
+				recreated[r.Metadata().ID] = true
+			} else if dep := findDependency(r, recreated); dep != "" {
+				p.add(r, PlanActionUpdate, fmt.Sprintf("dependency %s re-created", dep))
+				recreated[r.Metadata().ID] = true
+			} else {
+				p.add(r, PlanActionRefresh, "")
+			}
+		case constants.StatusTainted:
+			p.add(r, PlanActionRecreate, "tainted")
+			recreated[r.Metadata().ID] = true
+		case constants.StatusFailed:
+			p.add(r, PlanActionRecreate, "failed")
+			recreated[r.Metadata().ID] = true
+		case constants.StatusCreating, constants.StatusDestroying:
+			p.add(r, PlanActionRecreate, "interrupted")
+			recreated[r.Metadata().ID] = true
+		default:
+			p.add(r, PlanActionCreate, "")
+		}
+	}
+
+	for _, r := range state.Resources {
+		if !planResource(r) || inConfig[r.Metadata().ID] {
+			continue
+		}
+
+		// the image cache is managed by the engine and is never defined
+		// in the configuration
+		if r.Metadata().Type == resources.TypeImageCache {
+			continue
+		}
+
+		p.add(r, PlanActionDestroy, "removed from configuration")
+	}
+
+	return p
+}
+
+// Count returns the number of resources in the plan with the given action
+func (p *Plan) Count(a PlanAction) int {
+	count := 0
+	for _, i := range p.Resources {
+		if i.Action == a {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (p *Plan) add(r types.Resource, a PlanAction, reason string) {
+	p.Resources = append(p.Resources, PlanItem{
+		ID:     r.Metadata().ID,
+		Type:   r.Metadata().Type,
+		Action: a,
+		Reason: reason,
+	})
+}
+
+// modules are not created by a provider and are not part of the plan
+func planResource(r types.Resource) bool {
+	t := r.Metadata().Type
+	return t != types.TypeModule && t != types.TypeRoot && t != types.TypeVariable
+}
@@ -0,0 +1,145 @@
+package shipyard
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/shipyard/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func testPlanItem(t *testing.T, p *Plan, id string) PlanItem {
+	for _, i := range p.Resources {
+		if i.ID == id {
+			return i
+		}
+	}
+
+	require.Failf(t, "resource not found in plan", "id: %s", id)
+	return PlanItem{}
+}
+
+func TestPlanWithNoStateCreatesAllResources(t *testing.T) {
+	e, mp := setupTests(t, nil)
+
+	r, err := e.ParseConfig("../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	p := NewPlan(nil, r)
+
+	require.Len(t, p.Resources, 4)
+	require.Equal(t, 4, p.Count(PlanActionCreate))
+
+	// should not have called any providers
+	testAssertMethodCalled(t, mp, "Create", 0)
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+}
+
+func TestPlanWithStateReturnsActions(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, planState)
+
+	r, err := e.ParseConfig("../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	s, err := resources.LoadState()
+	require.NoError(t, err)
+
+	p := NewPlan(s, r)
+
+	require.Equal(t, PlanActionRecreate, testPlanItem(t, p, "resource.network.onprem").Action)
+	require.Equal(t, "tainted", testPlanItem(t, p, "resource.network.onprem").Reason)
+	require.Equal(t, PlanActionRefresh, testPlanItem(t, p, "resource.template.consul_config").Action)
+	require.Equal(t, PlanActionCreate, testPlanItem(t, p, "resource.container.consul").Action)
+	require.Equal(t, PlanActionCreate, testPlanItem(t, p, "output.consul_addr").Action)
+	require.Equal(t, PlanActionDestroy, testPlanItem(t, p, "resource.network.cloud").Action)
+	require.Equal(t, "removed from configuration", testPlanItem(t, p, "resource.network.cloud").Reason)
+
+	// image cache is managed by the engine and should never be destroyed
+	require.Len(t, p.Resources, 5)
+
+	testAssertMethodCalled(t, mp, "Create", 0)
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+}
+
+func TestPlanWithDisabledResourceReturnsDestroy(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, disabledAndCreatedState)
+
+	r, err := e.ParseConfig("../../examples/disabled")
+	require.NoError(t, err)
+
+	s, err := resources.LoadState()
+	require.NoError(t, err)
+
+	p := NewPlan(s, r)
+
+	require.Equal(t, PlanActionDestroy, testPlanItem(t, p, "resource.container.consul_disabled").Action)
+	require.Equal(t, "disabled", testPlanItem(t, p, "resource.container.consul_disabled").Reason)
+	require.Equal(t, PlanActionCreate, testPlanItem(t, p, "resource.container.consul_enabled").Action)
+
+	// parsing the config must not destroy the disabled resource
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+}
+
+func TestPlanWithChangedResourceReturnsUpdateForDependents(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	// change the checksum for the network to simulate a change in config
+	s, err := resources.LoadState()
+	require.NoError(t, err)
+
+	n, err := s.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+	n.Metadata().Properties[constants.PropertyChecksum] = "changed"
+
+	r, err := e.ParseConfig("../../examples/single_file/container.hcl")
+	require.NoError(t, err)
+
+	p := NewPlan(s, r)
+
+	require.Equal(t, PlanActionUpdate, testPlanItem(t, p, "resource.network.onprem").Action)
+	require.Equal(t, "configuration changed", testPlanItem(t, p, "resource.network.onprem").Reason)
+	require.Equal(t, PlanActionUpdate, testPlanItem(t, p, "resource.container.consul").Action)
+	require.Equal(t, PlanActionUpdate, testPlanItem(t, p, "output.consul_addr").Action)
+	require.Equal(t, PlanActionRefresh, testPlanItem(t, p, "resource.template.consul_config").Action)
+}
+
+var planState = `
+{
+  "resources": [
+	{
+      "name": "cloud",
+      "properties": {
+				"status": "created"
+			},
+      "subnet": "10.15.0.0/16",
+      "type": "network"
+	},
+	{
+      "name": "onprem",
+      "properties": {
+				"status": "tainted"
+			},
+      "subnet": "10.6.0.0/16",
+      "type": "network"
+	},
+	{
+      "name": "default",
+      "properties": {
+				"status": "created"
+			},
+      "type": "image_cache"
+	},
+	{
+      "name": "consul_config",
+      "properties": {
+				"status": "created"
+			},
+      "type": "template"
+	}
+  ]
+}
+`
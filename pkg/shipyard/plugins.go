@@ -0,0 +1,39 @@
+package shipyard
+
+import (
+	"fmt"
+
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/plugins"
+)
+
+// loadPlugins starts the provider plugins in the given directory and
+// registers the resource types they provide with the parser, it is called
+// by the parser the first time a configuration or the state is parsed
+func (e *EngineImpl) loadPlugins(dir string) error {
+	m, err := plugins.Load(dir, e.log.Named("plugins"))
+	if err != nil {
+		return fmt.Errorf("unable to load plugins from %s: %s", dir, err)
+	}
+
+	for _, s := range m.Schemas() {
+		err := resources.RegisterPluginType(s)
+		if err != nil {
+			e.log.Error("Unable to register plugin resource type", "type", s.Type, "error", err)
+			continue
+		}
+
+		e.log.Debug("Registered plugin resource type", "type", s.Type)
+	}
+
+	e.clients.Plugins = m
+
+	return nil
+}
+
+// Close stops the plugins which have been started by the engine
+func (e *EngineImpl) Close() {
+	if e.clients.Plugins != nil {
+		e.clients.Plugins.Close()
+	}
+}
@@ -10,6 +10,8 @@ import (
 // generateProviderImpl returns providers grouped together in order of execution
 func generateProviderImpl(c types.Resource, cc *clients.Clients) providers.Provider {
 	switch c.Metadata().Type {
+	case resources.TypeBlueprint:
+		return providers.NewNull(c.Metadata(), cc.Logger)
 	case resources.TypeCertificateCA:
 		return providers.NewCertificateCA(c.(*resources.CertificateCA), cc.Logger)
 	case resources.TypeCertificateLeaf:
@@ -50,6 +52,17 @@ func generateProviderImpl(c types.Resource, cc *clients.Clients) providers.Provi
 		return providers.NewContainerSidecar(c.(*resources.Sidecar), cc.ContainerTasks, cc.HTTP, cc.Logger)
 	case resources.TypeTemplate:
 		return providers.NewTemplate(c.(*resources.Template), cc.Logger)
+	case resources.TypeVolume:
+		return providers.NewVolume(c.(*resources.DockerVolume), cc.Docker, cc.Logger)
+	case resources.TypeWait:
+		return providers.NewWait(c.(*resources.Wait), cc.ContainerTasks, cc.HTTP, cc.Kubernetes, cc.Nomad, cc.Logger)
+	}
+
+	// resource types which are not built in are provided by plugins
+	if p, ok := c.(*resources.Plugin); ok && cc.Plugins != nil {
+		if pp := cc.Plugins.Provider(p.Type); pp != nil {
+			return providers.NewPlugin(p, pp, cc.Logger)
+		}
 	}
 
 	return nil
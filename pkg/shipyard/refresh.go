@@ -0,0 +1,85 @@
+package shipyard
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/providers"
+	"github.com/jumppad-labs/jumppad/pkg/shipyard/constants"
+	"github.com/shipyard-run/hclconfig/types"
+)
+
+// Drift describes a resource whose real world objects no longer match the
+// state
+type Drift struct {
+	Resource types.Resource
+	Reason   string
+}
+
+// Refresh checks that the real world objects for the created resources in
+// the state still exist and match the state. Resources which have drifted
+// are marked as tainted so that they are re-created on the next apply, no
+// other changes are made.
+func (e *EngineImpl) Refresh() ([]Drift, error) {
+	e.log.Info("Checking resources for drift")
+
+	// lock the state so that other processes can not modify it while
+	// resources are checked
+	unlock, err := resources.LockState()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	c, err := resources.LoadState()
+	if err != nil {
+		return nil, err
+	}
+
+	drift := []Drift{}
+	errs := []string{}
+
+	for _, r := range c.Resources {
+		if r.Metadata().Properties[constants.PropertyStatus] != constants.StatusCreated {
+			continue
+		}
+
+		// only providers which manage real world objects can drift
+		dc, ok := e.getProvider(r, e.clients).(providers.DriftChecker)
+		if !ok {
+			continue
+		}
+
+		err := dc.CheckDrift()
+
+		de := providers.DriftError{}
+		if errors.As(err, &de) {
+			e.log.Info("Resource has drifted", "ref", r.Metadata().ID, "reason", de.Reason)
+
+			r.Metadata().Properties[constants.PropertyStatus] = constants.StatusTainted
+			drift = append(drift, Drift{Resource: r, Reason: de.Reason})
+
+			continue
+		}
+
+		if err != nil {
+			e.log.Debug("Unable to check resource for drift", "ref", r.Metadata().ID, "error", err)
+			errs = append(errs, fmt.Sprintf("%s: %s", r.Metadata().ID, err))
+		}
+	}
+
+	if len(drift) > 0 {
+		err = resources.SaveState(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(errs) > 0 {
+		return drift, fmt.Errorf("unable to check resources for drift: %s", strings.Join(errs, "\n"))
+	}
+
+	return drift, nil
+}
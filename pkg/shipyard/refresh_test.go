@@ -0,0 +1,165 @@
+package shipyard
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/providers"
+	"github.com/jumppad-labs/jumppad/pkg/providers/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/shipyard/constants"
+	"github.com/shipyard-run/hclconfig/types"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestsWithDrift configures the providers for the resources with the
+// names in drift to return the given error from Refresh and CheckDrift
+func setupTestsWithDrift(t *testing.T, state string, drift map[string]error) (*EngineImpl, *[]*mocks.MockProvider) {
+	e, mp := setupTestsWithState(t, nil, state)
+
+	gp := e.getProvider
+	e.getProvider = func(c types.Resource, cc *clients.Clients) providers.Provider {
+		m := gp(c, cc).(*mocks.MockProvider)
+
+		if err, ok := drift[c.Metadata().Name]; ok {
+			m.ExpectedCalls = nil
+
+			m.On("Create").Return(nil)
+			m.On("Destroy").Return(nil)
+			m.On("Refresh").Return(err)
+			m.On("CheckDrift").Return(err)
+		}
+
+		return m
+	}
+
+	return e, mp
+}
+
+func TestApplyRecreatesDriftedResources(t *testing.T) {
+	e, mp := setupTestsWithDrift(t, existingState, map[string]error{
+		"consul_config": providers.DriftError{Reason: "file consul.hcl does not exist"},
+	})
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file")
+	require.NoError(t, err)
+
+	tmpl := getProviderForResource(t, mp, "consul_config")
+	require.Len(t, callsFor(tmpl, "Destroy"), 1)
+	require.Len(t, callsFor(tmpl, "Create"), 1)
+
+	sf := testLoadState(t, e)
+
+	r, err := sf.FindResource("resource.template.consul_config")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestApplyWithRefreshErrorSetsStatusFailed(t *testing.T) {
+	e, mp := setupTestsWithDrift(t, existingState, map[string]error{
+		"consul_config": fmt.Errorf("boom"),
+	})
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file")
+	require.Error(t, err)
+
+	tmpl := getProviderForResource(t, mp, "consul_config")
+	require.Len(t, callsFor(tmpl, "Destroy"), 0)
+
+	sf := testLoadState(t, e)
+
+	r, err := sf.FindResource("resource.template.consul_config")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusFailed, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestRefreshMarksDriftedResourcesTainted(t *testing.T) {
+	e, mp := setupTestsWithDrift(t, existingState, map[string]error{
+		"cloud": providers.DriftError{Reason: "network cloud does not exist"},
+	})
+
+	drift, err := e.Refresh()
+	require.NoError(t, err)
+
+	require.Len(t, drift, 1)
+	require.Equal(t, "resource.network.cloud", drift[0].Resource.Metadata().ID)
+	require.Equal(t, "network cloud does not exist", drift[0].Reason)
+
+	// refresh only checks the resources
+	testAssertMethodCalled(t, mp, "CheckDrift", 4)
+	testAssertMethodCalled(t, mp, "Create", 0)
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+	testAssertMethodCalled(t, mp, "Refresh", 0)
+
+	sf := testLoadState(t, e)
+
+	r, err := sf.FindResource("resource.network.cloud")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusTainted, r.Metadata().Properties[constants.PropertyStatus])
+
+	r, err = sf.FindResource("resource.container.container")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestRefreshWithNoDriftDoesNotModifyState(t *testing.T) {
+	e, _ := setupTestsWithState(t, nil, existingState)
+
+	drift, err := e.Refresh()
+	require.NoError(t, err)
+	require.Len(t, drift, 0)
+
+	sf := testLoadState(t, e)
+	for _, r := range sf.Resources {
+		require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+	}
+}
+
+func TestRefreshWithCheckErrorReturnsErrorAndDrift(t *testing.T) {
+	e, _ := setupTestsWithDrift(t, existingState, map[string]error{
+		"cloud":     providers.DriftError{Reason: "network cloud does not exist"},
+		"container": fmt.Errorf("boom"),
+	})
+
+	drift, err := e.Refresh()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "resource.container.container")
+	require.Len(t, drift, 1)
+
+	sf := testLoadState(t, e)
+
+	r, err := sf.FindResource("resource.network.cloud")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusTainted, r.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestRefreshWithNoStateReturnsError(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	_, err := e.Refresh()
+	require.Error(t, err)
+}
+
+func getProviderForResource(t *testing.T, mp *[]*mocks.MockProvider, name string) *mocks.MockProvider {
+	for _, m := range *mp {
+		if m.Config().Metadata().Name == name {
+			return m
+		}
+	}
+
+	t.Fatalf("no provider for resource %s", name)
+
+	return nil
+}
+
+func callsFor(m *mocks.MockProvider, method string) []string {
+	calls := []string{}
+	for _, c := range m.Calls {
+		if c.Method == method {
+			calls = append(calls, c.Method)
+		}
+	}
+
+	return calls
+}
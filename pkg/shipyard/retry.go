@@ -0,0 +1,85 @@
+package shipyard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/providers"
+	"github.com/jumppad-labs/jumppad/pkg/shipyard/constants"
+	"github.com/shipyard-run/hclconfig/types"
+)
+
+// createWithRetry calls the providers Create method applying the retry
+// policy and timeout for the resource, the number of attempts is recorded
+// in the resource properties
+func (e *EngineImpl) createWithRetry(ctx context.Context, p providers.Provider, r types.Resource) error {
+	attempts := 1
+
+	policy := resources.RetryForResource(r)
+	if policy != nil {
+		err := policy.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid retry for resource %s: %s", r.Metadata().ID, err)
+		}
+
+		attempts = policy.MaxAttempts()
+	}
+
+	var timeout time.Duration
+	if t := resources.TimeoutForResource(r); t != "" {
+		d, err := time.ParseDuration(t)
+		if err != nil {
+			return fmt.Errorf("unable to parse timeout %s for resource %s: %s", t, r.Metadata().ID, err)
+		}
+
+		timeout = d
+	}
+
+	for attempt := 1; ; attempt++ {
+		r.Metadata().Properties[constants.PropertyAttempts] = attempt
+
+		err := e.createWithTimeout(ctx, p, timeout)
+		if err == nil || attempt >= attempts || ctx.Err() != nil {
+			return err
+		}
+
+		backoff := policy.BackoffFor(attempt)
+		e.log.Warn("Unable to create resource, retrying", "ref", r.Metadata().ID, "attempt", attempt, "attempts", attempts, "backoff", backoff, "error", err)
+
+		// remove anything the failed attempt may have partially created
+		// so that the next attempt does not conflict with it
+		if derr := p.Destroy(ctx); derr != nil {
+			e.log.Debug("Unable to clean up failed attempt", "ref", r.Metadata().ID, "error", derr)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// createWithTimeout calls the providers Create method, when timeout is
+// greater than zero the context passed to the provider is cancelled once
+// the timeout has elapsed. The engine always waits for Create to return so
+// that a retry or rollback never runs while the provider is still creating
+// the resource.
+func (e *EngineImpl) createWithTimeout(ctx context.Context, p providers.Provider, timeout time.Duration) error {
+	if timeout <= 0 {
+		return p.Create(ctx)
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := p.Create(tctx)
+	if err != nil && ctx.Err() == nil && errors.Is(tctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timeout after %s: %w", timeout, err)
+	}
+
+	return err
+}
@@ -0,0 +1,207 @@
+package shipyard
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/jumppad-labs/jumppad/pkg/providers"
+	"github.com/jumppad-labs/jumppad/pkg/providers/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/shipyard/constants"
+	"github.com/shipyard-run/hclconfig/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRetryTests(t *testing.T, retry *resources.Retry, timeout string) (*EngineImpl, *resources.Container, *mocks.MockProvider) {
+	e, _ := setupTests(t, nil)
+
+	c := &resources.Container{
+		ResourceMetadata: types.ResourceMetadata{
+			ID:         "resource.container.consul",
+			Name:       "consul",
+			Type:       resources.TypeContainer,
+			Properties: map[string]interface{}{},
+		},
+		Retry:   retry,
+		Timeout: timeout,
+	}
+
+	return e, c, mocks.New(c)
+}
+
+func TestCreateWithRetryRetriesFailedCreate(t *testing.T) {
+	e, c, p := setupRetryTests(t, &resources.Retry{Attempts: 3, Backoff: "1ms"}, "")
+	p.On("Create").Return(fmt.Errorf("boom")).Twice()
+	p.On("Create").Return(nil)
+	p.On("Destroy").Return(nil)
+
+	err := e.createWithRetry(context.Background(), p, c)
+	require.NoError(t, err)
+
+	p.AssertNumberOfCalls(t, "Create", 3)
+	p.AssertNumberOfCalls(t, "Destroy", 2)
+	require.Equal(t, 3, c.Properties[constants.PropertyAttempts])
+}
+
+func TestCreateWithRetryReturnsErrorWhenAttemptsExhausted(t *testing.T) {
+	e, c, p := setupRetryTests(t, &resources.Retry{Attempts: 2, Backoff: "1ms"}, "")
+	p.On("Create").Return(fmt.Errorf("boom"))
+	p.On("Destroy").Return(nil)
+
+	err := e.createWithRetry(context.Background(), p, c)
+	require.Error(t, err)
+
+	p.AssertNumberOfCalls(t, "Create", 2)
+	require.Equal(t, 2, c.Properties[constants.PropertyAttempts])
+}
+
+func TestCreateWithRetryWithoutPolicyDoesNotRetry(t *testing.T) {
+	e, c, p := setupRetryTests(t, nil, "")
+	p.On("Create").Return(fmt.Errorf("boom"))
+
+	err := e.createWithRetry(context.Background(), p, c)
+	require.Error(t, err)
+
+	p.AssertNumberOfCalls(t, "Create", 1)
+	p.AssertNotCalled(t, "Destroy")
+	require.Equal(t, 1, c.Properties[constants.PropertyAttempts])
+}
+
+func TestCreateWithRetryDoesNotRetryWhenCancelled(t *testing.T) {
+	e, c, p := setupRetryTests(t, &resources.Retry{Attempts: 3, Backoff: "1ms"}, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.On("Create").Run(func(mock.Arguments) { cancel() }).Return(context.Canceled)
+
+	err := e.createWithRetry(ctx, p, c)
+	require.Error(t, err)
+
+	p.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestCreateWithRetryAppliesTimeout(t *testing.T) {
+	e, c, _ := setupRetryTests(t, nil, "10ms")
+
+	p := &timeoutProvider{mocks.New(c)}
+
+	err := e.createWithRetry(context.Background(), p, c)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "timeout after 10ms")
+}
+
+func TestCreateWithRetryWaitsForTimedOutCreateBeforeRetrying(t *testing.T) {
+	e, c, _ := setupRetryTests(t, &resources.Retry{Attempts: 2, Backoff: "1ms"}, "10ms")
+
+	p := &slowStopProvider{MockProvider: mocks.New(c)}
+	p.On("Destroy").Run(func(mock.Arguments) {
+		require.Zero(t, atomic.LoadInt32(&p.running), "destroy called while create is running")
+	}).Return(nil)
+
+	err := e.createWithRetry(context.Background(), p, c)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "timeout after 10ms")
+
+	require.Zero(t, atomic.LoadInt32(&p.running))
+	require.Equal(t, int32(1), atomic.LoadInt32(&p.max))
+	p.AssertNumberOfCalls(t, "Destroy", 1)
+}
+
+func TestCreateWithRetryWithInvalidTimeoutReturnsError(t *testing.T) {
+	e, c, p := setupRetryTests(t, nil, "abc")
+
+	err := e.createWithRetry(context.Background(), p, c)
+	require.Error(t, err)
+
+	p.AssertNotCalled(t, "Create")
+}
+
+func TestApplyRecordsAttemptsInState(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	// fail the first attempt to create the container only
+	gp := e.getProvider
+	e.getProvider = func(c types.Resource, cc *clients.Clients) providers.Provider {
+		m := gp(c, cc).(*mocks.MockProvider)
+		if c.Metadata().Name == "consul" {
+			m.ExpectedCalls = nil
+			m.On("Create").Return(fmt.Errorf("boom")).Once()
+			m.On("Create").Return(nil)
+			m.On("Destroy").Return(nil)
+		}
+
+		return m
+	}
+
+	_, err := e.Apply(context.Background(), "../../examples/retry")
+	require.NoError(t, err)
+
+	sf := testLoadState(t, e)
+
+	r, err := sf.FindResource("resource.container.consul")
+	require.NoError(t, err)
+	require.Equal(t, constants.StatusCreated, r.Metadata().Properties[constants.PropertyStatus])
+	require.Equal(t, float64(2), r.Metadata().Properties[constants.PropertyAttempts])
+
+	r, err = sf.FindResource("resource.network.onprem")
+	require.NoError(t, err)
+	require.Equal(t, float64(1), r.Metadata().Properties[constants.PropertyAttempts])
+}
+
+func TestRetryDoesNotChangeChecksum(t *testing.T) {
+	c := &resources.Container{Command: []string{"consul"}}
+
+	c1, err := resourceChecksum(c)
+	require.NoError(t, err)
+
+	c.Retry = &resources.Retry{Attempts: 5}
+	c.Timeout = "60s"
+
+	c2, err := resourceChecksum(c)
+	require.NoError(t, err)
+
+	require.Equal(t, c1, c2)
+}
+
+// timeoutProvider blocks create until the context is cancelled
+type timeoutProvider struct {
+	*mocks.MockProvider
+}
+
+func (p *timeoutProvider) Create(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(5 * time.Second):
+		return nil
+	}
+}
+
+// slowStopProvider takes time to stop after the context is cancelled and
+// records the maximum number of concurrent calls to create
+type slowStopProvider struct {
+	*mocks.MockProvider
+	running int32
+	max     int32
+}
+
+func (p *slowStopProvider) Create(ctx context.Context) error {
+	n := atomic.AddInt32(&p.running, 1)
+	defer atomic.AddInt32(&p.running, -1)
+
+	for {
+		m := atomic.LoadInt32(&p.max)
+		if n <= m || atomic.CompareAndSwapInt32(&p.max, m, n) {
+			break
+		}
+	}
+
+	<-ctx.Done()
+	time.Sleep(50 * time.Millisecond)
+
+	return ctx.Err()
+}
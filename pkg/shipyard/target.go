@@ -0,0 +1,96 @@
+package shipyard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jumppad-labs/jumppad/pkg/config/resources"
+	"github.com/shipyard-run/hclconfig/types"
+)
+
+// targetResources returns the ids of the resources which match the given
+// targets. When dependents is false the transitive dependencies of the
+// matched resources are also returned, this is the set of resources that
+// need to exist in order to create the targets. When dependents is true
+// the transitive dependents are returned, this is the set of resources that
+// need to be removed in order to destroy the targets.
+//
+// An error is returned if a target does not match any resource.
+func targetResources(res []types.Resource, targets []string, dependents bool) (map[string]bool, error) {
+	ids := map[string]bool{}
+
+	for _, t := range targets {
+		fqrn, err := types.ParseFQRN(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target %s: %s", t, err)
+		}
+
+		found := false
+		for _, r := range res {
+			if matchesTarget(r, fqrn) {
+				ids[r.Metadata().ID] = true
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("target %s does not match any resources", t)
+		}
+	}
+
+	// keep adding dependencies or dependents until there are no more
+	// resources to add
+	for {
+		added := false
+
+		for _, r := range res {
+			// the image cache is managed by the engine and depends on every
+			// network, it is never targeted
+			if ids[r.Metadata().ID] || r.Metadata().Type == resources.TypeImageCache {
+				continue
+			}
+
+			if dependents {
+				if findDependency(r, ids) == "" {
+					continue
+				}
+			} else {
+				if !isDependency(r, res, ids) {
+					continue
+				}
+			}
+
+			ids[r.Metadata().ID] = true
+			added = true
+		}
+
+		if !added {
+			return ids, nil
+		}
+	}
+}
+
+// isDependency returns true when any of the resources in ids depends on r
+func isDependency(r types.Resource, res []types.Resource, ids map[string]bool) bool {
+	for _, o := range res {
+		if !ids[o.Metadata().ID] {
+			continue
+		}
+
+		if findDependency(o, map[string]bool{r.Metadata().ID: true}) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesTarget returns true when the resource is the target or, if the
+// target is a module, when the resource is contained in the module
+func matchesTarget(r types.Resource, target *types.ResourceFQRN) bool {
+	if target.Type == types.TypeModule {
+		return strings.HasPrefix(r.Metadata().ID, target.String()+".")
+	}
+
+	return r.Metadata().ID == target.String()
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/moby/buildkit/frontend/dockerfile/dockerignore"
+)
+
+// DockerIgnorePatterns returns the exclude patterns from the .dockerignore
+// file in the root of the build context. Like the Docker CLI the Dockerfile
+// and the .dockerignore file are never excluded as the builder needs them.
+func DockerIgnorePatterns(context, dockerfile string) ([]string, error) {
+	f, err := os.Open(filepath.Join(context, ".dockerignore"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	excludes, err := dockerignore.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read .dockerignore: %s", err)
+	}
+
+	if len(excludes) == 0 {
+		return nil, nil
+	}
+
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+
+	return append(excludes, "!"+filepath.ToSlash(filepath.Clean(dockerfile)), "!.dockerignore"), nil
+}
+
+// WalkBuildContext walks the files in the build context calling fn for every
+// file and folder which is not excluded by the given .dockerignore patterns,
+// the path passed to fn is relative to the context.
+func WalkBuildContext(context string, excludes []string, fn func(path, rel string, fi os.FileInfo) error) error {
+	pm, err := fileutils.NewPatternMatcher(excludes)
+	if err != nil {
+		return fmt.Errorf("invalid .dockerignore pattern: %s", err)
+	}
+
+	return filepath.Walk(context, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(context, path)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		skip, err := pm.Matches(filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+
+		if skip {
+			// folders can only be skipped when there are no exceptions as
+			// a file inside the folder may be included again
+			if fi.IsDir() && !pm.Exclusions() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		return fn(path, rel, fi)
+	})
+}
+
+// BuildContextChecksum returns a hash of the names, modes and contents of the
+// files in the build context which are not excluded by the given patterns
+func BuildContextChecksum(context string, excludes []string) (string, error) {
+	paths := []string{}
+	files := map[string]string{}
+	modes := map[string]os.FileMode{}
+
+	err := WalkBuildContext(context, excludes, func(path, rel string, fi os.FileInfo) error {
+		rel = filepath.ToSlash(rel)
+		paths = append(paths, rel)
+		files[rel] = path
+		modes[rel] = fi.Mode()
+
+		return nil
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("unable to read build context %s: %s", context, err)
+	}
+
+	// walk returns files in lexical order, sort anyway so that the hash does
+	// not depend on the order
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, p := range paths {
+		fmt.Fprintf(h, "%s %s\n", p, modes[p])
+
+		if modes[p]&os.ModeSymlink != 0 {
+			target, err := os.Readlink(files[p])
+			if err != nil {
+				return "", fmt.Errorf("unable to read build context %s: %s", context, err)
+			}
+
+			fmt.Fprintf(h, "%s\n", target)
+			continue
+		}
+
+		if !modes[p].IsRegular() {
+			continue
+		}
+
+		f, err := os.Open(files[p])
+		if err != nil {
+			return "", fmt.Errorf("unable to read build context %s: %s", context, err)
+		}
+
+		_, err = io.Copy(h, f)
+		f.Close()
+
+		if err != nil {
+			return "", fmt.Errorf("unable to read build context %s: %s", context, err)
+		}
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
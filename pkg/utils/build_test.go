@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func setupBuildContext(t *testing.T) string {
+	dir := t.TempDir()
+
+	ioutil.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM alpine"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "debug.log"), []byte("debug"), 0644)
+
+	os.MkdirAll(filepath.Join(dir, "node_modules", "lib"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "node_modules", "lib", "index.js"), []byte(""), 0644)
+
+	return dir
+}
+
+func TestDockerIgnorePatternsWithoutFileReturnsNothing(t *testing.T) {
+	dir := setupBuildContext(t)
+
+	p, err := DockerIgnorePatterns(dir, "")
+	require.NoError(t, err)
+	require.Empty(t, p)
+}
+
+func TestDockerIgnorePatternsAlwaysIncludesDockerfile(t *testing.T) {
+	dir := setupBuildContext(t)
+	ioutil.WriteFile(filepath.Join(dir, ".dockerignore"), []byte("# comment\n*.log\nnode_modules\n"), 0644)
+
+	p, err := DockerIgnorePatterns(dir, "./build/Dockerfile")
+	require.NoError(t, err)
+	require.Equal(t, []string{"*.log", "node_modules", "!build/Dockerfile", "!.dockerignore"}, p)
+}
+
+func TestWalkBuildContextSkipsExcludedFiles(t *testing.T) {
+	dir := setupBuildContext(t)
+
+	files := []string{}
+	err := WalkBuildContext(dir, []string{"*.log", "node_modules"}, func(path, rel string, fi os.FileInfo) error {
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"Dockerfile", "main.go"}, files)
+}
+
+func TestBuildContextChecksumChangesWhenFilesChange(t *testing.T) {
+	dir := setupBuildContext(t)
+
+	cs, err := BuildContextChecksum(dir, nil)
+	require.NoError(t, err)
+	require.Len(t, cs, 64)
+
+	cs2, err := BuildContextChecksum(dir, nil)
+	require.NoError(t, err)
+	require.Equal(t, cs, cs2)
+
+	ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}"), 0644)
+
+	cs3, err := BuildContextChecksum(dir, nil)
+	require.NoError(t, err)
+	require.NotEqual(t, cs, cs3)
+}
+
+func TestBuildContextChecksumIgnoresExcludedFiles(t *testing.T) {
+	dir := setupBuildContext(t)
+
+	cs, err := BuildContextChecksum(dir, []string{"*.log"})
+	require.NoError(t, err)
+
+	ioutil.WriteFile(filepath.Join(dir, "debug.log"), []byte("more debug"), 0644)
+
+	cs2, err := BuildContextChecksum(dir, []string{"*.log"})
+	require.NoError(t, err)
+	require.Equal(t, cs, cs2)
+}
+
+func TestBuildContextChecksumWithMissingContextReturnsError(t *testing.T) {
+	_, err := BuildContextChecksum(filepath.Join(t.TempDir(), "missing"), nil)
+	require.Error(t, err)
+}
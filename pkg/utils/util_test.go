@@ -94,18 +94,18 @@ func TestValidatesNameAndReturnsErrorWhenTooLong(t *testing.T) {
 }
 
 func TestFQDNReturnsCorrectValue(t *testing.T) {
-	fq := FQDN("test", "type")
-	assert.Equal(t, "test.type.shipyard.run", fq)
+	fq := FQDN("test", "", "type")
+	assert.Equal(t, "test.type.jumppad.dev", fq)
 }
 
 func TestFQDNReplacesInvalidChars(t *testing.T) {
-	fq := FQDN("tes&t", "k8s_cluster")
-	assert.Equal(t, "tes-t.k8s-cluster.shipyard.run", fq)
+	fq := FQDN("tes&t", "", "k8s_cluster")
+	assert.Equal(t, "tes-t.k8s-cluster.jumppad.dev", fq)
 }
 
 func TestFQDNVolumeReturnsCorrectValue(t *testing.T) {
 	fq := FQDNVolumeName("test")
-	assert.Equal(t, "test.volume.shipyard.run", fq)
+	assert.Equal(t, "test.volume.jumppad.dev", fq)
 }
 
 func TestHomeReturnsCorrectValue(t *testing.T) {
@@ -115,14 +115,14 @@ func TestHomeReturnsCorrectValue(t *testing.T) {
 
 func TestStateReturnsCorrectValue(t *testing.T) {
 	h := StateDir()
-	expected := filepath.Join(os.Getenv(HomeEnvName()), ".shipyard/state")
+	expected := filepath.Join(os.Getenv(HomeEnvName()), ".jumppad/state")
 
 	assert.Equal(t, expected, h)
 }
 
 func TestStatePathReturnsCorrectValue(t *testing.T) {
 	h := StatePath()
-	assert.Equal(t, filepath.Join(os.Getenv(HomeEnvName()), ".shipyard/state/state.json"), h)
+	assert.Equal(t, filepath.Join(os.Getenv(HomeEnvName()), ".jumppad/state/state.json"), h)
 }
 
 func TestCreateKubeConfigPathReturnsCorrectValues(t *testing.T) {
@@ -133,9 +133,9 @@ func TestCreateKubeConfigPathReturnsCorrectValues(t *testing.T) {
 
 	d, f, dp := CreateKubeConfigPath("testing")
 
-	assert.Equal(t, filepath.Join(tmp, ".shipyard", "config", "testing"), d)
-	assert.Equal(t, filepath.Join(tmp, ".shipyard", "config", "testing", "kubeconfig.yaml"), f)
-	assert.Equal(t, filepath.Join(tmp, ".shipyard", "config", "testing", "kubeconfig-docker.yaml"), dp)
+	assert.Equal(t, filepath.Join(tmp, ".jumppad", "config", "testing"), d)
+	assert.Equal(t, filepath.Join(tmp, ".jumppad", "config", "testing", "kubeconfig.yaml"), f)
+	assert.Equal(t, filepath.Join(tmp, ".jumppad", "config", "testing", "kubeconfig-docker.yaml"), dp)
 
 	// check creates folder
 	s, err := os.Stat(d)
@@ -143,79 +143,6 @@ func TestCreateKubeConfigPathReturnsCorrectValues(t *testing.T) {
 	assert.True(t, s.IsDir())
 }
 
-func setupClusterConfigTest(t *testing.T) {
-	home := os.Getenv(HomeEnvName())
-	tmp := t.TempDir()
-	os.Setenv(HomeEnvName(), tmp)
-
-	t.Cleanup(func() {
-		os.Setenv(HomeEnvName(), home)
-	})
-}
-func TestGetClusterConfigReturnsExistingConfig(t *testing.T) {
-	setupClusterConfigTest(t)
-
-	configDir := filepath.Join(JumppadHome(), "config", "testing")
-	os.MkdirAll(configDir, os.ModePerm)
-
-	// create the temp config
-	cc := ClusterConfig{
-		LocalAddress: "testing",
-	}
-
-	err := cc.Save(filepath.Join(configDir, "config.json"))
-	assert.NoError(t, err)
-
-	conf, dir := GetClusterConfig("nomad_cluster.testing")
-
-	assert.Equal(t, cc.LocalAddress, conf.LocalAddress)
-	assert.Equal(t, configDir, dir)
-}
-
-func TestGetClusterConfigReturnsEmptyWhenUnableToParseName(t *testing.T) {
-	setupClusterConfigTest(t)
-
-	conf, dir := GetClusterConfig("nomad")
-
-	assert.Equal(t, "", conf.LocalAddress)
-	assert.Equal(t, "", dir)
-}
-
-func TestGetClusterConfigCreatesNewNomadConfig(t *testing.T) {
-	setupClusterConfigTest(t)
-	configDir := filepath.Join(JumppadHome(), "config", "testing")
-
-	conf, dir := GetClusterConfig("nomad_cluster.testing")
-
-	assert.Contains(t, GetDockerIP(), conf.LocalAddress)
-	assert.Equal(t, 4646, conf.RemoteAPIPort)
-	assert.Equal(t, "server.testing.nomad-cluster.shipyard.run", conf.RemoteAddress)
-	assert.Equal(t, GetDockerIP(), conf.LocalAddress)
-	assert.Equal(t, configDir, dir)
-}
-
-func TestGetClusterConfigTwiceReturnsSameConfig(t *testing.T) {
-	setupClusterConfigTest(t)
-
-	conf, _ := GetClusterConfig("nomad_cluster.testing")
-	conf2, _ := GetClusterConfig("nomad_cluster.testing")
-
-	assert.Equal(t, conf2.ConnectorAddress(LocalContext), conf.ConnectorAddress(LocalContext))
-}
-
-func TestGetClusterConfigCreatesNewKubernetesConfig(t *testing.T) {
-	setupClusterConfigTest(t)
-	configDir := filepath.Join(JumppadHome(), "config", "testing")
-
-	conf, dir := GetClusterConfig("k8s_cluster.testing")
-
-	assert.Contains(t, GetDockerIP(), conf.LocalAddress)
-	assert.Equal(t, conf.APIPort, conf.RemoteAPIPort)
-	assert.Equal(t, "server.testing.k8s-cluster.shipyard.run", conf.RemoteAddress)
-	assert.Equal(t, GetDockerIP(), conf.LocalAddress)
-	assert.Equal(t, configDir, dir)
-}
-
 func TestShipyardTempReturnsPath(t *testing.T) {
 	home := os.Getenv(HomeEnvName())
 	tmp, _ := ioutil.TempDir("", "")
@@ -228,7 +155,7 @@ func TestShipyardTempReturnsPath(t *testing.T) {
 
 	st := ShipyardTemp()
 
-	assert.Equal(t, filepath.Join(tmp, ".shipyard", "/tmp"), st)
+	assert.Equal(t, filepath.Join(tmp, ".jumppad", "/tmp"), st)
 
 	s, err := os.Stat(st)
 	assert.NoError(t, err)
@@ -247,7 +174,7 @@ func TestShipyardDataReturnsPath(t *testing.T) {
 
 	d := GetDataFolder("test", 0775)
 
-	assert.Equal(t, filepath.Join(tmp, ".shipyard", "/data", "/test"), d)
+	assert.Equal(t, filepath.Join(tmp, ".jumppad", "/data", "/test"), d)
 
 	s, err := os.Stat(d)
 	fmt.Println(d, s)
@@ -260,13 +187,13 @@ func TestHelmLocalFolderReturnsPath(t *testing.T) {
 	chart := "github.com/jetstack/cert-manager?ref=v1.2.0/deploy/charts//cert-manager"
 	h := GetHelmLocalFolder(chart)
 
-	assert.Equal(t, filepath.Join(os.Getenv(HomeEnvName()), ".shipyard", "/helm_charts", "github.com/jetstack/cert-manager/ref/v1.2.0/deploy/charts/cert-manager"), h)
+	assert.Equal(t, filepath.Join(os.Getenv(HomeEnvName()), ".jumppad", "/helm_charts", "github.com/jetstack/cert-manager/ref/v1.2.0/deploy/charts/cert-manager"), h)
 }
 
 func TestShipyardReleasesReturnsPath(t *testing.T) {
 	r := GetReleasesFolder()
 
-	assert.Equal(t, filepath.Join(os.Getenv(HomeEnvName()), ".shipyard", "/releases"), r)
+	assert.Equal(t, filepath.Join(os.Getenv(HomeEnvName()), ".jumppad", "/releases"), r)
 }
 
 func TestIsHCLFile(t *testing.T) {
@@ -341,7 +268,7 @@ func TestHTTPSProxyAddressReturnsDefaultWhenEnvNotSet(t *testing.T) {
 
 func TestHTTPProxyAddressReturnsEnvWhenEnvSet(t *testing.T) {
 	httpProxy := "http://myproxy.com"
-	os.Setenv("HTTP_PROXY", httpProxy)
+	t.Setenv("HTTP_PROXY", httpProxy)
 	proxy := HTTPProxyAddress()
 
 	assert.Equal(t, httpProxy, proxy)
@@ -349,7 +276,7 @@ func TestHTTPProxyAddressReturnsEnvWhenEnvSet(t *testing.T) {
 
 func TestHTTPSProxyAddressReturnsEnvWhenEnvSet(t *testing.T) {
 	httpsProxy := "https://myproxy.com"
-	os.Setenv("HTTPS_PROXY", httpsProxy)
+	t.Setenv("HTTPS_PROXY", httpsProxy)
 	proxy := HTTPSProxyAddress()
 
 	assert.Equal(t, httpsProxy, proxy)
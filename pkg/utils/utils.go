@@ -49,11 +49,13 @@ func ReplaceNonURIChars(s string) (string, error) {
 	return reg.ReplaceAllString(s, "-"), nil
 }
 
-// FQDN generates the full qualified name for a container
+// FQDN generates the full qualified name for a container, resources in
+// a workspace other than the default include the workspace in the name
+// e.g. consul.container.demo.jumppad.dev
 func FQDN(name, module, typeName string) string {
-	fqdn := fmt.Sprintf("%s.%s.jumppad.dev", name, typeName)
+	fqdn := fmt.Sprintf("%s.%s.%s", name, typeName, workspaceDomain())
 	if module != "" {
-		fqdn = fmt.Sprintf("%s.%s.%s.jumppad.dev", name, module, typeName)
+		fqdn = fmt.Sprintf("%s.%s.%s.%s", name, module, typeName, workspaceDomain())
 	}
 
 	// ensure that the name is valid for URI schema
@@ -73,13 +75,24 @@ func FQDNVolumeName(name string) string {
 		panic(err)
 	}
 
-	return fmt.Sprintf("%s.volume.jumppad.dev", cleanName)
+	return fmt.Sprintf("%s.volume.%s", cleanName, workspaceDomain())
+}
+
+// FQDNNetworkName creates the name of the Docker network for a network
+// resource, networks in the default workspace use the name of the resource
+// other workspaces add the workspace domain so that the names do not clash
+func FQDNNetworkName(name string) string {
+	if Workspace() == DefaultWorkspace {
+		return name
+	}
+
+	return fmt.Sprintf("%s.%s", name, workspaceDomain())
 }
 
 // CreateKubeConfigPath creates the file path for the KubeConfig file when
 // using Kubernetes cluster
 func CreateKubeConfigPath(name string) (dir, filePath string, dockerPath string) {
-	dir = filepath.Join(JumppadHome(), "/config/", name)
+	dir = filepath.Join(WorkspaceDir(Workspace()), "/config/", name)
 	filePath = filepath.Join(dir, "/kubeconfig.yaml")
 	dockerPath = filepath.Join(dir, "/kubeconfig-docker.yaml")
 
@@ -125,15 +138,16 @@ func ShipyardTemp() string {
 }
 
 // StateDir returns the location of the shipyard
-// state, usually $HOME/.shipyard/state
+// state, usually $HOME/.shipyard/state, or
+// $HOME/.jumppad/workspaces/<name>/state for workspaces other than the default
 func StateDir() string {
-	return filepath.Join(JumppadHome(), "/state")
+	return filepath.Join(WorkspaceDir(Workspace()), "/state")
 }
 
 // CertsDir returns the location of the certificates for the given resource
-// used to secure the Shipyard ingress, usually rooted at $HOME/.shipyard/certs
+// in the current workspace, usually rooted at $HOME/.jumppad/certs
 func CertsDir(name string) string {
-	certs := filepath.Join(JumppadHome(), "/certs", name)
+	certs := filepath.Join(WorkspaceDir(Workspace()), "/certs", name)
 	certs = filepath.FromSlash(certs)
 
 	// create the folder if it does not exist
@@ -155,9 +169,34 @@ func StatePath() string {
 	return filepath.Join(StateDir(), "/state.json")
 }
 
-// ImageCacheLog returns the location of the image cache log
+// StateHistoryDir returns the location of the backups of previous versions
+// of the state, usually $HOME/.jumppad/state/history
+func StateHistoryDir() string {
+	return filepath.Join(StateDir(), "/history")
+}
+
+// StateLockPath returns the full path for the lock file which prevents
+// concurrent modification of the state
+func StateLockPath() string {
+	return filepath.Join(StateDir(), "/state.lock")
+}
+
+// ConnectorCertsDir returns the location of the certificates used to secure
+// the connector, the connector is shared by all workspaces so the
+// certificates are not scoped to the workspace, usually
+// $HOME/.jumppad/connector/certs
+func ConnectorCertsDir() string {
+	certs := filepath.FromSlash(filepath.Join(JumppadHome(), "/connector", "/certs"))
+
+	// create the folder if it does not exist
+	os.MkdirAll(certs, os.ModePerm)
+	return certs
+}
+
+// ImageCacheLog returns the location of the image cache log for the current
+// workspace
 func ImageCacheLog() string {
-	return fmt.Sprintf("%s/images.log", JumppadHome())
+	return filepath.Join(WorkspaceDir(Workspace()), "/images.log")
 }
 
 // IsLocalFolder tests if the given path is a localfolder and can
@@ -237,14 +276,21 @@ func GetHelmLocalFolder(chart string) string {
 	return filepath.Join(JumppadHome(), "helm_charts", chart)
 }
 
+// PluginsDir returns the location of the provider plugins, usually
+// $HOME/.jumppad/plugins
+func PluginsDir() string {
+	return filepath.Join(JumppadHome(), "plugins")
+}
+
 // GetReleasesFolder return the path of the Shipyard releases
 func GetReleasesFolder() string {
 	return filepath.Join(JumppadHome(), "releases")
 }
 
-// GetDataFolder creates the data directory used by the application
+// GetDataFolder creates the data directory used by the application in the
+// current workspace
 func GetDataFolder(p string, perms os.FileMode) string {
-	data := filepath.Join(JumppadHome(), "data", p)
+	data := filepath.Join(WorkspaceDir(Workspace()), "data", p)
 
 	// create the folder if it does not exist
 	os.MkdirAll(data, perms)
@@ -282,7 +328,8 @@ func GetDockerIP() string {
 	return sp
 }
 
-// GetConnectorPIDFile returns the connector PID file used by the connector
+// GetConnectorPIDFile returns the connector PID file used by the connector,
+// the connector binds fixed ports and is shared by all workspaces
 func GetConnectorPIDFile() string {
 	return filepath.Join(JumppadHome(), "connector.pid")
 }
@@ -423,7 +470,7 @@ func HTTPProxyAddress() string {
 		return p
 	}
 
-	return shipyardProxyAddress
+	return proxyAddress()
 }
 
 // HTTPSProxyAddress returns the default HTTPProxy used by
@@ -434,7 +481,7 @@ func HTTPSProxyAddress() string {
 		return p
 	}
 
-	return shipyardProxyAddress
+	return proxyAddress()
 }
 
 // get all ipaddresses in a subnet
@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultWorkspace is the workspace used when no workspace has been
+// selected, resources in the default workspace use the same names and
+// state location as before workspaces were introduced
+const DefaultWorkspace = "default"
+
+// WorkspaceEnvName is the environment variable used to select the workspace
+// when the --workspace flag is not set
+const WorkspaceEnvName = "JUMPPAD_WORKSPACE"
+
+var WorkspaceNotFoundError = fmt.Errorf("Workspace does not exist")
+var WorkspaceExistsError = fmt.Errorf("Workspace already exists")
+var InvalidWorkspaceNameError = fmt.Errorf("Workspace names must start with a-z or 0-9, contain only a-z, 0-9 and -, and be at most 32 characters")
+
+var workspace = DefaultWorkspace
+var workspaceLock sync.Mutex
+
+// SetWorkspace sets the workspace used for the state and the names of
+// Docker objects
+func SetWorkspace(name string) {
+	workspaceLock.Lock()
+	defer workspaceLock.Unlock()
+
+	workspace = name
+}
+
+// Workspace returns the current workspace
+func Workspace() string {
+	workspaceLock.Lock()
+	defer workspaceLock.Unlock()
+
+	return workspace
+}
+
+// ValidateWorkspaceName ensures that the workspace name can be used in
+// a DNS name and as a directory name
+func ValidateWorkspaceName(name string) error {
+	if len(name) > 32 || !regexp.MustCompile(`^[a-z0-9][a-z0-9\-]*$`).MatchString(name) {
+		return InvalidWorkspaceNameError
+	}
+
+	return nil
+}
+
+// WorkspacesDir returns the location of the workspaces, usually
+// $HOME/.jumppad/workspaces
+func WorkspacesDir() string {
+	return filepath.Join(JumppadHome(), "/workspaces")
+}
+
+// WorkspaceDir returns the location of the files for the given workspace,
+// the default workspace uses $HOME/.jumppad
+func WorkspaceDir(name string) string {
+	if name == DefaultWorkspace {
+		return JumppadHome()
+	}
+
+	return filepath.Join(WorkspacesDir(), name)
+}
+
+// workspaceFile returns the path of the file which stores the selected
+// workspace
+func workspaceFile() string {
+	return filepath.Join(JumppadHome(), "/workspace")
+}
+
+// WorkspaceExists returns true when the workspace has been created, the
+// default workspace always exists
+func WorkspaceExists(name string) bool {
+	if name == DefaultWorkspace {
+		return true
+	}
+
+	if ValidateWorkspaceName(name) != nil {
+		return false
+	}
+
+	s, err := os.Stat(WorkspaceDir(name))
+	return err == nil && s.IsDir()
+}
+
+// CreateWorkspace creates a new workspace
+func CreateWorkspace(name string) error {
+	err := ValidateWorkspaceName(name)
+	if err != nil {
+		return err
+	}
+
+	if WorkspaceExists(name) {
+		return WorkspaceExistsError
+	}
+
+	return os.MkdirAll(WorkspaceDir(name), os.ModePerm)
+}
+
+// DeleteWorkspace removes the workspace and all of its files, the default
+// workspace can not be removed
+func DeleteWorkspace(name string) error {
+	if name == DefaultWorkspace {
+		return fmt.Errorf("The %s workspace can not be deleted", DefaultWorkspace)
+	}
+
+	if !WorkspaceExists(name) {
+		return WorkspaceNotFoundError
+	}
+
+	// deleting the selected workspace selects the default
+	if SelectedWorkspace() == name {
+		err := SelectWorkspace(DefaultWorkspace)
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.RemoveAll(WorkspaceDir(name))
+}
+
+// ListWorkspaces returns the names of all workspaces including the default
+// sorted by name
+func ListWorkspaces() ([]string, error) {
+	ws := []string{DefaultWorkspace}
+
+	entries, err := ioutil.ReadDir(WorkspacesDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		if e.IsDir() && ValidateWorkspaceName(e.Name()) == nil && e.Name() != DefaultWorkspace {
+			ws = append(ws, e.Name())
+		}
+	}
+
+	sort.Strings(ws)
+
+	return ws, nil
+}
+
+// SelectWorkspace stores the workspace which is used by commands when no
+// workspace is specified
+func SelectWorkspace(name string) error {
+	if !WorkspaceExists(name) {
+		return WorkspaceNotFoundError
+	}
+
+	err := os.MkdirAll(JumppadHome(), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(workspaceFile(), []byte(name), os.ModePerm)
+}
+
+// SelectedWorkspace returns the workspace stored by SelectWorkspace, when no
+// workspace has been selected or the selected workspace no longer exists the
+// default workspace is returned
+func SelectedWorkspace() string {
+	d, err := ioutil.ReadFile(workspaceFile())
+	if err != nil {
+		return DefaultWorkspace
+	}
+
+	name := strings.TrimSpace(string(d))
+	if !WorkspaceExists(name) {
+		return DefaultWorkspace
+	}
+
+	return name
+}
+
+// workspaceDomain returns the domain used for the names of Docker objects in
+// the current workspace
+func workspaceDomain() string {
+	if ws := Workspace(); ws != DefaultWorkspace {
+		return fmt.Sprintf("%s.jumppad.dev", ws)
+	}
+
+	return "jumppad.dev"
+}
+
+// proxyAddress returns the address of the image cache for the current
+// workspace
+func proxyAddress() string {
+	if Workspace() == DefaultWorkspace {
+		return shipyardProxyAddress
+	}
+
+	return fmt.Sprintf("http://%s:3128", FQDN("default", "", "image-cache"))
+}
+
+// WorkspaceStatePath returns the full path of the state file for the given
+// workspace
+func WorkspaceStatePath(name string) string {
+	return filepath.Join(WorkspaceDir(name), "/state", "/state.json")
+}
+
+// WorkspacesWithState returns the names of the workspaces which have a state
+// file, the state file is removed once all resources have been destroyed.
+// Only the local state files are checked, the state of workspaces which use
+// a remote or directory state backend is not found.
+func WorkspacesWithState() ([]string, error) {
+	all, err := ListWorkspaces()
+	if err != nil {
+		return nil, err
+	}
+
+	ws := []string{}
+	for _, w := range all {
+		if _, err := os.Stat(WorkspaceStatePath(w)); err == nil {
+			ws = append(ws, w)
+		}
+	}
+
+	return ws, nil
+}
+
+// RemoveWorkspaceFiles removes the data folder and the certificates of the
+// current workspace, files belonging to other workspaces are not removed
+func RemoveWorkspaceFiles() error {
+	err := os.RemoveAll(GetDataFolder("", os.ModePerm))
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(CertsDir(""))
+}
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func setupWorkspaceTest(t *testing.T) string {
+	home := os.Getenv(HomeEnvName())
+	tmp := t.TempDir()
+	os.Setenv(HomeEnvName(), tmp)
+
+	t.Cleanup(func() {
+		os.Setenv(HomeEnvName(), home)
+		SetWorkspace(DefaultWorkspace)
+	})
+
+	return tmp
+}
+
+func TestFQDNWithDefaultWorkspaceDoesNotIncludeWorkspace(t *testing.T) {
+	setupWorkspaceTest(t)
+
+	assert.Equal(t, "test.mine.container.jumppad.dev", FQDN("test", "mine", "container"))
+	assert.Equal(t, "test.volume.jumppad.dev", FQDNVolumeName("test"))
+	assert.Equal(t, "test", FQDNNetworkName("test"))
+	assert.Equal(t, shipyardProxyAddress, HTTPProxyAddress())
+}
+
+func TestFQDNWithWorkspaceIncludesWorkspace(t *testing.T) {
+	setupWorkspaceTest(t)
+	SetWorkspace("demo")
+
+	assert.Equal(t, "test.container.demo.jumppad.dev", FQDN("test", "", "container"))
+	assert.Equal(t, "test.mine.container.demo.jumppad.dev", FQDN("test", "mine", "container"))
+	assert.Equal(t, "test.volume.demo.jumppad.dev", FQDNVolumeName("test"))
+	assert.Equal(t, "test.demo.jumppad.dev", FQDNNetworkName("test"))
+	assert.Equal(t, "http://default.image-cache.demo.jumppad.dev:3128", HTTPProxyAddress())
+}
+
+func TestStateDirWithWorkspaceReturnsWorkspaceDir(t *testing.T) {
+	tmp := setupWorkspaceTest(t)
+
+	assert.Equal(t, filepath.Join(tmp, ".jumppad", "state"), StateDir())
+
+	SetWorkspace("demo")
+
+	assert.Equal(t, filepath.Join(tmp, ".jumppad", "workspaces", "demo", "state"), StateDir())
+	assert.Equal(t, filepath.Join(tmp, ".jumppad", "workspaces", "demo", "state", "state.json"), StatePath())
+	assert.Equal(t, StatePath(), WorkspaceStatePath("demo"))
+}
+
+func TestValidateWorkspaceName(t *testing.T) {
+	assert.NoError(t, ValidateWorkspaceName("integration-test"))
+	assert.NoError(t, ValidateWorkspaceName("demo1"))
+
+	assert.Error(t, ValidateWorkspaceName(""))
+	assert.Error(t, ValidateWorkspaceName("Demo"))
+	assert.Error(t, ValidateWorkspaceName("-demo"))
+	assert.Error(t, ValidateWorkspaceName("demo.test"))
+	assert.Error(t, ValidateWorkspaceName("../demo"))
+}
+
+func TestCreateWorkspaceAddsWorkspace(t *testing.T) {
+	setupWorkspaceTest(t)
+
+	err := CreateWorkspace("demo")
+	assert.NoError(t, err)
+
+	err = CreateWorkspace("demo")
+	assert.ErrorIs(t, err, WorkspaceExistsError)
+
+	err = CreateWorkspace(DefaultWorkspace)
+	assert.ErrorIs(t, err, WorkspaceExistsError)
+
+	ws, err := ListWorkspaces()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default", "demo"}, ws)
+}
+
+func TestSelectWorkspaceStoresWorkspace(t *testing.T) {
+	setupWorkspaceTest(t)
+
+	assert.Equal(t, DefaultWorkspace, SelectedWorkspace())
+
+	err := SelectWorkspace("demo")
+	assert.ErrorIs(t, err, WorkspaceNotFoundError)
+
+	err = CreateWorkspace("demo")
+	assert.NoError(t, err)
+
+	err = SelectWorkspace("demo")
+	assert.NoError(t, err)
+	assert.Equal(t, "demo", SelectedWorkspace())
+}
+
+func TestDeleteWorkspaceRemovesWorkspaceAndSelectsDefault(t *testing.T) {
+	setupWorkspaceTest(t)
+
+	err := CreateWorkspace("demo")
+	assert.NoError(t, err)
+
+	err = SelectWorkspace("demo")
+	assert.NoError(t, err)
+
+	err = DeleteWorkspace("demo")
+	assert.NoError(t, err)
+
+	assert.False(t, WorkspaceExists("demo"))
+	assert.Equal(t, DefaultWorkspace, SelectedWorkspace())
+
+	err = DeleteWorkspace("demo")
+	assert.ErrorIs(t, err, WorkspaceNotFoundError)
+
+	err = DeleteWorkspace(DefaultWorkspace)
+	assert.Error(t, err)
+}
+
+func TestWorkspaceFilesAreScopedToWorkspace(t *testing.T) {
+	tmp := setupWorkspaceTest(t)
+
+	assert.Equal(t, filepath.Join(tmp, ".jumppad", "data", "test"), GetDataFolder("test", os.ModePerm))
+	assert.Equal(t, filepath.Join(tmp, ".jumppad", "certs", "test"), CertsDir("test"))
+	assert.Equal(t, filepath.Join(tmp, ".jumppad", "images.log"), ImageCacheLog())
+
+	SetWorkspace("demo")
+
+	assert.Equal(t, filepath.Join(tmp, ".jumppad", "workspaces", "demo", "data", "test"), GetDataFolder("test", os.ModePerm))
+	assert.Equal(t, filepath.Join(tmp, ".jumppad", "workspaces", "demo", "certs", "test"), CertsDir("test"))
+	assert.Equal(t, filepath.Join(tmp, ".jumppad", "workspaces", "demo", "images.log"), ImageCacheLog())
+	assert.Equal(t, filepath.Join(tmp, ".jumppad", "connector", "certs"), ConnectorCertsDir())
+}
+
+// writeWorkspaceFiles creates a data file, a certificate and the state for
+// the given workspace and returns the paths
+func writeWorkspaceFiles(t *testing.T, name string) []string {
+	SetWorkspace(name)
+	defer SetWorkspace(DefaultWorkspace)
+
+	os.MkdirAll(StateDir(), os.ModePerm)
+
+	files := []string{
+		filepath.Join(GetDataFolder("test", os.ModePerm), "data.txt"),
+		filepath.Join(CertsDir("test"), "leaf.cert"),
+		StatePath(),
+	}
+
+	for _, f := range files {
+		err := ioutil.WriteFile(f, []byte("test"), os.ModePerm)
+		assert.NoError(t, err)
+	}
+
+	return files
+}
+
+func TestRemoveWorkspaceFilesDoesNotRemoveOtherWorkspaces(t *testing.T) {
+	setupWorkspaceTest(t)
+
+	for _, ws := range []string{"demo", DefaultWorkspace} {
+		t.Run(ws, func(t *testing.T) {
+			err := CreateWorkspace("other")
+			assert.NoError(t, err)
+			defer DeleteWorkspace("other")
+
+			other := writeWorkspaceFiles(t, "other")
+			files := writeWorkspaceFiles(t, ws)
+
+			// down removes the state before the files
+			SetWorkspace(ws)
+			defer SetWorkspace(DefaultWorkspace)
+
+			os.Remove(StatePath())
+
+			err = RemoveWorkspaceFiles()
+			assert.NoError(t, err)
+
+			for _, f := range files {
+				assert.NoFileExists(t, f)
+			}
+
+			for _, f := range other {
+				assert.FileExists(t, f)
+			}
+
+			withState, err := WorkspacesWithState()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"other"}, withState)
+		})
+	}
+}